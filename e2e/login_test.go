@@ -17,6 +17,7 @@
 package e2e
 
 import (
+	"strings"
 	"testing"
 
 	"gotest.tools/v3/icmd"
@@ -35,3 +36,15 @@ Please login to Docker Hub using the "docker login" command
 `,
 	})
 }
+
+func TestLoginWithPasswordStdinRequiresUsername(t *testing.T) {
+	cmd, cleanup := hubToolCmd(t, "login", "--password-stdin")
+	defer cleanup()
+	cmd.Stdin = strings.NewReader("token\n")
+
+	output := icmd.RunCmd(cmd)
+	output.Assert(t, icmd.Expected{
+		ExitCode: 1,
+		Err:      "a username is required to login with --password-stdin or HUB_TOKEN",
+	})
+}
@@ -36,6 +36,16 @@ func TestVersionCmd(t *testing.T) {
 	assert.Equal(t, output, expected)
 }
 
+func TestVersionCmdJSON(t *testing.T) {
+	cmd, cleanup := hubToolCmd(t, "version", "--format", "json")
+	defer cleanup()
+
+	output := icmd.RunCmd(cmd).Assert(t, icmd.Success).Combined()
+	expected := fmt.Sprintf("{\n  \"Version\": %q,\n  \"GitCommit\": %q\n}\n", internal.Version, internal.GitCommit)
+
+	assert.Equal(t, output, expected)
+}
+
 func TestVersionFlag(t *testing.T) {
 	cmd, cleanup := hubToolCmd(t, "--version")
 	defer cleanup()
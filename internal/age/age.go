@@ -0,0 +1,53 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package age parses the ages given to the commands, such as 90d
+package age
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// units are the suffixes of the ages given in days, weeks or years
+var units = map[string]time.Duration{
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+	"y": 365 * 24 * time.Hour,
+}
+
+// Parse parses an age given in days, such as 90d, weeks, such as 4w, years,
+// such as 1y, or as a Go duration such as 36h
+func Parse(value string) (time.Duration, error) {
+	invalid := fmt.Errorf("invalid age %q: should be a number of days (90d), weeks (4w), years (1y) or a duration (36h)", value)
+	if value == "" {
+		return 0, invalid
+	}
+	unit, ok := units[value[len(value)-1:]]
+	if !ok {
+		age, err := time.ParseDuration(value)
+		if err != nil || age <= 0 {
+			return 0, invalid
+		}
+		return age, nil
+	}
+	count, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || count <= 0 {
+		return 0, invalid
+	}
+	return time.Duration(count) * unit, nil
+}
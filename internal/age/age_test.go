@@ -0,0 +1,53 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package age
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		value         string
+		age           time.Duration
+		expectedError string
+	}{
+		{value: "90d", age: 90 * 24 * time.Hour},
+		{value: "4w", age: 28 * 24 * time.Hour},
+		{value: "1y", age: 365 * 24 * time.Hour},
+		{value: "36h", age: 36 * time.Hour},
+		{value: "", expectedError: `invalid age ""`},
+		{value: "0d", expectedError: `invalid age "0d"`},
+		{value: "d", expectedError: `invalid age "d"`},
+		{value: "3m2d", expectedError: `invalid age "3m2d"`},
+		{value: "-2h", expectedError: `invalid age "-2h"`},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.value, func(t *testing.T) {
+			age, err := Parse(testCase.value)
+			if testCase.expectedError != "" {
+				assert.ErrorContains(t, err, testCase.expectedError)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, age, testCase.age)
+		})
+	}
+}
@@ -0,0 +1,407 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package browse is an interactive terminal browser of the repositories and
+// tags of an account
+package browse
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/docker/go-units"
+
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	clearScreen = "\033[H\033[2J"
+	reverse     = "\033[7m"
+	bold        = "\033[1m"
+	reset       = "\033[0m"
+	help        = "↑/↓ move   enter open   ← back   d delete   r refresh   q quit"
+)
+
+// Source is what the browser lists and deletes, the Hub client in the tool
+type Source interface {
+	GetRepositories(ctx context.Context, account string, filters ...hub.RepositoryFilter) ([]hub.Repository, int, error)
+	GetTags(ctx context.Context, repository string, reqOps ...hub.RequestOp) ([]hub.Tag, int, error)
+	RemoveRepository(ctx context.Context, repository string) error
+	RemoveTag(ctx context.Context, repository, tag string) error
+	DryRun() bool
+}
+
+// Key is a key pressed by the user
+type Key int
+
+const (
+	// KeyOther is any key without action
+	KeyOther Key = iota
+	// KeyUp moves the selection up
+	KeyUp
+	// KeyDown moves the selection down
+	KeyDown
+	// KeyEnter opens the selected repository or tag
+	KeyEnter
+	// KeyBack goes back to the previous view
+	KeyBack
+	// KeyDelete asks to delete the selected repository or tag
+	KeyDelete
+	// KeyYes confirms the deletion
+	KeyYes
+	// KeyRefresh reloads the current view
+	KeyRefresh
+	// KeyQuit quits the browser
+	KeyQuit
+)
+
+// item is a row of a view, either a repository or a tag
+type item struct {
+	label      string
+	repository *hub.Repository
+	tag        *hub.Tag
+}
+
+// view is a list of repositories or tags, or the details of a tag when it
+// has no items
+type view struct {
+	title    string
+	items    []item
+	lines    []string
+	selected int
+	// repository is the repository of the listed tags
+	repository string
+}
+
+// Browser holds the views opened by the user, the last one being displayed
+type Browser struct {
+	ctx     context.Context
+	source  Source
+	account string
+	views   []*view
+	status  string
+	// deleting is the item waiting for the user to confirm its deletion
+	deleting *item
+}
+
+// New returns a browser of the repositories of the account
+func New(ctx context.Context, source Source, account string) *Browser {
+	return &Browser{ctx: ctx, source: source, account: account}
+}
+
+// Load lists the repositories of the account, as the first view
+func (b *Browser) Load() error {
+	v, err := b.repositoriesView()
+	if err != nil {
+		return err
+	}
+	b.views = []*view{v}
+	return nil
+}
+
+// Run displays the browser and handles the keys read from in, until the
+// user quits. The terminal is expected in raw mode.
+func Run(b *Browser, in io.Reader, out io.Writer, height func() int) error {
+	reader := bufio.NewReader(in)
+	for {
+		b.Render(out, height())
+		key, err := readKey(reader)
+		if err != nil {
+			return err
+		}
+		if b.HandleKey(key) {
+			fmt.Fprint(out, clearScreen)
+			return nil
+		}
+	}
+}
+
+// HandleKey updates the browser for a pressed key, returning true when the
+// user quits
+func (b *Browser) HandleKey(key Key) bool {
+	current := b.views[len(b.views)-1]
+	if b.deleting != nil {
+		deleting := b.deleting
+		b.deleting = nil
+		if key == KeyYes {
+			b.delete(current, deleting)
+		} else {
+			b.status = "Deletion canceled"
+		}
+		return false
+	}
+	b.status = ""
+	switch key {
+	case KeyQuit:
+		return true
+	case KeyUp:
+		if current.selected > 0 {
+			current.selected--
+		}
+	case KeyDown:
+		if current.selected < len(current.items)-1 {
+			current.selected++
+		}
+	case KeyBack:
+		if len(b.views) > 1 {
+			b.views = b.views[:len(b.views)-1]
+		}
+	case KeyEnter:
+		if len(current.items) == 0 {
+			return false
+		}
+		selected := current.items[current.selected]
+		var (
+			next *view
+			err  error
+		)
+		if selected.repository != nil {
+			next, err = b.tagsView(selected.repository.Name)
+		} else {
+			next = tagView(*selected.tag)
+		}
+		if err != nil {
+			b.status = "Error: " + err.Error()
+			return false
+		}
+		b.views = append(b.views, next)
+	case KeyDelete:
+		if len(current.items) == 0 {
+			return false
+		}
+		b.deleting = &current.items[current.selected]
+		b.status = fmt.Sprintf("Delete %s? This action is irreversible [y/N]", b.itemName(current, b.deleting))
+	case KeyRefresh:
+		b.refresh(current)
+	}
+	return false
+}
+
+// Render draws the current view, scrolled to keep the selection visible
+func (b *Browser) Render(out io.Writer, height int) {
+	current := b.views[len(b.views)-1]
+	var sb strings.Builder
+	sb.WriteString(clearScreen)
+	sb.WriteString(bold + current.title + reset + "\r\n\r\n")
+
+	// Keep room for the title and the status lines
+	rows := height - 4
+	if rows < 1 {
+		rows = 1
+	}
+	if len(current.items) == 0 {
+		for i, line := range current.lines {
+			if i >= rows {
+				break
+			}
+			sb.WriteString(line + "\r\n")
+		}
+		if len(current.lines) == 0 {
+			sb.WriteString("Nothing to show\r\n")
+		}
+	}
+	first := 0
+	if current.selected >= rows {
+		first = current.selected - rows + 1
+	}
+	for i := first; i < len(current.items) && i < first+rows; i++ {
+		if i == current.selected {
+			sb.WriteString(reverse + current.items[i].label + reset + "\r\n")
+		} else {
+			sb.WriteString(current.items[i].label + "\r\n")
+		}
+	}
+	sb.WriteString("\r\n")
+	if b.status != "" {
+		sb.WriteString(b.status)
+	} else {
+		sb.WriteString(help)
+	}
+	fmt.Fprint(out, sb.String())
+}
+
+func (b *Browser) repositoriesView() (*view, error) {
+	repositories, _, err := b.source.GetRepositories(b.ctx, b.account)
+	if err != nil {
+		return nil, err
+	}
+	v := &view{title: fmt.Sprintf("Repositories of %s (%d)", b.account, len(repositories))}
+	for i := range repositories {
+		r := repositories[i]
+		v.items = append(v.items, item{
+			label:      fmt.Sprintf("%-50s %10d pulls %6d stars   updated %s", r.Name, r.PullCount, r.StarCount, since(r.LastUpdated)),
+			repository: &r,
+		})
+	}
+	return v, nil
+}
+
+func (b *Browser) tagsView(repository string) (*view, error) {
+	tags, _, err := b.source.GetTags(b.ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	v := &view{title: fmt.Sprintf("Tags of %s (%d)", repository, len(tags)), repository: repository}
+	for i := range tags {
+		t := tags[i]
+		v.items = append(v.items, item{
+			label: fmt.Sprintf("%-40s %-19s %10s   pushed %s", shortTag(t.Name), shortDigest(t.Digest), units.HumanSize(float64(t.FullSize)), since(t.LastPushed)),
+			tag:   &t,
+		})
+	}
+	return v, nil
+}
+
+func tagView(tag hub.Tag) *view {
+	v := &view{title: tag.Name}
+	v.lines = append(v.lines,
+		fmt.Sprintf("Digest:       %s", tag.Digest),
+		fmt.Sprintf("Status:       %s", tag.Status),
+		fmt.Sprintf("Last pushed:  %s", since(tag.LastPushed)),
+		fmt.Sprintf("Last pulled:  %s", since(tag.LastPulled)),
+		fmt.Sprintf("Last updater: %s", tag.LastUpdaterUserName),
+		"",
+		"Images:",
+	)
+	for _, image := range tag.Images {
+		platform := hub.Platform{OS: image.Os, Architecture: image.Architecture, Variant: image.Variant}
+		v.lines = append(v.lines, fmt.Sprintf("  %-16s %-19s %10s", platform, shortDigest(image.Digest), units.HumanSize(float64(image.Size))))
+	}
+	return v
+}
+
+func (b *Browser) refresh(current *view) {
+	var (
+		fresh *view
+		err   error
+	)
+	switch {
+	case current.repository != "":
+		fresh, err = b.tagsView(current.repository)
+	case len(current.items) > 0 || len(b.views) == 1:
+		fresh, err = b.repositoriesView()
+	default:
+		return
+	}
+	if err != nil {
+		b.status = "Error: " + err.Error()
+		return
+	}
+	fresh.selected = current.selected
+	if fresh.selected >= len(fresh.items) {
+		fresh.selected = len(fresh.items) - 1
+	}
+	if fresh.selected < 0 {
+		fresh.selected = 0
+	}
+	*current = *fresh
+}
+
+func (b *Browser) delete(current *view, deleting *item) {
+	name := b.itemName(current, deleting)
+	if b.source.DryRun() {
+		b.status = "Would delete " + name
+		return
+	}
+	var err error
+	if deleting.repository != nil {
+		err = b.source.RemoveRepository(b.ctx, deleting.repository.Name)
+	} else {
+		err = b.source.RemoveTag(b.ctx, current.repository, shortTag(deleting.tag.Name))
+	}
+	if err != nil {
+		b.status = fmt.Sprintf("Error: failed to delete %s: %s", name, err)
+		return
+	}
+	current.items = append(current.items[:current.selected], current.items[current.selected+1:]...)
+	if current.selected >= len(current.items) && current.selected > 0 {
+		current.selected--
+	}
+	b.status = "Deleted " + name
+}
+
+func (b *Browser) itemName(current *view, i *item) string {
+	if i.repository != nil {
+		return i.repository.Name
+	}
+	return current.repository + ":" + shortTag(i.tag.Name)
+}
+
+// readKey reads a key, decoding the escape sequences of the arrow keys
+func readKey(reader *bufio.Reader) (Key, error) {
+	c, err := reader.ReadByte()
+	if err != nil {
+		return KeyOther, err
+	}
+	switch c {
+	case 'k':
+		return KeyUp, nil
+	case 'j':
+		return KeyDown, nil
+	case '\r', '\n', 'l':
+		return KeyEnter, nil
+	case 'h', 127, '\b':
+		return KeyBack, nil
+	case 'd':
+		return KeyDelete, nil
+	case 'y', 'Y':
+		return KeyYes, nil
+	case 'r':
+		return KeyRefresh, nil
+	case 'q', 3:
+		return KeyQuit, nil
+	case 27:
+		if reader.Buffered() == 0 {
+			return KeyBack, nil
+		}
+		if next, _ := reader.ReadByte(); next != '[' {
+			return KeyOther, nil
+		}
+		switch code, _ := reader.ReadByte(); code {
+		case 'A':
+			return KeyUp, nil
+		case 'B':
+			return KeyDown, nil
+		case 'C':
+			return KeyEnter, nil
+		case 'D':
+			return KeyBack, nil
+		}
+	}
+	return KeyOther, nil
+}
+
+func shortTag(name string) string {
+	return name[strings.LastIndex(name, ":")+1:]
+}
+
+func shortDigest(digest string) string {
+	if len(digest) > 19 {
+		return digest[:19]
+	}
+	return digest
+}
+
+func since(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return units.HumanDuration(time.Since(t)) + " ago"
+}
@@ -0,0 +1,109 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package browse
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+type fakeSource struct {
+	repositories []hub.Repository
+	tags         map[string][]hub.Tag
+	removed      []string
+	dryRun       bool
+}
+
+func (f *fakeSource) GetRepositories(ctx context.Context, account string, filters ...hub.RepositoryFilter) ([]hub.Repository, int, error) {
+	return f.repositories, len(f.repositories), nil
+}
+
+func (f *fakeSource) GetTags(ctx context.Context, repository string, reqOps ...hub.RequestOp) ([]hub.Tag, int, error) {
+	return f.tags[repository], len(f.tags[repository]), nil
+}
+
+func (f *fakeSource) RemoveRepository(ctx context.Context, repository string) error {
+	f.removed = append(f.removed, repository)
+	return nil
+}
+
+func (f *fakeSource) RemoveTag(ctx context.Context, repository, tag string) error {
+	f.removed = append(f.removed, repository+":"+tag)
+	return nil
+}
+
+func (f *fakeSource) DryRun() bool {
+	return f.dryRun
+}
+
+func TestBrowse(t *testing.T) {
+	source := &fakeSource{
+		repositories: []hub.Repository{{Name: "jdoe/app"}, {Name: "jdoe/db"}},
+		tags: map[string][]hub.Tag{
+			"jdoe/db": {{Name: "jdoe/db:v1", Digest: "sha256:1111"}, {Name: "jdoe/db:v2", Digest: "sha256:2222"}},
+		},
+	}
+	b := New(context.Background(), source, "jdoe")
+	assert.NilError(t, b.Load())
+
+	// Open the tags of the second repository, then the details of a tag
+	assert.Assert(t, !b.HandleKey(KeyDown))
+	b.HandleKey(KeyEnter)
+	assert.Equal(t, b.views[1].title, "Tags of jdoe/db (2)")
+	b.HandleKey(KeyDown)
+	b.HandleKey(KeyEnter)
+	out := &bytes.Buffer{}
+	b.Render(out, 20)
+	assert.Assert(t, strings.Contains(out.String(), "jdoe/db:v2"))
+	assert.Assert(t, strings.Contains(out.String(), "Digest:       sha256:2222"))
+
+	// Deleting needs a confirmation
+	b.HandleKey(KeyBack)
+	b.HandleKey(KeyDelete)
+	assert.Equal(t, b.status, "Delete jdoe/db:v2? This action is irreversible [y/N]")
+	b.HandleKey(KeyOther)
+	assert.Equal(t, b.status, "Deletion canceled")
+	assert.Equal(t, len(source.removed), 0)
+	b.HandleKey(KeyDelete)
+	b.HandleKey(KeyYes)
+	assert.DeepEqual(t, source.removed, []string{"jdoe/db:v2"})
+	assert.Equal(t, len(b.views[1].items), 1)
+	assert.Equal(t, b.views[1].selected, 0)
+
+	b.HandleKey(KeyBack)
+	b.HandleKey(KeyBack)
+	assert.Equal(t, len(b.views), 1)
+	assert.Assert(t, b.HandleKey(KeyQuit))
+}
+
+func TestReadKey(t *testing.T) {
+	reader := bufio.NewReader(strings.NewReader("j\x1b[A\x1b[D\rq"))
+	var keys []Key
+	for i := 0; i < 5; i++ {
+		key, err := readKey(reader)
+		assert.NilError(t, err)
+		keys = append(keys, key)
+	}
+	assert.DeepEqual(t, keys, []Key{KeyDown, KeyUp, KeyBack, KeyEnter, KeyQuit})
+}
@@ -0,0 +1,130 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package bulk runs the deletions of many repositories or tags, reporting
+// their progress and summing up their results
+package bulk
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/pflag"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	// clearLine moves the cursor to the start of the line and clears it
+	clearLine = "\r\033[K"
+	barWidth  = 30
+)
+
+// Deleter handles the concurrency flag of the bulk deletions and reports
+// their progress
+type Deleter struct {
+	concurrency int
+}
+
+// AddConcurrencyFlag adds the concurrency flag to a command
+func (d *Deleter) AddConcurrencyFlag(flags *pflag.FlagSet) {
+	flags.IntVar(&d.concurrency, "concurrency", 0, "Number of deletions run concurrently (defaults to the client concurrency)")
+}
+
+// Delete calls run to delete total items, which reports the result of each
+// one. Each deletion is printed as it completes, below a progress bar when the
+// output is a terminal, then a summary of the deleted and failed items. The
+// error of run is returned.
+func (d *Deleter) Delete(streams command.Streams, hubClient *hub.Client, noun string, total int, run func(report func(name string, err error)) error) error {
+	if d.concurrency != 0 {
+		if err := hubClient.Update(hub.WithConcurrency(d.concurrency)); err != nil {
+			return err
+		}
+	}
+	p := &progress{
+		out:   streams.Out(),
+		err:   streams.Err(),
+		tty:   streams.Out().IsTerminal(),
+		noun:  noun,
+		total: total,
+	}
+	p.draw()
+	err := run(p.report)
+	p.summary()
+	return err
+}
+
+type progress struct {
+	out    io.Writer
+	err    io.Writer
+	tty    bool
+	noun   string
+	total  int
+	done   int
+	failed []string
+}
+
+// report prints the result of a deletion above the progress bar
+func (p *progress) report(name string, err error) {
+	p.clear()
+	p.done++
+	if err != nil {
+		p.failed = append(p.failed, fmt.Sprintf("%s: %s", name, err))
+		fmt.Fprintln(p.err, ansi.Error(fmt.Sprintf("Failed to delete %s: %s", name, err)))
+	} else {
+		fmt.Fprintln(p.out, "Deleted", name)
+	}
+	p.draw()
+}
+
+func (p *progress) draw() {
+	if !p.tty || p.total == 0 {
+		return
+	}
+	filled := barWidth * p.done / p.total
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", barWidth-filled)
+	fmt.Fprintf(p.out, "Deleting [%s] %d/%d %s", bar, p.done, p.total, p.noun)
+}
+
+func (p *progress) clear() {
+	if p.tty {
+		fmt.Fprint(p.out, clearLine)
+	}
+}
+
+// summary replaces the progress bar with the number of deleted and failed
+// items, then the failures
+func (p *progress) summary() {
+	p.clear()
+	deleted := p.done - len(p.failed)
+	fmt.Fprintf(p.out, "Deleted %d of %d %s", deleted, p.total, p.noun)
+	if skipped := p.total - p.done; skipped > 0 {
+		fmt.Fprintf(p.out, ", %d skipped", skipped)
+	}
+	if len(p.failed) == 0 {
+		fmt.Fprintln(p.out)
+		return
+	}
+	fmt.Fprintln(p.out, ansi.Error(fmt.Sprintf(", %d failed:", len(p.failed))))
+	sort.Strings(p.failed)
+	for _, failure := range p.failed {
+		fmt.Fprintln(p.out, ansi.Error("  "+failure))
+	}
+}
@@ -0,0 +1,56 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package bulk
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestProgress(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	errOut := bytes.NewBuffer(nil)
+	p := &progress{out: out, err: errOut, noun: "tags", total: 4}
+	p.report("jdoe/repo:v1", nil)
+	p.report("jdoe/repo:v2", errors.New("not found"))
+	p.report("jdoe/repo:v3", nil)
+	p.summary()
+
+	assert.Equal(t, out.String(), `Deleted jdoe/repo:v1
+Deleted jdoe/repo:v3
+Deleted 2 of 4 tags, 1 skipped, 1 failed:
+  jdoe/repo:v2: not found
+`)
+	assert.Equal(t, errOut.String(), "Failed to delete jdoe/repo:v2: not found\n")
+}
+
+func TestProgressBar(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	p := &progress{out: out, err: out, tty: true, noun: "tags", total: 2}
+	p.draw()
+	p.report("jdoe/repo:v1", nil)
+	p.report("jdoe/repo:v2", nil)
+	p.summary()
+
+	assert.Equal(t, out.String(), "Deleting [                              ] 0/2 tags"+
+		clearLine+"Deleted jdoe/repo:v1\nDeleting [===============               ] 1/2 tags"+
+		clearLine+"Deleted jdoe/repo:v2\nDeleting [==============================] 2/2 tags"+
+		clearLine+"Deleted 2 of 2 tags\n")
+}
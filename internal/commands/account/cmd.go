@@ -21,7 +21,8 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/spf13/cobra"
 
-	"github.com/docker/hub-tool/internal/hub"
+	"github.com/docker/hub-tool/internal/credentials"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
@@ -29,7 +30,7 @@ const (
 )
 
 //NewAccountCmd configures the org manage command
-func NewAccountCmd(streams command.Streams, hubClient *hub.Client) *cobra.Command {
+func NewAccountCmd(streams command.Streams, hubClient *hub.Client, store credentials.Store) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:                   accountName,
 		Short:                 "Manage your account",
@@ -43,6 +44,9 @@ func NewAccountCmd(streams command.Streams, hubClient *hub.Client) *cobra.Comman
 	cmd.AddCommand(
 		newInfoCmd(streams, hubClient, accountName),
 		newRateLimitingCmd(streams, hubClient, accountName),
+		newUsageCmd(streams, hubClient, accountName),
+		newListCmd(streams, store, accountName),
+		newUseCmd(streams, store, accountName),
 	)
 	return cmd
 }
@@ -17,6 +17,7 @@
 package account
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"time"
@@ -29,8 +30,8 @@ import (
 
 	"github.com/docker/hub-tool/internal/ansi"
 	"github.com/docker/hub-tool/internal/format"
-	"github.com/docker/hub-tool/internal/hub"
 	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
@@ -56,16 +57,16 @@ func newInfoCmd(streams command.Streams, hubClient *hub.Client, parent string) *
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
-				return runOrgInfo(streams, hubClient, opts, args[0])
+				return runOrgInfo(cmd.Context(), streams, hubClient, opts, args[0])
 			}
-			return runUserInfo(streams, hubClient, opts)
+			return runUserInfo(cmd.Context(), streams, hubClient, opts)
 		},
 	}
 	opts.AddFormatFlag(cmd.Flags())
 	return cmd
 }
 
-func runOrgInfo(streams command.Streams, hubClient *hub.Client, opts infoOptions, orgName string) error {
+func runOrgInfo(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts infoOptions, orgName string) error {
 	var (
 		org         *hub.Account
 		consumption *hub.Consumption
@@ -74,19 +75,19 @@ func runOrgInfo(streams command.Streams, hubClient *hub.Client, opts infoOptions
 	g := errgroup.Group{}
 	g.Go(func() error {
 		var err error
-		org, err = hubClient.GetOrganizationInfo(orgName)
+		org, err = hubClient.GetOrganizationInfo(ctx, orgName)
 		return checkForbiddenError(err)
 	})
 	g.Go(func() error {
 		var err error
-		consumption, err = hubClient.GetOrgConsumption(orgName)
+		consumption, err = hubClient.GetOrgConsumption(ctx, orgName)
 		return checkForbiddenError(err)
 	})
 	if err := g.Wait(); err != nil {
 		return err
 	}
 
-	plan, err := hubClient.GetHubPlan(org.ID)
+	plan, err := hubClient.GetHubPlan(ctx, org.ID)
 	if err != nil {
 		return checkForbiddenError(err)
 	}
@@ -94,16 +95,16 @@ func runOrgInfo(streams command.Streams, hubClient *hub.Client, opts infoOptions
 	return opts.Print(streams.Out(), account{org, plan, consumption}, printAccount)
 }
 
-func runUserInfo(streams command.Streams, hubClient *hub.Client, opts infoOptions) error {
-	user, err := hubClient.GetUserInfo()
+func runUserInfo(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts infoOptions) error {
+	user, err := hubClient.GetUserInfo(ctx)
 	if err != nil {
 		return checkForbiddenError(err)
 	}
-	consumption, err := hubClient.GetUserConsumption(user.Name)
+	consumption, err := hubClient.GetUserConsumption(ctx, user.Name)
 	if err != nil {
 		return checkForbiddenError(err)
 	}
-	plan, err := hubClient.GetHubPlan(user.ID)
+	plan, err := hubClient.GetHubPlan(ctx, user.ID)
 	if err != nil {
 		return checkForbiddenError(err)
 	}
@@ -0,0 +1,139 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package account
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/credentials"
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/format/tabwriter"
+	"github.com/docker/hub-tool/internal/metrics"
+)
+
+const (
+	listName = "ls"
+	useName  = "use"
+)
+
+// profile is an account with stored credentials
+type profile struct {
+	Name    string `json:"name"`
+	Current bool   `json:"current"`
+}
+
+type listOptions struct {
+	format.Option
+}
+
+func newListCmd(streams command.Streams, store credentials.Store, parent string) *cobra.Command {
+	var opts listOptions
+	cmd := &cobra.Command{
+		Use:                   listName + " [OPTIONS]",
+		Aliases:               []string{"list"},
+		Short:                 "List the accounts you are logged in with",
+		Args:                  cli.NoArgs,
+		DisableFlagsInUseLine: true,
+		Annotations: map[string]string{
+			"anonymous": "true",
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, listName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(streams, store, opts)
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	return cmd
+}
+
+func runList(streams command.Streams, store credentials.Store, opts listOptions) error {
+	names, err := store.Profiles()
+	if err != nil {
+		return err
+	}
+	current, err := store.CurrentProfile()
+	if err != nil {
+		return err
+	}
+	profiles := make(profileList, len(names))
+	for i, name := range names {
+		profiles[i] = profile{Name: name, Current: name == current}
+	}
+	return opts.Print(streams.Out(), profiles, printProfiles)
+}
+
+func printProfiles(out io.Writer, values interface{}) error {
+	profiles := values.(profileList)
+
+	tw := tabwriter.New(out, "    ")
+	tw.Column(ansi.Header("NAME"), len("NAME"))
+	tw.Column(ansi.Header("CURRENT"), len("CURRENT"))
+	tw.Line()
+	for _, p := range profiles {
+		tw.Column(p.Name, len(p.Name))
+		current := ""
+		if p.Current {
+			current = "*"
+		}
+		tw.Column(current, len(current))
+		tw.Line()
+	}
+	return tw.Flush()
+}
+
+// profileList prints a row per account in csv and tsv
+type profileList []profile
+
+// Table returns the raw values of the accounts
+func (l profileList) Table() ([]string, [][]interface{}) {
+	rows := make([][]interface{}, len(l))
+	for i, p := range l {
+		rows[i] = []interface{}{p.Name, p.Current}
+	}
+	return []string{"NAME", "CURRENT"}, rows
+}
+
+func newUseCmd(streams command.Streams, store credentials.Store, parent string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   useName + " ACCOUNT",
+		Short:                 "Switch to another account you are logged in with",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		Annotations: map[string]string{
+			"anonymous": "true",
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, useName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := store.UseProfile(args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintln(streams.Out(), ansi.Info(fmt.Sprintf("Using account %q", args[0])))
+			return nil
+		},
+	}
+	return cmd
+}
@@ -17,6 +17,7 @@
 package account
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"time"
@@ -28,8 +29,9 @@ import (
 
 	"github.com/docker/hub-tool/internal/ansi"
 	"github.com/docker/hub-tool/internal/format"
-	"github.com/docker/hub-tool/internal/hub"
 	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/internal/watch"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
@@ -38,6 +40,8 @@ const (
 
 type rateLimitingOptions struct {
 	format.Option
+	watch.Watcher
+	anonymous bool
 }
 
 func newRateLimitingCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
@@ -54,16 +58,27 @@ func newRateLimitingCmd(streams command.Streams, hubClient *hub.Client, parent s
 			metrics.Send(parent, rateLimitingName)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runRateLimiting(streams, hubClient, opts)
+			if opts.Watching() {
+				return opts.Watch(cmd.Context(), streams, hubClient, cmd.CommandPath(), func(streams command.Streams) error {
+					return runRateLimiting(cmd.Context(), streams, hubClient, opts)
+				})
+			}
+			return runRateLimiting(cmd.Context(), streams, hubClient, opts)
 		},
 	}
 	opts.AddFormatFlag(cmd.Flags())
+	opts.AddWatchFlag(cmd.Flags())
+	cmd.Flags().BoolVar(&opts.anonymous, "anonymous", false, "Print the rate limits of anonymous pulls from this IP address")
 
 	return cmd
 }
 
-func runRateLimiting(streams command.Streams, hubClient *hub.Client, opts rateLimitingOptions) error {
-	rl, err := hubClient.GetRateLimits()
+func runRateLimiting(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts rateLimitingOptions) error {
+	getRateLimits := hubClient.GetRateLimits
+	if opts.anonymous {
+		getRateLimits = hubClient.GetAnonymousRateLimits
+	}
+	rl, err := getRateLimits(ctx)
 	if err != nil {
 		return err
 	}
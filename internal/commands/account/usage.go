@@ -0,0 +1,194 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package account
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	usageName = "usage"
+	// unlimited is the limit Hub reports for the unlimited resources of a plan
+	unlimited = 9999
+)
+
+type usageOptions struct {
+	format.Option
+}
+
+// usage is the consumption of an account against its plan limits
+type usage struct {
+	Account             string          `json:"account"`
+	Plan                string          `json:"plan"`
+	Seats               quota           `json:"seats"`
+	PrivateRepositories quota           `json:"private_repositories"`
+	Storage             int64           `json:"storage"`
+	Pulls               *hub.RateLimits `json:"pulls,omitempty"`
+}
+
+// quota is the use of a resource, without limit when unlimited
+type quota struct {
+	Used  int  `json:"used"`
+	Limit *int `json:"limit,omitempty"`
+}
+
+func newUsageCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts usageOptions
+	cmd := &cobra.Command{
+		Use:                   usageName + " [OPTIONS] [ORGANIZATION]",
+		Short:                 "Print the account usage against its plan limits",
+		Args:                  cli.RequiresMaxArgs(1),
+		DisableFlagsInUseLine: true,
+		Annotations: map[string]string{
+			"sudo": "true",
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, usageName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return runOrgUsage(cmd.Context(), streams, hubClient, opts, args[0])
+			}
+			return runUserUsage(cmd.Context(), streams, hubClient, opts)
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	return cmd
+}
+
+func runOrgUsage(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts usageOptions, orgName string) error {
+	var (
+		org         *hub.Account
+		consumption *hub.Consumption
+	)
+
+	g := errgroup.Group{}
+	g.Go(func() error {
+		var err error
+		org, err = hubClient.GetOrganizationInfo(ctx, orgName)
+		return checkForbiddenError(err)
+	})
+	g.Go(func() error {
+		var err error
+		consumption, err = hubClient.GetOrgConsumption(ctx, orgName)
+		return checkForbiddenError(err)
+	})
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	plan, err := hubClient.GetHubPlan(ctx, org.ID)
+	if err != nil {
+		return checkForbiddenError(err)
+	}
+
+	return opts.Print(streams.Out(), newUsage(org, plan, consumption, nil), printUsage)
+}
+
+// runUserUsage also reports the pull rate limits, which apply to the user
+// pulling and not to the organizations
+func runUserUsage(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts usageOptions) error {
+	user, err := hubClient.GetUserInfo(ctx)
+	if err != nil {
+		return checkForbiddenError(err)
+	}
+
+	var (
+		consumption *hub.Consumption
+		plan        *hub.Plan
+		pulls       *hub.RateLimits
+	)
+	g := errgroup.Group{}
+	g.Go(func() error {
+		var err error
+		consumption, err = hubClient.GetUserConsumption(ctx, user.Name)
+		return checkForbiddenError(err)
+	})
+	g.Go(func() error {
+		var err error
+		plan, err = hubClient.GetHubPlan(ctx, user.ID)
+		return checkForbiddenError(err)
+	})
+	g.Go(func() error {
+		var err error
+		pulls, err = hubClient.GetRateLimits(ctx)
+		return err
+	})
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	return opts.Print(streams.Out(), newUsage(user, plan, consumption, pulls), printUsage)
+}
+
+func newUsage(account *hub.Account, plan *hub.Plan, consumption *hub.Consumption, pulls *hub.RateLimits) usage {
+	return usage{
+		Account:             account.Name,
+		Plan:                plan.Name,
+		Seats:               newQuota(consumption.Seats, plan.Limits.Seats),
+		PrivateRepositories: newQuota(consumption.PrivateRepositories, plan.Limits.PrivateRepos),
+		Storage:             consumption.Storage,
+		Pulls:               pulls,
+	}
+}
+
+func newQuota(used, limit int) quota {
+	if limit == unlimited {
+		return quota{Used: used}
+	}
+	return quota{Used: used, Limit: &limit}
+}
+
+func printUsage(out io.Writer, value interface{}) error {
+	usage := value.(usage)
+
+	fmt.Fprintf(out, ansi.Key("Account:")+"\t\t%s\n", usage.Account)
+	fmt.Fprintf(out, ansi.Key("Plan:")+"\t\t\t%s\n", ansi.Emphasise(usage.Plan))
+	fmt.Fprintf(out, ansi.Key("Seats:")+"\t\t\t%s\n", printQuota(usage.Seats))
+	fmt.Fprintf(out, ansi.Key("Private repositories:")+"\t%s\n", printQuota(usage.PrivateRepositories))
+	fmt.Fprintf(out, ansi.Key("Storage:")+"\t\t%s\n", units.HumanSize(float64(usage.Storage)))
+	if usage.Pulls != nil {
+		fmt.Fprintf(out, ansi.Key("Pulls:")+"\t\t\t%d/%d, %s window\n", *usage.Pulls.Limit-*usage.Pulls.Remaining, *usage.Pulls.Limit,
+			units.HumanDuration(time.Duration(*usage.Pulls.LimitWindow)*time.Second))
+	}
+	return nil
+}
+
+func printQuota(q quota) string {
+	if q.Limit == nil {
+		return fmt.Sprintf("%d/%s", q.Used, ansi.Emphasise("unlimited"))
+	}
+	value := fmt.Sprintf("%d/%d", q.Used, *q.Limit)
+	if q.Used >= *q.Limit {
+		return ansi.Warn(value)
+	}
+	return value
+}
@@ -0,0 +1,57 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package account
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+func TestUsageOutput(t *testing.T) {
+	limit, remaining, window := 200, 150, 21600
+	usage := newUsage(
+		&hub.Account{Name: "my-user-name"},
+		&hub.Plan{
+			Name: "pro",
+			Limits: hub.Limits{
+				Seats:        1,
+				PrivateRepos: 9999,
+			},
+		},
+		&hub.Consumption{
+			Seats:               1,
+			PrivateRepositories: 12,
+			Storage:             3 * 1000 * 1000 * 1000,
+		},
+		&hub.RateLimits{
+			Limit:           &limit,
+			LimitWindow:     &window,
+			Remaining:       &remaining,
+			RemainingWindow: &window,
+		},
+	)
+	assert.Assert(t, usage.PrivateRepositories.Limit == nil)
+	buf := bytes.NewBuffer(nil)
+	err := printUsage(buf, usage)
+	assert.NilError(t, err)
+	golden.Assert(t, buf.String(), "usage.golden")
+}
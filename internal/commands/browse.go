@@ -0,0 +1,72 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package commands
+
+import (
+	"errors"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/browse"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	browseName = "browse"
+)
+
+func newBrowseCmd(streams command.Streams, hubClient *hub.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   browseName + " [ORGANIZATION]",
+		Short:                 "Browse the repositories and tags of your account or of an organization",
+		Long:                  "Browse the repositories and tags of your account or of an organization in an interactive terminal UI, moving with the arrow keys, opening with enter, deleting with d and quitting with q.",
+		Args:                  cli.RequiresMaxArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send("root", browseName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !streams.In().IsTerminal() || !streams.Out().IsTerminal() {
+				return errors.New("browse needs an interactive terminal")
+			}
+			account := hubClient.DefaultNamespace()
+			if len(args) > 0 {
+				account = args[0]
+			}
+			// Refreshing a view should show the current state of Hub
+			if err := hubClient.Update(hub.WithAllElements(), hub.WithCache("", 0)); err != nil {
+				return err
+			}
+			b := browse.New(cmd.Context(), hubClient, account)
+			if err := b.Load(); err != nil {
+				return err
+			}
+			if err := streams.In().SetRawTerminal(); err != nil {
+				return err
+			}
+			defer streams.In().RestoreTerminal()
+			return browse.Run(b, streams.In(), streams.Out(), func() int {
+				height, _ := streams.Out().GetTtySize()
+				return int(height)
+			})
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,95 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	cacheName      = "cache"
+	cacheClearName = "clear"
+	// cacheTTLEnvVar enables the response cache for every invocation, scripts
+	// can set it instead of passing --cache-ttl each time
+	cacheTTLEnvVar = "HUB_TOOL_CACHE_TTL"
+)
+
+func newCacheCmd(streams command.Streams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   cacheName,
+		Short:                 "Manage the local cache of Hub responses",
+		Args:                  cli.NoArgs,
+		DisableFlagsInUseLine: true,
+		RunE:                  command.ShowHelp(streams.Err()),
+	}
+	cmd.AddCommand(newCacheClearCmd(streams))
+	return cmd
+}
+
+func newCacheClearCmd(streams command.Streams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   cacheClearName,
+		Short:                 "Remove all the cached Hub responses",
+		Args:                  cli.NoArgs,
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(cacheName, cacheClearName)
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			dir, err := cacheDir()
+			if err != nil {
+				return err
+			}
+			if err := hub.ClearCache(dir); err != nil {
+				return err
+			}
+			fmt.Fprintln(streams.Out(), ansi.Info("Cache cleared"))
+			return nil
+		},
+	}
+	return cmd
+}
+
+// cacheDir returns the directory where the Hub responses are cached
+func cacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".hub-tool", cacheName), nil
+}
+
+// defaultCacheTTL reads the cache TTL from the environment, the cache being
+// disabled when it isn't set
+func defaultCacheTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv(cacheTTLEnvVar))
+	if err != nil {
+		return 0
+	}
+	return ttl
+}
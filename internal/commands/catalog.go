@@ -0,0 +1,124 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package commands
+
+import (
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	officialName  = "official"
+	publisherName = "publisher"
+	catalogLsName = "ls"
+)
+
+type catalogOptions struct {
+	format.Option
+	categories []string
+	limit      int
+}
+
+func (o *catalogOptions) addFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&o.categories, "category", nil, "Only list the images of this category (e.g.: database)")
+	cmd.Flags().IntVar(&o.limit, "limit", 100, "Maximum number of images listed")
+	o.AddFormatFlag(cmd.Flags())
+}
+
+func (o catalogOptions) filters() []hub.SearchFilter {
+	var filters []hub.SearchFilter
+	for _, category := range o.categories {
+		filters = append(filters, hub.WithCategory(category))
+	}
+	return filters
+}
+
+func newOfficialCmd(streams command.Streams, hubClient *hub.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   officialName,
+		Short:                 "Browse the Docker official images",
+		Args:                  cli.NoArgs,
+		DisableFlagsInUseLine: true,
+		RunE:                  command.ShowHelp(streams.Err()),
+	}
+	var opts catalogOptions
+	lsCmd := &cobra.Command{
+		Use:                   catalogLsName + " [OPTIONS]",
+		Aliases:               []string{"list"},
+		Short:                 "List the Docker official images, from the library namespace",
+		Example:               "  hub-tool official ls --category database",
+		Args:                  cli.NoArgs,
+		DisableFlagsInUseLine: true,
+		Annotations: map[string]string{
+			"anonymous": "true",
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(officialName, catalogLsName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filters := append(opts.filters(), hub.WithOfficialImages())
+			results, total, err := hubClient.Search(cmd.Context(), "", opts.limit, filters...)
+			if err != nil {
+				return err
+			}
+			return opts.Print(streams.Out(), searchResultList(results), printSearchResults(total))
+		},
+	}
+	opts.addFlags(lsCmd)
+	cmd.AddCommand(lsCmd)
+	return cmd
+}
+
+func newPublisherCmd(streams command.Streams, hubClient *hub.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   publisherName,
+		Short:                 "Browse the images of the verified publishers",
+		Args:                  cli.NoArgs,
+		DisableFlagsInUseLine: true,
+		RunE:                  command.ShowHelp(streams.Err()),
+	}
+	var opts catalogOptions
+	lsCmd := &cobra.Command{
+		Use:                   catalogLsName + " [OPTIONS] ORGANIZATION",
+		Aliases:               []string{"list"},
+		Short:                 "List the images of a verified publisher",
+		Example:               "  hub-tool publisher ls bitnami --category database",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		Annotations: map[string]string{
+			"anonymous": "true",
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(publisherName, catalogLsName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filters := append(opts.filters(), hub.WithVerifiedPublishers(), hub.WithNamespace(args[0]))
+			results, total, err := hubClient.Search(cmd.Context(), args[0], opts.limit, filters...)
+			if err != nil {
+				return err
+			}
+			return opts.Print(streams.Out(), searchResultList(results), printSearchResults(total))
+		},
+	}
+	opts.addFlags(lsCmd)
+	cmd.AddCommand(lsCmd)
+	return cmd
+}
@@ -0,0 +1,174 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/config"
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+)
+
+const (
+	configName     = "config"
+	configSetName  = "set"
+	configGetName  = "get"
+	configViewName = "view"
+)
+
+func newConfigCmd(streams command.Streams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   configName,
+		Short:                 "Manage the defaults of the configuration file",
+		Long:                  "Manage the defaults of the configuration file ~/.hub-tool/config.yaml. The flags given on the command line override them.",
+		Args:                  cli.NoArgs,
+		DisableFlagsInUseLine: true,
+		Annotations:           map[string]string{"anonymous": "true"},
+		RunE:                  command.ShowHelp(streams.Err()),
+	}
+	cmd.AddCommand(
+		newConfigSetCmd(streams),
+		newConfigGetCmd(streams),
+		newConfigViewCmd(streams),
+	)
+	return cmd
+}
+
+func newConfigSetCmd(streams command.Streams) *cobra.Command {
+	var unset bool
+	cmd := &cobra.Command{
+		Use:                   configSetName + " [OPTIONS] KEY [VALUE]",
+		Short:                 "Set a default in the configuration file",
+		Long:                  "Set a default in the configuration file, or remove it with --unset. The supported keys are listed by \"hub-tool config view\".",
+		Example:               "  hub-tool config set namespace myorg\n  hub-tool config set format json",
+		Args:                  cli.RequiresRangeArgs(1, 2),
+		DisableFlagsInUseLine: true,
+		Annotations:           map[string]string{"anonymous": "true"},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(configName, configSetName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if unset != (len(args) == 1) {
+				return fmt.Errorf("%q requires a KEY and a VALUE, or a KEY with --unset", cmd.CommandPath())
+			}
+			path, cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			if unset {
+				if !config.IsKey(args[0]) {
+					return fmt.Errorf("unknown configuration key %q", args[0])
+				}
+				delete(cfg, args[0])
+			} else {
+				if err := config.Validate(args[0], args[1]); err != nil {
+					return err
+				}
+				cfg[args[0]] = args[1]
+			}
+			return cfg.Save(path)
+		},
+	}
+	cmd.Flags().BoolVar(&unset, "unset", false, "Remove the key from the configuration file")
+	return cmd
+}
+
+func newConfigGetCmd(streams command.Streams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   configGetName + " KEY",
+		Short:                 "Print a default of the configuration file",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		Annotations:           map[string]string{"anonymous": "true"},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(configName, configGetName)
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			_, cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			if !config.IsKey(args[0]) {
+				return fmt.Errorf("unknown configuration key %q", args[0])
+			}
+			value, ok := cfg[args[0]]
+			if !ok {
+				return fmt.Errorf("%q is not set", args[0])
+			}
+			fmt.Fprintln(streams.Out(), value)
+			return nil
+		},
+	}
+	return cmd
+}
+
+type configView struct {
+	Path   string
+	Values config.Config
+}
+
+func newConfigViewCmd(streams command.Streams) *cobra.Command {
+	var opts format.Option
+	cmd := &cobra.Command{
+		Use:                   configViewName + " [OPTIONS]",
+		Short:                 "Print the configuration file and the supported keys",
+		Args:                  cli.NoArgs,
+		DisableFlagsInUseLine: true,
+		Annotations:           map[string]string{"anonymous": "true"},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(configName, configViewName)
+		},
+		RunE: func(_ *cobra.Command, args []string) error {
+			path, cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			return opts.Print(streams.Out(), configView{Path: path, Values: cfg}, printConfigView)
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	return cmd
+}
+
+func printConfigView(out io.Writer, value interface{}) error {
+	view := value.(configView)
+	fmt.Fprintln(out, ansi.Key("File:"), view.Path)
+	fmt.Fprintln(out)
+	headers := []string{"KEY", "VALUE", "DESCRIPTION"}
+	var rows [][]interface{}
+	for _, key := range config.Keys {
+		rows = append(rows, []interface{}{key.Name, view.Values[key.Name], key.Description})
+	}
+	return format.PrintTable(out, headers, rows)
+}
+
+// loadConfig returns the path and the content of the configuration file
+func loadConfig() (string, config.Config, error) {
+	path, err := config.Path()
+	if err != nil {
+		return "", nil, err
+	}
+	cfg, err := config.Load(path)
+	return path, cfg, err
+}
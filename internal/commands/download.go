@@ -0,0 +1,142 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/go-units"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/internal/registry"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	downloadName = "download"
+	// downloadDirSuffix is appended to a tarball to get the layout directory
+	// it is built from, kept when the download fails to resume it
+	downloadDirSuffix = ".download"
+)
+
+type downloadOptions struct {
+	output      string
+	platform    string
+	parallelism int
+}
+
+func newDownloadCmd(streams command.Streams, hubClient *hub.Client) *cobra.Command {
+	var opts downloadOptions
+	cmd := &cobra.Command{
+		Use:   downloadName + " [OPTIONS] REPOSITORY[:TAG]",
+		Short: "Download an image to an OCI image layout or tarball, without the Docker daemon",
+		Long: `Download an image to an OCI image layout or tarball, without the Docker daemon.
+
+The image is written as a tarball when the output ends with .tar, which can be
+loaded with docker load from Docker 25, and as an OCI image layout directory
+otherwise. Running the same download again after a failure resumes it.`,
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		Annotations: map[string]string{
+			"public": "true",
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send("root", downloadName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDownload(cmd.Context(), streams, hubClient, opts, args[0])
+		},
+	}
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "Tarball (.tar) or OCI image layout directory to write the image to")
+	cmd.Flags().StringVar(&opts.platform, "platform", "", "Only download the image of this platform of a multi-platform image, given as os/arch[/variant]")
+	cmd.Flags().IntVar(&opts.parallelism, "parallel", 4, "Maximum number of layers downloaded at once")
+	_ = cmd.MarkFlagRequired("output")
+	return cmd
+}
+
+func runDownload(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts downloadOptions, ref string) error {
+	downloadOpts := registry.DownloadOptions{
+		Parallelism: opts.parallelism,
+		Progress: func(descriptor ocispec.Descriptor, skipped bool) {
+			status := "Downloaded"
+			if skipped {
+				status = "Already downloaded"
+			}
+			fmt.Fprintf(streams.Err(), "%s: %s (%s)\n", shortDigest(descriptor), status, units.HumanSize(float64(descriptor.Size)))
+		},
+	}
+	if opts.platform != "" {
+		platform, err := platforms.Parse(opts.platform)
+		if err != nil {
+			return fmt.Errorf("invalid platform %q: %s", opts.platform, err)
+		}
+		downloadOpts.Platform = &platform
+	}
+
+	tarball := strings.HasSuffix(opts.output, ".tar")
+	dir := opts.output
+	if tarball {
+		dir = opts.output + downloadDirSuffix
+	}
+	resolver := registry.NewResolver(hubClient)
+	image, err := registry.Download(ctx, resolver, ref, dir, downloadOpts)
+	if err != nil {
+		return err
+	}
+	if tarball {
+		if err := writeTarball(dir, opts.output); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(streams.Out(), "Downloaded %s (%s) to %s\n", ref, image.Digest, opts.output)
+	return nil
+}
+
+// writeTarball archives the layout directory to the tarball, removing the
+// directory once done
+func writeTarball(dir, output string) error {
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := registry.TarLayout(dir, f); err != nil {
+		_ = f.Close()
+		_ = os.Remove(output)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// shortDigest returns the first 12 characters of the digest of a blob, as
+// docker pull prints them
+func shortDigest(descriptor ocispec.Descriptor) string {
+	encoded := descriptor.Digest.Encoded()
+	if len(encoded) > 12 {
+		return encoded[:12]
+	}
+	return encoded
+}
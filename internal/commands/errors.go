@@ -0,0 +1,96 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+// Exit codes of the tool, telling scripts why a command failed
+const (
+	// ExitError is the exit code of the failures without a specific code
+	ExitError = 1
+	// ExitUnauthorized is the exit code when not logged in or when the
+	// credentials are rejected, e.g. an expired token
+	ExitUnauthorized = 3
+	// ExitForbidden is the exit code when the user isn't allowed to do the
+	// operation
+	ExitForbidden = 4
+	// ExitNotFound is the exit code when a resource, such as a repository or
+	// a tag, doesn't exist
+	ExitNotFound = 5
+	// ExitRateLimited is the exit code when Hub refused the requests because
+	// of the rate limiting
+	ExitRateLimited = 6
+)
+
+// errNotLoggedIn is returned by the commands requiring an account when nobody
+// is logged in
+var errNotLoggedIn = fmt.Errorf(`you need to be logged in to Docker Hub to use this tool, please login using the "hub-tool login" command: %w`, hub.ErrUnauthorized)
+
+var errorKinds = []struct {
+	err      error
+	code     string
+	exitCode int
+}{
+	{hub.ErrUnauthorized, "unauthorized", ExitUnauthorized},
+	{hub.ErrForbidden, "forbidden", ExitForbidden},
+	{hub.ErrNotFound, "not_found", ExitNotFound},
+	{hub.ErrRateLimited, "rate_limited", ExitRateLimited},
+}
+
+// cliError is the JSON object printed for an error when the output is JSON
+type cliError struct {
+	Error    string `json:"error"`
+	Code     string `json:"code"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// ExitCode returns the exit code telling why the command failed with the error
+func ExitCode(err error) int {
+	return newCLIError(err).ExitCode
+}
+
+// PrintError prints the error the command failed with, as a JSON object when
+// the command prints JSON
+func PrintError(out io.Writer, cmd *cobra.Command, err error) {
+	if cmd != nil {
+		if flag := cmd.Flags().Lookup("format"); flag != nil && flag.Value.String() == "json" {
+			if data, jsonErr := json.Marshal(newCLIError(err)); jsonErr == nil {
+				fmt.Fprintln(out, string(data))
+				return
+			}
+		}
+	}
+	fmt.Fprintln(out, "Error:", err)
+}
+
+func newCLIError(err error) cliError {
+	for _, kind := range errorKinds {
+		if errors.Is(err, kind.err) {
+			return cliError{Error: err.Error(), Code: kind.code, ExitCode: kind.exitCode}
+		}
+	}
+	return cliError{Error: err.Error(), Code: "error", ExitCode: ExitError}
+}
@@ -0,0 +1,55 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, ExitCode(errors.New("failure")), ExitError)
+	assert.Equal(t, ExitCode(fmt.Errorf("token: %w", hub.ErrUnauthorized)), ExitUnauthorized)
+	assert.Equal(t, ExitCode(fmt.Errorf("repository: %w", hub.ErrNotFound)), ExitNotFound)
+	assert.Equal(t, ExitCode(hub.ErrForbidden), ExitForbidden)
+	assert.Equal(t, ExitCode(hub.ErrRateLimited), ExitRateLimited)
+	assert.Equal(t, ExitCode(errNotLoggedIn), ExitUnauthorized)
+}
+
+func TestPrintError(t *testing.T) {
+	var opts format.Option
+	cmd := &cobra.Command{}
+	opts.AddFormatFlag(cmd.Flags())
+	err := fmt.Errorf("repository jdoe/app: %w", hub.ErrNotFound)
+
+	out := bytes.NewBuffer(nil)
+	PrintError(out, cmd, err)
+	assert.Equal(t, out.String(), "Error: repository jdoe/app: not found\n")
+
+	assert.NilError(t, cmd.Flags().Set("format", "json"))
+	out.Reset()
+	PrintError(out, cmd, err)
+	assert.Equal(t, out.String(), `{"error":"repository jdoe/app: not found","code":"not_found","exit_code":5}`+"\n")
+}
@@ -18,6 +18,7 @@ package commands
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/pkg/errors"
 
@@ -28,18 +29,26 @@ import (
 	"github.com/docker/hub-tool/internal/ansi"
 	"github.com/docker/hub-tool/internal/credentials"
 	"github.com/docker/hub-tool/internal/errdef"
-	"github.com/docker/hub-tool/internal/hub"
 	"github.com/docker/hub-tool/internal/login"
 	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
 	loginName = "login"
+	// hubTokenEnvVar holds a personal access token used as the login password
+	hubTokenEnvVar = "HUB_TOKEN"
 )
 
+type loginOptions struct {
+	username      string
+	passwordStdin bool
+}
+
 func newLoginCmd(streams command.Streams, store credentials.Store, hubClient *hub.Client) *cobra.Command {
+	var opts loginOptions
 	cmd := &cobra.Command{
-		Use:                   loginName + " [USERNAME]",
+		Use:                   loginName + " [OPTIONS] [USERNAME]",
 		Short:                 "Login to the Hub",
 		Args:                  cli.RequiresMaxArgs(1),
 		DisableFlagsInUseLine: true,
@@ -47,11 +56,21 @@ func newLoginCmd(streams command.Streams, store credentials.Store, hubClient *hu
 			metrics.Send("root", loginName)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			username := ""
+			username := opts.username
 			if len(args) > 0 {
 				username = args[0]
 			}
-			err := login.RunLogin(cmd.Context(), streams, hubClient, store, username)
+			password := os.Getenv(hubTokenEnvVar)
+			if (opts.passwordStdin || password != "") && username == "" {
+				return fmt.Errorf("a username is required to login with --password-stdin or %s", hubTokenEnvVar)
+			}
+			if opts.passwordStdin {
+				var err error
+				if password, err = login.ReadPasswordFromStdin(streams.In()); err != nil {
+					return err
+				}
+			}
+			err := login.RunLogin(cmd.Context(), streams, hubClient, store, username, password)
 			if err != nil {
 				if errors.Is(err, errdef.ErrCanceled) {
 					return nil
@@ -63,5 +82,7 @@ func newLoginCmd(streams command.Streams, store credentials.Store, hubClient *hu
 			return nil
 		},
 	}
+	cmd.Flags().StringVarP(&opts.username, "username", "u", "", "Username")
+	cmd.Flags().BoolVar(&opts.passwordStdin, "password-stdin", false, "Take the password or personal access token from stdin")
 	return cmd
 }
@@ -21,6 +21,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/hub-tool/internal/ansi"
 	"github.com/docker/hub-tool/internal/credentials"
@@ -33,13 +34,17 @@ const (
 
 func newLogoutCmd(streams command.Streams, store credentials.Store) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:                   logoutName + " USERNAME",
-		Short:                 "Logout of the Hub",
+		Use:                   logoutName + " [USERNAME]",
+		Short:                 "Logout of the Hub, from the current account or from the given one",
+		Args:                  cli.RequiresMaxArgs(1),
 		DisableFlagsInUseLine: true,
 		PreRun: func(cmd *cobra.Command, args []string) {
 			metrics.Send("root", logoutName)
 		},
 		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				store.SetProfile(args[0])
+			}
 			if err := store.Erase(); err != nil {
 				return err
 			}
@@ -0,0 +1,125 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package org
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/age"
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/format/tabwriter"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	activityName = "activity"
+)
+
+var (
+	activityColumns = []activityColumn{
+		{"DATE", func(l hub.AuditLog) (string, int) {
+			s := l.Timestamp.Local().Format(time.RFC3339)
+			return s, len(s)
+		}},
+		{"ACTOR", func(l hub.AuditLog) (string, int) { return l.Actor, len(l.Actor) }},
+		{"ACTION", func(l hub.AuditLog) (string, int) { return l.Action, len(l.Action) }},
+		{"NAME", func(l hub.AuditLog) (string, int) { return l.Name, len(l.Name) }},
+		{"DESCRIPTION", func(l hub.AuditLog) (string, int) { return l.Description, len(l.Description) }},
+	}
+)
+
+type activityColumn struct {
+	header string
+	value  func(l hub.AuditLog) (string, int)
+}
+
+type activityOptions struct {
+	format.Option
+	since string
+}
+
+func newActivityCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts activityOptions
+	cmd := &cobra.Command{
+		Use:                   activityName + " [OPTIONS] ORGANIZATION",
+		Short:                 "Print the audit log of an organization",
+		Long:                  "Print the audit log of an organization: who pushed, deleted or changed the settings of its repositories and teams.",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, activityName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runActivity(cmd.Context(), streams, hubClient, opts, args[0])
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	cmd.Flags().StringVar(&opts.since, "since", "7d", "Only print the events of this age, e.g. 7d, 4w or 36h")
+	return cmd
+}
+
+func runActivity(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts activityOptions, organization string) error {
+	since, err := age.Parse(opts.since)
+	if err != nil {
+		return err
+	}
+	logs, err := hubClient.GetAuditLogs(ctx, organization, time.Now().Add(-since))
+	if err != nil {
+		return err
+	}
+	return opts.Print(streams.Out(), auditLogList(logs), printActivity)
+}
+
+func printActivity(out io.Writer, values interface{}) error {
+	logs := values.(auditLogList)
+	tw := tabwriter.New(out, "    ")
+	for _, column := range activityColumns {
+		tw.Column(ansi.Header(column.header), len(column.header))
+	}
+
+	tw.Line()
+
+	for _, log := range logs {
+		for _, column := range activityColumns {
+			value, width := column.value(log)
+			tw.Column(value, width)
+		}
+		tw.Line()
+	}
+
+	return tw.Flush()
+}
+
+// auditLogList prints a row per event in csv and tsv
+type auditLogList []hub.AuditLog
+
+// Table returns the raw values of the logs
+func (l auditLogList) Table() ([]string, [][]interface{}) {
+	rows := make([][]interface{}, len(l))
+	for i, l := range l {
+		rows[i] = []interface{}{l.Timestamp, l.Actor, l.Action, l.Name, l.Description}
+	}
+	return []string{"DATE", "ACTOR", "ACTION", "NAME", "DESCRIPTION"}, rows
+}
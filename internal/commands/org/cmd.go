@@ -21,7 +21,7 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/spf13/cobra"
 
-	"github.com/docker/hub-tool/internal/hub"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
@@ -38,8 +38,14 @@ func NewOrgCmd(streams command.Streams, hubClient *hub.Client) *cobra.Command {
 		RunE:                  command.ShowHelp(streams.Err()),
 	}
 	cmd.AddCommand(
+		newActivityCmd(streams, hubClient, orgName),
+		newInviteCmd(streams, hubClient, orgName),
 		newListCmd(streams, hubClient, orgName),
+		newMemberCmd(streams, hubClient, orgName),
 		newMembersCmd(streams, hubClient, orgName),
+		newPlanCmd(streams, hubClient, orgName),
+		newSettingsCmd(streams, hubClient, orgName),
+		newTeamCmd(streams, hubClient, orgName),
 		newTeamsCmd(streams, hubClient, orgName),
 	)
 	return cmd
@@ -0,0 +1,232 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package org
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/format/tabwriter"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	inviteName       = "invite"
+	inviteLsName     = "ls"
+	inviteSendName   = "send"
+	inviteCancelName = "cancel"
+	inviteResendName = "resend"
+)
+
+var (
+	invitationColumns = []invitationColumn{
+		{"INVITEE", func(i hub.Invitation) (string, int) { return i.Invitee, len(i.Invitee) }},
+		{"ROLE", func(i hub.Invitation) (string, int) { return i.Role, len(i.Role) }},
+		{"TEAM", func(i hub.Invitation) (string, int) { return i.Team, len(i.Team) }},
+		{"INVITED BY", func(i hub.Invitation) (string, int) { return i.Inviter, len(i.Inviter) }},
+		{"SENT", func(i hub.Invitation) (string, int) {
+			s := fmt.Sprintf("%s ago", units.HumanDuration(time.Since(i.CreatedAt)))
+			return s, len(s)
+		}},
+	}
+)
+
+type invitationColumn struct {
+	header string
+	value  func(i hub.Invitation) (string, int)
+}
+
+func newInviteCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmdName := parent + " " + inviteName
+	cmd := &cobra.Command{
+		Use:                   inviteName,
+		Short:                 "Manage the invitations to join an organization",
+		Args:                  cli.NoArgs,
+		DisableFlagsInUseLine: true,
+		RunE:                  command.ShowHelp(streams.Err()),
+	}
+	cmd.AddCommand(
+		newInviteLsCmd(streams, hubClient, cmdName),
+		newInviteSendCmd(streams, hubClient, cmdName),
+		newInviteCancelCmd(streams, hubClient, cmdName),
+		newInviteResendCmd(streams, hubClient, cmdName),
+	)
+	return cmd
+}
+
+func newInviteLsCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts format.Option
+	cmd := &cobra.Command{
+		Use:                   inviteLsName + " [OPTIONS] ORGANIZATION",
+		Aliases:               []string{"list"},
+		Short:                 "List the pending invitations of an organization",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, inviteLsName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			invitations, err := hubClient.GetInvitations(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return opts.Print(streams.Out(), invitationList(invitations), printInvitations)
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	return cmd
+}
+
+func newInviteSendCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var (
+		opts format.Option
+		role string
+		team string
+	)
+	cmd := &cobra.Command{
+		Use:                   inviteSendName + " [OPTIONS] ORGANIZATION USERNAME|EMAIL",
+		Short:                 "Invite a user to join an organization",
+		Example:               "  hub-tool org invite send myorg jdoe@example.com --team developers",
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, inviteSendName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateRole(role); err != nil {
+				return err
+			}
+			invitation, err := hubClient.InviteMember(cmd.Context(), args[0], team, role, args[1])
+			if err != nil {
+				return err
+			}
+			return opts.Print(streams.Out(), invitation, func(out io.Writer, value interface{}) error {
+				fmt.Fprintf(out, "Invited %s to join %s as %s\n", args[1], args[0], role)
+				return nil
+			})
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	cmd.Flags().StringVar(&role, "role", defaultRoleName, `Role of the user in the organization, "owner", "editor" or "member"`)
+	cmd.Flags().StringVar(&team, "team", "", "Team to add the user to")
+	return cmd
+}
+
+func newInviteCancelCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   inviteCancelName + " ORGANIZATION USERNAME|EMAIL",
+		Short:                 "Cancel the pending invitation of a user",
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, inviteCancelName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			invitation, err := findInvitation(cmd.Context(), hubClient, args[0], args[1])
+			if err != nil {
+				return err
+			}
+			if err := hubClient.CancelInvitation(cmd.Context(), invitation.ID); err != nil {
+				return err
+			}
+			fmt.Fprintf(streams.Out(), "Cancelled the invitation of %s to join %s\n", args[1], args[0])
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newInviteResendCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   inviteResendName + " ORGANIZATION USERNAME|EMAIL",
+		Short:                 "Send the pending invitation of a user again",
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, inviteResendName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			invitation, err := findInvitation(cmd.Context(), hubClient, args[0], args[1])
+			if err != nil {
+				return err
+			}
+			if err := hubClient.ResendInvitation(cmd.Context(), invitation.ID); err != nil {
+				return err
+			}
+			fmt.Fprintf(streams.Out(), "Sent the invitation of %s to join %s again\n", args[1], args[0])
+			return nil
+		},
+	}
+	return cmd
+}
+
+// findInvitation returns the pending invitation of a user, the invitations
+// being managed by their ID
+func findInvitation(ctx context.Context, hubClient *hub.Client, organization, invitee string) (*hub.Invitation, error) {
+	invitations, err := hubClient.GetInvitations(ctx, organization)
+	if err != nil {
+		return nil, err
+	}
+	for _, invitation := range invitations {
+		if invitation.Invitee == invitee {
+			return &invitation, nil
+		}
+	}
+	return nil, fmt.Errorf("no pending invitation of %s to join %s", invitee, organization)
+}
+
+func printInvitations(out io.Writer, values interface{}) error {
+	invitations := values.(invitationList)
+	tw := tabwriter.New(out, "    ")
+	for _, column := range invitationColumns {
+		tw.Column(ansi.Header(column.header), len(column.header))
+	}
+
+	tw.Line()
+
+	for _, invitation := range invitations {
+		for _, column := range invitationColumns {
+			value, width := column.value(invitation)
+			tw.Column(value, width)
+		}
+		tw.Line()
+	}
+
+	return tw.Flush()
+}
+
+// invitationList prints a row per invitation in csv and tsv
+type invitationList []hub.Invitation
+
+// Table returns the raw values of the invitations
+func (l invitationList) Table() ([]string, [][]interface{}) {
+	rows := make([][]interface{}, len(l))
+	for i, invitation := range l {
+		rows[i] = []interface{}{invitation.Invitee, invitation.Role, invitation.Team, invitation.Inviter, invitation.CreatedAt}
+	}
+	return []string{"INVITEE", "ROLE", "TEAM", "INVITED BY", "SENT"}, rows
+}
@@ -28,8 +28,8 @@ import (
 	"github.com/docker/hub-tool/internal/ansi"
 	"github.com/docker/hub-tool/internal/format"
 	"github.com/docker/hub-tool/internal/format/tabwriter"
-	"github.com/docker/hub-tool/internal/hub"
 	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
@@ -87,11 +87,11 @@ func runList(ctx context.Context, streams command.Streams, hubClient *hub.Client
 	if err != nil {
 		return err
 	}
-	return opts.Print(streams.Out(), organizations, printOrganizations)
+	return opts.Print(streams.Out(), organizationList(organizations), printOrganizations)
 }
 
 func printOrganizations(out io.Writer, values interface{}) error {
-	organizations := values.([]hub.Organization)
+	organizations := values.(organizationList)
 
 	tw := tabwriter.New(out, "    ")
 
@@ -111,3 +111,15 @@ func printOrganizations(out io.Writer, values interface{}) error {
 
 	return tw.Flush()
 }
+
+// organizationList prints a row per organization in csv and tsv
+type organizationList []hub.Organization
+
+// Table returns the raw values of the organizations
+func (l organizationList) Table() ([]string, [][]interface{}) {
+	rows := make([][]interface{}, len(l))
+	for i, o := range l {
+		rows[i] = []interface{}{o.Namespace, o.FullName, o.Role, len(o.Teams), len(o.Members)}
+	}
+	return []string{"NAMESPACE", "NAME", "MY ROLE", "TEAMS", "MEMBERS"}, rows
+}
@@ -0,0 +1,135 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package org
+
+import (
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	memberName      = "member"
+	memberLsName    = "ls"
+	memberAddName   = "add"
+	memberRmName    = "rm"
+	defaultRoleName = "member"
+)
+
+var (
+	roles = []string{"owner", "editor", "member"}
+)
+
+func newMemberCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmdName := parent + " " + memberName
+	cmd := &cobra.Command{
+		Use:                   memberName,
+		Short:                 "Manage the members of an organization",
+		Args:                  cli.NoArgs,
+		DisableFlagsInUseLine: true,
+		RunE:                  command.ShowHelp(streams.Err()),
+	}
+	cmd.AddCommand(
+		newMemberLsCmd(streams, hubClient, cmdName),
+		newMemberAddCmd(streams, hubClient, cmdName),
+		newMemberRmCmd(streams, hubClient, cmdName),
+	)
+	return cmd
+}
+
+func newMemberLsCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts memberOptions
+	cmd := &cobra.Command{
+		Use:                   memberLsName + " [OPTIONS] ORGANIZATION",
+		Aliases:               []string{"list"},
+		Short:                 "List the members of an organization",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, memberLsName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMembers(cmd.Context(), streams, hubClient, opts, args[0])
+		},
+	}
+	opts.addFlags(cmd)
+	return cmd
+}
+
+func newMemberAddCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var (
+		role string
+		team string
+	)
+	cmd := &cobra.Command{
+		Use:                   memberAddName + " [OPTIONS] ORGANIZATION USERNAME|EMAIL",
+		Short:                 "Invite a user to join an organization",
+		Long:                  "Invite a user to join an organization. The user becomes a member after accepting the invitation.",
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, memberAddName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateRole(role); err != nil {
+				return err
+			}
+			if _, err := hubClient.InviteMember(cmd.Context(), args[0], team, role, args[1]); err != nil {
+				return err
+			}
+			fmt.Fprintf(streams.Out(), "Invited %s to join %s as %s\n", args[1], args[0], role)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&role, "role", defaultRoleName, `Role of the user in the organization, "owner", "editor" or "member"`)
+	cmd.Flags().StringVar(&team, "team", "", "Team to add the user to")
+	return cmd
+}
+
+func newMemberRmCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   memberRmName + " ORGANIZATION USERNAME",
+		Short:                 "Remove a member from an organization and all its teams",
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, memberRmName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := hubClient.RemoveMember(cmd.Context(), args[0], args[1]); err != nil {
+				return err
+			}
+			fmt.Fprintf(streams.Out(), "Removed %s from %s\n", args[1], args[0])
+			return nil
+		},
+	}
+	return cmd
+}
+
+func validateRole(role string) error {
+	for _, r := range roles {
+		if role == r {
+			return nil
+		}
+	}
+	return fmt.Errorf(`invalid role %q: should be either "owner", "editor" or "member"`, role)
+}
@@ -17,6 +17,7 @@
 package org
 
 import (
+	"context"
 	"io"
 
 	"github.com/docker/cli/cli"
@@ -26,8 +27,8 @@ import (
 	"github.com/docker/hub-tool/internal/ansi"
 	"github.com/docker/hub-tool/internal/format"
 	"github.com/docker/hub-tool/internal/format/tabwriter"
-	"github.com/docker/hub-tool/internal/hub"
 	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
@@ -38,6 +39,13 @@ var (
 	memberColumns = []memberColumn{
 		{"USERNAME", func(m hub.Member) (string, int) { return m.Username, len(m.Username) }},
 		{"FULL NAME", func(m hub.Member) (string, int) { return m.FullName, len(m.FullName) }},
+		{"ROLE", func(m hub.Member) (string, int) { return m.Role, len(m.Role) }},
+		{"STATUS", func(m hub.Member) (string, int) {
+			if m.Pending {
+				return ansi.Emphasise("invited"), len("invited")
+			}
+			return "active", len("active")
+		}},
 	}
 )
 
@@ -48,6 +56,14 @@ type memberColumn struct {
 
 type memberOptions struct {
 	format.Option
+	role    string
+	pending bool
+}
+
+func (o *memberOptions) addFlags(cmd *cobra.Command) {
+	o.AddFormatFlag(cmd.Flags())
+	cmd.Flags().StringVar(&o.role, "role", "", `Only list the members with this role, "owner", "editor" or "member"`)
+	cmd.Flags().BoolVar(&o.pending, "pending", false, "Also list the users who haven't accepted their invitation yet")
 }
 
 func newMembersCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
@@ -56,28 +72,39 @@ func newMembersCmd(streams command.Streams, hubClient *hub.Client, parent string
 		Use:                   membersName + " ORGANIZATION",
 		Short:                 "List all the members in an organization",
 		Args:                  cli.ExactArgs(1),
+		Deprecated:            `use "org member ls" instead`,
 		DisableFlagsInUseLine: true,
 		PreRun: func(cmd *cobra.Command, args []string) {
 			metrics.Send(parent, membersName)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runMembers(streams, hubClient, opts, args[0])
+			return runMembers(cmd.Context(), streams, hubClient, opts, args[0])
 		},
 	}
-	opts.AddFormatFlag(cmd.Flags())
+	opts.addFlags(cmd)
 	return cmd
 }
 
-func runMembers(streams command.Streams, hubClient *hub.Client, opts memberOptions, organization string) error {
-	members, err := hubClient.GetMembers(organization)
+func runMembers(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts memberOptions, organization string) error {
+	var reqOps []hub.RequestOp
+	if opts.role != "" {
+		if err := validateRole(opts.role); err != nil {
+			return err
+		}
+		reqOps = append(reqOps, hub.WithMemberRole(opts.role))
+	}
+	if opts.pending {
+		reqOps = append(reqOps, hub.WithPendingInvitations())
+	}
+	members, err := hubClient.GetMembers(ctx, organization, reqOps...)
 	if err != nil {
 		return err
 	}
-	return opts.Print(streams.Out(), members, printMembers)
+	return opts.Print(streams.Out(), memberList(members), printMembers)
 }
 
 func printMembers(out io.Writer, values interface{}) error {
-	members := values.([]hub.Member)
+	members := values.(memberList)
 	tw := tabwriter.New(out, "    ")
 	for _, column := range memberColumns {
 		tw.Column(ansi.Header(column.header), len(column.header))
@@ -95,3 +122,15 @@ func printMembers(out io.Writer, values interface{}) error {
 
 	return tw.Flush()
 }
+
+// memberList prints a row per member in csv and tsv
+type memberList []hub.Member
+
+// Table returns the raw values of the members
+func (l memberList) Table() ([]string, [][]interface{}) {
+	rows := make([][]interface{}, len(l))
+	for i, m := range l {
+		rows[i] = []interface{}{m.Username, m.FullName, m.Role, m.Pending}
+	}
+	return []string{"USERNAME", "FULL NAME", "ROLE", "PENDING"}, rows
+}
@@ -0,0 +1,78 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package org
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	planName = "plan"
+)
+
+func newPlanCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts format.Option
+	cmd := &cobra.Command{
+		Use:                   planName + " [OPTIONS] ORGANIZATION",
+		Short:                 "Print the plan, the seat usage and the renewal date of an organization",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, planName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			plan, err := hubClient.GetOrganizationPlan(cmd.Context(), args[0])
+			if hub.IsForbiddenError(err) {
+				return fmt.Errorf(ansi.Error("failed to get the plan of %s, you need to be the organization Owner"), args[0])
+			}
+			if err != nil {
+				return err
+			}
+			return opts.Print(streams.Out(), plan, printPlan)
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	return cmd
+}
+
+func printPlan(out io.Writer, value interface{}) error {
+	plan := value.(*hub.OrganizationPlan)
+	fmt.Fprintf(out, ansi.Key("Organization:")+"\t%s\n", plan.Organization)
+	fmt.Fprintf(out, ansi.Key("Plan:")+"\t\t%s\n", ansi.Emphasise(plan.Plan.Name))
+	fmt.Fprintf(out, ansi.Key("Seats:")+"\t\t%d/%d\n", plan.UsedSeats, plan.Subscription.Seats)
+	if plan.UsedSeats > plan.Subscription.Seats {
+		fmt.Fprintln(out, ansi.Warn(fmt.Sprintf("%d seats over the purchased ones", plan.UsedSeats-plan.Subscription.Seats)))
+	}
+	if plan.Subscription.Cycle != "" {
+		fmt.Fprintf(out, ansi.Key("Billing cycle:")+"\t%s\n", plan.Subscription.Cycle)
+	}
+	if !plan.Subscription.RenewalDate.IsZero() {
+		fmt.Fprintf(out, ansi.Key("Renewal date:")+"\t%s (in %s)\n", plan.Subscription.RenewalDate.Local().Format("2006-01-02"), units.HumanDuration(time.Until(plan.Subscription.RenewalDate)))
+	}
+	return nil
+}
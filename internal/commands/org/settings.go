@@ -0,0 +1,151 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package org
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	settingsName    = "settings"
+	settingsGetName = "get"
+	settingsSetName = "set"
+)
+
+func newSettingsCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmdName := parent + " " + settingsName
+	cmd := &cobra.Command{
+		Use:                   settingsName,
+		Short:                 "Manage the settings of an organization",
+		Args:                  cli.NoArgs,
+		DisableFlagsInUseLine: true,
+		RunE:                  command.ShowHelp(streams.Err()),
+	}
+	cmd.AddCommand(
+		newSettingsGetCmd(streams, hubClient, cmdName),
+		newSettingsSetCmd(streams, hubClient, cmdName),
+	)
+	return cmd
+}
+
+func newSettingsGetCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts format.Option
+	cmd := &cobra.Command{
+		Use:                   settingsGetName + " [OPTIONS] ORGANIZATION",
+		Short:                 "Print the default repository visibility and the image access management of an organization",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, settingsGetName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings, err := hubClient.GetOrganizationSettings(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return opts.Print(streams.Out(), settings, printSettings)
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	return cmd
+}
+
+type settingsSetOptions struct {
+	format.Option
+	defaultVisibility       string
+	imageAccessManagement   bool
+	allowOfficialImages     bool
+	allowVerifiedPublishers bool
+}
+
+func newSettingsSetCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts settingsSetOptions
+	cmd := &cobra.Command{
+		Use:                   settingsSetName + " [OPTIONS] ORGANIZATION",
+		Short:                 "Change the settings of an organization",
+		Long:                  "Change the settings of an organization, the ones not given being left unchanged.",
+		Example:               "  hub-tool org settings set myorg --default-visibility private --image-access-management --allow-official-images",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, settingsSetName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var update hub.UpdateOrganizationSettingsOptions
+			flags := cmd.Flags()
+			if flags.Changed("default-visibility") {
+				update.DefaultVisibility = &opts.defaultVisibility
+			}
+			if flags.Changed("image-access-management") {
+				update.RestrictedImages = &opts.imageAccessManagement
+			}
+			if flags.Changed("allow-official-images") {
+				update.AllowOfficialImages = &opts.allowOfficialImages
+			}
+			if flags.Changed("allow-verified-publishers") {
+				update.AllowVerifiedPublishers = &opts.allowVerifiedPublishers
+			}
+			if update == (hub.UpdateOrganizationSettingsOptions{}) {
+				return fmt.Errorf("no setting to change, see %q", cmd.CommandPath()+" --help")
+			}
+			settings, err := hubClient.UpdateOrganizationSettings(cmd.Context(), args[0], update)
+			if err != nil {
+				return err
+			}
+			return opts.Print(streams.Out(), settings, printSettings)
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	cmd.Flags().StringVar(&opts.defaultVisibility, "default-visibility", "", `Visibility of the new repositories, "public" or "private"`)
+	cmd.Flags().BoolVar(&opts.imageAccessManagement, "image-access-management", false, "Only allow the members to pull the images of the organization and the allowed ones")
+	cmd.Flags().BoolVar(&opts.allowOfficialImages, "allow-official-images", false, "Allow the members to pull the Docker Official Images with the image access management")
+	cmd.Flags().BoolVar(&opts.allowVerifiedPublishers, "allow-verified-publishers", false, "Allow the members to pull the Verified Publisher images with the image access management")
+	return cmd
+}
+
+func printSettings(out io.Writer, value interface{}) error {
+	settings := value.(*hub.OrganizationSettings)
+	fmt.Fprintf(out, ansi.Key("Default visibility:")+"\t\t%s\n", settings.DefaultVisibility)
+	fmt.Fprintf(out, ansi.Key("Image access management:")+"\t%s\n", enabled(settings.RestrictedImages.Enabled))
+	fmt.Fprintf(out, ansi.Key("  Official images:")+"\t\t%s\n", allowed(settings.RestrictedImages.AllowOfficialImages))
+	fmt.Fprintf(out, ansi.Key("  Verified publishers:")+"\t\t%s\n", allowed(settings.RestrictedImages.AllowVerifiedPublishers))
+	return nil
+}
+
+func enabled(value bool) string {
+	if value {
+		return ansi.Emphasise("enabled")
+	}
+	return "disabled"
+}
+
+func allowed(value bool) string {
+	if value {
+		return "allowed"
+	}
+	return "denied"
+}
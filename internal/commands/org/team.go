@@ -0,0 +1,145 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package org
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	teamName          = "team"
+	teamCreateName    = "create"
+	teamRmName        = "rm"
+	teamAddMemberName = "add-member"
+	teamRmMemberName  = "rm-member"
+)
+
+func newTeamCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmdName := parent + " " + teamName
+	cmd := &cobra.Command{
+		Use:                   teamName,
+		Short:                 "Manage the teams of an organization",
+		Args:                  cli.NoArgs,
+		DisableFlagsInUseLine: true,
+		RunE:                  command.ShowHelp(streams.Err()),
+	}
+	cmd.AddCommand(
+		newTeamCreateCmd(streams, hubClient, cmdName),
+		newTeamRmCmd(streams, hubClient, cmdName),
+		newTeamAddMemberCmd(streams, hubClient, cmdName),
+		newTeamRmMemberCmd(streams, hubClient, cmdName),
+	)
+	return cmd
+}
+
+func newTeamCreateCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var (
+		opts        format.Option
+		description string
+	)
+	cmd := &cobra.Command{
+		Use:                   teamCreateName + " [OPTIONS] ORGANIZATION TEAM",
+		Short:                 "Create a team in an organization",
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, teamCreateName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			team, err := hubClient.CreateTeam(cmd.Context(), args[0], args[1], description)
+			if err != nil {
+				return err
+			}
+			return opts.Print(streams.Out(), team, func(out io.Writer, value interface{}) error {
+				fmt.Fprintf(out, "Created team %q in %s\n", team.Name, args[0])
+				return nil
+			})
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	cmd.Flags().StringVar(&description, "description", "", "Description of the team")
+	return cmd
+}
+
+func newTeamRmCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   teamRmName + " ORGANIZATION TEAM",
+		Short:                 "Delete a team from an organization",
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, teamRmName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := hubClient.RemoveTeam(cmd.Context(), args[0], args[1]); err != nil {
+				return err
+			}
+			fmt.Fprintf(streams.Out(), "Deleted team %q from %s\n", args[1], args[0])
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newTeamAddMemberCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   teamAddMemberName + " ORGANIZATION TEAM USERNAME",
+		Short:                 "Add a member of the organization to a team",
+		Args:                  cli.ExactArgs(3),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, teamAddMemberName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := hubClient.AddTeamMember(cmd.Context(), args[0], args[1], args[2]); err != nil {
+				return err
+			}
+			fmt.Fprintf(streams.Out(), "Added %s to team %q\n", args[2], args[1])
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newTeamRmMemberCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   teamRmMemberName + " ORGANIZATION TEAM USERNAME",
+		Short:                 "Remove a member from a team",
+		Args:                  cli.ExactArgs(3),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, teamRmMemberName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := hubClient.RemoveTeamMember(cmd.Context(), args[0], args[1], args[2]); err != nil {
+				return err
+			}
+			fmt.Fprintf(streams.Out(), "Removed %s from team %q\n", args[2], args[1])
+			return nil
+		},
+	}
+	return cmd
+}
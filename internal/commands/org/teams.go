@@ -17,6 +17,7 @@
 package org
 
 import (
+	"context"
 	"fmt"
 	"io"
 
@@ -27,8 +28,8 @@ import (
 	"github.com/docker/hub-tool/internal/ansi"
 	"github.com/docker/hub-tool/internal/format"
 	"github.com/docker/hub-tool/internal/format/tabwriter"
-	"github.com/docker/hub-tool/internal/hub"
 	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
@@ -66,23 +67,23 @@ func newTeamsCmd(streams command.Streams, hubClient *hub.Client, parent string)
 			metrics.Send(parent, teamsName)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runTeams(streams, hubClient, opts, args[0])
+			return runTeams(cmd.Context(), streams, hubClient, opts, args[0])
 		},
 	}
 	opts.AddFormatFlag(cmd.Flags())
 	return cmd
 }
 
-func runTeams(streams command.Streams, hubClient *hub.Client, opts teamsOptions, organization string) error {
-	teams, err := hubClient.GetTeams(organization)
+func runTeams(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts teamsOptions, organization string) error {
+	teams, err := hubClient.GetTeams(ctx, organization)
 	if err != nil {
 		return err
 	}
-	return opts.Print(streams.Out(), teams, printTeams)
+	return opts.Print(streams.Out(), teamList(teams), printTeams)
 }
 
 func printTeams(out io.Writer, values interface{}) error {
-	teams := values.([]hub.Team)
+	teams := values.(teamList)
 	tw := tabwriter.New(out, "    ")
 
 	for _, column := range teamsColumns {
@@ -100,3 +101,15 @@ func printTeams(out io.Writer, values interface{}) error {
 
 	return tw.Flush()
 }
+
+// teamList prints a row per team in csv and tsv
+type teamList []hub.Team
+
+// Table returns the raw values of the teams
+func (l teamList) Table() ([]string, [][]interface{}) {
+	rows := make([][]interface{}, len(l))
+	for i, t := range l {
+		rows[i] = []interface{}{t.Name, t.Description, len(t.Members)}
+	}
+	return []string{"TEAM", "DESCRIPTION", "MEMBERS"}, rows
+}
@@ -0,0 +1,130 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package policy
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/bulk"
+	"github.com/docker/hub-tool/internal/errdef"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/internal/retention"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	applyName = "apply"
+)
+
+type applyOptions struct {
+	file        string
+	autoApprove bool
+	bulk.Deleter
+}
+
+func newApplyCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts applyOptions
+	cmd := &cobra.Command{
+		Use:   applyName + " [OPTIONS]",
+		Short: "Delete the tags the retention policy doesn't keep",
+		Long: `Delete the tags the retention policy doesn't keep.
+
+The plan is printed and confirmed before deleting anything, see policy plan for
+the format of the policy file.`,
+		Args:                  cli.NoArgs,
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, applyName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runApply(cmd.Context(), streams, hubClient, opts)
+			if err == nil || errors.Is(err, errdef.ErrCanceled) {
+				return nil
+			}
+			return err
+		},
+	}
+	addFileFlag(cmd.Flags(), &opts.file)
+	cmd.Flags().BoolVar(&opts.autoApprove, "auto-approve", false, "Do not prompt for confirmation of the plan")
+	opts.AddConcurrencyFlag(cmd.Flags())
+	return cmd
+}
+
+func runApply(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts applyOptions) error {
+	policy, plans, err := loadPlan(ctx, hubClient, opts.file)
+	if err != nil {
+		return err
+	}
+	if err := printPlan(streams.Out(), plans); err != nil {
+		return err
+	}
+	if len(plans) == 0 {
+		return nil
+	}
+	if !opts.autoApprove {
+		if err := confirmPlan(ctx, streams, policy); err != nil {
+			return err
+		}
+	}
+
+	total := 0
+	for _, plan := range plans {
+		total += len(plan.Delete)
+	}
+	return opts.Delete(streams, hubClient, "tags", total, func(report func(string, error)) error {
+		for _, plan := range plans {
+			repository := plan.Repository
+			if _, err := hubClient.RemoveTags(ctx, repository, plan.Delete, func(tag string, err error) {
+				report(repository+":"+tag, err)
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// confirmPlan asks the user to confirm the deletions of the printed plan
+func confirmPlan(ctx context.Context, streams command.Streams, policy *retention.Policy) error {
+	fmt.Fprintln(streams.Out(), ansi.Warn(fmt.Sprintf("WARNING: You are about to permanently delete these tags of %s", policy.Organization)))
+	fmt.Fprintln(streams.Out(), ansi.Warn("         This action is irreversible"))
+	fmt.Fprint(streams.Out(), ansi.Info("Do you want to apply the plan? [y/N] "))
+	userIn := make(chan string, 1)
+	go func() {
+		reader := bufio.NewReader(streams.In())
+		input, _ := reader.ReadString('\n')
+		userIn <- strings.ToLower(strings.TrimSpace(input))
+	}()
+	input := ""
+	select {
+	case <-ctx.Done():
+		return errdef.ErrCanceled
+	case input = <-userIn:
+	}
+	if input != "y" {
+		return errors.New("apply aborted")
+	}
+	return nil
+}
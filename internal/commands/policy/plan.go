@@ -0,0 +1,120 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package policy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/internal/retention"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	planName = "plan"
+	// defaultPolicyFile is the policy file read without --file
+	defaultPolicyFile = "retention.yaml"
+)
+
+type planOptions struct {
+	format.Option
+	file string
+}
+
+func addFileFlag(flags *pflag.FlagSet, file *string) {
+	flags.StringVarP(file, "file", "f", defaultPolicyFile, "Retention policy file")
+}
+
+func newPlanCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts planOptions
+	cmd := &cobra.Command{
+		Use:   planName + " [OPTIONS]",
+		Short: "Print the tags the retention policy would delete",
+		Long: `Print the tags the retention policy would delete, without deleting them.
+
+The policy file describes the rules of the repositories of an organization, the
+first rule whose repositories match the name of a repository being applied:
+
+  organization: myorg
+  rules:
+    - repositories: "app-*"
+      tags: "pr-*"
+      keep-last: 5
+      older-than: 30d
+    - repositories: "*"
+      keep-last: 20
+      exclude: ["latest", "release-*"]`,
+		Args:                  cli.NoArgs,
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, planName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, plans, err := loadPlan(cmd.Context(), hubClient, opts.file)
+			if err != nil {
+				return err
+			}
+			return opts.Print(streams.Out(), plans, printPlan)
+		},
+	}
+	addFileFlag(cmd.Flags(), &opts.file)
+	opts.AddFormatFlag(cmd.Flags())
+	return cmd
+}
+
+// loadPlan reads the policy file and plans the deletions it requires
+func loadPlan(ctx context.Context, hubClient *hub.Client, file string) (*retention.Policy, []retention.RepositoryPlan, error) {
+	policy, err := retention.Load(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := hubClient.Update(hub.WithAllElements()); err != nil {
+		return nil, nil, err
+	}
+	plans, err := retention.Plan(ctx, hubClient, policy, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	return policy, plans, nil
+}
+
+func printPlan(out io.Writer, values interface{}) error {
+	plans := values.([]retention.RepositoryPlan)
+	if len(plans) == 0 {
+		fmt.Fprintln(out, "No tag to delete, the repositories comply with the policy")
+		return nil
+	}
+	total := 0
+	for _, plan := range plans {
+		total += len(plan.Delete)
+		fmt.Fprintf(out, "%s %s\n", ansi.Title(plan.Repository), ansi.Info(fmt.Sprintf("(rule %d): delete %d of %d tag(s)", plan.Rule, len(plan.Delete), plan.Tags)))
+		for _, tag := range plan.Delete {
+			fmt.Fprintf(out, "  - %s\n", tag)
+		}
+	}
+	fmt.Fprintf(out, "\nPlan: %d tag(s) to delete in %d repositories\n", total, len(plans))
+	return nil
+}
@@ -0,0 +1,264 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	buildName   = "build"
+	triggerName = "trigger"
+	logsName    = "logs"
+)
+
+func newBuildCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmdName := parent + " " + buildName
+	cmd := &cobra.Command{
+		Use:                   buildName,
+		Short:                 "Manage the automated builds of a repository",
+		Args:                  cli.NoArgs,
+		DisableFlagsInUseLine: true,
+		RunE:                  command.ShowHelp(streams.Err()),
+	}
+	cmd.AddCommand(
+		newBuildListCmd(streams, hubClient, cmdName),
+		newBuildTriggerCmd(streams, hubClient, cmdName),
+		newBuildLogsCmd(streams, hubClient, cmdName),
+	)
+	return cmd
+}
+
+func newBuildListCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts format.Option
+	cmd := &cobra.Command{
+		Use:                   listName + " [OPTIONS] REPOSITORY",
+		Aliases:               []string{"list"},
+		Short:                 "Print the source and the build rules of a repository",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, listName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			settings, err := hubClient.GetBuildSettings(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return opts.Print(streams.Out(), buildSettings{settings}, printBuildSettings)
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	return cmd
+}
+
+type buildTriggerOptions struct {
+	format.Option
+	sourceBranch string
+	sourceTag    string
+	wait         bool
+	interval     time.Duration
+}
+
+func newBuildTriggerCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts buildTriggerOptions
+	cmd := &cobra.Command{
+		Use:                   triggerName + " [OPTIONS] REPOSITORY --source-branch BRANCH|--source-tag TAG",
+		Short:                 "Build a branch or a tag of the source repository",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, triggerName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (opts.sourceBranch == "") == (opts.sourceTag == "") {
+				return errors.New("exactly one of --source-branch or --source-tag is required")
+			}
+			return runBuildTrigger(cmd.Context(), streams, hubClient, opts, args[0])
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	cmd.Flags().StringVar(&opts.sourceBranch, "source-branch", "", "Branch of the source repository to build")
+	cmd.Flags().StringVar(&opts.sourceTag, "source-tag", "", "Tag of the source repository to build")
+	cmd.Flags().BoolVar(&opts.wait, "wait", false, "Wait for the builds to end, failing if one of them fails")
+	cmd.Flags().DurationVar(&opts.interval, "interval", 10*time.Second, "Interval between two checks of the build status with --wait")
+	return cmd
+}
+
+func runBuildTrigger(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts buildTriggerOptions, repository string) error {
+	sourceType, sourceName := hub.BuildSourceBranch, opts.sourceBranch
+	if opts.sourceTag != "" {
+		sourceType, sourceName = hub.BuildSourceTag, opts.sourceTag
+	}
+	builds, err := hubClient.TriggerBuild(ctx, repository, sourceType, sourceName)
+	if err != nil {
+		return err
+	}
+	if opts.wait {
+		if builds, err = waitForBuilds(ctx, streams, hubClient, repository, builds, opts.interval); err != nil {
+			return err
+		}
+	}
+	if err := opts.Print(streams.Out(), buildList(builds), printBuilds); err != nil {
+		return err
+	}
+	for _, build := range builds {
+		if build.State == hub.BuildStateFailed || build.State == hub.BuildStateCanceled {
+			return fmt.Errorf("build %s of %s %s", build.ID, build.Tag, build.State)
+		}
+	}
+	return nil
+}
+
+// waitForBuilds polls the builds until they are all over, printing their
+// state changes on stderr
+func waitForBuilds(ctx context.Context, streams command.Streams, hubClient *hub.Client, repository string, builds []hub.Build, interval time.Duration) ([]hub.Build, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		done := true
+		for i := range builds {
+			if builds[i].Done() {
+				continue
+			}
+			build, err := hubClient.GetBuild(ctx, repository, builds[i].ID)
+			if err != nil {
+				return nil, err
+			}
+			if build.State != builds[i].State {
+				fmt.Fprintf(streams.Err(), "Build %s of %s: %s\n", build.ID, build.Tag, build.State)
+			}
+			builds[i] = *build
+			done = done && build.Done()
+		}
+		if done {
+			return builds, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+type buildLogsOptions struct {
+	follow   bool
+	interval time.Duration
+}
+
+func newBuildLogsCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts buildLogsOptions
+	cmd := &cobra.Command{
+		Use:                   logsName + " [OPTIONS] REPOSITORY BUILD",
+		Short:                 "Print the logs of an automated build",
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, logsName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBuildLogs(cmd.Context(), streams, hubClient, opts, args[0], args[1])
+		},
+	}
+	cmd.Flags().BoolVarP(&opts.follow, "follow", "f", false, "Follow the logs until the build ends")
+	cmd.Flags().DurationVar(&opts.interval, "interval", 2*time.Second, "Interval between two fetches of the logs with --follow")
+	return cmd
+}
+
+func runBuildLogs(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts buildLogsOptions, repository, id string) error {
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+	offset := 0
+	for {
+		// Get the build state first, so the logs fetched after a build ended
+		// are complete
+		var build *hub.Build
+		if opts.follow {
+			var err error
+			if build, err = hubClient.GetBuild(ctx, repository, id); err != nil {
+				return err
+			}
+		}
+		logs, err := hubClient.GetBuildLogs(ctx, repository, id, offset)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(streams.Out(), logs)
+		offset += len(logs)
+		if build == nil || build.Done() {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func printBuildSettings(out io.Writer, value interface{}) error {
+	settings := value.(buildSettings)
+	fmt.Fprintf(out, ansi.Key("Source:")+"\t%s (%s)\n", settings.Source, settings.Provider)
+	fmt.Fprintf(out, ansi.Key("Autotests:")+"\t%s\n\n", settings.Autotests)
+	headers, rows := settings.Table()
+	return format.PrintTable(out, headers, rows)
+}
+
+// buildSettings prints a row per build rule in csv and tsv
+type buildSettings struct {
+	*hub.BuildSettings
+}
+
+// Table returns the raw values of the build rules
+func (s buildSettings) Table() ([]string, [][]interface{}) {
+	rows := make([][]interface{}, len(s.Rules))
+	for i, r := range s.Rules {
+		rows[i] = []interface{}{r.SourceType, r.SourceName, r.Tag, r.Dockerfile, r.BuildContext, r.Autobuild}
+	}
+	return []string{"SOURCE TYPE", "SOURCE", "TAG", "DOCKERFILE", "CONTEXT", "AUTOBUILD"}, rows
+}
+
+func printBuilds(out io.Writer, values interface{}) error {
+	headers, rows := values.(buildList).Table()
+	return format.PrintTable(out, headers, rows)
+}
+
+// buildList prints a row per build in csv and tsv
+type buildList []hub.Build
+
+// Table returns the raw values of the builds
+func (l buildList) Table() ([]string, [][]interface{}) {
+	rows := make([][]interface{}, len(l))
+	for i, b := range l {
+		rows[i] = []interface{}{b.ID, b.SourceName, b.Tag, b.State, b.CreatedAt}
+	}
+	return []string{"BUILD", "SOURCE", "TAG", "STATE", "CREATED"}, rows
+}
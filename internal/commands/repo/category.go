@@ -0,0 +1,113 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	categoriesName  = "categories"
+	setCategoryName = "set-category"
+)
+
+func newCategoriesCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts format.Option
+	cmd := &cobra.Command{
+		Use:                   categoriesName + " [OPTIONS] [REPOSITORY]",
+		Short:                 "List the categories of a repository, or all the categories without repository",
+		Args:                  cli.RequiresMaxArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, categoriesName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				categories []hub.Category
+				err        error
+			)
+			if len(args) == 0 {
+				categories, err = hubClient.GetCategories(cmd.Context())
+			} else {
+				var repo *hub.Repository
+				if repo, err = hubClient.GetRepository(cmd.Context(), args[0]); err == nil {
+					categories = repo.Categories
+				}
+			}
+			if err != nil {
+				return err
+			}
+			return opts.Print(streams.Out(), categoryList(categories), printCategories)
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	return cmd
+}
+
+func newSetCategoryCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   setCategoryName + " REPOSITORY CATEGORY[,CATEGORY...]",
+		Short:                 "Set the categories of a repository, given as a comma separated list of slugs",
+		Long:                  `Set the categories of a repository, given as a comma separated list of slugs. The categories are replaced, use "" to remove them all.`,
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, setCategoryName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var slugs []string
+			for _, slug := range strings.Split(args[1], ",") {
+				if slug = strings.TrimSpace(slug); slug != "" {
+					slugs = append(slugs, slug)
+				}
+			}
+			if err := hubClient.SetRepositoryCategories(cmd.Context(), args[0], slugs); err != nil {
+				return err
+			}
+			fmt.Fprintln(streams.Out(), "Updated the categories of", args[0])
+			return nil
+		},
+	}
+	return cmd
+}
+
+func printCategories(out io.Writer, values interface{}) error {
+	headers, rows := values.(categoryList).Table()
+	return format.PrintTable(out, headers, rows)
+}
+
+// categoryList prints a row per category in csv and tsv
+type categoryList []hub.Category
+
+// Table returns the raw values of the categories
+func (l categoryList) Table() ([]string, [][]interface{}) {
+	rows := make([][]interface{}, len(l))
+	for i, c := range l {
+		rows[i] = []interface{}{c.Slug, c.Name}
+	}
+	return []string{"SLUG", "NAME"}, rows
+}
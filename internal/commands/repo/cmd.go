@@ -21,7 +21,7 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/spf13/cobra"
 
-	"github.com/docker/hub-tool/internal/hub"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
@@ -38,8 +38,31 @@ func NewRepoCmd(streams command.Streams, hubClient *hub.Client) *cobra.Command {
 		RunE:                  command.ShowHelp(streams.Err()),
 	}
 	cmd.AddCommand(
+		newBuildCmd(streams, hubClient, repoName),
+		newCategoriesCmd(streams, hubClient, repoName),
+		newCollaboratorCmd(streams, hubClient, repoName),
+		newCreateCmd(streams, hubClient, repoName),
+		newDuCmd(streams, hubClient, repoName),
+		newExportCmd(streams, hubClient, repoName),
+		newGrantCmd(streams, hubClient, repoName),
+		newImportCmd(streams, hubClient, repoName),
 		newListCmd(streams, hubClient, repoName),
+		newPermissionsCmd(streams, hubClient, repoName),
+		newReadmeCmd(streams, hubClient, repoName),
+		newRenameCmd(streams, hubClient, repoName),
+		newRevokeCmd(streams, hubClient, repoName),
 		newRmCmd(streams, hubClient, repoName),
+		newSetCategoryCmd(streams, hubClient, repoName),
+		newSetVisibilityCmd(streams, hubClient, repoName),
+		newStaleCmd(streams, hubClient, repoName),
+		newStarCmd(streams, hubClient, repoName),
+		newStarsCmd(streams, hubClient, repoName),
+		newStatsCmd(streams, hubClient, repoName),
+		newSyncCmd(streams, hubClient, repoName),
+		newTransferCmd(streams, hubClient, repoName),
+		newUnstarCmd(streams, hubClient, repoName),
+		newUpdateCmd(streams, hubClient, repoName),
+		newWebhookCmd(streams, hubClient, repoName),
 	)
 	return cmd
 }
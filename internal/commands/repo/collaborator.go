@@ -0,0 +1,132 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	collaboratorName = "collaborator"
+	addName          = "add"
+)
+
+func newCollaboratorCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmdName := parent + " " + collaboratorName
+	cmd := &cobra.Command{
+		Use:                   collaboratorName,
+		Short:                 "Manage the collaborators of a personal repository",
+		Args:                  cli.NoArgs,
+		DisableFlagsInUseLine: true,
+		RunE:                  command.ShowHelp(streams.Err()),
+	}
+	cmd.AddCommand(
+		newCollaboratorListCmd(streams, hubClient, cmdName),
+		newCollaboratorAddCmd(streams, hubClient, cmdName),
+		newCollaboratorRmCmd(streams, hubClient, cmdName),
+	)
+	return cmd
+}
+
+func newCollaboratorListCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts format.Option
+	cmd := &cobra.Command{
+		Use:                   listName + " [OPTIONS] REPOSITORY",
+		Aliases:               []string{"list"},
+		Short:                 "List the collaborators of a repository",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, listName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			collaborators, err := hubClient.GetCollaborators(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return opts.Print(streams.Out(), collaboratorList(collaborators), printCollaborators)
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	return cmd
+}
+
+func newCollaboratorAddCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   addName + " REPOSITORY USERNAME",
+		Short:                 "Give a user access to a repository",
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, addName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := hubClient.AddCollaborator(cmd.Context(), args[0], args[1]); err != nil {
+				return err
+			}
+			fmt.Fprintf(streams.Out(), "%s is now a collaborator of %s\n", args[1], args[0])
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newCollaboratorRmCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   rmName + " REPOSITORY USERNAME",
+		Short:                 "Remove the access of a user to a repository",
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, rmName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := hubClient.RemoveCollaborator(cmd.Context(), args[0], args[1]); err != nil {
+				return err
+			}
+			fmt.Fprintf(streams.Out(), "%s is no longer a collaborator of %s\n", args[1], args[0])
+			return nil
+		},
+	}
+	return cmd
+}
+
+func printCollaborators(out io.Writer, values interface{}) error {
+	headers, rows := values.(collaboratorList).Table()
+	return format.PrintTable(out, headers, rows)
+}
+
+// collaboratorList prints a row per collaborator in csv and tsv
+type collaboratorList []hub.Collaborator
+
+// Table returns the raw values of the collaborators
+func (l collaboratorList) Table() ([]string, [][]interface{}) {
+	rows := make([][]interface{}, len(l))
+	for i, c := range l {
+		rows[i] = []interface{}{c.Username}
+	}
+	return []string{"USERNAME"}, rows
+}
@@ -0,0 +1,100 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	createName = "create"
+)
+
+type createOptions struct {
+	format.Option
+	namespace   string
+	description string
+	private     bool
+}
+
+func newCreateCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts createOptions
+	cmd := &cobra.Command{
+		Use:                   createName + " [OPTIONS] [NAMESPACE/]REPOSITORY",
+		Short:                 "Create a repository",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, createName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCreate(cmd.Context(), streams, hubClient, opts, args[0])
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	cmd.Flags().StringVar(&opts.namespace, "namespace", "", "Namespace of the repository, defaults to the configured namespace or the current account")
+	cmd.Flags().StringVar(&opts.description, "description", "", "Short description of the repository")
+	cmd.Flags().BoolVar(&opts.private, "private", false, "Make the repository private")
+	return cmd
+}
+
+func runCreate(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts createOptions, repository string) error {
+	namespace, name, err := splitRepositoryName(repository, opts.namespace, hubClient.DefaultNamespace())
+	if err != nil {
+		return err
+	}
+	repo, err := hubClient.CreateRepository(ctx, namespace, name, hub.CreateRepositoryOptions{
+		Description: opts.description,
+		IsPrivate:   opts.private,
+	})
+	if err != nil {
+		return err
+	}
+	return opts.Print(streams.Out(), repo, func(out io.Writer, value interface{}) error {
+		fmt.Fprintln(out, "Created", value.(*hub.Repository).Name)
+		return nil
+	})
+}
+
+// splitRepositoryName returns the namespace and the name of a repository given
+// as [NAMESPACE/]NAME, falling back to the namespace flag then the account
+func splitRepositoryName(repository, namespace, account string) (string, string, error) {
+	parts := strings.Split(repository, "/")
+	switch {
+	case len(parts) == 1 && namespace != "":
+		return namespace, parts[0], nil
+	case len(parts) == 1:
+		return account, parts[0], nil
+	case len(parts) == 2 && (namespace == "" || namespace == parts[0]):
+		return parts[0], parts[1], nil
+	case len(parts) == 2:
+		return "", "", fmt.Errorf("repository namespace %q conflicts with --namespace %q", parts[0], namespace)
+	default:
+		return "", "", fmt.Errorf("invalid repository name %q", repository)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSplitRepositoryName(t *testing.T) {
+	testCases := []struct {
+		repository string
+		namespace  string
+		expected   [2]string
+		err        string
+	}{
+		{repository: "app", expected: [2]string{"jdoe", "app"}},
+		{repository: "app", namespace: "myorg", expected: [2]string{"myorg", "app"}},
+		{repository: "myorg/app", expected: [2]string{"myorg", "app"}},
+		{repository: "myorg/app", namespace: "myorg", expected: [2]string{"myorg", "app"}},
+		{repository: "myorg/app", namespace: "other", err: `repository namespace "myorg" conflicts with --namespace "other"`},
+		{repository: "docker.io/myorg/app", err: `invalid repository name "docker.io/myorg/app"`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.repository+" "+tc.namespace, func(t *testing.T) {
+			namespace, name, err := splitRepositoryName(tc.repository, tc.namespace, "jdoe")
+			if tc.err != "" {
+				assert.Error(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, [2]string{namespace, name}, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/containerd/containerd/remotes"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/internal/registry"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	duName = "du"
+)
+
+// repositoryUsage is the storage used by a repository, each layer being
+// counted once even when shared by several images
+type repositoryUsage struct {
+	Repository string `json:"repository"`
+	Tags       int    `json:"tags"`
+	Images     int    `json:"images"`
+	Layers     int    `json:"layers"`
+	Size       int64  `json:"size"`
+}
+
+func newDuCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts format.Option
+	cmd := &cobra.Command{
+		Use:   duName + " [OPTIONS] [ORGANIZATION]",
+		Short: "Print the storage used by each repository",
+		Long: `Print the storage used by each repository, summing the size of the unique layers of all its tags.
+A layer shared by several tags or platforms is only counted once, as opposed to the sizes of repo ls.`,
+		Args:                  cli.RequiresMaxArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, duName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			account := hubClient.DefaultNamespace()
+			if len(args) > 0 {
+				account = args[0]
+			}
+			return runDu(cmd.Context(), streams, hubClient, opts, account)
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	return cmd
+}
+
+func runDu(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts format.Option, account string) error {
+	if err := hubClient.Update(hub.WithAllElements()); err != nil {
+		return err
+	}
+	repos, _, err := hubClient.GetRepositories(ctx, account)
+	if err != nil {
+		return err
+	}
+	resolver := registry.NewResolver(hubClient)
+
+	usages := make(usageList, len(repos))
+	sem := make(chan struct{}, hubClient.Concurrency())
+	g, ctx := errgroup.WithContext(ctx)
+	for i := range repos {
+		i := i
+		g.Go(func() error {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			defer func() { <-sem }()
+			usage, err := getRepositoryUsage(ctx, hubClient, resolver, repos[i].Name)
+			if err != nil {
+				return fmt.Errorf("%s: %w", repos[i].Name, err)
+			}
+			usages[i] = *usage
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	sort.SliceStable(usages, func(i, j int) bool { return usages[i].Size > usages[j].Size })
+	return opts.Print(streams.Out(), usages, printUsages)
+}
+
+// getRepositoryUsage resolves the manifests of all the tags of a repository
+// and sums the size of their configs and unique layers
+func getRepositoryUsage(ctx context.Context, hubClient *hub.Client, resolver remotes.Resolver, repository string) (*repositoryUsage, error) {
+	tags, _, err := hubClient.GetTags(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	usage := repositoryUsage{Repository: repository, Tags: len(tags)}
+	var (
+		blobs = map[string]int64{}
+		seen  = map[string]bool{}
+	)
+	for _, tag := range tags {
+		if tag.Digest == "" || seen[tag.Digest] {
+			continue
+		}
+		seen[tag.Digest] = true
+		ref, err := reference.ParseNormalizedNamed(repository + "@" + tag.Digest)
+		if err != nil {
+			return nil, err
+		}
+		manifests, err := registry.GetManifests(ctx, resolver, ref.String())
+		if err != nil {
+			return nil, err
+		}
+		usage.Images += len(manifests)
+		for _, manifest := range manifests {
+			blobs[manifest.Config.Digest.String()] = manifest.Config.Size
+			for _, layer := range manifest.Layers {
+				if _, ok := blobs[layer.Digest.String()]; !ok {
+					usage.Layers++
+				}
+				blobs[layer.Digest.String()] = layer.Size
+			}
+		}
+	}
+	for _, size := range blobs {
+		usage.Size += size
+	}
+	return &usage, nil
+}
+
+func printUsages(out io.Writer, values interface{}) error {
+	usages := values.(usageList)
+	headers, rows := usages.Table()
+	for i, usage := range usages {
+		rows[i][4] = units.HumanSize(float64(usage.Size))
+	}
+	var total int64
+	for _, usage := range usages {
+		total += usage.Size
+	}
+	if err := format.PrintTable(out, headers, rows); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(out, "\nTotal: %s\n", units.HumanSize(float64(total)))
+	return err
+}
+
+// usageList prints a row per repository in csv and tsv
+type usageList []repositoryUsage
+
+// Table returns the raw values of the repository usages, sizes in bytes
+func (l usageList) Table() ([]string, [][]interface{}) {
+	rows := make([][]interface{}, len(l))
+	for i, u := range l {
+		rows[i] = []interface{}{u.Repository, u.Tags, u.Images, u.Layers, u.Size}
+	}
+	return []string{"REPOSITORY", "TAGS", "IMAGES", "LAYERS", "SIZE"}, rows
+}
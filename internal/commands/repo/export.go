@@ -0,0 +1,141 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	exportName = "export"
+	importName = "import"
+)
+
+type exportOptions struct {
+	output string
+}
+
+func newExportCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts exportOptions
+	cmd := &cobra.Command{
+		Use:                   exportName + " [OPTIONS] [ORGANIZATION]",
+		Short:                 "Export the metadata of the repositories to a JSON file",
+		Long:                  "Export the description, overview, visibility, categories and team permissions of the repositories of an account to a JSON file, to back them up or to import them in another account with \"repo import\".",
+		Example:               "  hub-tool repo export myorg -o repos.json",
+		Args:                  cli.RequiresMaxArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, exportName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			account := hubClient.DefaultNamespace()
+			if len(args) > 0 {
+				account = args[0]
+			}
+			export, err := hubClient.ExportMetadata(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			data, err := json.MarshalIndent(export, "", "  ")
+			if err != nil {
+				return err
+			}
+			data = append(data, '\n')
+			if opts.output == "-" {
+				_, err := streams.Out().Write(data)
+				return err
+			}
+			if err := ioutil.WriteFile(opts.output, data, 0644); err != nil {
+				return err
+			}
+			fmt.Fprintf(streams.Out(), "Exported %d repositories of %s to %s\n", len(export.Repositories), account, opts.output)
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "-", `File to write the metadata to, "-" for stdout`)
+	return cmd
+}
+
+type importOptions struct {
+	namespace string
+}
+
+func newImportCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts importOptions
+	cmd := &cobra.Command{
+		Use:                   importName + " [OPTIONS] FILE",
+		Short:                 "Import the metadata of repositories from a JSON file",
+		Long:                  "Import the metadata of repositories written by \"repo export\", creating the missing repositories. They are imported in the exported account unless --namespace is given.",
+		Example:               "  hub-tool repo import repos.json --namespace mynewerorg",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, importName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				data []byte
+				err  error
+			)
+			if args[0] == "-" {
+				data, err = ioutil.ReadAll(streams.In())
+			} else {
+				data, err = ioutil.ReadFile(args[0])
+			}
+			if err != nil {
+				return err
+			}
+			var export hub.MetadataExport
+			if err := json.Unmarshal(data, &export); err != nil {
+				return fmt.Errorf("invalid export file %s: %s", args[0], err)
+			}
+			if export.Version != hub.MetadataExportVersion {
+				return fmt.Errorf("unsupported export version %d", export.Version)
+			}
+			namespace := export.Account
+			if opts.namespace != "" {
+				namespace = opts.namespace
+			}
+			failed := 0
+			for _, repo := range export.Repositories {
+				name := namespace + "/" + repo.Name
+				if err := hubClient.ImportRepositoryMetadata(cmd.Context(), namespace, repo); err != nil {
+					fmt.Fprintln(streams.Err(), ansi.Error(fmt.Sprintf("Failed to import %s: %s", name, err)))
+					failed++
+					continue
+				}
+				fmt.Fprintln(streams.Out(), "Imported", name)
+			}
+			if failed > 0 {
+				return fmt.Errorf("failed to import %d of %d repositories", failed, len(export.Repositories))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&opts.namespace, "namespace", "", "Namespace to import the repositories in, instead of the exported one")
+	return cmd
+}
@@ -17,62 +17,33 @@
 package repo
 
 import (
+	"context"
 	"fmt"
 	"io"
-	"time"
+	"strings"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
-	"github.com/docker/go-units"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/hub-tool/internal/ansi"
 	"github.com/docker/hub-tool/internal/format"
-	"github.com/docker/hub-tool/internal/format/tabwriter"
-	"github.com/docker/hub-tool/internal/hub"
 	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/internal/watch"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
 	listName = "ls"
 )
 
-var (
-	defaultColumns = []column{
-		{"REPOSITORY", func(r hub.Repository) (string, int) {
-			return ansi.Link(fmt.Sprintf("https://hub.docker.com/repository/docker/%s", r.Name), r.Name), len(r.Name)
-		}},
-		{"DESCRIPTION", func(r hub.Repository) (string, int) { return r.Description, len(r.Description) }},
-		{"LAST UPDATE", func(r hub.Repository) (string, int) {
-			if r.LastUpdated.Nanosecond() == 0 {
-				return "", 0
-			}
-			s := fmt.Sprintf("%s ago", units.HumanDuration(time.Since(r.LastUpdated)))
-			return s, len(s)
-		}},
-		{"PULLS", func(r hub.Repository) (string, int) {
-			s := fmt.Sprintf("%d", r.PullCount)
-			return s, len(s)
-		}},
-		{"STARS", func(r hub.Repository) (string, int) {
-			s := fmt.Sprintf("%d", r.StarCount)
-			return s, len(s)
-		}},
-		{"PRIVATE", func(r hub.Repository) (string, int) {
-			s := fmt.Sprintf("%v", r.IsPrivate)
-			return s, len(s)
-		}},
-	}
-)
-
-type column struct {
-	header string
-	value  func(t hub.Repository) (string, int)
-}
-
 type listOptions struct {
 	format.Option
-	all bool
+	watch.Watcher
+	all        bool
+	maxResults int
+	pageSize   int
 }
 
 func newListCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
@@ -83,20 +54,34 @@ func newListCmd(streams command.Streams, hubClient *hub.Client, parent string) *
 		Short:                 "List all the repositories from your account or an organization",
 		Args:                  cli.RequiresMaxArgs(1),
 		DisableFlagsInUseLine: true,
+		Annotations: map[string]string{
+			"public": "true",
+		},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			metrics.Send(parent, listName)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runList(streams, hubClient, opts, args)
+			if opts.Watching() {
+				return opts.Watch(cmd.Context(), streams, hubClient, cmd.CommandPath()+" "+strings.Join(args, " "), func(streams command.Streams) error {
+					return runList(cmd.Context(), streams, hubClient, opts, args)
+				})
+			}
+			return runList(cmd.Context(), streams, hubClient, opts, args)
 		},
 	}
 	cmd.Flags().BoolVar(&opts.all, "all", false, "Fetch all available repositories")
+	cmd.Flags().IntVar(&opts.maxResults, "max-results", 0, "Stop after listing this number of repositories")
+	cmd.Flags().IntVar(&opts.pageSize, "page-size", 0, "Number of repositories fetched per request, at most --max-results")
 	opts.AddFormatFlag(cmd.Flags())
+	opts.AddWatchFlag(cmd.Flags())
 	return cmd
 }
 
-func runList(streams command.Streams, hubClient *hub.Client, opts listOptions, args []string) error {
-	account := hubClient.AuthConfig.Username
+func runList(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts listOptions, args []string) error {
+	if opts.pageSize < 0 {
+		return fmt.Errorf("invalid page size %d, must be at least 1", opts.pageSize)
+	}
+	account := hubClient.DefaultNamespace()
 	if opts.all {
 		if err := hubClient.Update(hub.WithAllElements()); err != nil {
 			return err
@@ -105,7 +90,23 @@ func runList(streams command.Streams, hubClient *hub.Client, opts listOptions, a
 	if len(args) > 0 {
 		account = args[0]
 	}
-	repositories, total, err := hubClient.GetRepositories(account)
+	if account == "" {
+		return errors.New(`an account or organization must be given to list its public repositories without being logged in, e.g. "repo ls library"`)
+	}
+	var (
+		repositories []hub.Repository
+		total        int
+		err          error
+	)
+	if opts.maxResults > 0 || opts.pageSize > 0 {
+		max := opts.maxResults
+		if max == 0 && !opts.all {
+			max = opts.pageSize
+		}
+		repositories, total, err = listRepositories(ctx, hubClient, account, opts.pageSize, max)
+	} else {
+		repositories, total, err = hubClient.GetRepositories(ctx, account)
+	}
 	if err != nil {
 		return err
 	}
@@ -113,25 +114,29 @@ func runList(streams command.Streams, hubClient *hub.Client, opts listOptions, a
 	return opts.Print(streams.Out(), repositories, printRepositories(total))
 }
 
+// listRepositories streams the repositories of the account, only fetching the
+// pages needed to return at most max repositories, or all of them when max is 0.
+// The page size is capped at max so that no more repositories are requested
+// than will be listed.
+func listRepositories(ctx context.Context, hubClient *hub.Client, account string, pageSize, max int) ([]hub.Repository, int, error) {
+	if max > 0 && (pageSize == 0 || pageSize > max) {
+		pageSize = max
+	}
+	repositories := []hub.Repository{}
+	total, err := hubClient.RepositoriesIter(ctx, account, pageSize, func(repository hub.Repository) error {
+		repositories = append(repositories, repository)
+		if max > 0 && len(repositories) >= max {
+			return hub.ErrStopIteration
+		}
+		return nil
+	})
+	return repositories, total, err
+}
+
 func printRepositories(total int) format.PrettyPrinter {
 	return func(out io.Writer, values interface{}) error {
 		repositories := values.([]hub.Repository)
-		tw := tabwriter.New(out, "    ")
-
-		for _, column := range defaultColumns {
-			tw.Column(ansi.Header(column.header), len(column.header))
-		}
-
-		tw.Line()
-
-		for _, repository := range repositories {
-			for _, column := range defaultColumns {
-				value, width := column.value(repository)
-				tw.Column(value, width)
-			}
-			tw.Line()
-		}
-		if err := tw.Flush(); err != nil {
+		if err := format.NewPrinter(out, format.TableFormat).PrintRepositories(repositories); err != nil {
 			return err
 		}
 
@@ -0,0 +1,58 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestListRepositoriesCapsPageSize(t *testing.T) {
+	testCases := []struct {
+		name             string
+		pageSize         int
+		max              int
+		expectedPageSize string
+		expectedCount    int
+	}{
+		{name: "default page size", max: 3, expectedPageSize: "3", expectedCount: 3},
+		{name: "page size above max", pageSize: 50, max: 3, expectedPageSize: "3", expectedCount: 3},
+		{name: "page size below max", pageSize: 2, max: 3, expectedPageSize: "2", expectedCount: 3},
+		{name: "no max", pageSize: 2, expectedPageSize: "2", expectedCount: 4},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hubClient := newTestHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.URL.Query().Get("page_size"), tc.expectedPageSize)
+				next := "null"
+				if r.URL.Query().Get("page") != "2" {
+					next = fmt.Sprintf(`"http://%s%s?page=2&page_size=%s"`, r.Host, r.URL.Path, tc.expectedPageSize)
+				}
+				fmt.Fprintf(w, `{"count": 4, "next": %s, "results": [{"name": "a"}, {"name": "b"}]}`, next)
+			}))
+
+			repositories, total, err := listRepositories(context.Background(), hubClient, "jdoe", tc.pageSize, tc.max)
+			assert.NilError(t, err)
+			assert.Equal(t, total, 4)
+			assert.Equal(t, len(repositories), tc.expectedCount)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	grantName       = "grant"
+	revokeName      = "revoke"
+	permissionsName = "permissions"
+)
+
+func newGrantCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var permission string
+	cmd := &cobra.Command{
+		Use:                   grantName + " [OPTIONS] REPOSITORY TEAM",
+		Short:                 "Grant a team of the organization a permission on a repository",
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, grantName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := hub.ParsePermission(permission)
+			if err != nil {
+				return err
+			}
+			if err := hubClient.GrantTeamPermission(cmd.Context(), args[0], args[1], p); err != nil {
+				return err
+			}
+			fmt.Fprintf(streams.Out(), "Granted %s permission on %s to team %q\n", p, args[0], args[1])
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&permission, "permission", string(hub.ReadPermission), "Permission to grant: read, write or admin")
+	return cmd
+}
+
+func newRevokeCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   revokeName + " REPOSITORY TEAM",
+		Short:                 "Revoke the permission of a team on a repository",
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, revokeName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := hubClient.RevokeTeamPermission(cmd.Context(), args[0], args[1]); err != nil {
+				return err
+			}
+			fmt.Fprintf(streams.Out(), "Revoked permission on %s from team %q\n", args[0], args[1])
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newPermissionsCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts format.Option
+	cmd := &cobra.Command{
+		Use:                   permissionsName + " [OPTIONS] REPOSITORY",
+		Short:                 "List the permissions granted to teams on a repository",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, permissionsName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			permissions, err := hubClient.GetRepositoryPermissions(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return opts.Print(streams.Out(), permissionList(permissions), printPermissions)
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	return cmd
+}
+
+func printPermissions(out io.Writer, values interface{}) error {
+	headers, rows := values.(permissionList).Table()
+	return format.PrintTable(out, headers, rows)
+}
+
+// permissionList prints a row per team in csv and tsv
+type permissionList []hub.TeamPermission
+
+// Table returns the raw values of the permissions
+func (l permissionList) Table() ([]string, [][]interface{}) {
+	rows := make([][]interface{}, len(l))
+	for i, p := range l {
+		rows[i] = []interface{}{p.Team, p.Permission}
+	}
+	return []string{"TEAM", "PERMISSION"}, rows
+}
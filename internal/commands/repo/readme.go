@@ -0,0 +1,121 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	readmeName = "readme"
+	getName    = "get"
+	setName    = "set"
+)
+
+func newReadmeCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmdName := parent + " " + readmeName
+	cmd := &cobra.Command{
+		Use:                   readmeName,
+		Short:                 "Get or set the overview of a repository, to keep it in sync with a README file",
+		Args:                  cli.NoArgs,
+		DisableFlagsInUseLine: true,
+		RunE:                  command.ShowHelp(streams.Err()),
+	}
+	cmd.AddCommand(
+		newReadmeGetCmd(streams, hubClient, cmdName),
+		newReadmeSetCmd(streams, hubClient, cmdName),
+	)
+	return cmd
+}
+
+func newReadmeGetCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   getName + " REPOSITORY",
+		Short:                 "Print the overview of a repository, in markdown",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, getName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo, err := hubClient.GetRepository(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(streams.Out(), repo.FullDescription)
+			return nil
+		},
+	}
+	return cmd
+}
+
+type readmeSetOptions struct {
+	file string
+}
+
+func newReadmeSetCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts readmeSetOptions
+	cmd := &cobra.Command{
+		Use:                   setName + " [OPTIONS] REPOSITORY",
+		Short:                 "Set the overview of a repository from a markdown file",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, setName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				readme []byte
+				err    error
+			)
+			if opts.file == "-" {
+				readme, err = ioutil.ReadAll(streams.In())
+			} else {
+				readme, err = ioutil.ReadFile(opts.file)
+			}
+			if err != nil {
+				return err
+			}
+			repo, err := hubClient.GetRepository(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			// Don't touch the repository when the overview is already in sync,
+			// not to change its last update on every CI run
+			overview := string(readme)
+			if repo.FullDescription == overview {
+				fmt.Fprintln(streams.Out(), "Overview of", repo.Name, "is up to date")
+				return nil
+			}
+			if _, err := hubClient.UpdateRepository(cmd.Context(), args[0], hub.UpdateRepositoryOptions{FullDescription: &overview}); err != nil {
+				return err
+			}
+			fmt.Fprintln(streams.Out(), "Updated the overview of", repo.Name)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&opts.file, "file", "README.md", `Markdown file to use as the overview, "-" to read it from stdin`)
+	return cmd
+}
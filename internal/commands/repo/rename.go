@@ -0,0 +1,111 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/errdef"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	renameName = "rename"
+)
+
+type renameOptions struct {
+	yes bool
+}
+
+func newRenameCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts renameOptions
+	cmd := &cobra.Command{
+		Use:                   renameName + " [OPTIONS] REPOSITORY NEW_NAME",
+		Short:                 "Rename a repository, keeping its tags",
+		Example:               "  hub-tool repo rename myorg/app myorg/application --yes",
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, renameName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runRename(cmd.Context(), streams, hubClient, opts, args[0], args[1])
+			if errors.Is(err, errdef.ErrCanceled) {
+				return nil
+			}
+			return err
+		},
+	}
+	cmd.Flags().BoolVarP(&opts.yes, "yes", "y", false, "Don't ask for confirmation")
+	return cmd
+}
+
+func runRename(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts renameOptions, repository, newName string) error {
+	namespace, name, err := splitRepositoryName(repository, "", hubClient.DefaultNamespace())
+	if err != nil {
+		return err
+	}
+	// The new name may repeat the namespace, but can't move the repository
+	newNamespace, newName, err := splitRepositoryName(newName, "", namespace)
+	if err != nil {
+		return err
+	}
+	if newNamespace != namespace {
+		return fmt.Errorf("can't rename %s/%s to another namespace, use \"repo transfer\" instead", namespace, name)
+	}
+	if newName == name {
+		return fmt.Errorf("%s/%s is already named %s", namespace, name, newName)
+	}
+	repository = namespace + "/" + name
+	if !opts.yes {
+		fmt.Fprintln(streams.Out(), ansi.Warn(fmt.Sprintf("WARNING: You are about to rename repository %q to %s/%s", repository, namespace, newName)))
+		fmt.Fprintln(streams.Out(), ansi.Warn("         The pulls of the old name will fail, update your Dockerfiles, compose files and CI"))
+		fmt.Fprint(streams.Out(), ansi.Info("Are you sure you want to rename this repository? [y/N] "))
+		userIn := make(chan string, 1)
+		go func() {
+			reader := bufio.NewReader(streams.In())
+			input, _ := reader.ReadString('\n')
+			userIn <- strings.ToLower(strings.TrimSpace(input))
+		}()
+		input := ""
+		select {
+		case <-ctx.Done():
+			return errdef.ErrCanceled
+		case input = <-userIn:
+		}
+		if input != "y" {
+			return errors.New("rename aborted")
+		}
+	}
+
+	renamed, err := hubClient.RenameRepository(ctx, repository, newName)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(streams.Out(), "%s renamed to %s\n", repository, renamed.Name)
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -32,8 +33,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/docker/hub-tool/internal/ansi"
-	"github.com/docker/hub-tool/internal/hub"
+	"github.com/docker/hub-tool/internal/bulk"
 	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
@@ -41,21 +43,29 @@ const (
 )
 
 type rmOptions struct {
-	force bool
+	force       bool
+	stopOnError bool
+	bulk.Deleter
 }
 
 func newRmCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
 	var opts rmOptions
 	cmd := &cobra.Command{
-		Use:                   rmName + " [OPTIONS] REPOSITORY",
+		Use:                   rmName + " [OPTIONS] REPOSITORY|-",
 		Short:                 "Delete a repository",
+		Long:                  "Delete a repository. With \"-\", newline-delimited repository names are read from stdin and deleted concurrently.",
 		Args:                  cli.ExactArgs(1),
 		DisableFlagsInUseLine: true,
 		PreRun: func(cmd *cobra.Command, args []string) {
 			metrics.Send(parent, rmName)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			err := runRm(cmd.Context(), streams, hubClient, opts, args[0])
+			var err error
+			if args[0] == "-" {
+				err = runRmFromStdin(cmd.Context(), streams, hubClient, opts)
+			} else {
+				err = runRm(cmd.Context(), streams, hubClient, opts, args[0])
+			}
 			if err == nil || errors.Is(err, errdef.ErrCanceled) {
 				return nil
 			}
@@ -63,6 +73,8 @@ func newRmCmd(streams command.Streams, hubClient *hub.Client, parent string) *co
 		},
 	}
 	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Force deletion of the repository")
+	cmd.Flags().BoolVar(&opts.stopOnError, "stop-on-error", false, "Stop at the first failed deletion when reading from stdin")
+	opts.AddConcurrencyFlag(cmd.Flags())
 	return cmd
 }
 
@@ -76,8 +88,13 @@ func runRm(ctx context.Context, streams command.Streams, hubClient *hub.Client,
 		return fmt.Errorf("invalid reference: repository not specified")
 	}
 
+	if hubClient.DryRun() {
+		fmt.Fprintln(streams.Out(), "Would delete", namedRef.Name())
+		return nil
+	}
+
 	if !opts.force {
-		_, count, err := hubClient.GetTags(namedRef.Name())
+		_, count, err := hubClient.GetTags(ctx, namedRef.Name())
 		if err != nil {
 			return err
 		}
@@ -101,9 +118,68 @@ func runRm(ctx context.Context, streams command.Streams, hubClient *hub.Client,
 		}
 	}
 
-	if err := hubClient.RemoveRepository(namedRef.Name()); err != nil {
+	if err := hubClient.RemoveRepository(ctx, namedRef.Name()); err != nil {
 		return err
 	}
 	fmt.Fprintln(streams.Out(), "Deleted", repository)
 	return nil
 }
+
+func runRmFromStdin(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts rmOptions) error {
+	if !opts.force && !hubClient.DryRun() {
+		return errors.New("--force is required when reading repositories from stdin")
+	}
+	repositories, err := readRepositories(streams.In())
+	if err != nil {
+		return err
+	}
+	if hubClient.DryRun() {
+		for _, repository := range repositories {
+			fmt.Fprintln(streams.Out(), "Would delete", repository)
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var firstErr error
+	err = opts.Delete(streams, hubClient, "repositories", len(repositories), func(report func(string, error)) error {
+		return hubClient.RemoveRepositories(ctx, repositories, func(repository string, err error) {
+			report(repository, err)
+			if err != nil && opts.stopOnError && firstErr == nil {
+				firstErr = err
+				cancel()
+			}
+		})
+	})
+	if firstErr != nil {
+		return firstErr
+	}
+	if errors.Is(err, context.Canceled) {
+		return errdef.ErrCanceled
+	}
+	return err
+}
+
+// readRepositories reads newline-delimited repository names, skipping empty
+// lines and comments
+func readRepositories(in io.Reader) ([]string, error) {
+	var repositories []string
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ref, err := reference.Parse(line)
+		if err != nil {
+			return nil, err
+		}
+		namedRef, ok := ref.(reference.Named)
+		if !ok {
+			return nil, fmt.Errorf("invalid reference %q: repository not specified", line)
+		}
+		repositories = append(repositories, namedRef.Name())
+	}
+	return repositories, scanner.Err()
+}
@@ -0,0 +1,133 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/streams"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+type testStreams struct {
+	in  *streams.In
+	out *streams.Out
+	err io.Writer
+}
+
+func (s testStreams) In() *streams.In   { return s.in }
+func (s testStreams) Out() *streams.Out { return s.out }
+func (s testStreams) Err() io.Writer    { return s.err }
+
+func newTestStreams(in string, out, err io.Writer) testStreams {
+	return testStreams{
+		in:  streams.NewIn(ioutil.NopCloser(strings.NewReader(in))),
+		out: streams.NewOut(out),
+		err: err,
+	}
+}
+
+// newTestHubClient returns a hub client sending its requests to a test server
+// answering with the given handler
+func newTestHubClient(t *testing.T, handler http.Handler) *hub.Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	assert.NilError(t, os.Setenv("DOCKER_HUB_API_URL", server.URL))
+	assert.NilError(t, os.Setenv("DOCKER_REGISTRY_URL", server.URL))
+	defer os.Unsetenv("DOCKER_HUB_API_URL")  //nolint:errcheck
+	defer os.Unsetenv("DOCKER_REGISTRY_URL") //nolint:errcheck
+	hubClient, err := hub.NewClient()
+	assert.NilError(t, err)
+	return hubClient
+}
+
+func TestReadRepositories(t *testing.T) {
+	repositories, err := readRepositories(strings.NewReader("jdoe/first\n\n# a comment\n  jdoe/second  \n"))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, repositories, []string{"jdoe/first", "jdoe/second"})
+
+	_, err = readRepositories(strings.NewReader("Invalid Name\n"))
+	assert.ErrorContains(t, err, "invalid reference format")
+}
+
+func TestRmFromStdin(t *testing.T) {
+	hubClient := newTestHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, "DELETE")
+		if r.URL.Path == "/v2/repositories/jdoe/missing/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	input := "jdoe/first\njdoe/missing\njdoe/second\n"
+
+	testCases := []struct {
+		name          string
+		opts          rmOptions
+		dryRun        bool
+		deleted       []string
+		expectedError string
+	}{
+		{
+			name:          "force is required",
+			expectedError: "--force is required when reading repositories from stdin",
+		},
+		{
+			name:   "dry run",
+			dryRun: true,
+		},
+		{
+			name:          "report each deletion",
+			opts:          rmOptions{force: true},
+			deleted:       []string{"jdoe/first", "jdoe/second"},
+			expectedError: "failed to remove 1 repositories: jdoe/missing",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+			errOut := bytes.NewBuffer(nil)
+			client := hubClient
+			if testCase.dryRun {
+				client = newTestHubClient(t, http.NotFoundHandler())
+				assert.NilError(t, client.Update(hub.WithDryRun(out)))
+			}
+			err := runRmFromStdin(context.Background(), newTestStreams(input, out, errOut), client, testCase.opts)
+			if testCase.expectedError != "" {
+				assert.ErrorContains(t, err, testCase.expectedError)
+			} else {
+				assert.NilError(t, err)
+			}
+			for _, repository := range testCase.deleted {
+				assert.Assert(t, strings.Contains(out.String(), "Deleted "+repository+"\n"))
+			}
+			if testCase.dryRun {
+				assert.Equal(t, out.String(), "Would delete jdoe/first\nWould delete jdoe/missing\nWould delete jdoe/second\n")
+			}
+		})
+	}
+}
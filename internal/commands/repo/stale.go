@@ -0,0 +1,208 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/hub-tool/internal/age"
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	staleName = "stale"
+)
+
+type staleOptions struct {
+	format.Option
+	olderThan string
+	tags      bool
+}
+
+// staleRepository is a repository with no push since the cutoff, or with
+// tags not pushed since the cutoff
+type staleRepository struct {
+	Repository string    `json:"repository"`
+	LastPushed time.Time `json:"last_pushed"`
+	LastPulled time.Time `json:"last_pulled"`
+	Pulls      int       `json:"pulls"`
+	Tags       int       `json:"tags"`
+	StaleTags  []hub.Tag `json:"stale_tags,omitempty"`
+}
+
+func newStaleCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	opts := staleOptions{olderThan: "1y"}
+	cmd := &cobra.Command{
+		Use:                   staleName + " [OPTIONS] [ORGANIZATION]",
+		Short:                 "List the repositories not pushed to for a while, to plan cleanups",
+		Args:                  cli.RequiresMaxArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, staleName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			account := hubClient.DefaultNamespace()
+			if len(args) > 0 {
+				account = args[0]
+			}
+			return runStale(cmd.Context(), streams, hubClient, opts, account)
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	cmd.Flags().StringVar(&opts.olderThan, "older-than", opts.olderThan, "Only list the repositories last pushed before this age, e.g. 1y, 90d or 4w")
+	cmd.Flags().BoolVar(&opts.tags, "tags", false, "List the stale tags of all the repositories instead")
+	return cmd
+}
+
+func runStale(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts staleOptions, account string) error {
+	maxAge, err := age.Parse(opts.olderThan)
+	if err != nil {
+		return err
+	}
+	cutoff := time.Now().Add(-maxAge)
+	if err := hubClient.Update(hub.WithAllElements()); err != nil {
+		return err
+	}
+	repos, _, err := hubClient.GetRepositories(ctx, account)
+	if err != nil {
+		return err
+	}
+
+	// The last push of a repository comes from its tags, the repository last
+	// update also changing with its settings
+	found := make([]*staleRepository, len(repos))
+	sem := make(chan struct{}, hubClient.Concurrency())
+	g, ctx := errgroup.WithContext(ctx)
+	for i := range repos {
+		i := i
+		g.Go(func() error {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			defer func() { <-sem }()
+			tags, _, err := hubClient.GetTags(ctx, repos[i].Name)
+			if err != nil {
+				return fmt.Errorf("%s: %w", repos[i].Name, err)
+			}
+			found[i] = findStale(repos[i], tags, cutoff, opts.tags)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	stale := staleList{}
+	for _, repo := range found {
+		if repo != nil {
+			stale = append(stale, *repo)
+		}
+	}
+	sort.SliceStable(stale, func(i, j int) bool { return stale[i].LastPushed.Before(stale[j].LastPushed) })
+	if opts.tags {
+		return opts.Print(streams.Out(), staleTagList(stale), printStaleTags)
+	}
+	return opts.Print(streams.Out(), stale, printStale)
+}
+
+// findStale returns the repository when it wasn't pushed to since the cutoff,
+// or with its stale tags when looking for tags, nil when nothing is stale
+func findStale(repo hub.Repository, tags []hub.Tag, cutoff time.Time, withTags bool) *staleRepository {
+	stale := staleRepository{
+		Repository: repo.Name,
+		LastPushed: repo.LastUpdated,
+		Pulls:      repo.PullCount,
+		Tags:       len(tags),
+	}
+	if len(tags) > 0 {
+		stale.LastPushed = time.Time{}
+	}
+	for _, tag := range tags {
+		pushed := lastPushed(tag)
+		if pushed.After(stale.LastPushed) {
+			stale.LastPushed = pushed
+		}
+		if tag.LastPulled.After(stale.LastPulled) {
+			stale.LastPulled = tag.LastPulled
+		}
+		if withTags && pushed.Before(cutoff) {
+			stale.StaleTags = append(stale.StaleTags, tag)
+		}
+	}
+	if (withTags && len(stale.StaleTags) > 0) || (!withTags && stale.LastPushed.Before(cutoff)) {
+		return &stale
+	}
+	return nil
+}
+
+// lastPushed returns when a tag was last pushed, falling back to its last
+// update for the tags pushed before Hub kept track of the pushes
+func lastPushed(tag hub.Tag) time.Time {
+	if tag.LastPushed.IsZero() {
+		return tag.LastUpdated
+	}
+	return tag.LastPushed
+}
+
+func printStale(out io.Writer, values interface{}) error {
+	headers, rows := values.(staleList).Table()
+	return format.PrintTable(out, headers, rows)
+}
+
+// staleList prints a row per stale repository in csv and tsv
+type staleList []staleRepository
+
+// Table returns the raw values of the stale repositories
+func (l staleList) Table() ([]string, [][]interface{}) {
+	rows := make([][]interface{}, len(l))
+	for i, r := range l {
+		rows[i] = []interface{}{r.Repository, r.LastPushed, r.LastPulled, r.Pulls, r.Tags}
+	}
+	return []string{"REPOSITORY", "LAST PUSHED", "LAST PULLED", "PULLS", "TAGS"}, rows
+}
+
+func printStaleTags(out io.Writer, values interface{}) error {
+	headers, rows := values.(staleTagList).Table()
+	return format.PrintTable(out, headers, rows)
+}
+
+// staleTagList prints a row per stale tag in csv and tsv
+type staleTagList []staleRepository
+
+// Table returns the raw values of the stale tags
+func (l staleTagList) Table() ([]string, [][]interface{}) {
+	var rows [][]interface{}
+	for _, r := range l {
+		for _, tag := range r.StaleTags {
+			rows = append(rows, []interface{}{tag.Name, lastPushed(tag), tag.LastPulled, r.Pulls})
+		}
+	}
+	return []string{"TAG", "LAST PUSHED", "LAST PULLED", "REPOSITORY PULLS"}, rows
+}
@@ -0,0 +1,50 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+func TestFindStale(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	cutoff := now.AddDate(-1, 0, 0)
+	repo := hub.Repository{Name: "myorg/app", PullCount: 42, LastUpdated: now}
+	old := hub.Tag{Name: "myorg/app:v1", LastPushed: now.AddDate(-2, 0, 0), LastPulled: now.AddDate(0, -1, 0)}
+	legacy := hub.Tag{Name: "myorg/app:v0", LastUpdated: now.AddDate(-3, 0, 0)}
+	recent := hub.Tag{Name: "myorg/app:v2", LastPushed: now.AddDate(0, -2, 0)}
+
+	// The repository last update doesn't count as a push
+	stale := findStale(repo, []hub.Tag{old, legacy}, cutoff, false)
+	assert.Assert(t, stale != nil)
+	assert.Equal(t, stale.LastPushed, old.LastPushed)
+	assert.Equal(t, stale.LastPulled, old.LastPulled)
+	assert.Equal(t, stale.Pulls, 42)
+	assert.Equal(t, stale.Tags, 2)
+
+	assert.Assert(t, findStale(repo, []hub.Tag{old, recent}, cutoff, false) == nil)
+
+	stale = findStale(repo, []hub.Tag{old, legacy, recent}, cutoff, true)
+	assert.Assert(t, stale != nil)
+	assert.DeepEqual(t, stale.StaleTags, []hub.Tag{old, legacy})
+	assert.Assert(t, findStale(repo, []hub.Tag{recent}, cutoff, true) == nil)
+}
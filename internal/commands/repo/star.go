@@ -0,0 +1,101 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	starName   = "star"
+	unstarName = "unstar"
+	starsName  = "stars"
+)
+
+func newStarCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   starName + " REPOSITORY",
+		Short:                 "Star a repository",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, starName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := hubClient.StarRepository(cmd.Context(), args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintln(streams.Out(), "Starred", args[0])
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newUnstarCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   unstarName + " REPOSITORY",
+		Short:                 "Remove the star of a repository",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, unstarName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := hubClient.UnstarRepository(cmd.Context(), args[0]); err != nil {
+				return err
+			}
+			fmt.Fprintln(streams.Out(), "Unstarred", args[0])
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newStarsCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts format.Option
+	cmd := &cobra.Command{
+		Use:                   starsName + " [OPTIONS] [USER|ORGANIZATION]",
+		Short:                 "List the repositories starred by a user or an organization",
+		Args:                  cli.RequiresMaxArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, starsName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			account := ""
+			if len(args) > 0 {
+				account = args[0]
+			}
+			repositories, err := hubClient.GetStarredRepositories(cmd.Context(), account)
+			if err != nil {
+				return err
+			}
+			return opts.Print(streams.Out(), repositories, printRepositories(len(repositories)))
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	return cmd
+}
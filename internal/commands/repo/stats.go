@@ -0,0 +1,171 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package repo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/distribution/reference"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/internal/stats"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	statsName = "stats"
+)
+
+type statsOptions struct {
+	format.Option
+	record  bool
+	history bool
+}
+
+func newStatsCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts statsOptions
+	cmd := &cobra.Command{
+		Use:   statsName + " [OPTIONS] REPOSITORY",
+		Short: "Print the pull and star counts of a repository, and their recorded history",
+		Long: `Print the pull and star counts of a repository, and their recorded history.
+
+Hub only returns the current counts, --record appends them to a local history
+in ~/.hub-tool/stats, for instance from a daily cron job, which --history charts.`,
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		Annotations: map[string]string{
+			"public": "true",
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, statsName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStats(cmd.Context(), streams, hubClient, opts, args[0])
+		},
+	}
+	cmd.Flags().BoolVar(&opts.record, "record", false, "Append the current counts to the local history of the repository")
+	cmd.Flags().BoolVar(&opts.history, "history", false, "Print the recorded history instead of the current counts")
+	opts.AddFormatFlag(cmd.Flags())
+	return cmd
+}
+
+func runStats(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts statsOptions, repository string) error {
+	named, err := reference.ParseNormalizedNamed(repository)
+	if err != nil {
+		return err
+	}
+	path, err := stats.Path(reference.Path(named))
+	if err != nil {
+		return err
+	}
+	samples, err := stats.Load(path)
+	if err != nil {
+		return err
+	}
+
+	if opts.history && !opts.record {
+		if len(samples) == 0 {
+			return fmt.Errorf("no history recorded for %s, run repo stats --record first", reference.FamiliarName(named))
+		}
+		return opts.Print(streams.Out(), sampleList(samples), printHistory)
+	}
+
+	repo, err := hubClient.GetRepository(ctx, reference.Path(named))
+	if err != nil {
+		return err
+	}
+	sample := stats.Sample{Time: time.Now().UTC(), Pulls: repo.PullCount, Stars: repo.StarCount}
+	if opts.record {
+		if err := stats.Append(path, sample); err != nil {
+			return err
+		}
+	}
+	if opts.history {
+		return opts.Print(streams.Out(), sampleList(append(samples, sample)), printHistory)
+	}
+	current := repositoryStats{Repository: reference.FamiliarName(named), Sample: sample}
+	if len(samples) > 0 {
+		last := samples[len(samples)-1]
+		current.Since = &last.Time
+		current.NewPulls = sample.Pulls - last.Pulls
+		current.NewStars = sample.Stars - last.Stars
+	}
+	return opts.Print(streams.Out(), current, printStats)
+}
+
+// repositoryStats are the current counts of a repository, with their change
+// since the last recorded sample if any
+type repositoryStats struct {
+	Repository string `json:"repository"`
+	stats.Sample
+	Since    *time.Time `json:"since,omitempty"`
+	NewPulls int        `json:"new_pulls,omitempty"`
+	NewStars int        `json:"new_stars,omitempty"`
+}
+
+func printStats(out io.Writer, value interface{}) error {
+	s := value.(repositoryStats)
+	fmt.Fprintf(out, ansi.Key("Repository:")+"\t%s\n", s.Repository)
+	fmt.Fprintf(out, ansi.Key("Pulls:")+"\t%d\n", s.Pulls)
+	fmt.Fprintf(out, ansi.Key("Stars:")+"\t%d\n", s.Stars)
+	if s.Since != nil {
+		since := fmt.Sprintf("Since %s:", s.Since.Local().Format("2006-01-02 15:04"))
+		fmt.Fprintf(out, ansi.Key(since)+"\t%+d pulls, %+d stars\n", s.NewPulls, s.NewStars)
+	}
+	return nil
+}
+
+func printHistory(out io.Writer, values interface{}) error {
+	samples := values.(sampleList)
+	headers, rows := samples.Table()
+	for i, row := range rows {
+		row[0] = samples[i].Time.Local().Format("2006-01-02 15:04")
+	}
+	if err := format.PrintTable(out, headers, rows); err != nil {
+		return err
+	}
+	pulls := make([]int, len(samples))
+	starCounts := make([]int, len(samples))
+	for i, sample := range samples {
+		pulls[i] = sample.Pulls
+		starCounts[i] = sample.Stars
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, ansi.Key("Pulls:")+"\t%s %+d\n", stats.Sparkline(pulls), last.Pulls-first.Pulls)
+	fmt.Fprintf(out, ansi.Key("Stars:")+"\t%s %+d\n", stats.Sparkline(starCounts), last.Stars-first.Stars)
+	return nil
+}
+
+// sampleList prints a row per recorded sample in csv and tsv
+type sampleList []stats.Sample
+
+// Table returns the raw values of the samples, times in RFC 3339
+func (l sampleList) Table() ([]string, [][]interface{}) {
+	rows := make([][]interface{}, len(l))
+	for i, s := range l {
+		rows[i] = []interface{}{s.Time.Format(time.RFC3339), s.Pulls, s.Stars}
+	}
+	return []string{"TIME", "PULLS", "STARS"}, rows
+}
@@ -0,0 +1,189 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/errdef"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	syncName = "sync"
+)
+
+type syncOptions struct {
+	force bool
+}
+
+// metadataChange lists what a sync changes on a repository of the destination
+type metadataChange struct {
+	metadata hub.RepositoryMetadata
+	create   bool
+	fields   []fieldChange
+}
+
+type fieldChange struct {
+	field string
+	from  string
+	to    string
+}
+
+func newSyncCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts syncOptions
+	cmd := &cobra.Command{
+		Use:                   syncName + " [OPTIONS] SOURCE DESTINATION",
+		Short:                 "Copy the metadata of the repositories of a namespace to another one",
+		Long:                  "Copy the description, overview and visibility of the repositories of a namespace to the repositories of the same name in another one, creating the missing ones. The images are not copied. The changes are printed before being applied.",
+		Example:               "  hub-tool repo sync myorg mynewerorg",
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, syncName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runSync(cmd.Context(), streams, hubClient, opts, args[0], args[1])
+			if errors.Is(err, errdef.ErrCanceled) {
+				return nil
+			}
+			return err
+		},
+	}
+	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Don't ask for confirmation")
+	return cmd
+}
+
+func runSync(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts syncOptions, source, destination string) error {
+	if source == destination {
+		return errors.New("source and destination namespaces must differ")
+	}
+	src, err := hubClient.ExportMetadata(ctx, source)
+	if err != nil {
+		return err
+	}
+	dst, err := hubClient.ExportMetadata(ctx, destination)
+	if err != nil {
+		return err
+	}
+	changes := diffMetadata(src.Repositories, dst.Repositories)
+	if len(changes) == 0 {
+		fmt.Fprintf(streams.Out(), "%s is in sync with %s\n", destination, source)
+		return nil
+	}
+	for _, change := range changes {
+		name := destination + "/" + change.metadata.Name
+		if change.create {
+			fmt.Fprintln(streams.Out(), ansi.Emphasise("+ "+name))
+			continue
+		}
+		fmt.Fprintln(streams.Out(), ansi.Warn("~ "+name))
+		for _, field := range change.fields {
+			fmt.Fprintf(streams.Out(), "    %s: %s -> %s\n", field.field, field.from, field.to)
+		}
+	}
+
+	if !opts.force {
+		fmt.Fprint(streams.Out(), ansi.Info(fmt.Sprintf("Apply these changes to %d repositories of %s? [y/N] ", len(changes), destination)))
+		userIn := make(chan string, 1)
+		go func() {
+			reader := bufio.NewReader(streams.In())
+			input, _ := reader.ReadString('\n')
+			userIn <- strings.ToLower(strings.TrimSpace(input))
+		}()
+		input := ""
+		select {
+		case <-ctx.Done():
+			return errdef.ErrCanceled
+		case input = <-userIn:
+		}
+		if input != "y" {
+			return errors.New("sync aborted")
+		}
+	}
+
+	failed := 0
+	for _, change := range changes {
+		name := destination + "/" + change.metadata.Name
+		if err := hubClient.ImportRepositoryMetadata(ctx, destination, change.metadata); err != nil {
+			fmt.Fprintln(streams.Err(), ansi.Error(fmt.Sprintf("Failed to sync %s: %s", name, err)))
+			failed++
+			continue
+		}
+		fmt.Fprintln(streams.Out(), "Synced", name)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to sync %d of %d repositories", failed, len(changes))
+	}
+	return nil
+}
+
+// diffMetadata returns the changes making the destination repositories match
+// the description, overview and visibility of the source ones, in the source
+// order. The categories and the team permissions are left out, as they
+// depend on the namespace.
+func diffMetadata(src, dst []hub.RepositoryMetadata) []metadataChange {
+	existing := map[string]hub.RepositoryMetadata{}
+	for _, repo := range dst {
+		existing[repo.Name] = repo
+	}
+	var changes []metadataChange
+	for _, repo := range src {
+		change := metadataChange{metadata: hub.RepositoryMetadata{
+			Name:        repo.Name,
+			Description: repo.Description,
+			Overview:    repo.Overview,
+			IsPrivate:   repo.IsPrivate,
+		}}
+		current, ok := existing[repo.Name]
+		if !ok {
+			change.create = true
+			changes = append(changes, change)
+			continue
+		}
+		if current.Description != repo.Description {
+			change.fields = append(change.fields, fieldChange{"description", fmt.Sprintf("%q", current.Description), fmt.Sprintf("%q", repo.Description)})
+		}
+		if current.Overview != repo.Overview {
+			change.fields = append(change.fields, fieldChange{"overview", fmt.Sprintf("%d characters", len(current.Overview)), fmt.Sprintf("%d characters", len(repo.Overview))})
+		}
+		if current.IsPrivate != repo.IsPrivate {
+			change.fields = append(change.fields, fieldChange{"visibility", visibility(current.IsPrivate), visibility(repo.IsPrivate)})
+		}
+		if len(change.fields) > 0 {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func visibility(private bool) string {
+	if private {
+		return "private"
+	}
+	return "public"
+}
@@ -0,0 +1,50 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+func TestDiffMetadata(t *testing.T) {
+	src := []hub.RepositoryMetadata{
+		{Name: "app", Description: "An app", Overview: "# App", IsPrivate: true, Categories: []string{"databases"}},
+		{Name: "api", Description: "An API", Overview: "# API"},
+		{Name: "web", Description: "A website"},
+	}
+	dst := []hub.RepositoryMetadata{
+		{Name: "api", Description: "The API", Overview: "# API v1", IsPrivate: true},
+		{Name: "web", Description: "A website", Permissions: []hub.TeamPermission{{Team: "devs", Permission: hub.ReadPermission}}},
+		{Name: "other"},
+	}
+	changes := diffMetadata(src, dst)
+	assert.Equal(t, len(changes), 2)
+
+	assert.Assert(t, changes[0].create)
+	assert.DeepEqual(t, changes[0].metadata, hub.RepositoryMetadata{Name: "app", Description: "An app", Overview: "# App", IsPrivate: true})
+
+	assert.Assert(t, !changes[1].create)
+	assert.Equal(t, changes[1].metadata.Name, "api")
+	assert.Equal(t, len(changes[1].fields), 3)
+	assert.Equal(t, changes[1].fields[0], fieldChange{"description", `"The API"`, `"An API"`})
+	assert.Equal(t, changes[1].fields[1], fieldChange{"overview", "8 characters", "5 characters"})
+	assert.Equal(t, changes[1].fields[2], fieldChange{"visibility", "private", "public"})
+}
@@ -0,0 +1,102 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/errdef"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	transferName = "transfer"
+)
+
+type transferOptions struct {
+	force bool
+}
+
+func newTransferCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts transferOptions
+	cmd := &cobra.Command{
+		Use:                   transferName + " [OPTIONS] REPOSITORY NAMESPACE",
+		Short:                 "Move a repository, with its tags, to another namespace",
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, transferName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runTransfer(cmd.Context(), streams, hubClient, opts, args[0], args[1])
+			if errors.Is(err, errdef.ErrCanceled) {
+				return nil
+			}
+			return err
+		},
+	}
+	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Don't ask for confirmation")
+	return cmd
+}
+
+func runTransfer(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts transferOptions, repository, namespace string) error {
+	from, name, err := splitRepositoryName(repository, "", hubClient.DefaultNamespace())
+	if err != nil {
+		return err
+	}
+	if from == namespace {
+		return fmt.Errorf("%s/%s is already in the %s namespace", from, name, namespace)
+	}
+	repository = from + "/" + name
+	if !opts.force {
+		fmt.Fprintln(streams.Out(), ansi.Warn(fmt.Sprintf("WARNING: You are about to move repository %q to %s/%s", repository, namespace, name)))
+		fmt.Fprintln(streams.Out(), ansi.Warn("         Its pulls will have to use the new name"))
+		fmt.Fprint(streams.Out(), ansi.Info("Are you sure you want to transfer this repository? [y/N] "))
+		userIn := make(chan string, 1)
+		go func() {
+			reader := bufio.NewReader(streams.In())
+			input, _ := reader.ReadString('\n')
+			userIn <- strings.ToLower(strings.TrimSpace(input))
+		}()
+		input := ""
+		select {
+		case <-ctx.Done():
+			return errdef.ErrCanceled
+		case input = <-userIn:
+		}
+		if input != "y" {
+			return errors.New("transfer aborted")
+		}
+	}
+
+	moved, err := hubClient.TransferRepository(ctx, repository, namespace)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(streams.Out(), "%s moved to %s\n", repository, moved.Name)
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	updateName = "update"
+)
+
+type updateOptions struct {
+	format.Option
+	description string
+	readmeFile  string
+}
+
+func newUpdateCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts updateOptions
+	cmd := &cobra.Command{
+		Use:                   updateName + " [OPTIONS] REPOSITORY",
+		Short:                 "Update the description and the overview of a repository",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, updateName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var updates hub.UpdateRepositoryOptions
+			if cmd.Flags().Changed("description") {
+				updates.Description = &opts.description
+			}
+			if opts.readmeFile != "" {
+				readme, err := ioutil.ReadFile(opts.readmeFile)
+				if err != nil {
+					return err
+				}
+				overview := string(readme)
+				updates.FullDescription = &overview
+			}
+			if updates.Description == nil && updates.FullDescription == nil {
+				return errors.New("nothing to update, use --description or --readme-file")
+			}
+			return runUpdate(cmd.Context(), streams, hubClient, opts, updates, args[0])
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	cmd.Flags().StringVar(&opts.description, "description", "", "Short description of the repository")
+	cmd.Flags().StringVar(&opts.readmeFile, "readme-file", "", "Markdown file to use as the repository overview")
+	return cmd
+}
+
+func runUpdate(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts updateOptions, updates hub.UpdateRepositoryOptions, repository string) error {
+	repo, err := hubClient.UpdateRepository(ctx, repository, updates)
+	if err != nil {
+		return err
+	}
+	return opts.Print(streams.Out(), repo, func(out io.Writer, value interface{}) error {
+		fmt.Fprintln(out, "Updated", value.(*hub.Repository).Name)
+		return nil
+	})
+}
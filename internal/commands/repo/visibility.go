@@ -0,0 +1,73 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	setVisibilityName = "set-visibility"
+)
+
+type setVisibilityOptions struct {
+	private bool
+	public  bool
+}
+
+func newSetVisibilityCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts setVisibilityOptions
+	cmd := &cobra.Command{
+		Use:                   setVisibilityName + " REPOSITORY --private|--public",
+		Short:                 "Make a repository private or public",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, setVisibilityName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.private == opts.public {
+				return errors.New("exactly one of --private or --public is required")
+			}
+			return runSetVisibility(cmd.Context(), streams, hubClient, opts.private, args[0])
+		},
+	}
+	cmd.Flags().BoolVar(&opts.private, "private", false, "Make the repository private")
+	cmd.Flags().BoolVar(&opts.public, "public", false, "Make the repository public")
+	return cmd
+}
+
+func runSetVisibility(ctx context.Context, streams command.Streams, hubClient *hub.Client, private bool, repository string) error {
+	if err := hubClient.SetRepositoryPrivacy(ctx, repository, private); err != nil {
+		return err
+	}
+	visibility := "public"
+	if private {
+		visibility = "private"
+	}
+	fmt.Fprintf(streams.Out(), "%s is now %s\n", repository, visibility)
+	return nil
+}
@@ -0,0 +1,59 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSetVisibility(t *testing.T) {
+	hubClient := newTestHubClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method+" "+r.URL.Path, "POST /v2/repositories/jdoe/app/privacy/")
+	}))
+
+	testCases := []struct {
+		args     []string
+		expected string
+		err      string
+	}{
+		{args: []string{"jdoe/app", "--private"}, expected: "jdoe/app is now private\n"},
+		{args: []string{"jdoe/app", "--public"}, expected: "jdoe/app is now public\n"},
+		{args: []string{"jdoe/app"}, err: "exactly one of --private or --public is required"},
+		{args: []string{"jdoe/app", "--private", "--public"}, err: "exactly one of --private or --public is required"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expected+tc.err, func(t *testing.T) {
+			out := bytes.NewBuffer(nil)
+			cmd := newSetVisibilityCmd(newTestStreams("", out, out), hubClient, "repo")
+			cmd.PreRun = nil
+			cmd.SilenceUsage = true
+			cmd.SilenceErrors = true
+			cmd.SetArgs(tc.args)
+			err := cmd.Execute()
+			if tc.err != "" {
+				assert.Error(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, out.String(), tc.expected)
+		})
+	}
+}
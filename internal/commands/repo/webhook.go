@@ -0,0 +1,187 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package repo
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	webhookName     = "webhook"
+	webhookTestName = "test"
+)
+
+func newWebhookCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmdName := parent + " " + webhookName
+	cmd := &cobra.Command{
+		Use:                   webhookName,
+		Short:                 "Manage the webhooks of a repository",
+		Args:                  cli.NoArgs,
+		DisableFlagsInUseLine: true,
+		RunE:                  command.ShowHelp(streams.Err()),
+	}
+	cmd.AddCommand(
+		newWebhookListCmd(streams, hubClient, cmdName),
+		newWebhookCreateCmd(streams, hubClient, cmdName),
+		newWebhookRmCmd(streams, hubClient, cmdName),
+		newWebhookTestCmd(streams, hubClient, cmdName),
+	)
+	return cmd
+}
+
+func newWebhookListCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts format.Option
+	cmd := &cobra.Command{
+		Use:                   listName + " [OPTIONS] REPOSITORY",
+		Aliases:               []string{"list"},
+		Short:                 "List the webhooks of a repository",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, listName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			webhooks, err := hubClient.GetWebhooks(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			return opts.Print(streams.Out(), webhookList(webhooks), printWebhooks)
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	return cmd
+}
+
+func newWebhookCreateCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts format.Option
+	cmd := &cobra.Command{
+		Use:                   createName + " [OPTIONS] REPOSITORY NAME URL",
+		Short:                 "Add a webhook to a repository",
+		Args:                  cli.ExactArgs(3),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, createName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			webhook, err := hubClient.CreateWebhook(cmd.Context(), args[0], args[1], args[2])
+			if err != nil {
+				return err
+			}
+			return opts.Print(streams.Out(), webhook, func(out io.Writer, value interface{}) error {
+				fmt.Fprintf(out, "Created webhook %q\n", webhook.Slug)
+				return nil
+			})
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	return cmd
+}
+
+func newWebhookRmCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   rmName + " REPOSITORY WEBHOOK",
+		Short:                 "Remove a webhook from a repository",
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, rmName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := hubClient.RemoveWebhook(cmd.Context(), args[0], args[1]); err != nil {
+				return err
+			}
+			fmt.Fprintln(streams.Out(), "Deleted webhook", args[1])
+			return nil
+		},
+	}
+	return cmd
+}
+
+type webhookTestOptions struct {
+	format.Option
+	tag string
+}
+
+func newWebhookTestCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts webhookTestOptions
+	cmd := &cobra.Command{
+		Use:   webhookTestName + " [OPTIONS] REPOSITORY WEBHOOK",
+		Short: "Send a test push payload to a webhook of a repository",
+		Long: `Send a test push payload to a webhook of a repository.
+
+The payload is the one Hub sends when the tag is pushed, but it is sent by
+hub-tool as Hub can't fire a webhook on demand, and it has no callback URL. The
+command fails when the receiver doesn't respond with a 2xx status.`,
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, webhookTestName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := hubClient.TestWebhook(cmd.Context(), args[0], args[1], opts.tag)
+			if err != nil {
+				return err
+			}
+			if hubClient.DryRun() {
+				return nil
+			}
+			if err := opts.Print(streams.Out(), result, printWebhookTest); err != nil {
+				return err
+			}
+			if result.StatusCode < 200 || result.StatusCode >= 300 {
+				return fmt.Errorf("webhook %s responded with %q", args[1], result.Status)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&opts.tag, "tag", "latest", "Tag of the push described by the payload")
+	opts.AddFormatFlag(cmd.Flags())
+	return cmd
+}
+
+func printWebhookTest(out io.Writer, value interface{}) error {
+	result := value.(*hub.WebhookTestResult)
+	fmt.Fprintf(out, "Sent a test payload to %s: %s in %s\n", result.HookURL, result.Status, result.Duration.Round(time.Millisecond))
+	return nil
+}
+
+func printWebhooks(out io.Writer, values interface{}) error {
+	headers, rows := values.(webhookList).Table()
+	return format.PrintTable(out, headers, rows)
+}
+
+// webhookList prints a row per webhook in csv and tsv
+type webhookList []hub.Webhook
+
+// Table returns the raw values of the webhooks
+func (l webhookList) Table() ([]string, [][]interface{}) {
+	rows := make([][]interface{}, len(l))
+	for i, w := range l {
+		rows[i] = []interface{}{w.Slug, w.Name, w.HookURL, w.CreatedAt}
+	}
+	return []string{"WEBHOOK", "NAME", "URL", "CREATED"}, rows
+}
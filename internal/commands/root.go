@@ -19,6 +19,11 @@ package commands
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -26,25 +31,44 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/docker/hub-tool/internal"
-	"github.com/docker/hub-tool/internal/ansi"
 	"github.com/docker/hub-tool/internal/commands/account"
 	"github.com/docker/hub-tool/internal/commands/org"
+	"github.com/docker/hub-tool/internal/commands/policy"
 	"github.com/docker/hub-tool/internal/commands/repo"
 	"github.com/docker/hub-tool/internal/commands/tag"
 	"github.com/docker/hub-tool/internal/commands/token"
+	"github.com/docker/hub-tool/internal/config"
 	"github.com/docker/hub-tool/internal/credentials"
-	"github.com/docker/hub-tool/internal/hub"
+	"github.com/docker/hub-tool/internal/format"
 	"github.com/docker/hub-tool/internal/login"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 type options struct {
 	showVersion bool
 	trace       bool
 	verbose     bool
+	retries     int
+	cacheTTL    time.Duration
+	noCache     bool
+	instance    string
+	caCert      string
+	insecure    bool
+	account     string
+	dryRun      bool
+	logLevel    string
+	logFormat   string
 }
 
+const (
+	// instanceEnvVar sets the Hub instance when --instance isn't given
+	instanceEnvVar = "HUB_INSTANCE"
+	// accountEnvVar sets the account when --account isn't given
+	accountEnvVar = "HUB_ACCOUNT"
+)
+
 var (
-	anonCmds = []string{"version", "help", "login", "logout"}
+	anonCmds = []string{"version", "help", "login", "logout", cacheName, cacheClearName, searchName, officialName, publisherName}
 )
 
 // NewRootCmd returns the main command
@@ -56,17 +80,41 @@ func NewRootCmd(streams command.Streams, hubClient *hub.Client, store credential
 		Long:                  `A tool to manage your Docker Hub images`,
 		Annotations:           map[string]string{},
 		SilenceUsage:          true,
+		SilenceErrors:         true,
 		DisableFlagsInUseLine: true,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			if flags.trace {
-				log.SetLevel(log.TraceLevel)
-			} else if flags.verbose {
-				log.SetLevel(log.DebugLevel)
+			if err := setupConfig(cmd, hubClient); err != nil {
+				return err
+			}
+			if err := setupLogging(cmd, streams, hubClient, flags); err != nil {
+				return err
+			}
+			if err := hubClient.Update(hub.WithRetries(flags.retries), hub.WithCACert(flags.caCert)); err != nil {
+				return err
+			}
+			if flags.insecure {
+				if err := hubClient.Update(hub.WithInsecureSkipVerify()); err != nil {
+					return err
+				}
+			}
+			if flags.dryRun {
+				if err := hubClient.Update(hub.WithDryRun(streams.Out())); err != nil {
+					return err
+				}
+			}
+			if err := setupCache(hubClient, flags); err != nil {
+				return err
+			}
+			if err := setupInstance(hubClient, store, flags.instance); err != nil {
+				return err
+			}
+			if err := setupProfile(store, flags.account); err != nil {
+				return err
 			}
 			if flags.showVersion {
 				return nil
 			}
-			if contains(anonCmds, cmd.Name()) {
+			if contains(anonCmds, cmd.Name()) || cmd.Annotations["anonymous"] == "true" {
 				return nil
 			}
 
@@ -75,9 +123,19 @@ func NewRootCmd(streams command.Streams, hubClient *hub.Client, store credential
 				return err
 			}
 
+			if ac.Username == "" && cmd.Annotations["public"] == "true" {
+				// Browse the public content anonymously
+				return nil
+			}
 			if ac.Username == "" {
-				log.Fatal(ansi.Error(`You need to be logged in to Docker Hub to use this tool.
-Please login to Docker Hub using the "hub-tool login" command.`))
+				return errNotLoggedIn
+			}
+			if err := hubClient.Update(
+				hub.WithHubAccount(ac.Username),
+				hub.WithPassword(ac.Password),
+				hub.WithRefreshToken(ac.RefreshToken),
+				hub.WithHubToken(ac.Token)); err != nil {
+				return err
 			}
 
 			if cmd.Annotations["sudo"] == "true" {
@@ -101,23 +159,163 @@ Please login to Docker Hub using the "hub-tool login" command.`))
 		},
 	}
 	cmd.Flags().BoolVar(&flags.showVersion, "version", false, "Display the version of this tool")
-	cmd.PersistentFlags().BoolVar(&flags.verbose, "verbose", false, "Print logs")
+	cmd.PersistentFlags().BoolVar(&flags.verbose, "verbose", false, "Print logs, with the method, URL, status, timing and rate limits of each Hub request")
+	cmd.PersistentFlags().BoolVar(&flags.verbose, "debug", false, "Same as --verbose")
 	cmd.PersistentFlags().BoolVar(&flags.trace, "trace", false, "Print trace logs")
 	_ = cmd.PersistentFlags().MarkHidden("trace")
+	cmd.PersistentFlags().StringVar(&flags.logLevel, "log-level", "warn", `Minimum level of the logs printed on the standard error ("trace", "debug", "info", "warn" or "error"), "debug" with --verbose`)
+	cmd.PersistentFlags().StringVar(&flags.logFormat, "log-format", "text", `Format of the logs ("text" or "json")`)
+	cmd.PersistentFlags().IntVar(&flags.retries, "retries", 3, "Number of times a request failing with a transient Hub error is retried")
+	cmd.PersistentFlags().DurationVar(&flags.cacheTTL, "cache-ttl", defaultCacheTTL(), "Serve repeated read requests from a local cache for this duration, also set by "+cacheTTLEnvVar)
+	cmd.PersistentFlags().BoolVar(&flags.noCache, "no-cache", false, "Don't use the local cache of Hub responses")
+	cmd.PersistentFlags().StringVar(&flags.caCert, "cacert", "", "Trust the certificate authorities of this PEM file, for TLS intercepting proxies")
+	cmd.PersistentFlags().BoolVar(&flags.insecure, "insecure", false, "Don't verify the TLS certificates of the Hub, only use for testing")
+	cmd.PersistentFlags().BoolVar(&flags.dryRun, "dry-run", false, "Print the requests which would change something on the Hub instead of sending them")
+	cmd.PersistentFlags().StringVar(&flags.account, "account", os.Getenv(accountEnvVar), "Use the stored credentials of this account instead of the current one, also set by "+accountEnvVar)
+	cmd.PersistentFlags().StringVar(&flags.instance, "instance", os.Getenv(instanceEnvVar), "Base URL of a Hub compatible API to use instead of Docker Hub, also set by "+instanceEnvVar)
 
 	cmd.AddCommand(
 		newLoginCmd(streams, store, hubClient),
 		newLogoutCmd(streams, store),
-		account.NewAccountCmd(streams, hubClient),
+		account.NewAccountCmd(streams, hubClient, store),
 		token.NewTokenCmd(streams, hubClient),
 		org.NewOrgCmd(streams, hubClient),
 		repo.NewRepoCmd(streams, hubClient),
 		tag.NewTagCmd(streams, hubClient),
+		policy.NewPolicyCmd(streams, hubClient),
 		newVersionCmd(streams),
+		newCacheCmd(streams),
+		newSearchCmd(streams, hubClient),
+		newOfficialCmd(streams, hubClient),
+		newPublisherCmd(streams, hubClient),
+		newBrowseCmd(streams, hubClient),
+		newConfigCmd(streams),
+		newDownloadCmd(streams, hubClient),
+		newUploadCmd(streams, hubClient),
+		newServeMetricsCmd(streams, hubClient),
 	)
 	return cmd
 }
 
+// setupLogging configures the level and the format of the logs, and logs the
+// requests sent to the Hub from the debug level
+func setupLogging(cmd *cobra.Command, streams command.Streams, hubClient *hub.Client, flags options) error {
+	level, err := log.ParseLevel(flags.logLevel)
+	if err != nil {
+		return fmt.Errorf("invalid --log-level %q", flags.logLevel)
+	}
+	if !cmd.Flags().Changed("log-level") {
+		if flags.trace {
+			level = log.TraceLevel
+		} else if flags.verbose {
+			level = log.DebugLevel
+		}
+	}
+	log.SetLevel(level)
+	log.SetOutput(streams.Err())
+	switch flags.logFormat {
+	case "text":
+		log.SetFormatter(&log.TextFormatter{})
+	case "json":
+		log.SetFormatter(&log.JSONFormatter{})
+	default:
+		return fmt.Errorf("invalid --log-format %q, must be text or json", flags.logFormat)
+	}
+	if !log.IsLevelEnabled(log.DebugLevel) {
+		return nil
+	}
+	return hubClient.Update(hub.WithRequestLogger(func(l hub.RequestLog) {
+		fields := log.Fields{
+			"method":      l.Method,
+			"url":         l.URL,
+			"duration_ms": l.Duration.Milliseconds(),
+		}
+		if l.Status != "" {
+			fields["status"] = l.Status
+		}
+		for name := range l.RateLimits {
+			fields[strings.ToLower(name)] = l.RateLimits.Get(name)
+		}
+		entry := log.WithFields(fields)
+		if l.Err != nil {
+			entry = entry.WithError(l.Err)
+		}
+		entry.Debug(l.String())
+		entry.Tracef("Request headers: %v", l.Header)
+	}))
+}
+
+func setupCache(hubClient *hub.Client, flags options) error {
+	if flags.noCache || flags.cacheTTL == 0 {
+		return hubClient.Update(hub.WithCache("", 0))
+	}
+	dir, err := cacheDir()
+	if err != nil {
+		return err
+	}
+	return hubClient.Update(hub.WithCache(dir, flags.cacheTTL))
+}
+
+// setupConfig applies the defaults of the configuration file to the flags not
+// given on the command line. The configuration commands ignore it, so that an
+// invalid file can be fixed with them.
+func setupConfig(cmd *cobra.Command, hubClient *hub.Client) error {
+	if cmd.HasParent() && cmd.Parent().Name() == configName {
+		return nil
+	}
+	_, cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	for key, value := range cfg {
+		switch key {
+		case config.NamespaceKey:
+			if err := hubClient.Update(hub.WithDefaultNamespace(value)); err != nil {
+				return err
+			}
+			continue
+		case config.ConcurrencyKey:
+			concurrency, _ := strconv.Atoi(value)
+			if err := hubClient.Update(hub.WithConcurrency(concurrency)); err != nil {
+				return err
+			}
+		}
+		if flag := cmd.Flags().Lookup(key); flag != nil && !flag.Changed {
+			if err := flag.Value.Set(value); err != nil {
+				return fmt.Errorf("invalid %s %q in the configuration file: %s", key, value, err)
+			}
+		}
+	}
+	return nil
+}
+
+// setupInstance points the client to the given Hub instance, keeping its
+// credentials apart from the Docker Hub ones
+func setupInstance(hubClient *hub.Client, store credentials.Store, instance string) error {
+	if instance == "" {
+		return nil
+	}
+	u, err := hub.ParseInstanceURL(instance)
+	if err != nil {
+		return err
+	}
+	store.SetInstance(u.Host)
+	return hubClient.Update(hub.WithInstance(instance))
+}
+
+// setupProfile makes the store use the credentials of the given account, or
+// of the current one
+func setupProfile(store credentials.Store, account string) error {
+	if account == "" {
+		var err error
+		if account, err = store.CurrentProfile(); err != nil {
+			return err
+		}
+	}
+	store.SetProfile(account)
+	return nil
+}
+
 func contains(haystack []string, needle string) bool {
 	for _, v := range haystack {
 		if needle == v {
@@ -127,16 +325,27 @@ func contains(haystack []string, needle string) bool {
 	return false
 }
 
+type versionInfo struct {
+	Version   string
+	GitCommit string
+}
+
 func newVersionCmd(streams command.Streams) *cobra.Command {
-	return &cobra.Command{
+	var opts format.Option
+	cmd := &cobra.Command{
 		Use:   "version",
 		Short: "Version information about this tool",
 		Args:  cli.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			_, err := fmt.Fprintf(streams.Out(), "Version:    %s\nGit commit: %s\n", internal.Version, internal.GitCommit)
-			return err
+			info := versionInfo{Version: internal.Version, GitCommit: internal.GitCommit}
+			return opts.Print(streams.Out(), info, func(out io.Writer, _ interface{}) error {
+				_, err := fmt.Fprintf(out, "Version:    %s\nGit commit: %s\n", info.Version, info.GitCommit)
+				return err
+			})
 		},
 	}
+	opts.AddFormatFlag(cmd.Flags())
+	return cmd
 }
 
 func tryLogin(ctx context.Context, streams command.Streams, hubClient *hub.Client, ac *credentials.Auth, store credentials.Store) error {
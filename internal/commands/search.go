@@ -0,0 +1,160 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/format/tabwriter"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	searchName = "search"
+)
+
+var (
+	searchColumns = []searchColumn{
+		{"NAME", func(r hub.SearchResult) (string, int) { return r.Name, len(r.Name) }},
+		{"DESCRIPTION", func(r hub.SearchResult) (string, int) { return r.Description, len(r.Description) }},
+		{"STARS", func(r hub.SearchResult) (string, int) {
+			s := fmt.Sprintf("%v", r.StarCount)
+			return s, len(s)
+		}},
+		{"PULLS", func(r hub.SearchResult) (string, int) { return r.PullCount, len(r.PullCount) }},
+		{"BADGE", func(r hub.SearchResult) (string, int) {
+			s := searchBadge(r)
+			return s, len(s)
+		}},
+	}
+)
+
+type searchColumn struct {
+	header string
+	value  func(r hub.SearchResult) (string, int)
+}
+
+type searchOptions struct {
+	format.Option
+	official   bool
+	verified   bool
+	categories []string
+	minStars   int
+	limit      int
+}
+
+func newSearchCmd(streams command.Streams, hubClient *hub.Client) *cobra.Command {
+	var opts searchOptions
+	cmd := &cobra.Command{
+		Use:                   searchName + " [OPTIONS] QUERY",
+		Short:                 "Search for images on Docker Hub",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send("root", searchName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSearch(cmd.Context(), streams, hubClient, opts, args[0])
+		},
+	}
+	cmd.Flags().BoolVar(&opts.official, "official", false, "Only show the Docker official images")
+	cmd.Flags().BoolVar(&opts.verified, "verified-publisher", false, "Only show the images of verified publishers")
+	cmd.Flags().StringSliceVar(&opts.categories, "category", nil, "Only show the images of this category (e.g.: database)")
+	cmd.Flags().IntVar(&opts.minStars, "min-stars", 0, "Only show the images with at least this number of stars")
+	cmd.Flags().IntVar(&opts.limit, "limit", 25, "Maximum number of images searched")
+	opts.AddFormatFlag(cmd.Flags())
+	return cmd
+}
+
+func runSearch(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts searchOptions, query string) error {
+	var filters []hub.SearchFilter
+	if opts.official {
+		filters = append(filters, hub.WithOfficialImages())
+	}
+	if opts.verified {
+		filters = append(filters, hub.WithVerifiedPublishers())
+	}
+	for _, category := range opts.categories {
+		filters = append(filters, hub.WithCategory(category))
+	}
+	if opts.minStars > 0 {
+		filters = append(filters, hub.WithMinStars(opts.minStars))
+	}
+	results, total, err := hubClient.Search(ctx, query, opts.limit, filters...)
+	if err != nil {
+		return err
+	}
+	return opts.Print(streams.Out(), searchResultList(results), printSearchResults(total))
+}
+
+func printSearchResults(total int) format.PrettyPrinter {
+	return func(out io.Writer, values interface{}) error {
+		results := values.(searchResultList)
+		tw := tabwriter.New(out, "    ")
+		for _, column := range searchColumns {
+			tw.Column(ansi.Header(column.header), len(column.header))
+		}
+		tw.Line()
+		for _, result := range results {
+			for _, column := range searchColumns {
+				value, width := column.value(result)
+				tw.Column(value, width)
+			}
+			tw.Line()
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		if len(results) < total {
+			fmt.Fprintln(out, ansi.Info(fmt.Sprintf("%v/%v listed, use --limit to search more images", len(results), total)))
+		}
+		return nil
+	}
+}
+
+func searchBadge(r hub.SearchResult) string {
+	var badges []string
+	if r.Official {
+		badges = append(badges, "official")
+	}
+	if r.VerifiedPublisher {
+		badges = append(badges, "verified publisher")
+	}
+	return strings.Join(badges, ", ")
+}
+
+// searchResultList prints a row per result in csv and tsv
+type searchResultList []hub.SearchResult
+
+// Table returns the raw values of the results
+func (l searchResultList) Table() ([]string, [][]interface{}) {
+	rows := make([][]interface{}, len(l))
+	for i, r := range l {
+		rows[i] = []interface{}{r.Name, r.Description, r.StarCount, r.PullCount, searchBadge(r)}
+	}
+	return []string{"NAME", "DESCRIPTION", "STARS", "PULLS", "BADGE"}, rows
+}
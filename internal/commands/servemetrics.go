@@ -0,0 +1,102 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package commands
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/exporter"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	serveMetricsName = "serve-metrics"
+)
+
+type serveMetricsOptions struct {
+	accounts []string
+	listen   string
+	interval time.Duration
+}
+
+func newServeMetricsCmd(streams command.Streams, hubClient *hub.Client) *cobra.Command {
+	var opts serveMetricsOptions
+	cmd := &cobra.Command{
+		Use:   serveMetricsName + " [OPTIONS]",
+		Short: "Expose the pull counts, rate limits and quotas of accounts as Prometheus metrics",
+		Long: `Expose the pull counts, rate limits and quotas of accounts as Prometheus metrics.
+
+The metrics are scraped from the Hub at each interval and served on /metrics,
+until interrupted. The quotas are only exposed for the organizations and the
+current user.`,
+		Args:                  cli.NoArgs,
+		DisableFlagsInUseLine: true,
+		Annotations: map[string]string{
+			"public": "true",
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send("root", serveMetricsName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runServeMetrics(cmd.Context(), streams, hubClient, opts)
+		},
+	}
+	cmd.Flags().StringSliceVar(&opts.accounts, "accounts", nil, "Accounts whose repositories are scraped (default the current namespace)")
+	cmd.Flags().StringVar(&opts.listen, "listen", ":9100", "Address to serve the metrics on")
+	cmd.Flags().DurationVar(&opts.interval, "interval", 5*time.Minute, "Interval between two scrapes of the Hub")
+	return cmd
+}
+
+func runServeMetrics(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts serveMetricsOptions) error {
+	if opts.interval < time.Minute {
+		return fmt.Errorf("invalid interval %s, must be at least 1m not to hit the Hub rate limits", opts.interval)
+	}
+	accounts := opts.accounts
+	if len(accounts) == 0 {
+		if hubClient.DefaultNamespace() == "" {
+			return fmt.Errorf("no account to scrape, use --accounts")
+		}
+		accounts = []string{hubClient.DefaultNamespace()}
+	}
+	// Each scrape must return fresh values
+	if err := hubClient.Update(hub.WithCache("", 0), hub.WithAllElements()); err != nil {
+		return err
+	}
+
+	e := exporter.New(hubClient, accounts)
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", e)
+	server := &http.Server{Addr: opts.listen, Handler: mux}
+	go e.Run(ctx, opts.interval)
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	fmt.Fprintf(streams.Out(), "Serving the metrics of %v on %s/metrics\n", accounts, opts.listen)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
@@ -21,7 +21,7 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/spf13/cobra"
 
-	"github.com/docker/hub-tool/internal/hub"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
@@ -37,9 +37,16 @@ func NewTagCmd(streams command.Streams, hubClient *hub.Client) *cobra.Command {
 		RunE:  command.ShowHelp(streams.Err()),
 	}
 	cmd.AddCommand(
+		newCopyCmd(streams, hubClient, tagName),
+		newDiffCmd(streams, hubClient, tagName),
 		newInspectCmd(streams, hubClient, tagName),
 		newListCmd(streams, hubClient, tagName),
+		newProvenanceCmd(streams, hubClient, tagName),
+		newPruneCmd(streams, hubClient, tagName),
 		newRmCmd(streams, hubClient, tagName),
+		newSbomCmd(streams, hubClient, tagName),
+		newScanReportCmd(streams, hubClient, tagName),
+		newVerifyCmd(streams, hubClient, tagName),
 	)
 	return cmd
 }
@@ -0,0 +1,108 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package tag
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/distribution/reference"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/internal/registry"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	copyName = "copy"
+)
+
+func newCopyCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   copyName + " REPOSITORY:TAG|REPOSITORY@DIGEST REPOSITORY:TAG",
+		Aliases:               []string{"cp"},
+		Short:                 "Tag an image of a repository with another tag, without pulling it",
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, copyName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCopy(cmd.Context(), streams, hubClient, args[0], args[1])
+		},
+	}
+	return cmd
+}
+
+// runCopy pushes the manifest of the source image under the target tag. Only
+// the manifest is sent, the registry already having the layers, which is why
+// both tags must be in the same repository.
+func runCopy(ctx context.Context, streams command.Streams, hubClient *hub.Client, source, target string) error {
+	src, err := reference.ParseNormalizedNamed(source)
+	if err != nil {
+		return err
+	}
+	src = reference.TagNameOnly(src)
+	dstRef, err := reference.ParseNormalizedNamed(target)
+	if err != nil {
+		return err
+	}
+	dst, ok := dstRef.(reference.NamedTagged)
+	if !ok {
+		return fmt.Errorf("invalid reference %q: tag must be specified", target)
+	}
+	if src.Name() != dst.Name() {
+		return fmt.Errorf("can't copy %q to %q: tags can only be copied within a repository", reference.FamiliarString(src), reference.FamiliarString(dst))
+	}
+
+	resolver := registry.NewResolver(hubClient)
+	fullName, descriptor, err := resolver.Resolve(ctx, src.String())
+	if err != nil {
+		return err
+	}
+	raw, err := registry.GetBlob(ctx, resolver, fullName, descriptor)
+	if err != nil {
+		return err
+	}
+	pusher, err := resolver.Pusher(ctx, dst.String())
+	if err != nil {
+		return err
+	}
+	writer, err := pusher.Push(ctx, descriptor)
+	if err != nil && !errdefs.IsAlreadyExists(err) {
+		return err
+	}
+	if err == nil {
+		defer writer.Close() //nolint:errcheck
+		if _, err := writer.Write(raw); err != nil {
+			return err
+		}
+		if err := writer.Commit(ctx, descriptor.Size, descriptor.Digest); err != nil {
+			return err
+		}
+	}
+	if hubClient.DryRun() {
+		fmt.Fprintf(streams.Out(), "Would copy %s to %s\n", reference.FamiliarString(src), reference.FamiliarString(dst))
+		return nil
+	}
+	fmt.Fprintf(streams.Out(), "Copied %s to %s\n", reference.FamiliarString(src), reference.FamiliarString(dst))
+	return nil
+}
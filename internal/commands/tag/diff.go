@@ -0,0 +1,288 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package tag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/platforms"
+	"github.com/containerd/containerd/remotes"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/distribution/reference"
+	"github.com/docker/go-units"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/internal/registry"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	diffName = "diff"
+)
+
+type diffOptions struct {
+	format.Option
+	platform string
+}
+
+// imageDiff holds the differences between two images, for a platform when
+// they are multi-platform images
+type imageDiff struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Platform string `json:"platform,omitempty"`
+	// AddedPlatforms and RemovedPlatforms are only set when comparing
+	// multi-platform images
+	AddedPlatforms   []string             `json:"added_platforms,omitempty"`
+	RemovedPlatforms []string             `json:"removed_platforms,omitempty"`
+	AddedLayers      []ocispec.Descriptor `json:"added_layers"`
+	RemovedLayers    []ocispec.Descriptor `json:"removed_layers"`
+	SharedLayers     int                  `json:"shared_layers"`
+	FromSize         int64                `json:"from_size"`
+	ToSize           int64                `json:"to_size"`
+	Labels           []labelChange        `json:"labels"`
+}
+
+// labelChange is a label added, removed or whose value changed, an empty
+// value meaning the label isn't set
+type labelChange struct {
+	Name string `json:"name"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func newDiffCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts diffOptions
+	cmd := &cobra.Command{
+		Use:                   diffName + " [OPTIONS] REPOSITORY:TAG REPOSITORY:TAG",
+		Short:                 "Compare the layers, size, platforms and labels of two images",
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, diffName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDiff(cmd.Context(), streams, hubClient, opts, args[0], args[1])
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	cmd.Flags().StringVar(&opts.platform, "platform", defaultPlatform, "Platform of the images to compare, for multi-platform images")
+	return cmd
+}
+
+func runDiff(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts diffOptions, from, to string) error {
+	platform, err := platforms.Parse(opts.platform)
+	if err != nil {
+		return fmt.Errorf("invalid platform %q: %s", opts.platform, err)
+	}
+	resolver := registry.NewResolver(hubClient)
+	fromImage, fromPlatforms, err := loadImage(ctx, resolver, from, platform)
+	if err != nil {
+		return err
+	}
+	toImage, toPlatforms, err := loadImage(ctx, resolver, to, platform)
+	if err != nil {
+		return err
+	}
+	diff := diffImages(fromImage, toImage)
+	diff.From, diff.To = from, to
+	if fromPlatforms != nil || toPlatforms != nil {
+		diff.Platform = platforms.Format(platform)
+		diff.AddedPlatforms, diff.RemovedPlatforms = diffStrings(fromPlatforms, toPlatforms)
+	}
+	return opts.Print(streams.Out(), diff, printDiff)
+}
+
+// loadImage reads the image of a tag, selecting the image of the platform
+// for a multi-platform image. The platforms of a multi-platform image are
+// returned too.
+func loadImage(ctx context.Context, resolver remotes.Resolver, imageRef string, platform ocispec.Platform) (*Image, []string, error) {
+	ref, err := reference.ParseNormalizedNamed(imageRef)
+	if err != nil {
+		return nil, nil, err
+	}
+	ref = reference.TagNameOnly(ref)
+	fullName, descriptor, err := resolver.Resolve(ctx, ref.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	raw, err := registry.GetBlob(ctx, resolver, fullName, descriptor)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch descriptor.MediaType {
+	case images.MediaTypeDockerSchema2Manifest, ocispec.MediaTypeImageManifest:
+		image, err := readImage(ctx, resolver, raw, descriptor, ref.Name())
+		return image, nil, err
+	case images.MediaTypeDockerSchema2ManifestList, ocispec.MediaTypeImageIndex:
+		var index ocispec.Index
+		if err := json.Unmarshal(raw, &index); err != nil {
+			return nil, nil, err
+		}
+		matcher := platforms.NewMatcher(platform)
+		var (
+			selected  *ocispec.Descriptor
+			available []string
+		)
+		for i, manifest := range index.Manifests {
+			if manifest.Platform == nil {
+				continue
+			}
+			available = append(available, platforms.Format(*manifest.Platform))
+			if selected == nil && matcher.Match(*manifest.Platform) {
+				selected = &index.Manifests[i]
+			}
+		}
+		if selected == nil {
+			return nil, nil, fmt.Errorf("platform %q does not match any available platform for the tag %q", platforms.Format(platform), imageRef)
+		}
+		raw, err := registry.GetBlob(ctx, resolver, ref.Name(), *selected)
+		if err != nil {
+			return nil, nil, err
+		}
+		image, err := readImage(ctx, resolver, raw, *selected, ref.Name())
+		return image, available, err
+	default:
+		return nil, nil, fmt.Errorf("unsupported media type %q for %s", descriptor.MediaType, imageRef)
+	}
+}
+
+// diffImages compares the layers, by digest, the sizes and the labels of two
+// images
+func diffImages(from, to *Image) imageDiff {
+	diff := imageDiff{
+		AddedLayers:   []ocispec.Descriptor{},
+		RemovedLayers: []ocispec.Descriptor{},
+		FromSize:      imageSize(from.Manifest),
+		ToSize:        imageSize(to.Manifest),
+		Labels:        []labelChange{},
+	}
+	fromLayers := map[string]bool{}
+	for _, layer := range from.Manifest.Layers {
+		fromLayers[layer.Digest.String()] = true
+	}
+	toLayers := map[string]bool{}
+	for _, layer := range to.Manifest.Layers {
+		toLayers[layer.Digest.String()] = true
+		if fromLayers[layer.Digest.String()] {
+			diff.SharedLayers++
+		} else {
+			diff.AddedLayers = append(diff.AddedLayers, layer)
+		}
+	}
+	for _, layer := range from.Manifest.Layers {
+		if !toLayers[layer.Digest.String()] {
+			diff.RemovedLayers = append(diff.RemovedLayers, layer)
+		}
+	}
+
+	fromLabels, toLabels := from.Config.Config.Labels, to.Config.Config.Labels
+	names := map[string]bool{}
+	for name := range fromLabels {
+		names[name] = true
+	}
+	for name := range toLabels {
+		names[name] = true
+	}
+	for name := range names {
+		if fromLabels[name] != toLabels[name] {
+			diff.Labels = append(diff.Labels, labelChange{Name: name, From: fromLabels[name], To: toLabels[name]})
+		}
+	}
+	sort.Slice(diff.Labels, func(i, j int) bool { return diff.Labels[i].Name < diff.Labels[j].Name })
+	return diff
+}
+
+// diffStrings returns the values only in to, then the ones only in from
+func diffStrings(from, to []string) ([]string, []string) {
+	inFrom := map[string]bool{}
+	for _, s := range from {
+		inFrom[s] = true
+	}
+	inTo := map[string]bool{}
+	var added, removed []string
+	for _, s := range to {
+		inTo[s] = true
+		if !inFrom[s] {
+			added = append(added, s)
+		}
+	}
+	for _, s := range from {
+		if !inTo[s] {
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
+
+func printDiff(out io.Writer, value interface{}) error {
+	diff := value.(imageDiff)
+	fmt.Fprintf(out, "%s %s\n", ansi.Key("From:"), diff.From)
+	fmt.Fprintf(out, "%s %s\n", ansi.Key("To:"), diff.To)
+	if diff.Platform != "" {
+		fmt.Fprintf(out, "%s %s\n", ansi.Key("Platform:"), diff.Platform)
+	}
+	delta := diff.ToSize - diff.FromSize
+	sign := "+"
+	if delta < 0 {
+		sign, delta = "-", -delta
+	}
+	fmt.Fprintf(out, "%s %s -> %s (%s%s)\n", ansi.Key("Size:"), units.HumanSize(float64(diff.FromSize)), units.HumanSize(float64(diff.ToSize)), sign, units.HumanSize(float64(delta)))
+
+	if len(diff.AddedPlatforms) > 0 || len(diff.RemovedPlatforms) > 0 {
+		fmt.Fprintln(out, "\n"+ansi.Title("Platforms"))
+		for _, platform := range diff.AddedPlatforms {
+			fmt.Fprintln(out, "+", platform)
+		}
+		for _, platform := range diff.RemovedPlatforms {
+			fmt.Fprintln(out, "-", platform)
+		}
+	}
+
+	fmt.Fprintln(out, "\n"+ansi.Title(fmt.Sprintf("Layers (%d shared)", diff.SharedLayers)))
+	for _, layer := range diff.AddedLayers {
+		fmt.Fprintf(out, "+ %s %s\n", layer.Digest, units.HumanSize(float64(layer.Size)))
+	}
+	for _, layer := range diff.RemovedLayers {
+		fmt.Fprintf(out, "- %s %s\n", layer.Digest, units.HumanSize(float64(layer.Size)))
+	}
+
+	if len(diff.Labels) > 0 {
+		fmt.Fprintln(out, "\n"+ansi.Title("Labels"))
+		for _, label := range diff.Labels {
+			switch {
+			case label.From == "":
+				fmt.Fprintf(out, "+ %s=%s\n", label.Name, label.To)
+			case label.To == "":
+				fmt.Fprintf(out, "- %s=%s\n", label.Name, label.From)
+			default:
+				fmt.Fprintf(out, "~ %s=%s -> %s\n", label.Name, label.From, label.To)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package tag
+
+import (
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+)
+
+func TestDiffImages(t *testing.T) {
+	base := ocispec.Descriptor{Digest: "sha256:1111", Size: 100}
+	app := ocispec.Descriptor{Digest: "sha256:2222", Size: 20}
+	newApp := ocispec.Descriptor{Digest: "sha256:3333", Size: 30}
+
+	from := &Image{
+		Manifest: ocispec.Manifest{Config: ocispec.Descriptor{Size: 1}, Layers: []ocispec.Descriptor{base, app}},
+		Config:   ocispec.Image{Config: ocispec.ImageConfig{Labels: map[string]string{"version": "1", "vendor": "acme", "old": "yes"}}},
+	}
+	to := &Image{
+		Manifest: ocispec.Manifest{Config: ocispec.Descriptor{Size: 1}, Layers: []ocispec.Descriptor{base, newApp}},
+		Config:   ocispec.Image{Config: ocispec.ImageConfig{Labels: map[string]string{"version": "2", "vendor": "acme", "new": "yes"}}},
+	}
+
+	diff := diffImages(from, to)
+	assert.Equal(t, diff.SharedLayers, 1)
+	assert.DeepEqual(t, diff.AddedLayers, []ocispec.Descriptor{newApp})
+	assert.DeepEqual(t, diff.RemovedLayers, []ocispec.Descriptor{app})
+	assert.Equal(t, diff.FromSize, int64(121))
+	assert.Equal(t, diff.ToSize, int64(131))
+	assert.DeepEqual(t, diff.Labels, []labelChange{
+		{Name: "new", To: "yes"},
+		{Name: "old", From: "yes"},
+		{Name: "version", From: "1", To: "2"},
+	})
+}
+
+func TestDiffStrings(t *testing.T) {
+	added, removed := diffStrings([]string{"linux/amd64", "linux/arm/v7"}, []string{"linux/amd64", "linux/arm64"})
+	assert.DeepEqual(t, added, []string{"linux/arm64"})
+	assert.DeepEqual(t, removed, []string{"linux/arm/v7"})
+}
@@ -0,0 +1,163 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package tag
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+// tagFilter selects the tags to list
+type tagFilter func(hub.Tag) bool
+
+// parseTagFilters parses the --filter values, given as key=value: "name" is a
+// glob on the tag name, "before" keeps the tags last pushed before a date and
+// "arch" the tags with an image for the architecture, optionally followed by
+// its variant such as arm/v7, and "type" the tags of an artifact type such as
+// helm. A nil filter is returned when none is given.
+func parseTagFilters(values []string) (tagFilter, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	var filters []tagFilter
+	for _, value := range values {
+		fields := strings.SplitN(value, "=", 2)
+		if len(fields) != 2 || fields[1] == "" {
+			return nil, fmt.Errorf("invalid filter %q: should be key=value", value)
+		}
+		filter, err := newTagFilter(fields[0], fields[1])
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return func(tag hub.Tag) bool {
+		for _, filter := range filters {
+			if !filter(tag) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func newTagFilter(key, value string) (tagFilter, error) {
+	switch key {
+	case "name":
+		if _, err := path.Match(value, ""); err != nil {
+			return nil, fmt.Errorf("invalid name filter %q: %s", value, err)
+		}
+		return func(tag hub.Tag) bool {
+			matched, _ := path.Match(value, tag.Name)
+			return matched
+		}, nil
+	case "before":
+		date, err := parseDate(value)
+		if err != nil {
+			return nil, err
+		}
+		return func(tag hub.Tag) bool {
+			return lastPushed(tag).Before(date)
+		}, nil
+	case "arch":
+		arch := strings.SplitN(value, "/", 2)
+		return func(tag hub.Tag) bool {
+			for _, image := range tag.Images {
+				if image.Architecture == arch[0] && (len(arch) == 1 || image.Variant == arch[1]) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	case "type":
+		if !isArtifactType(value) {
+			return nil, fmt.Errorf("invalid type filter %q: should be one of %s", value, strings.Join(hub.ArtifactTypes, ", "))
+		}
+		return func(tag hub.Tag) bool {
+			return tag.ArtifactType == value
+		}, nil
+	default:
+		return nil, fmt.Errorf(`unknown filter %q: should be either "name", "before", "arch" or "type"`, key)
+	}
+}
+
+func isArtifactType(value string) bool {
+	for _, t := range hub.ArtifactTypes {
+		if t == value {
+			return true
+		}
+	}
+	return false
+}
+
+func filterTags(tags []hub.Tag, filter tagFilter) []hub.Tag {
+	filtered := []hub.Tag{}
+	for _, tag := range tags {
+		if filter(tag) {
+			filtered = append(filtered, tag)
+		}
+	}
+	return filtered
+}
+
+// parseDate accepts a RFC 3339 date or a day, such as 2020-11-02
+func parseDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: should be either 2006-01-02 or 2006-01-02T15:04:05Z07:00", value)
+	}
+	return date, nil
+}
+
+// lastPushed returns when the tag was last pushed, falling back to its last
+// update for the tags without push date
+func lastPushed(tag hub.Tag) time.Time {
+	if tag.LastPushed.IsZero() {
+		return tag.LastUpdated
+	}
+	return tag.LastPushed
+}
+
+// selectPlatform keeps the tags with an image for the platform, given as
+// os/arch[/variant], and only their images for this platform
+func selectPlatform(tags []hub.Tag, platform string) ([]hub.Tag, error) {
+	fields := strings.Split(platform, "/")
+	if len(fields) < 2 || len(fields) > 3 {
+		return nil, fmt.Errorf("invalid platform %q: should be os/arch[/variant]", platform)
+	}
+	selected := []hub.Tag{}
+	for _, tag := range tags {
+		var images []hub.Image
+		for _, image := range tag.Images {
+			if image.Os == fields[0] && image.Architecture == fields[1] && (len(fields) == 2 || image.Variant == fields[2]) {
+				images = append(images, image)
+			}
+		}
+		if len(images) > 0 {
+			tag.Images = images
+			selected = append(selected, tag)
+		}
+	}
+	return selected, nil
+}
@@ -17,12 +17,10 @@
 package tag
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
 	"sort"
 	"strings"
 	"time"
@@ -30,7 +28,6 @@ import (
 	"github.com/containerd/containerd/images"
 	"github.com/containerd/containerd/platforms"
 	"github.com/containerd/containerd/remotes"
-	"github.com/containerd/containerd/remotes/docker"
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/distribution/reference"
@@ -39,8 +36,10 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/docker/hub-tool/internal/ansi"
-	"github.com/docker/hub-tool/internal/hub"
 	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/internal/registry"
+	"github.com/docker/hub-tool/internal/trust"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
@@ -48,8 +47,9 @@ const (
 )
 
 type inspectOptions struct {
-	format   string
-	platform string
+	format       string
+	platform     string
+	requireTrust bool
 }
 
 //Image is the combination of a manifest and its config object
@@ -77,16 +77,17 @@ func newInspectCmd(streams command.Streams, hubClient *hub.Client, parent string
 		PreRun: func(cmd *cobra.Command, args []string) {
 			metrics.Send(parent, inspectName)
 		},
-		RunE: func(_ *cobra.Command, args []string) error {
-			return runInspect(streams, hubClient, opts, args[0])
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInspect(cmd.Context(), streams, hubClient, opts, args[0])
 		},
 	}
 	cmd.Flags().StringVar(&opts.format, "format", "", `Print original manifest ("json|raw")`)
 	cmd.Flags().StringVar(&opts.platform, "platform", "", `Select a platform if the tag is a multi-architecture image`)
+	cmd.Flags().BoolVar(&opts.requireTrust, "require-trust-data", false, "Fail if the Docker Content Trust data has no target matching the tag digest. The TUF signatures aren't verified, use \"docker trust inspect\" to verify them")
 	return cmd
 }
 
-func runInspect(streams command.Streams, hubClient *hub.Client, opts inspectOptions, imageRef string) error {
+func runInspect(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts inspectOptions, imageRef string) error {
 	var (
 		platform *ocispec.Platform
 	)
@@ -97,14 +98,7 @@ func runInspect(streams command.Streams, hubClient *hub.Client, opts inspectOpti
 		}
 		platform = &p
 	}
-	authorizer := docker.NewDockerAuthorizer(docker.WithAuthCreds(func(string) (string, string, error) {
-		return hubClient.AuthConfig.Username, hubClient.AuthConfig.Password, nil
-	}))
-	registryHosts := docker.ConfigureDefaultRegistries(docker.WithClient(http.DefaultClient), docker.WithAuthorizer(authorizer))
-
-	resolver := docker.NewResolver(docker.ResolverOptions{
-		Hosts: registryHosts,
-	})
+	resolver := registry.NewResolver(hubClient)
 
 	// Parse image reference
 	ref, err := reference.ParseNormalizedNamed(imageRef)
@@ -114,53 +108,87 @@ func runInspect(streams command.Streams, hubClient *hub.Client, opts inspectOpti
 	ref = reference.TagNameOnly(ref)
 
 	// Read descriptor
-	fullName, descriptor, err := resolver.Resolve(hubClient.Ctx, ref.String())
+	fullName, descriptor, err := resolver.Resolve(ctx, ref.String())
 	if err != nil {
 		return err
 	}
 
-	raw, err := getBlob(hubClient.Ctx, resolver, fullName, descriptor)
+	raw, err := registry.GetBlob(ctx, resolver, fullName, descriptor)
 	if err != nil {
 		return err
 	}
 
+	var (
+		signature *trust.Signature
+		trustErr  error
+	)
+	if opts.requireTrust || opts.format == "" {
+		signature, trustErr = readSignature(ctx, hubClient, ref)
+	}
+	if opts.requireTrust {
+		if err := checkSignature(signature, trustErr, descriptor, ref); err != nil {
+			return err
+		}
+	}
+
 	switch descriptor.MediaType {
 	// case images.MediaTypeDockerSchema2Manifest, specs.MediaTypeImageManifest:
 	// TODO: handle distribution manifest and schema1
 	case images.MediaTypeDockerSchema2ManifestList, ocispec.MediaTypeImageIndex:
-		return formatManifestlist(hubClient.Ctx, streams, resolver, opts.format, raw, descriptor, ref.Name(), platform)
+		err = formatManifestlist(ctx, streams, resolver, opts.format, raw, descriptor, ref.Name(), platform)
 	case images.MediaTypeDockerSchema2Manifest, ocispec.MediaTypeImageManifest:
-		return formatManifest(hubClient.Ctx, streams, resolver, opts.format, raw, descriptor, ref.Name())
+		err = formatManifest(ctx, streams, resolver, opts.format, raw, descriptor, ref.Name())
 	default:
 		fmt.Fprintln(streams.Out(), ansi.Title("Unsupported mediatype"))
 		fmt.Fprintln(streams.Out(), raw)
+		return nil
 	}
-
+	if err != nil || opts.format != "" {
+		return err
+	}
+	printSignature(streams.Out(), signature, trustErr, descriptor)
 	return nil
 }
 
-func getBlob(ctx context.Context, resolver remotes.Resolver, fullName string, descriptor ocispec.Descriptor) ([]byte, error) {
-	// Fetch the blob
-	fetcher, err := resolver.Fetcher(ctx, fullName)
-	if err != nil {
-		return nil, err
+// readSignature reads the Docker Content Trust signed target of the tag, if
+// any, without verifying its signatures
+func readSignature(ctx context.Context, hubClient *hub.Client, ref reference.Named) (*trust.Signature, error) {
+	tagged, ok := ref.(reference.Tagged)
+	if !ok {
+		return nil, nil
 	}
+	client := trust.NewClient(trust.DefaultServer, hubClient.HTTPClient(), hubClient.AuthConfig.Username, hubClient.AuthConfig.Password)
+	return client.Signature(ctx, ref.Name(), tagged.Tag())
+}
 
-	rc, err := fetcher.Fetch(ctx, descriptor)
-	if err != nil {
-		return nil, err
+func checkSignature(signature *trust.Signature, trustErr error, descriptor ocispec.Descriptor, ref reference.Named) error {
+	if trustErr != nil {
+		return fmt.Errorf("failed to read the signature of %s: %s", reference.FamiliarString(ref), trustErr)
 	}
-	defer func() {
-		_ = rc.Close()
-	}()
-
-	// Read the blob
-	buf := bytes.NewBuffer(nil)
-	if _, err = io.Copy(buf, rc); err != nil {
-		return nil, err
+	if signature == nil {
+		return fmt.Errorf("%s has no Docker Content Trust data", reference.FamiliarString(ref))
 	}
+	if signature.Digest != descriptor.Digest.String() {
+		return fmt.Errorf("the Docker Content Trust data of %s is for %s but the tag references %s", reference.FamiliarString(ref), signature.Digest, descriptor.Digest)
+	}
+	return nil
+}
 
-	return buf.Bytes(), nil
+func printSignature(out io.Writer, signature *trust.Signature, trustErr error, descriptor ocispec.Descriptor) {
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, ansi.Title("Content Trust:")+"\n")
+	switch {
+	case trustErr != nil:
+		fmt.Fprintf(out, ansi.Key("Signed:")+"\t\t%s\n", ansi.Warn("unknown, "+trustErr.Error()))
+	case signature == nil:
+		fmt.Fprintf(out, ansi.Key("Signed:")+"\t\tno\n")
+	default:
+		fmt.Fprintf(out, ansi.Key("Signed:")+"\t\tyes\n")
+		fmt.Fprintf(out, ansi.Key("Signers:")+"\t%s\n", strings.Join(signature.Signers, ", "))
+		if signature.Digest != descriptor.Digest.String() {
+			fmt.Fprintf(out, ansi.Key("Signed digest:")+"\t%s\n", ansi.Warn(signature.Digest+", the tag has been pushed since"))
+		}
+	}
 }
 
 func formatManifestlist(ctx context.Context, streams command.Streams, resolver remotes.Resolver,
@@ -233,7 +261,7 @@ func formatSelectManifest(ctx context.Context, streams command.Streams, resolver
 	if selectedDescriptor == nil {
 		return fmt.Errorf("platform %q does not match any available platform for the tag %q", platforms.Format(platform), name)
 	}
-	raw, err := getBlob(ctx, resolver, name, *selectedDescriptor)
+	raw, err := registry.GetBlob(ctx, resolver, name, *selectedDescriptor)
 	if err != nil {
 		return err
 	}
@@ -248,7 +276,7 @@ func readImage(ctx context.Context, resolver remotes.Resolver, rawManifest []byt
 
 	configRef := fmt.Sprintf("%s@%s", name, manifest.Config.Digest)
 
-	configRaw, err := getBlob(ctx, resolver, configRef, manifest.Config)
+	configRaw, err := registry.GetBlob(ctx, resolver, configRef, manifest.Config)
 	if err != nil {
 		return nil, err
 	}
@@ -301,6 +329,7 @@ func printManifest(out io.Writer, image *Image) error {
 	fmt.Fprintf(out, ansi.Key("Name:")+"\t\t%s\n", image.Name)
 	fmt.Fprintf(out, ansi.Key("MediaType:")+"\t%s\n", image.Descriptor.MediaType)
 	fmt.Fprintf(out, ansi.Key("Digest:")+"\t\t%s\n", image.Descriptor.Digest)
+	fmt.Fprintf(out, ansi.Key("Size:")+"\t\t%v\n", units.HumanSize(float64(imageSize(image.Manifest))))
 	if image.Descriptor.Platform != nil {
 		fmt.Fprintf(out, ansi.Key("Platform:")+"\t%s\n", formatPlatform(image.Descriptor.Platform))
 	}
@@ -391,6 +420,16 @@ func printLayers(out io.Writer, image *Image) error {
 	return nil
 }
 
+// imageSize returns the compressed size of the image, as pulled from the
+// registry
+func imageSize(manifest ocispec.Manifest) int64 {
+	size := manifest.Config.Size
+	for _, layer := range manifest.Layers {
+		size += layer.Size
+	}
+	return size
+}
+
 func formatPlatform(platform *ocispec.Platform) string {
 	if platform == nil {
 		return ""
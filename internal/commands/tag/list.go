@@ -17,99 +17,35 @@
 package tag
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
-	"time"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
-	"github.com/docker/go-units"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/hub-tool/internal/ansi"
 	"github.com/docker/hub-tool/internal/format"
-	"github.com/docker/hub-tool/internal/format/tabwriter"
-	"github.com/docker/hub-tool/internal/hub"
 	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/internal/watch"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
 	lsName = "ls"
 )
 
-var (
-	defaultColumns = []column{
-		{"TAG", func(t hub.Tag) (string, int) { return t.Name, len(t.Name) }},
-		{"DIGEST", func(t hub.Tag) (string, int) {
-			if len(t.Images) > 0 {
-				return t.Images[0].Digest, len(t.Images[0].Digest)
-			}
-			return "", 0
-		}},
-		{"STATUS", func(t hub.Tag) (string, int) {
-			return t.Status, len(t.Status)
-		}},
-		{"LAST UPDATE", func(t hub.Tag) (string, int) {
-			if t.LastUpdated.Nanosecond() == 0 {
-				return "", 0
-			}
-			s := fmt.Sprintf("%s ago", units.HumanDuration(time.Since(t.LastUpdated)))
-			return s, len(s)
-		}},
-		{"LAST PUSHED", func(t hub.Tag) (string, int) {
-			if t.LastPushed.Nanosecond() == 0 {
-				return "", 0
-			}
-			s := units.HumanDuration(time.Since(t.LastPushed))
-			return s, len(s)
-		}},
-		{"LAST PULLED", func(t hub.Tag) (string, int) {
-			if t.LastPulled.Nanosecond() == 0 {
-				return "", 0
-			}
-			s := units.HumanDuration(time.Since(t.LastPulled))
-			return s, len(s)
-		}},
-		{"SIZE", func(t hub.Tag) (string, int) {
-			size := t.FullSize
-			if len(t.Images) > 0 {
-				size = 0
-				for _, image := range t.Images {
-					size += image.Size
-				}
-			}
-			s := units.HumanSize(float64(size))
-			return s, len(s)
-		}},
-	}
-	platformColumn = column{
-		"OS/ARCH",
-		func(t hub.Tag) (string, int) {
-			var platforms []string
-			for _, image := range t.Images {
-				platform := fmt.Sprintf("%s/%s", image.Os, image.Architecture)
-				if image.Variant != "" {
-					platform += "/" + image.Variant
-				}
-				platforms = append(platforms, platform)
-			}
-			s := strings.Join(platforms, ",")
-			return s, len(s)
-		},
-	}
-)
-
-type column struct {
-	header string
-	value  func(t hub.Tag) (string, int)
-}
-
 type listOptions struct {
 	format.Option
+	watch.Watcher
 	platforms bool
 	all       bool
 	sort      string
+	filters   []string
+	platform  string
 }
 
 func newListCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
@@ -120,26 +56,48 @@ func newListCmd(streams command.Streams, hubClient *hub.Client, parent string) *
 		Short:                 "List all the images in a repository",
 		Args:                  cli.ExactArgs(1),
 		DisableFlagsInUseLine: true,
+		Annotations: map[string]string{
+			"public": "true",
+		},
 		PreRun: func(cmd *cobra.Command, args []string) {
 			metrics.Send(parent, lsName)
 		},
-		RunE: func(_ *cobra.Command, args []string) error {
-			return runList(streams, hubClient, opts, args[0])
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Watching() {
+				return opts.Watch(cmd.Context(), streams, hubClient, cmd.CommandPath()+" "+args[0], func(streams command.Streams) error {
+					return runList(cmd.Context(), streams, hubClient, opts, args[0])
+				})
+			}
+			return runList(cmd.Context(), streams, hubClient, opts, args[0])
 		},
 	}
-	cmd.Flags().BoolVar(&opts.platforms, "platforms", false, "List all available platforms per tag")
+	cmd.Flags().BoolVar(&opts.platforms, "platforms", false, "List the platform and size of each image of the tags")
+	cmd.Flags().StringVar(&opts.platform, "platform", "", "Only list the tags with an image for this platform, given as os/arch[/variant]")
 	cmd.Flags().BoolVar(&opts.all, "all", false, "Fetch all available tags")
-	cmd.Flags().StringVar(&opts.sort, "sort", "", "Sort tags by (updated|name)[=(asc|desc)] (e.g.: --sort updated or --sort name=desc)")
+	cmd.Flags().StringVar(&opts.sort, "sort", "", "Sort tags by (updated|pushed|size|name)[=(asc|desc)] (e.g.: --sort updated or --sort name=desc)")
+	cmd.Flags().StringArrayVar(&opts.filters, "filter", nil, "Filter tags by name=<glob>, before=<date>, arch=<arch>[/<variant>] or type=<artifact type>")
 	opts.AddFormatFlag(cmd.Flags())
+	opts.AddWatchFlag(cmd.Flags())
 	return cmd
 }
 
-func runList(streams command.Streams, hubClient *hub.Client, opts listOptions, repository string) error {
-	ordering, err := mapOrdering(opts.sort)
+func runList(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts listOptions, repository string) error {
+	less, err := clientOrdering(opts.sort)
 	if err != nil {
 		return err
 	}
-	if opts.all {
+	ordering := ""
+	if less == nil {
+		if ordering, err = mapOrdering(opts.sort); err != nil {
+			return err
+		}
+	}
+	filter, err := parseTagFilters(opts.filters)
+	if err != nil {
+		return err
+	}
+	// Filtering and sorting client-side need all the tags
+	if opts.all || less != nil || filter != nil || opts.platform != "" {
 		if err := hubClient.Update(hub.WithAllElements()); err != nil {
 			return err
 		}
@@ -149,36 +107,33 @@ func runList(streams command.Streams, hubClient *hub.Client, opts listOptions, r
 	if ordering != "" {
 		reqOps = append(reqOps, hub.WithSortingOrder(ordering))
 	}
-	tags, total, err := hubClient.GetTags(repository, reqOps...)
+	tags, total, err := hubClient.GetTags(ctx, repository, reqOps...)
 	if err != nil {
 		return err
 	}
-
-	if opts.platforms {
-		defaultColumns = append(defaultColumns, platformColumn)
+	if filter != nil {
+		tags = filterTags(tags, filter)
+		total = len(tags)
+	}
+	if opts.platform != "" {
+		if tags, err = selectPlatform(tags, opts.platform); err != nil {
+			return err
+		}
+		total = len(tags)
+	}
+	if less != nil {
+		sort.SliceStable(tags, func(i, j int) bool { return less(tags[i], tags[j]) })
 	}
 
-	return opts.Print(streams.Out(), tags, printTags(total))
+	return opts.Print(streams.Out(), tags, printTags(total, opts.platforms))
 }
 
-func printTags(total int) format.PrettyPrinter {
+func printTags(total int, platforms bool) format.PrettyPrinter {
 	return func(out io.Writer, values interface{}) error {
 		tags := values.([]hub.Tag)
-		tw := tabwriter.New(out, "    ")
-		for _, column := range defaultColumns {
-			tw.Column(ansi.Header(column.header), len(column.header))
-		}
-
-		tw.Line()
-
-		for _, tag := range tags {
-			for _, column := range defaultColumns {
-				value, width := column.value(tag)
-				tw.Column(value, width)
-			}
-			tw.Line()
-		}
-		if err := tw.Flush(); err != nil {
+		printer := format.NewPrinter(out, format.TableFormat)
+		printer.Platforms = platforms
+		if err := printer.PrintTags(tags); err != nil {
 			return err
 		}
 
@@ -218,6 +173,32 @@ func mapOrdering(order string) (string, error) {
 	case "name":
 		return name, nil
 	default:
-		return "", fmt.Errorf(`unknown sorting column %q: should be either "name" or "updated"`, fields[0])
+		return "", fmt.Errorf(`unknown sorting column %q: should be either "name", "updated", "pushed" or "size"`, fields[0])
+	}
+}
+
+// clientSortColumns compare the tags on the columns the Hub API can't sort by
+var clientSortColumns = map[string]func(a, b hub.Tag) bool{
+	"pushed": func(a, b hub.Tag) bool { return lastPushed(a).Before(lastPushed(b)) },
+	"size":   func(a, b hub.Tag) bool { return a.FullSize < b.FullSize },
+}
+
+// clientOrdering returns how to sort the tags client-side, or nil when the Hub
+// API sorts them
+func clientOrdering(order string) (func(a, b hub.Tag) bool, error) {
+	fields := strings.SplitN(order, "=", 2)
+	less, ok := clientSortColumns[fields[0]]
+	if !ok {
+		return nil, nil
+	}
+	if len(fields) == 2 {
+		switch fields[1] {
+		case sortDesc:
+			return func(a, b hub.Tag) bool { return less(b, a) }, nil
+		case sortAsc:
+		default:
+			return nil, fmt.Errorf(`invalid sorting direction %q: should be either "asc" or "desc"`, fields[1])
+		}
 	}
+	return less, nil
 }
@@ -17,9 +17,13 @@
 package tag
 
 import (
+	"sort"
 	"testing"
+	"time"
 
 	"gotest.tools/v3/assert"
+
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 func TestMappingSortFieldToOrderingAPI(t *testing.T) {
@@ -38,7 +42,7 @@ func TestMappingSortFieldToOrderingAPI(t *testing.T) {
 			name:          "invalid sort by",
 			sort:          "invalid",
 			ordering:      "",
-			expectedError: `unknown sorting column "invalid": should be either "name" or "updated"`,
+			expectedError: `unknown sorting column "invalid": should be either "name", "updated", "pushed" or "size"`,
 		},
 		{
 			name:     "ascending order by default",
@@ -82,3 +86,96 @@ func TestMappingSortFieldToOrderingAPI(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterTags(t *testing.T) {
+	tags := []hub.Tag{
+		{Name: "1.0", ArtifactType: hub.ArtifactTypeImage, LastPushed: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC), Images: []hub.Image{{Architecture: "amd64"}}},
+		{Name: "1.1", ArtifactType: hub.ArtifactTypeImage, LastPushed: time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC), Images: []hub.Image{{Architecture: "amd64"}, {Architecture: "arm64", Variant: "v8"}}},
+		{Name: "latest", ArtifactType: hub.ArtifactTypeHelm, LastUpdated: time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC), Images: []hub.Image{{Architecture: "arm", Variant: "v7"}}},
+	}
+	testCases := []struct {
+		name          string
+		filters       []string
+		expected      []string
+		expectedError string
+	}{
+		{name: "name", filters: []string{"name=1.*"}, expected: []string{"1.0", "1.1"}},
+		{name: "before", filters: []string{"before=2020-10-01"}, expected: []string{"1.0", "1.1"}},
+		{name: "before without push date", filters: []string{"before=2020-12-01T00:00:00Z"}, expected: []string{"1.0", "1.1", "latest"}},
+		{name: "arch", filters: []string{"arch=arm64"}, expected: []string{"1.1"}},
+		{name: "arch and variant", filters: []string{"arch=arm/v7"}, expected: []string{"latest"}},
+		{name: "all filters match", filters: []string{"name=1.*", "before=2020-07-01"}, expected: []string{"1.0"}},
+		{name: "type", filters: []string{"type=helm"}, expected: []string{"latest"}},
+		{name: "invalid type", filters: []string{"type=chart"}, expectedError: `invalid type filter "chart": should be one of image, helm, wasm, cosign, unknown`},
+		{name: "invalid filter", filters: []string{"name"}, expectedError: `invalid filter "name": should be key=value`},
+		{name: "unknown filter", filters: []string{"size=10"}, expectedError: `unknown filter "size": should be either "name", "before", "arch" or "type"`},
+		{name: "invalid date", filters: []string{"before=yesterday"}, expectedError: `invalid date "yesterday": should be either 2006-01-02 or 2006-01-02T15:04:05Z07:00`},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			filter, err := parseTagFilters(testCase.filters)
+			if testCase.expectedError != "" {
+				assert.Error(t, err, testCase.expectedError)
+				return
+			}
+			assert.NilError(t, err)
+			names := []string{}
+			for _, tag := range filterTags(tags, filter) {
+				names = append(names, tag.Name)
+			}
+			assert.DeepEqual(t, names, testCase.expected)
+		})
+	}
+}
+
+func TestClientOrdering(t *testing.T) {
+	tags := []hub.Tag{
+		{Name: "small", FullSize: 10, LastPushed: time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "large", FullSize: 30, LastPushed: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "medium", FullSize: 20, LastPushed: time.Date(2020, 11, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	testCases := []struct {
+		sort     string
+		expected []string
+	}{
+		{sort: "size", expected: []string{"small", "medium", "large"}},
+		{sort: "size=desc", expected: []string{"large", "medium", "small"}},
+		{sort: "pushed", expected: []string{"large", "small", "medium"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.sort, func(t *testing.T) {
+			less, err := clientOrdering(testCase.sort)
+			assert.NilError(t, err)
+			sorted := append([]hub.Tag{}, tags...)
+			sort.SliceStable(sorted, func(i, j int) bool { return less(sorted[i], sorted[j]) })
+			names := []string{}
+			for _, tag := range sorted {
+				names = append(names, tag.Name)
+			}
+			assert.DeepEqual(t, names, testCase.expected)
+		})
+	}
+
+	less, err := clientOrdering("name")
+	assert.NilError(t, err)
+	assert.Assert(t, less == nil)
+}
+
+func TestSelectPlatform(t *testing.T) {
+	tags := []hub.Tag{
+		{Name: "multi", Images: []hub.Image{{Os: "linux", Architecture: "amd64"}, {Os: "linux", Architecture: "arm", Variant: "v7"}}},
+		{Name: "amd64", Images: []hub.Image{{Os: "linux", Architecture: "amd64"}}},
+	}
+	selected, err := selectPlatform(tags, "linux/arm/v7")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, selected, []hub.Tag{
+		{Name: "multi", Images: []hub.Image{{Os: "linux", Architecture: "arm", Variant: "v7"}}},
+	})
+
+	selected, err = selectPlatform(tags, "linux/amd64")
+	assert.NilError(t, err)
+	assert.Equal(t, len(selected), 2)
+
+	_, err = selectPlatform(tags, "amd64")
+	assert.Error(t, err, `invalid platform "amd64": should be os/arch[/variant]`)
+}
@@ -0,0 +1,242 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package tag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/distribution/reference"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/internal/registry"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	provenanceName = "provenance"
+)
+
+type provenanceOptions struct {
+	format.Option
+	platform string
+	raw      bool
+}
+
+// provenance is the summary of a SLSA provenance, v0.2 or v1
+type provenance struct {
+	PredicateType string            `json:"predicate_type"`
+	Builder       string            `json:"builder"`
+	BuildType     string            `json:"build_type"`
+	Source        string            `json:"source,omitempty"`
+	Commit        string            `json:"commit,omitempty"`
+	EntryPoint    string            `json:"entry_point,omitempty"`
+	Parameters    map[string]string `json:"parameters,omitempty"`
+	StartedOn     *time.Time        `json:"started_on,omitempty"`
+	FinishedOn    *time.Time        `json:"finished_on,omitempty"`
+}
+
+type slsaV02Predicate struct {
+	Builder struct {
+		ID string `json:"id"`
+	} `json:"builder"`
+	BuildType  string `json:"buildType"`
+	Invocation struct {
+		ConfigSource struct {
+			URI        string            `json:"uri"`
+			Digest     map[string]string `json:"digest"`
+			EntryPoint string            `json:"entryPoint"`
+		} `json:"configSource"`
+		Parameters map[string]interface{} `json:"parameters"`
+	} `json:"invocation"`
+	Metadata struct {
+		BuildStartedOn  *time.Time `json:"buildStartedOn"`
+		BuildFinishedOn *time.Time `json:"buildFinishedOn"`
+		// BuildKit records the git source of the build in its own metadata
+		BuildKit struct {
+			VCS struct {
+				Source   string `json:"source"`
+				Revision string `json:"revision"`
+			} `json:"vcs"`
+		} `json:"https://mobyproject.org/buildkit@v1#metadata"`
+	} `json:"metadata"`
+}
+
+type slsaV1Predicate struct {
+	BuildDefinition struct {
+		BuildType          string                 `json:"buildType"`
+		ExternalParameters map[string]interface{} `json:"externalParameters"`
+	} `json:"buildDefinition"`
+	RunDetails struct {
+		Builder struct {
+			ID string `json:"id"`
+		} `json:"builder"`
+		Metadata struct {
+			StartedOn  *time.Time `json:"startedOn"`
+			FinishedOn *time.Time `json:"finishedOn"`
+		} `json:"metadata"`
+	} `json:"runDetails"`
+}
+
+func newProvenanceCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts provenanceOptions
+	cmd := &cobra.Command{
+		Use:                   provenanceName + " [OPTIONS] REPOSITORY:TAG",
+		Short:                 "Print the builder, source and parameters of the SLSA provenance attached to an image",
+		Example:               "  hub-tool tag provenance myorg/app:1.0 --platform linux/arm64",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, provenanceName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runProvenance(cmd.Context(), streams, hubClient, opts, args[0])
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	cmd.Flags().StringVar(&opts.platform, "platform", defaultPlatform, "Platform of the image, for multi-platform images")
+	cmd.Flags().BoolVar(&opts.raw, "raw", false, "Print the provenance predicate as attached to the image")
+	return cmd
+}
+
+func runProvenance(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts provenanceOptions, imageRef string) error {
+	platform, err := platforms.Parse(opts.platform)
+	if err != nil {
+		return fmt.Errorf("invalid platform %q: %s", opts.platform, err)
+	}
+	ref, err := reference.ParseNormalizedNamed(imageRef)
+	if err != nil {
+		return err
+	}
+	ref = reference.TagNameOnly(ref)
+
+	attestations, err := registry.GetAttestations(ctx, registry.NewResolver(hubClient), ref.String(), registry.SLSAProvenancePredicateType)
+	if err != nil {
+		return err
+	}
+	attestation := selectAttestation(attestations, platform)
+	if attestation == nil {
+		return fmt.Errorf("no SLSA provenance attached to %s for %s", reference.FamiliarString(ref), platforms.Format(platform))
+	}
+	if opts.raw {
+		return writePredicate(streams.Out(), "", attestation.Statement.Predicate)
+	}
+	summary, err := parseProvenance(attestation.Statement)
+	if err != nil {
+		return err
+	}
+	return opts.Print(streams.Out(), summary, printProvenance)
+}
+
+// parseProvenance summarizes a SLSA provenance, the v1 one or the v0.2 one
+// BuildKit attaches by default
+func parseProvenance(statement registry.Statement) (*provenance, error) {
+	summary := provenance{PredicateType: statement.PredicateType}
+	if statement.PredicateType == registry.SLSAProvenancePredicateType+"v1" {
+		var predicate slsaV1Predicate
+		if err := json.Unmarshal(statement.Predicate, &predicate); err != nil {
+			return nil, err
+		}
+		summary.Builder = predicate.RunDetails.Builder.ID
+		summary.BuildType = predicate.BuildDefinition.BuildType
+		summary.Parameters = stringParameters(predicate.BuildDefinition.ExternalParameters)
+		summary.StartedOn = predicate.RunDetails.Metadata.StartedOn
+		summary.FinishedOn = predicate.RunDetails.Metadata.FinishedOn
+		return &summary, nil
+	}
+
+	var predicate slsaV02Predicate
+	if err := json.Unmarshal(statement.Predicate, &predicate); err != nil {
+		return nil, err
+	}
+	summary.Builder = predicate.Builder.ID
+	summary.BuildType = predicate.BuildType
+	summary.Source = predicate.Invocation.ConfigSource.URI
+	summary.Commit = predicate.Invocation.ConfigSource.Digest["sha1"]
+	summary.EntryPoint = predicate.Invocation.ConfigSource.EntryPoint
+	summary.Parameters = stringParameters(predicate.Invocation.Parameters)
+	summary.StartedOn = predicate.Metadata.BuildStartedOn
+	summary.FinishedOn = predicate.Metadata.BuildFinishedOn
+	if vcs := predicate.Metadata.BuildKit.VCS; vcs.Source != "" {
+		summary.Source, summary.Commit = vcs.Source, vcs.Revision
+	}
+	return &summary, nil
+}
+
+// stringParameters flattens the build parameters, printing the nested ones
+// as JSON
+func stringParameters(parameters map[string]interface{}) map[string]string {
+	if len(parameters) == 0 {
+		return nil
+	}
+	result := map[string]string{}
+	for key, value := range parameters {
+		if s, ok := value.(string); ok {
+			result[key] = s
+			continue
+		}
+		buf, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		result[key] = string(buf)
+	}
+	return result
+}
+
+func printProvenance(out io.Writer, value interface{}) error {
+	summary := value.(*provenance)
+	fmt.Fprintf(out, ansi.Key("Predicate type:")+"\t%s\n", summary.PredicateType)
+	fmt.Fprintf(out, ansi.Key("Builder:")+"\t%s\n", summary.Builder)
+	fmt.Fprintf(out, ansi.Key("Build type:")+"\t%s\n", summary.BuildType)
+	if summary.Source != "" {
+		fmt.Fprintf(out, ansi.Key("Source:")+"\t\t%s\n", summary.Source)
+	}
+	if summary.Commit != "" {
+		fmt.Fprintf(out, ansi.Key("Commit:")+"\t\t%s\n", summary.Commit)
+	}
+	if summary.EntryPoint != "" {
+		fmt.Fprintf(out, ansi.Key("Entry point:")+"\t%s\n", summary.EntryPoint)
+	}
+	if summary.StartedOn != nil {
+		fmt.Fprintf(out, ansi.Key("Started:")+"\t%s\n", summary.StartedOn.Local().Format(time.RFC3339))
+	}
+	if summary.FinishedOn != nil {
+		fmt.Fprintf(out, ansi.Key("Finished:")+"\t%s\n", summary.FinishedOn.Local().Format(time.RFC3339))
+	}
+	if len(summary.Parameters) > 0 {
+		fmt.Fprintf(out, ansi.Key("Parameters:")+"\n")
+		keys := make([]string, 0, len(summary.Parameters))
+		for key := range summary.Parameters {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(out, "    %s: %s\n", key, summary.Parameters[key])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package tag
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/hub-tool/internal/registry"
+)
+
+func TestParseProvenance(t *testing.T) {
+	started := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	finished := time.Date(2023, 3, 1, 10, 4, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		statement registry.Statement
+		expected  *provenance
+	}{
+		{
+			name: "buildkit v0.2",
+			statement: registry.Statement{
+				PredicateType: "https://slsa.dev/provenance/v0.2",
+				Predicate: []byte(`{
+  "builder": {"id": "https://github.com/myorg/app/actions/runs/42"},
+  "buildType": "https://mobyproject.org/buildkit@v1",
+  "invocation": {
+    "configSource": {"entryPoint": "Dockerfile"},
+    "parameters": {"frontend": "dockerfile.v0", "args": {"build-arg:VERSION": "1.0"}}
+  },
+  "metadata": {
+    "buildStartedOn": "2023-03-01T10:00:00Z",
+    "buildFinishedOn": "2023-03-01T10:04:00Z",
+    "https://mobyproject.org/buildkit@v1#metadata": {"vcs": {"source": "https://github.com/myorg/app", "revision": "8f2c1e0"}}
+  }
+}`),
+			},
+			expected: &provenance{
+				PredicateType: "https://slsa.dev/provenance/v0.2",
+				Builder:       "https://github.com/myorg/app/actions/runs/42",
+				BuildType:     "https://mobyproject.org/buildkit@v1",
+				Source:        "https://github.com/myorg/app",
+				Commit:        "8f2c1e0",
+				EntryPoint:    "Dockerfile",
+				Parameters:    map[string]string{"frontend": "dockerfile.v0", "args": `{"build-arg:VERSION":"1.0"}`},
+				StartedOn:     &started,
+				FinishedOn:    &finished,
+			},
+		},
+		{
+			name: "v1",
+			statement: registry.Statement{
+				PredicateType: "https://slsa.dev/provenance/v1",
+				Predicate: []byte(`{
+  "buildDefinition": {"buildType": "https://actions.github.io/buildtypes/workflow/v1", "externalParameters": {"workflow": "release.yml"}},
+  "runDetails": {"builder": {"id": "https://github.com/actions/runner"}, "metadata": {"startedOn": "2023-03-01T10:00:00Z"}}
+}`),
+			},
+			expected: &provenance{
+				PredicateType: "https://slsa.dev/provenance/v1",
+				Builder:       "https://github.com/actions/runner",
+				BuildType:     "https://actions.github.io/buildtypes/workflow/v1",
+				Parameters:    map[string]string{"workflow": "release.yml"},
+				StartedOn:     &started,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			summary, err := parseProvenance(tc.statement)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, summary, tc.expected)
+		})
+	}
+}
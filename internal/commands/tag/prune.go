@@ -0,0 +1,181 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package tag
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/distribution/reference"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/age"
+	"github.com/docker/hub-tool/internal/bulk"
+	"github.com/docker/hub-tool/internal/errdef"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	pruneName = "prune"
+)
+
+type pruneOptions struct {
+	keepLast  int
+	olderThan string
+	exclude   []string
+	force     bool
+	bulk.Deleter
+}
+
+func newPruneCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts pruneOptions
+	cmd := &cobra.Command{
+		Use:   pruneName + " [OPTIONS] REPOSITORY",
+		Short: "Delete the old tags of a repository",
+		Long: `Delete the old tags of a repository, keeping the last pushed ones with --keep-last and the recent ones with --older-than.
+When both are given, only the tags outside of the last pushed ones and older than the age are deleted.`,
+		Example:               `  hub-tool tag prune myorg/myrepo --keep-last 10 --older-than 90d --exclude 'release-*'`,
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, pruneName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runPrune(cmd.Context(), streams, hubClient, opts, args[0])
+			if err == nil || errors.Is(err, errdef.ErrCanceled) {
+				return nil
+			}
+			return err
+		},
+	}
+	cmd.Flags().IntVar(&opts.keepLast, "keep-last", 0, "Keep the given number of last pushed tags")
+	cmd.Flags().StringVar(&opts.olderThan, "older-than", "", "Only delete the tags last pushed before this age, e.g. 90d, 4w, 1y or 36h")
+	cmd.Flags().StringArrayVar(&opts.exclude, "exclude", nil, "Never delete the tags matching a glob pattern, e.g. 'release-*'")
+	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Do not prompt for confirmation")
+	opts.AddConcurrencyFlag(cmd.Flags())
+	return cmd
+}
+
+func runPrune(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts pruneOptions, repository string) error {
+	if opts.keepLast < 0 {
+		return fmt.Errorf("invalid --keep-last %d: should be positive", opts.keepLast)
+	}
+	if opts.keepLast == 0 && opts.olderThan == "" {
+		return errors.New("--keep-last or --older-than must be specified")
+	}
+	var cutoff time.Time
+	if opts.olderThan != "" {
+		maxAge, err := age.Parse(opts.olderThan)
+		if err != nil {
+			return err
+		}
+		cutoff = time.Now().Add(-maxAge)
+	}
+	var excluded []func(string) bool
+	for _, pattern := range opts.exclude {
+		match, err := tagMatcher(pattern, false)
+		if err != nil {
+			return err
+		}
+		excluded = append(excluded, match)
+	}
+
+	normRef, err := reference.ParseNormalizedNamed(repository)
+	if err != nil {
+		return err
+	}
+	if _, ok := normRef.(reference.Tagged); ok {
+		return fmt.Errorf("invalid reference: tag can't be specified")
+	}
+	name := reference.FamiliarName(normRef)
+
+	if err := hubClient.Update(hub.WithAllElements()); err != nil {
+		return err
+	}
+	tags, _, err := hubClient.GetTags(ctx, name)
+	if err != nil {
+		return err
+	}
+	pruned := pruneTags(tags, opts.keepLast, cutoff, excluded)
+	if len(pruned) == 0 {
+		fmt.Fprintln(streams.Out(), "No tag to delete")
+		return nil
+	}
+
+	if hubClient.DryRun() {
+		for _, tag := range pruned {
+			fmt.Fprintf(streams.Out(), "Would delete %s:%s\n", name, tag)
+		}
+		return nil
+	}
+
+	if !opts.force {
+		warning := fmt.Sprintf("WARNING: You are about to permanently delete %d tag(s) from repository %q: %s", len(pruned), name, strings.Join(pruned, ", "))
+		if err := confirmDeletion(ctx, streams, warning, "Are you sure you want to delete these tags?"); err != nil {
+			return err
+		}
+	}
+
+	return removeTags(ctx, streams, hubClient, opts.Deleter, name, pruned)
+}
+
+// pruneTags returns the names of the tags to delete, from the oldest pushed:
+// the excluded tags are never deleted, and neither are the keepLast last
+// pushed of the others nor the ones pushed after the cutoff, if any
+func pruneTags(tags []hub.Tag, keepLast int, cutoff time.Time, excluded []func(string) bool) []string {
+	var candidates []hub.Tag
+	for _, tag := range tags {
+		if !isExcluded(shortTagName(tag), excluded) {
+			candidates = append(candidates, tag)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return lastPushed(candidates[i]).After(lastPushed(candidates[j]))
+	})
+	if keepLast >= len(candidates) {
+		return nil
+	}
+	var pruned []string
+	for i := len(candidates) - 1; i >= keepLast; i-- {
+		if !cutoff.IsZero() && !lastPushed(candidates[i]).Before(cutoff) {
+			continue
+		}
+		pruned = append(pruned, shortTagName(candidates[i]))
+	}
+	return pruned
+}
+
+func isExcluded(tag string, excluded []func(string) bool) bool {
+	for _, match := range excluded {
+		if match(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// shortTagName strips the repository the tag names are prefixed with
+func shortTagName(tag hub.Tag) string {
+	return tag.Name[strings.LastIndex(tag.Name, ":")+1:]
+}
@@ -0,0 +1,80 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package tag
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+func TestPruneTags(t *testing.T) {
+	now := time.Date(2020, 11, 30, 0, 0, 0, 0, time.UTC)
+	tags := []hub.Tag{
+		{Name: "repo:release-1", LastPushed: now.AddDate(0, 0, -200)},
+		{Name: "repo:v1", LastPushed: now.AddDate(0, 0, -120)},
+		{Name: "repo:v2", LastPushed: now.AddDate(0, 0, -100)},
+		{Name: "repo:v3", LastPushed: now.AddDate(0, 0, -60)},
+		{Name: "repo:latest", LastUpdated: now.AddDate(0, 0, -1)},
+	}
+	release, err := tagMatcher("release-*", false)
+	assert.NilError(t, err)
+
+	testCases := []struct {
+		name     string
+		keepLast int
+		cutoff   time.Time
+		excluded []func(string) bool
+		pruned   []string
+	}{
+		{
+			name:     "keep last",
+			keepLast: 2,
+			pruned:   []string{"release-1", "v1", "v2"},
+		},
+		{
+			name:   "older than",
+			cutoff: now.AddDate(0, 0, -90),
+			pruned: []string{"release-1", "v1", "v2"},
+		},
+		{
+			name:     "keep last and older than",
+			keepLast: 4,
+			cutoff:   now.AddDate(0, 0, -90),
+			pruned:   []string{"release-1"},
+		},
+		{
+			name:     "excluded tags are kept and not counted",
+			keepLast: 2,
+			excluded: []func(string) bool{release},
+			pruned:   []string{"v1", "v2"},
+		},
+		{
+			name:     "keep more than the tags",
+			keepLast: 10,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			pruned := pruneTags(tags, testCase.keepLast, testCase.cutoff, testCase.excluded)
+			assert.DeepEqual(t, pruned, testCase.pruned)
+		})
+	}
+}
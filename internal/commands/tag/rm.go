@@ -20,15 +20,18 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"path"
+	"regexp"
 	"strings"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/distribution/reference"
 	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/bulk"
 	"github.com/docker/hub-tool/internal/errdef"
-	"github.com/docker/hub-tool/internal/hub"
 	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -38,21 +41,30 @@ const (
 )
 
 type rmOptions struct {
-	force bool
+	force  bool
+	match  string
+	regexp bool
+	bulk.Deleter
 }
 
 func newRmCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
 	var opts rmOptions
 	cmd := &cobra.Command{
-		Use:                   rmName + " [OPTIONS] REPOSITORY:TAG",
+		Use:                   rmName + " [OPTIONS] REPOSITORY:TAG|REPOSITORY@DIGEST|REPOSITORY",
 		Short:                 "Delete a tag in a repository",
+		Long:                  "Delete a tag in a repository, or an image given by its digest. With --match, all the tags of the repository matching the pattern are deleted.",
 		Args:                  cli.ExactArgs(1),
 		DisableFlagsInUseLine: true,
 		PreRun: func(cmd *cobra.Command, args []string) {
 			metrics.Send(parent, rmName)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			err := runRm(cmd.Context(), streams, hubClient, opts, args[0])
+			var err error
+			if opts.match != "" {
+				err = runRmMatching(cmd.Context(), streams, hubClient, opts, args[0])
+			} else {
+				err = runRm(cmd.Context(), streams, hubClient, opts, args[0])
+			}
 			if err == nil || errors.Is(err, errdef.ErrCanceled) {
 				return nil
 			}
@@ -60,6 +72,9 @@ func newRmCmd(streams command.Streams, hubClient *hub.Client, parent string) *co
 		},
 	}
 	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Force deletion of the tag")
+	cmd.Flags().StringVar(&opts.match, "match", "", "Delete all the tags matching a glob pattern, e.g. 'v1.2.*'")
+	cmd.Flags().BoolVar(&opts.regexp, "regexp", false, "Use the --match pattern as a regular expression, matching the whole tag")
+	opts.AddConcurrencyFlag(cmd.Flags())
 	return cmd
 }
 
@@ -68,36 +83,164 @@ func runRm(ctx context.Context, streams command.Streams, hubClient *hub.Client,
 	if err != nil {
 		return err
 	}
+	if digested, ok := normRef.(reference.Canonical); ok {
+		return runRmDigest(ctx, streams, hubClient, opts, digested)
+	}
 	normRef = reference.TagNameOnly(normRef)
 	ref, ok := normRef.(reference.NamedTagged)
 	if !ok {
 		return fmt.Errorf("invalid reference: tag must be specified")
 	}
 
+	if hubClient.DryRun() {
+		fmt.Fprintln(streams.Out(), "Would delete", image)
+		return nil
+	}
 	if !opts.force {
-		fmt.Fprintln(streams.Out(), ansi.Warn(fmt.Sprintf(`WARNING: You are about to permanently delete image "%s:%s"`, reference.FamiliarName(ref), ref.Tag())))
-		fmt.Fprintln(streams.Out(), ansi.Warn("         This action is irreversible"))
-		fmt.Fprintf(streams.Out(), ansi.Info("Are you sure you want to delete the image tagged %q from repository %q? [y/N] "), ref.Tag(), reference.FamiliarName(ref))
-		userIn := make(chan string, 1)
-		go func() {
-			reader := bufio.NewReader(streams.In())
-			input, _ := reader.ReadString('\n')
-			userIn <- strings.ToLower(strings.TrimSpace(input))
-		}()
-		input := ""
-		select {
-		case <-ctx.Done():
-			return errdef.ErrCanceled
-		case input = <-userIn:
-		}
-		if strings.ToLower(input) != "y" {
-			return errors.New("deletion aborted")
+		warning := fmt.Sprintf(`WARNING: You are about to permanently delete image "%s:%s"`, reference.FamiliarName(ref), ref.Tag())
+		question := fmt.Sprintf("Are you sure you want to delete the image tagged %q from repository %q?", ref.Tag(), reference.FamiliarName(ref))
+		if err := confirmDeletion(ctx, streams, warning, question); err != nil {
+			return err
 		}
 	}
 
-	if err := hubClient.RemoveTag(reference.FamiliarName(ref), ref.Tag()); err != nil {
+	if err := hubClient.RemoveTag(ctx, reference.FamiliarName(ref), ref.Tag()); err != nil {
 		return err
 	}
 	fmt.Fprintln(streams.Out(), "Deleted", image)
 	return nil
 }
+
+// runRmDigest deletes an image by its digest, asking whether the tags still
+// referencing it should be deleted too
+func runRmDigest(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts rmOptions, ref reference.Canonical) error {
+	name := reference.FamiliarName(ref)
+	digest := ref.Digest().String()
+	if hubClient.DryRun() {
+		fmt.Fprintf(streams.Out(), "Would delete %s@%s\n", name, digest)
+		return nil
+	}
+	if !opts.force {
+		warning := fmt.Sprintf(`WARNING: You are about to permanently delete image "%s@%s"`, name, digest)
+		question := fmt.Sprintf("Are you sure you want to delete the image %s from repository %q?", digest, name)
+		if err := confirmDeletion(ctx, streams, warning, question); err != nil {
+			return err
+		}
+	}
+
+	err := hubClient.RemoveImage(ctx, name, digest, opts.force)
+	if referenced, ok := err.(*hub.ImageReferencedError); ok {
+		warning := fmt.Sprintf("WARNING: The image is still referenced by the tags %s", strings.Join(referenced.Tags, ", "))
+		if err := confirmDeletion(ctx, streams, warning, "Are you sure you want to delete the image along with these tags?"); err != nil {
+			return err
+		}
+		err = hubClient.RemoveImage(ctx, name, digest, true)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(streams.Out(), "Deleted %s@%s\n", name, digest)
+	return nil
+}
+
+func runRmMatching(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts rmOptions, repository string) error {
+	normRef, err := reference.ParseNormalizedNamed(repository)
+	if err != nil {
+		return err
+	}
+	if _, ok := normRef.(reference.Tagged); ok {
+		return fmt.Errorf("invalid reference: tag can't be specified with --match")
+	}
+	match, err := tagMatcher(opts.match, opts.regexp)
+	if err != nil {
+		return err
+	}
+	name := reference.FamiliarName(normRef)
+
+	if err := hubClient.Update(hub.WithAllElements()); err != nil {
+		return err
+	}
+	tags, _, err := hubClient.GetTags(ctx, name)
+	if err != nil {
+		return err
+	}
+	var matching []string
+	for _, tag := range tags {
+		if t := shortTagName(tag); match(t) {
+			matching = append(matching, t)
+		}
+	}
+	if len(matching) == 0 {
+		fmt.Fprintln(streams.Out(), "No tag matches", opts.match)
+		return nil
+	}
+
+	if hubClient.DryRun() {
+		for _, tag := range matching {
+			fmt.Fprintf(streams.Out(), "Would delete %s:%s\n", name, tag)
+		}
+		return nil
+	}
+
+	if !opts.force {
+		warning := fmt.Sprintf("WARNING: You are about to permanently delete %d tag(s) from repository %q", len(matching), name)
+		if err := confirmDeletion(ctx, streams, warning, "Are you sure you want to delete these tags?"); err != nil {
+			return err
+		}
+	}
+
+	return removeTags(ctx, streams, hubClient, opts.Deleter, name, matching)
+}
+
+// removeTags deletes concurrently the tags of a repository, reporting each
+// deletion
+func removeTags(ctx context.Context, streams command.Streams, hubClient *hub.Client, deleter bulk.Deleter, repository string, tags []string) error {
+	return deleter.Delete(streams, hubClient, "tags", len(tags), func(report func(string, error)) error {
+		_, err := hubClient.RemoveTags(ctx, repository, tags, func(tag string, err error) {
+			report(repository+":"+tag, err)
+		})
+		return err
+	})
+}
+
+// confirmDeletion prints the warning and asks the user to confirm the deletion
+func confirmDeletion(ctx context.Context, streams command.Streams, warning, question string) error {
+	fmt.Fprintln(streams.Out(), ansi.Warn(warning))
+	fmt.Fprintln(streams.Out(), ansi.Warn("         This action is irreversible"))
+	fmt.Fprint(streams.Out(), ansi.Info(question+" [y/N] "))
+	userIn := make(chan string, 1)
+	go func() {
+		reader := bufio.NewReader(streams.In())
+		input, _ := reader.ReadString('\n')
+		userIn <- strings.ToLower(strings.TrimSpace(input))
+	}()
+	input := ""
+	select {
+	case <-ctx.Done():
+		return errdef.ErrCanceled
+	case input = <-userIn:
+	}
+	if input != "y" {
+		return errors.New("deletion aborted")
+	}
+	return nil
+}
+
+// tagMatcher returns a function telling if a tag name matches the whole
+// pattern, either a glob or a regular expression
+func tagMatcher(pattern string, isRegexp bool) (func(string) bool, error) {
+	if isRegexp {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %s", pattern, err)
+		}
+		return re.MatchString, nil
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %s", pattern, err)
+	}
+	return func(tag string) bool {
+		matched, _ := path.Match(pattern, tag)
+		return matched
+	}, nil
+}
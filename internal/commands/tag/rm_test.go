@@ -0,0 +1,85 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package tag
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestTagMatcher(t *testing.T) {
+	tags := []string{"v1", "v10", "v1.2", "v1.2-rc", "dev1", "latest"}
+	testCases := []struct {
+		name          string
+		pattern       string
+		regexp        bool
+		matching      []string
+		expectedError string
+	}{
+		{
+			name:     "glob",
+			pattern:  "v1.*",
+			matching: []string{"v1.2", "v1.2-rc"},
+		},
+		{
+			name:     "glob matches the whole tag",
+			pattern:  "v1",
+			matching: []string{"v1"},
+		},
+		{
+			name:     "regexp matches the whole tag",
+			pattern:  "v1",
+			regexp:   true,
+			matching: []string{"v1"},
+		},
+		{
+			name:     "regexp alternation is anchored",
+			pattern:  "v1|dev.*",
+			regexp:   true,
+			matching: []string{"v1", "dev1"},
+		},
+		{
+			name:          "invalid glob",
+			pattern:       "v1[",
+			expectedError: `invalid pattern "v1["`,
+		},
+		{
+			name:          "invalid regexp",
+			pattern:       "v1(",
+			regexp:        true,
+			expectedError: `invalid pattern "v1("`,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			match, err := tagMatcher(testCase.pattern, testCase.regexp)
+			if testCase.expectedError != "" {
+				assert.ErrorContains(t, err, testCase.expectedError)
+				return
+			}
+			assert.NilError(t, err)
+			var matching []string
+			for _, tag := range tags {
+				if match(tag) {
+					matching = append(matching, tag)
+				}
+			}
+			assert.DeepEqual(t, matching, testCase.matching)
+		})
+	}
+}
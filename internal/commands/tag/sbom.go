@@ -0,0 +1,153 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package tag
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/containerd/containerd/platforms"
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/distribution/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/internal/registry"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	sbomName = "sbom"
+)
+
+// sbomFormats are the predicate types of the SBOM formats
+var sbomFormats = map[string]string{
+	"spdx":      registry.SPDXPredicateType,
+	"cyclonedx": registry.CycloneDXPredicateType,
+}
+
+type sbomOptions struct {
+	platform   string
+	sbomFormat string
+	output     string
+}
+
+func newSbomCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts sbomOptions
+	cmd := &cobra.Command{
+		Use:   sbomName + " [OPTIONS] REPOSITORY:TAG",
+		Short: "Print the SBOM attached to an image, as SPDX or CycloneDX JSON",
+		Long: `Print the SBOM attached to an image, as SPDX or CycloneDX JSON, without pulling it.
+The SBOM is read from the attestations pushed by BuildKit with the image, or by cosign attest.`,
+		Example:               "  hub-tool tag sbom myorg/app:1.0 --platform linux/arm64 -o sbom.spdx.json",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, sbomName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSbom(cmd.Context(), streams, hubClient, opts, args[0])
+		},
+	}
+	cmd.Flags().StringVar(&opts.platform, "platform", defaultPlatform, "Platform of the image, for multi-platform images")
+	cmd.Flags().StringVar(&opts.sbomFormat, "sbom-format", "", `Format of the SBOM, "spdx" or "cyclonedx", the first one found by default`)
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "", "Write the SBOM to this file instead of the standard output")
+	return cmd
+}
+
+func runSbom(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts sbomOptions, imageRef string) error {
+	predicateTypes := []string{registry.SPDXPredicateType, registry.CycloneDXPredicateType}
+	if opts.sbomFormat != "" {
+		predicateType, ok := sbomFormats[opts.sbomFormat]
+		if !ok {
+			return fmt.Errorf("invalid SBOM format %q, must be spdx or cyclonedx", opts.sbomFormat)
+		}
+		predicateTypes = []string{predicateType}
+	}
+	platform, err := platforms.Parse(opts.platform)
+	if err != nil {
+		return fmt.Errorf("invalid platform %q: %s", opts.platform, err)
+	}
+	ref, err := reference.ParseNormalizedNamed(imageRef)
+	if err != nil {
+		return err
+	}
+	ref = reference.TagNameOnly(ref)
+
+	attestations, err := registry.GetAttestations(ctx, registry.NewResolver(hubClient), ref.String(), predicateTypes...)
+	if err != nil {
+		return err
+	}
+	attestation := selectAttestation(attestations, platform)
+	if attestation == nil {
+		return fmt.Errorf("no %s SBOM attached to %s for %s", strings.Join(sbomFormatNames(predicateTypes), " or "), reference.FamiliarString(ref), platforms.Format(platform))
+	}
+	return writePredicate(streams.Out(), opts.output, attestation.Statement.Predicate)
+}
+
+// selectAttestation returns the attestation of the platform, or the one about
+// the whole image if there's none
+func selectAttestation(attestations []registry.Attestation, platform ocispec.Platform) *registry.Attestation {
+	matcher := platforms.NewMatcher(platform)
+	var whole *registry.Attestation
+	for i, attestation := range attestations {
+		if attestation.Platform == "" {
+			if whole == nil {
+				whole = &attestations[i]
+			}
+			continue
+		}
+		p, err := platforms.Parse(attestation.Platform)
+		if err == nil && matcher.Match(p) {
+			return &attestations[i]
+		}
+	}
+	return whole
+}
+
+func sbomFormatNames(predicateTypes []string) []string {
+	var names []string
+	for _, predicateType := range predicateTypes {
+		for name, t := range sbomFormats {
+			if t == predicateType {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// writePredicate writes an indented predicate to the output file, or out if
+// there's none
+func writePredicate(out io.Writer, output string, predicate json.RawMessage) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, predicate, "", "  "); err != nil {
+		return err
+	}
+	buf.WriteString("\n")
+	if output == "" {
+		_, err := buf.WriteTo(out)
+		return err
+	}
+	return ioutil.WriteFile(output, buf.Bytes(), 0644)
+}
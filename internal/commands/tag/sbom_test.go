@@ -0,0 +1,47 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package tag
+
+import (
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/hub-tool/internal/registry"
+)
+
+func TestSelectAttestation(t *testing.T) {
+	attestations := []registry.Attestation{
+		{Source: registry.AttestationSourceCosign},
+		{Platform: "linux/amd64", Source: registry.AttestationSourceBuildKit},
+		{Platform: "linux/arm64/v8", Source: registry.AttestationSourceBuildKit},
+	}
+	testCases := []struct {
+		name     string
+		platform ocispec.Platform
+		expected *registry.Attestation
+	}{
+		{name: "platform", platform: ocispec.Platform{OS: "linux", Architecture: "arm64"}, expected: &attestations[2]},
+		{name: "whole image", platform: ocispec.Platform{OS: "windows", Architecture: "amd64"}, expected: &attestations[0]},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, selectAttestation(attestations, tc.platform), tc.expected)
+		})
+	}
+	assert.Assert(t, selectAttestation(nil, ocispec.Platform{OS: "linux", Architecture: "amd64"}) == nil)
+}
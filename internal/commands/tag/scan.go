@@ -0,0 +1,209 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package tag
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/distribution/reference"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/format/tabwriter"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/internal/sarif"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	scanReportName  = "scan-report"
+	defaultPlatform = "linux/amd64"
+	sarifFormat     = "sarif"
+)
+
+var (
+	vulnerabilityColumns = []vulnerabilityColumn{
+		{"ID", func(v hub.Vulnerability) (string, int) { return v.ID, len(v.ID) }},
+		{"SEVERITY", func(v hub.Vulnerability) (string, int) { return colorSeverity(v.Severity), len(v.Severity) }},
+		{"PACKAGE", func(v hub.Vulnerability) (string, int) { return v.Package, len(v.Package) }},
+		{"VERSION", func(v hub.Vulnerability) (string, int) { return v.Version, len(v.Version) }},
+		{"FIXED IN", func(v hub.Vulnerability) (string, int) { return v.FixedVersion, len(v.FixedVersion) }},
+		{"TITLE", func(v hub.Vulnerability) (string, int) { return v.Title, len(v.Title) }},
+	}
+)
+
+type vulnerabilityColumn struct {
+	header string
+	value  func(v hub.Vulnerability) (string, int)
+}
+
+type scanReportOptions struct {
+	format.Option
+	platform string
+	severity string
+}
+
+func newScanReportCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts scanReportOptions
+	cmd := &cobra.Command{
+		Use:   scanReportName + " [OPTIONS] REPOSITORY:TAG",
+		Short: "Print the vulnerabilities found by the scan of an image",
+		Long: `Print the vulnerabilities found by the Docker Hub vulnerability scan of an image.
+The command fails when critical vulnerabilities are found, to be used as a gate in CI.
+With --format sarif, the report is printed in the SARIF format to be uploaded to GitHub code scanning.`,
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, scanReportName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runScanReport(cmd.Context(), streams, hubClient, opts, args[0])
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	cmd.Flags().StringVar(&opts.platform, "platform", "", "Platform of the image of a multi-platform tag, given as os/arch[/variant] (default linux/amd64)")
+	cmd.Flags().StringVar(&opts.severity, "severity", hub.SeverityLow, `Only print the vulnerabilities of this severity or higher, "low", "medium", "high" or "critical"`)
+	return cmd
+}
+
+func runScanReport(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts scanReportOptions, image string) error {
+	minRank := hub.SeverityRank(opts.severity)
+	if minRank < 0 {
+		return fmt.Errorf("invalid severity %q: should be either %s", opts.severity, strings.Join(hub.Severities, ", "))
+	}
+	normRef, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return err
+	}
+	normRef = reference.TagNameOnly(normRef)
+	ref, ok := normRef.(reference.NamedTagged)
+	if !ok {
+		return fmt.Errorf("invalid reference: tag must be specified")
+	}
+	name := reference.FamiliarName(ref)
+
+	tag, err := hubClient.GetTag(ctx, name, ref.Tag())
+	if err != nil {
+		return err
+	}
+	digest, err := scannedImage(*tag, opts.platform)
+	if err != nil {
+		return err
+	}
+	report, err := hubClient.GetScanReport(ctx, name, digest)
+	if err != nil {
+		return err
+	}
+
+	critical := 0
+	vulnerabilities := []hub.Vulnerability{}
+	for _, vulnerability := range report.Vulnerabilities {
+		if vulnerability.Severity == hub.SeverityCritical {
+			critical++
+		}
+		if hub.SeverityRank(vulnerability.Severity) >= minRank {
+			vulnerabilities = append(vulnerabilities, vulnerability)
+		}
+	}
+	report.Vulnerabilities = vulnerabilities
+	if opts.Format() == sarifFormat {
+		err = sarif.Write(streams.Out(), reference.FamiliarString(ref), report)
+	} else {
+		err = opts.Print(streams.Out(), scanReport{report}, printScanReport)
+	}
+	if err != nil {
+		return err
+	}
+	if critical > 0 {
+		return fmt.Errorf("found %d critical vulnerabilities in %s", critical, reference.FamiliarString(ref))
+	}
+	return nil
+}
+
+// scannedImage returns the digest of the image of the tag for the platform,
+// the only image of single platform tags being returned by default
+func scannedImage(tag hub.Tag, platform string) (string, error) {
+	if platform == "" {
+		if len(tag.Images) == 1 {
+			return tag.Images[0].Digest, nil
+		}
+		platform = defaultPlatform
+	}
+	selected, err := selectPlatform([]hub.Tag{tag}, platform)
+	if err != nil {
+		return "", err
+	}
+	if len(selected) == 0 {
+		return "", fmt.Errorf("no image for platform %s in %s", platform, tag.Name)
+	}
+	return selected[0].Images[0].Digest, nil
+}
+
+func printScanReport(out io.Writer, value interface{}) error {
+	report := value.(scanReport)
+	if len(report.Vulnerabilities) == 0 {
+		fmt.Fprintln(out, "No vulnerability found")
+		return nil
+	}
+	tw := tabwriter.New(out, "    ")
+	for _, column := range vulnerabilityColumns {
+		tw.Column(ansi.Header(column.header), len(column.header))
+	}
+
+	tw.Line()
+
+	for _, vulnerability := range report.Vulnerabilities {
+		for _, column := range vulnerabilityColumns {
+			value, width := column.value(vulnerability)
+			tw.Column(value, width)
+		}
+		tw.Line()
+	}
+
+	return tw.Flush()
+}
+
+func colorSeverity(severity string) string {
+	switch severity {
+	case hub.SeverityCritical:
+		return ansi.Error(severity)
+	case hub.SeverityHigh:
+		return ansi.Warn(severity)
+	default:
+		return severity
+	}
+}
+
+// scanReport prints a row per vulnerability in csv and tsv
+type scanReport struct {
+	*hub.ScanReport
+}
+
+// Table returns the raw values of the vulnerabilities
+func (r scanReport) Table() ([]string, [][]interface{}) {
+	rows := make([][]interface{}, len(r.Vulnerabilities))
+	for i, v := range r.Vulnerabilities {
+		rows[i] = []interface{}{v.ID, v.Severity, v.Package, v.Version, v.FixedVersion, v.Title}
+	}
+	return []string{"ID", "SEVERITY", "PACKAGE", "VERSION", "FIXED IN", "TITLE"}, rows
+}
@@ -0,0 +1,48 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package tag
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+func TestScannedImage(t *testing.T) {
+	single := hub.Tag{Name: "jdoe/app:arm", Images: []hub.Image{{Digest: "sha256:arm", Os: "linux", Architecture: "arm64"}}}
+	multi := hub.Tag{Name: "jdoe/app:latest", Images: []hub.Image{
+		{Digest: "sha256:arm", Os: "linux", Architecture: "arm64"},
+		{Digest: "sha256:amd", Os: "linux", Architecture: "amd64"},
+	}}
+
+	digest, err := scannedImage(single, "")
+	assert.NilError(t, err)
+	assert.Equal(t, digest, "sha256:arm")
+
+	digest, err = scannedImage(multi, "")
+	assert.NilError(t, err)
+	assert.Equal(t, digest, "sha256:amd")
+
+	digest, err = scannedImage(multi, "linux/arm64")
+	assert.NilError(t, err)
+	assert.Equal(t, digest, "sha256:arm")
+
+	_, err = scannedImage(single, "linux/amd64")
+	assert.Error(t, err, "no image for platform linux/amd64 in jdoe/app:arm")
+}
@@ -0,0 +1,143 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package tag
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/distribution/reference"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/internal/registry"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	verifyName = "verify"
+)
+
+type verifyOptions struct {
+	format.Option
+	identity string
+	issuer   string
+}
+
+// verification holds the cosign artifacts attached to an image
+type verification struct {
+	Image        string                     `json:"image"`
+	Digest       string                     `json:"digest"`
+	Signatures   []registry.CosignSignature `json:"signatures"`
+	Attestations int                        `json:"attestations"`
+}
+
+func newVerifyCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts verifyOptions
+	cmd := &cobra.Command{
+		Use:   verifyName + " [OPTIONS] REPOSITORY:TAG",
+		Short: "Check an image has cosign signatures, and print their signer identity",
+		Long: `Check an image has cosign signatures, pushed to its sha256-<digest>.sig tag, and print the identity of their signing certificate.
+The signatures are only looked for, use cosign verify to check them cryptographically.`,
+		Example:               "  hub-tool tag verify myorg/app:1.0 --certificate-identity release@example.com",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, verifyName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runVerify(cmd.Context(), streams, hubClient, opts, args[0])
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	cmd.Flags().StringVar(&opts.identity, "certificate-identity", "", "Fail unless a signature was made by this identity, an email or a URI")
+	cmd.Flags().StringVar(&opts.issuer, "certificate-oidc-issuer", "", "Fail unless a signature identity was authenticated by this OIDC issuer")
+	return cmd
+}
+
+func runVerify(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts verifyOptions, imageRef string) error {
+	ref, err := reference.ParseNormalizedNamed(imageRef)
+	if err != nil {
+		return err
+	}
+	ref = reference.TagNameOnly(ref)
+	resolver := registry.NewResolver(hubClient)
+	_, descriptor, err := resolver.Resolve(ctx, ref.String())
+	if err != nil {
+		return err
+	}
+	signatures, err := registry.GetCosignSignatures(ctx, resolver, ref.Name(), descriptor)
+	if err != nil {
+		return err
+	}
+	attestations, err := registry.GetCosignManifest(ctx, resolver, ref.Name(), descriptor, registry.CosignAttestationSuffix)
+	if err != nil {
+		return err
+	}
+	result := verification{
+		Image:      reference.FamiliarString(ref),
+		Digest:     descriptor.Digest.String(),
+		Signatures: signatures,
+	}
+	if attestations != nil {
+		result.Attestations = len(attestations.Layers)
+	}
+	if err := opts.Print(streams.Out(), result, printVerification); err != nil {
+		return err
+	}
+
+	if len(signatures) == 0 {
+		return fmt.Errorf("%s has no cosign signature", result.Image)
+	}
+	if opts.identity != "" || opts.issuer != "" {
+		for _, signature := range signatures {
+			if (opts.identity == "" || signature.Identity == opts.identity) && (opts.issuer == "" || signature.Issuer == opts.issuer) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s has no cosign signature matching the expected identity", result.Image)
+	}
+	return nil
+}
+
+func printVerification(out io.Writer, value interface{}) error {
+	result := value.(verification)
+	fmt.Fprintf(out, ansi.Key("Image:")+"\t\t%s\n", result.Image)
+	fmt.Fprintf(out, ansi.Key("Digest:")+"\t\t%s\n", result.Digest)
+	fmt.Fprintf(out, ansi.Key("Attestations:")+"\t%d\n", result.Attestations)
+	if len(result.Signatures) == 0 {
+		fmt.Fprintf(out, ansi.Key("Signatures:")+"\t%s\n", ansi.Warn("none"))
+		return nil
+	}
+	fmt.Fprintf(out, ansi.Key("Signatures:")+"\t%d\n", len(result.Signatures))
+	for _, signature := range result.Signatures {
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, ansi.Key("  Payload:")+"\t%s\n", signature.Digest)
+		if signature.Identity == "" {
+			fmt.Fprintf(out, ansi.Key("  Identity:")+"\tsigned with a key\n")
+		} else {
+			fmt.Fprintf(out, ansi.Key("  Identity:")+"\t%s\n", signature.Identity)
+			fmt.Fprintf(out, ansi.Key("  Issuer:")+"\t%s\n", signature.Issuer)
+		}
+		fmt.Fprintf(out, ansi.Key("  Rekor:")+"\t%v\n", signature.TransparencyLog)
+	}
+	return nil
+}
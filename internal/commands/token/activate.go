@@ -17,6 +17,7 @@
 package token
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/docker/cli/cli"
@@ -25,8 +26,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/docker/hub-tool/internal/ansi"
-	"github.com/docker/hub-tool/internal/hub"
 	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
@@ -45,19 +46,19 @@ func newActivateCmd(streams command.Streams, hubClient *hub.Client, parent strin
 		PreRun: func(cmd *cobra.Command, args []string) {
 			metrics.Send(parent, activateName)
 		},
-		RunE: func(_ *cobra.Command, args []string) error {
-			return runActivate(streams, hubClient, args[0])
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runActivate(cmd.Context(), streams, hubClient, args[0])
 		},
 	}
 	return cmd
 }
 
-func runActivate(streams command.Streams, hubClient *hub.Client, tokenUUID string) error {
+func runActivate(ctx context.Context, streams command.Streams, hubClient *hub.Client, tokenUUID string) error {
 	u, err := uuid.Parse(tokenUUID)
 	if err != nil {
 		return err
 	}
-	if _, err := hubClient.UpdateToken(u.String(), "", true); err != nil {
+	if _, err := hubClient.UpdateToken(ctx, u.String(), "", true); err != nil {
 		return err
 	}
 	fmt.Fprintf(streams.Out(), ansi.Emphasise("%s is active\n"), u.String())
@@ -21,7 +21,7 @@ import (
 	"github.com/docker/cli/cli/command"
 	"github.com/spf13/cobra"
 
-	"github.com/docker/hub-tool/internal/hub"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
@@ -45,7 +45,10 @@ func NewTokenCmd(streams command.Streams, hubClient *hub.Client) *cobra.Command
 		newListCmd(streams, hubClient, tokenName),
 		newActivateCmd(streams, hubClient, tokenName),
 		newDeactivateCmd(streams, hubClient, tokenName),
+		newExchangeCmd(streams, hubClient, tokenName),
+		newPruneCmd(streams, hubClient, tokenName),
 		newRmCmd(streams, hubClient, tokenName),
+		newUpdateCmd(streams, hubClient, tokenName),
 	)
 	return cmd
 }
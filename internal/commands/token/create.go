@@ -17,8 +17,10 @@
 package token
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli/command"
@@ -26,8 +28,8 @@ import (
 
 	"github.com/docker/hub-tool/internal/ansi"
 	"github.com/docker/hub-tool/internal/format"
-	"github.com/docker/hub-tool/internal/hub"
 	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
@@ -37,6 +39,7 @@ const (
 type createOptions struct {
 	format.Option
 	description string
+	scopes      []string
 	quiet       bool
 }
 
@@ -53,18 +56,22 @@ func newCreateCmd(streams command.Streams, hubClient *hub.Client, parent string)
 		PreRun: func(cmd *cobra.Command, args []string) {
 			metrics.Send(parent, createName)
 		},
-		RunE: func(_ *cobra.Command, args []string) error {
-			return runCreate(streams, hubClient, opts)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCreate(cmd.Context(), streams, hubClient, opts)
 		},
 	}
 	opts.AddFormatFlag(cmd.Flags())
 	cmd.Flags().StringVar(&opts.description, "description", "", "Set token's description")
+	cmd.Flags().StringSliceVar(&opts.scopes, "scope", nil, fmt.Sprintf("Scope given to the token, can be repeated (%s)", strings.Join(hub.TokenScopes, ", ")))
 	cmd.Flags().BoolVar(&opts.quiet, "quiet", false, "Display only created token")
 	return cmd
 }
 
-func runCreate(streams command.Streams, hubClient *hub.Client, opts createOptions) error {
-	token, err := hubClient.CreateToken(opts.description)
+func runCreate(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts createOptions) error {
+	if err := hub.ValidateTokenScopes(opts.scopes); err != nil {
+		return err
+	}
+	token, err := hubClient.CreateToken(ctx, opts.description, opts.scopes...)
 	if err != nil {
 		return err
 	}
@@ -17,6 +17,7 @@
 package token
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/docker/cli/cli"
@@ -25,8 +26,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/docker/hub-tool/internal/ansi"
-	"github.com/docker/hub-tool/internal/hub"
 	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
@@ -45,19 +46,19 @@ func newDeactivateCmd(streams command.Streams, hubClient *hub.Client, parent str
 		PreRun: func(cmd *cobra.Command, args []string) {
 			metrics.Send(parent, deactivateName)
 		},
-		RunE: func(_ *cobra.Command, args []string) error {
-			return runDeactivate(streams, hubClient, args[0])
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDeactivate(cmd.Context(), streams, hubClient, args[0])
 		},
 	}
 	return cmd
 }
 
-func runDeactivate(streams command.Streams, hubClient *hub.Client, tokenUUID string) error {
+func runDeactivate(ctx context.Context, streams command.Streams, hubClient *hub.Client, tokenUUID string) error {
 	u, err := uuid.Parse(tokenUUID)
 	if err != nil {
 		return err
 	}
-	if _, err := hubClient.UpdateToken(u.String(), "", false); err != nil {
+	if _, err := hubClient.UpdateToken(ctx, u.String(), "", false); err != nil {
 		return err
 	}
 	fmt.Fprintf(streams.Out(), ansi.Emphasise("%s is inactive\n"), u.String())
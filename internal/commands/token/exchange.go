@@ -0,0 +1,75 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package token
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	exchangeName = "exchange"
+)
+
+type exchangeOptions struct {
+	format.Option
+	scope string
+}
+
+func newExchangeCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts exchangeOptions
+	cmd := &cobra.Command{
+		Use:   exchangeName + " [OPTIONS] REPOSITORY",
+		Short: "Print a short-lived registry token to pull or push a repository",
+		Long: `Print a short-lived bearer token to pull or push a repository, for curl or other registry tooling.
+Without being logged in, the token can only pull the public repositories.`,
+		Example: `  TOKEN=$(hub-tool token exchange library/alpine)
+  curl -H "Authorization: Bearer $TOKEN" https://registry-1.docker.io/v2/library/alpine/tags/list`,
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		Annotations: map[string]string{
+			"public": "true",
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, exchangeName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			token, err := hubClient.RegistryToken(cmd.Context(), args[0], opts.scope)
+			if err != nil {
+				return err
+			}
+			return opts.Print(streams.Out(), token, printRegistryToken)
+		},
+	}
+	cmd.Flags().StringVar(&opts.scope, "scope", "pull", fmt.Sprintf("Actions allowed by the token (%s)", strings.Join(hub.RegistryTokenScopes, ", ")))
+	opts.AddFormatFlag(cmd.Flags())
+	return cmd
+}
+
+func printRegistryToken(out io.Writer, value interface{}) error {
+	token := value.(*hub.RegistryToken)
+	fmt.Fprintln(out, token.Token)
+	return nil
+}
@@ -17,6 +17,7 @@
 package token
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"strings"
@@ -30,8 +31,8 @@ import (
 
 	"github.com/docker/hub-tool/internal/ansi"
 	"github.com/docker/hub-tool/internal/format"
-	"github.com/docker/hub-tool/internal/hub"
 	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
@@ -56,19 +57,19 @@ func newInspectCmd(streams command.Streams, hubClient *hub.Client, parent string
 			metrics.Send(parent, inspectName)
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runInspect(streams, hubClient, opts, args[0])
+			return runInspect(cmd.Context(), streams, hubClient, opts, args[0])
 		},
 	}
 	opts.AddFormatFlag(cmd.Flags())
 	return cmd
 }
 
-func runInspect(streams command.Streams, hubClient *hub.Client, opts inspectOptions, tokenUUID string) error {
+func runInspect(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts inspectOptions, tokenUUID string) error {
 	u, err := uuid.Parse(tokenUUID)
 	if err != nil {
 		return err
 	}
-	token, err := hubClient.GetToken(u.String())
+	token, err := hubClient.GetToken(ctx, u.String())
 	if err != nil {
 		return err
 	}
@@ -84,8 +85,12 @@ func printInspectToken(out io.Writer, value interface{}) error {
 		fmt.Fprintf(out, ansi.Key("Description:")+"\t%s\n", token.Description)
 	}
 	fmt.Fprintf(out, ansi.Key("Is Active:")+"\t%v\n", token.IsActive)
+	fmt.Fprintf(out, ansi.Key("Scopes:")+"\t%s\n", scopes(*token))
 	fmt.Fprintf(out, ansi.Key("Created:")+"\t%s\n", fmt.Sprintf("%s ago", units.HumanDuration(time.Since(token.CreatedAt))))
 	fmt.Fprintf(out, ansi.Key("Last Used:")+"\t%s\n", getLastUsed(token.LastUsed))
+	if token.LastUsedIP != "" {
+		fmt.Fprintf(out, ansi.Key("Last Used IP:")+"\t%s\n", token.LastUsedIP)
+	}
 	fmt.Fprintf(out, ansi.Key("Creator User Agent:")+"\t%s\n", token.CreatorUA)
 	fmt.Fprintf(out, ansi.Key("Creator IP:")+"\t%s\n", token.CreatorIP)
 	fmt.Fprintf(out, ansi.Key("Generated:")+"\t%s\n", getGeneratedBy(token))
@@ -17,8 +17,10 @@
 package token
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/docker/cli/cli"
@@ -26,11 +28,12 @@ import (
 	"github.com/docker/go-units"
 	"github.com/spf13/cobra"
 
+	"github.com/docker/hub-tool/internal/age"
 	"github.com/docker/hub-tool/internal/ansi"
 	"github.com/docker/hub-tool/internal/format"
 	"github.com/docker/hub-tool/internal/format/tabwriter"
-	"github.com/docker/hub-tool/internal/hub"
 	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
@@ -41,6 +44,10 @@ var (
 	defaultColumns = []column{
 		{"DESCRIPTION", func(t hub.Token) (string, int) { return t.Description, len(t.Description) }},
 		{"UUID", func(t hub.Token) (string, int) { return t.UUID.String(), len(t.UUID.String()) }},
+		{"SCOPES", func(t hub.Token) (string, int) {
+			s := scopes(t)
+			return s, len(s)
+		}},
 		{"LAST USED", func(t hub.Token) (string, int) {
 			s := "Never"
 			if !t.LastUsed.IsZero() {
@@ -48,8 +55,9 @@ var (
 			}
 			return s, len(s)
 		}},
+		{"LAST USED IP", func(t hub.Token) (string, int) { return t.LastUsedIP, len(t.LastUsedIP) }},
 		{"CREATED", func(t hub.Token) (string, int) {
-			s := units.HumanDuration(time.Since(t.CreatedAt))
+			s := t.CreatedAt.Local().Format("2006-01-02")
 			return s, len(s)
 		}},
 		{"ACTIVE", func(t hub.Token) (string, int) {
@@ -66,7 +74,8 @@ type column struct {
 
 type listOptions struct {
 	format.Option
-	all bool
+	all         bool
+	unusedSince string
 }
 
 func newListCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
@@ -75,6 +84,7 @@ func newListCmd(streams command.Streams, hubClient *hub.Client, parent string) *
 		Use:                   lsName + " [OPTION]",
 		Aliases:               []string{"list"},
 		Short:                 "List all the Personal Access Tokens",
+		Example:               "  hub-tool token ls --unused-since 90d",
 		Args:                  cli.NoArgs,
 		DisableFlagsInUseLine: true,
 		Annotations: map[string]string{
@@ -83,31 +93,39 @@ func newListCmd(streams command.Streams, hubClient *hub.Client, parent string) *
 		PreRun: func(cmd *cobra.Command, args []string) {
 			metrics.Send(parent, lsName)
 		},
-		RunE: func(_ *cobra.Command, args []string) error {
-			return runList(streams, hubClient, opts)
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(cmd.Context(), streams, hubClient, opts)
 		},
 	}
 	cmd.Flags().BoolVar(&opts.all, "all", false, "Fetch all available tokens")
+	cmd.Flags().StringVar(&opts.unusedSince, "unused-since", "", "Only list the active tokens not used since this age, e.g. 90d, 4w or 1y")
 	opts.AddFormatFlag(cmd.Flags())
 	return cmd
 }
 
-func runList(streams command.Streams, hubClient *hub.Client, opts listOptions) error {
+func runList(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts listOptions) error {
+	if opts.unusedSince != "" {
+		tokens, err := findUnusedTokens(ctx, hubClient, opts.unusedSince)
+		if err != nil {
+			return err
+		}
+		return opts.Print(streams.Out(), tokenList(tokens), printTokens(len(tokens)))
+	}
 	if opts.all {
 		if err := hubClient.Update(hub.WithAllElements()); err != nil {
 			return err
 		}
 	}
-	tokens, total, err := hubClient.GetTokens()
+	tokens, total, err := hubClient.GetTokens(ctx)
 	if err != nil {
 		return err
 	}
-	return opts.Print(streams.Out(), tokens, printTokens(total))
+	return opts.Print(streams.Out(), tokenList(tokens), printTokens(total))
 }
 
 func printTokens(total int) format.PrettyPrinter {
 	return func(out io.Writer, values interface{}) error {
-		tokens := values.([]hub.Token)
+		tokens := values.(tokenList)
 		tw := tabwriter.New(out, "    ")
 		for _, column := range defaultColumns {
 			tw.Column(ansi.Header(column.header), len(column.header))
@@ -131,3 +149,33 @@ func printTokens(total int) format.PrettyPrinter {
 		return nil
 	}
 }
+
+// tokenList prints a row per token in csv and tsv
+type tokenList []hub.Token
+
+// Table returns the raw values of the tokens
+func (l tokenList) Table() ([]string, [][]interface{}) {
+	rows := make([][]interface{}, len(l))
+	for i, t := range l {
+		rows[i] = []interface{}{t.Description, t.UUID, strings.Join(t.Scopes, " "), t.LastUsed, t.LastUsedIP, t.CreatedAt, t.IsActive}
+	}
+	return []string{"DESCRIPTION", "UUID", "SCOPES", "LAST USED", "LAST USED IP", "CREATED", "ACTIVE"}, rows
+}
+
+// scopes returns the scopes of a token, the ones created before the scopes
+// were introduced having them all
+func scopes(t hub.Token) string {
+	if len(t.Scopes) == 0 {
+		return "all"
+	}
+	return strings.Join(t.Scopes, ", ")
+}
+
+// findUnusedTokens returns the active tokens not used for the given age
+func findUnusedTokens(ctx context.Context, hubClient *hub.Client, unusedSince string) ([]hub.Token, error) {
+	maxAge, err := age.Parse(unusedSince)
+	if err != nil {
+		return nil, err
+	}
+	return hubClient.FindUnusedTokens(ctx, time.Now().Add(-maxAge))
+}
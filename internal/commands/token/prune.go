@@ -0,0 +1,119 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package token
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/errdef"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	pruneName = "prune"
+)
+
+type pruneOptions struct {
+	unusedSince string
+	force       bool
+}
+
+func newPruneCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var opts pruneOptions
+	cmd := &cobra.Command{
+		Use:                   pruneName + " [OPTIONS]",
+		Short:                 "Deactivate the Personal Access Tokens not used for a while",
+		Example:               "  hub-tool token prune --unused-since 90d",
+		Args:                  cli.NoArgs,
+		DisableFlagsInUseLine: true,
+		Annotations: map[string]string{
+			"sudo": "true",
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, pruneName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := runPrune(cmd.Context(), streams, hubClient, opts)
+			if errors.Is(err, errdef.ErrCanceled) {
+				return nil
+			}
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&opts.unusedSince, "unused-since", "", "Deactivate the tokens not used since this age, e.g. 90d, 4w or 1y")
+	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Do not prompt for confirmation")
+	_ = cmd.MarkFlagRequired("unused-since")
+	return cmd
+}
+
+func runPrune(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts pruneOptions) error {
+	tokens, err := findUnusedTokens(ctx, hubClient, opts.unusedSince)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		fmt.Fprintf(streams.Out(), "No token unused since %s\n", opts.unusedSince)
+		return nil
+	}
+
+	if !opts.force {
+		fmt.Fprintln(streams.Out(), ansi.Warn(fmt.Sprintf("WARNING: You are about to deactivate %d token(s) not used since %s:", len(tokens), opts.unusedSince)))
+		for _, token := range tokens {
+			fmt.Fprintf(streams.Out(), "  %s %s\n", token.UUID, token.Description)
+		}
+		fmt.Fprintln(streams.Out(), ansi.Warn("         The Docker clients authenticated with them will fail"))
+		fmt.Fprint(streams.Out(), ansi.Info("Are you sure you want to deactivate these tokens? [y/N] "))
+		userIn := make(chan string, 1)
+		go func() {
+			reader := bufio.NewReader(streams.In())
+			input, _ := reader.ReadString('\n')
+			userIn <- strings.ToLower(strings.TrimSpace(input))
+		}()
+		input := ""
+		select {
+		case <-ctx.Done():
+			return errdef.ErrCanceled
+		case input = <-userIn:
+		}
+		if input != "y" {
+			return errors.New("deactivation aborted")
+		}
+	}
+
+	failed := 0
+	for _, token := range tokens {
+		if _, err := hubClient.UpdateToken(ctx, token.UUID.String(), "", false); err != nil {
+			fmt.Fprintf(streams.Err(), "Failed to deactivate %s: %s\n", token.UUID, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(streams.Out(), ansi.Emphasise("%s is inactive\n"), token.UUID)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to deactivate %d of %d token(s)", failed, len(tokens))
+	}
+	return nil
+}
@@ -18,6 +18,7 @@ package token
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"strings"
 
@@ -27,8 +28,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/docker/hub-tool/internal/ansi"
-	"github.com/docker/hub-tool/internal/hub"
 	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 const (
@@ -52,15 +53,15 @@ func newRmCmd(streams command.Streams, hubClient *hub.Client, parent string) *co
 		PreRun: func(cmd *cobra.Command, args []string) {
 			metrics.Send(parent, removeNAme)
 		},
-		RunE: func(_ *cobra.Command, args []string) error {
-			return runRemove(streams, hubClient, opts, args[0])
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRemove(cmd.Context(), streams, hubClient, opts, args[0])
 		},
 	}
 	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Force deletion of the tag")
 	return cmd
 }
 
-func runRemove(streams command.Streams, hubClient *hub.Client, opts removeOptions, tokenUUID string) error {
+func runRemove(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts removeOptions, tokenUUID string) error {
 	u, err := uuid.Parse(tokenUUID)
 	if err != nil {
 		return err
@@ -80,7 +81,7 @@ Please type your username %q to confirm deletion: `, hubClient.AuthConfig.Userna
 		}
 	}
 
-	if err := hubClient.RemoveToken(u.String()); err != nil {
+	if err := hubClient.RemoveToken(ctx, u.String()); err != nil {
 		return err
 	}
 	fmt.Fprintln(streams.Out(), ansi.Emphasise("Deleted"), u)
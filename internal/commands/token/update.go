@@ -0,0 +1,83 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package token
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/format"
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	updateName = "update"
+)
+
+func newUpdateCmd(streams command.Streams, hubClient *hub.Client, parent string) *cobra.Command {
+	var (
+		opts        format.Option
+		description string
+	)
+	cmd := &cobra.Command{
+		Use:                   updateName + " [OPTIONS] TOKEN_UUID",
+		Short:                 "Update the description of a Personal Access Token",
+		Args:                  cli.ExactArgs(1),
+		DisableFlagsInUseLine: true,
+		Annotations: map[string]string{
+			"sudo": "true",
+		},
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send(parent, updateName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUpdate(cmd.Context(), streams, hubClient, opts, args[0], description)
+		},
+	}
+	opts.AddFormatFlag(cmd.Flags())
+	cmd.Flags().StringVar(&description, "description", "", "Set token's description")
+	_ = cmd.MarkFlagRequired("description")
+	return cmd
+}
+
+func runUpdate(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts format.Option, tokenUUID, description string) error {
+	u, err := uuid.Parse(tokenUUID)
+	if err != nil {
+		return err
+	}
+	// The activeness is always sent, keep the current one
+	token, err := hubClient.GetToken(ctx, u.String())
+	if err != nil {
+		return err
+	}
+	updated, err := hubClient.UpdateToken(ctx, u.String(), description, token.IsActive)
+	if err != nil {
+		return err
+	}
+	return opts.Print(streams.Out(), updated, func(out io.Writer, _ interface{}) error {
+		fmt.Fprintf(out, ansi.Emphasise("%s is updated\n"), u.String())
+		return nil
+	})
+}
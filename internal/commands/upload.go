@@ -0,0 +1,121 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/docker/cli/cli"
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/go-units"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/docker/hub-tool/internal/metrics"
+	"github.com/docker/hub-tool/internal/registry"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	uploadName = "upload"
+)
+
+type uploadOptions struct {
+	chunkSize string
+}
+
+func newUploadCmd(streams command.Streams, hubClient *hub.Client) *cobra.Command {
+	var opts uploadOptions
+	cmd := &cobra.Command{
+		Use:   uploadName + " [OPTIONS] PATH REPOSITORY:TAG",
+		Short: "Upload an image from an OCI image layout or tarball, without the Docker daemon",
+		Long: `Upload an image from an OCI image layout or tarball, without the Docker daemon.
+
+PATH is either a tarball of an OCI image layout, such as written by download or
+docker save from Docker 25, or an OCI image layout directory. Blobs are uploaded
+in chunks, a failed chunk being sent again.`,
+		Args:                  cli.ExactArgs(2),
+		DisableFlagsInUseLine: true,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			metrics.Send("root", uploadName)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runUpload(cmd.Context(), streams, hubClient, opts, args[0], args[1])
+		},
+	}
+	cmd.Flags().StringVar(&opts.chunkSize, "chunk-size", "16MiB", "Size of the chunks blobs are uploaded in")
+	return cmd
+}
+
+func runUpload(ctx context.Context, streams command.Streams, hubClient *hub.Client, opts uploadOptions, path, ref string) error {
+	chunkSize, err := units.RAMInBytes(opts.chunkSize)
+	if err != nil {
+		return fmt.Errorf("invalid chunk size %q: %s", opts.chunkSize, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	dir := path
+	if !info.IsDir() {
+		if dir, err = extractTarball(path); err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir) //nolint:errcheck
+	}
+
+	image, err := registry.Upload(ctx, hubClient, dir, ref, registry.UploadOptions{
+		ChunkSize: chunkSize,
+		Progress: func(descriptor ocispec.Descriptor, skipped bool) {
+			status := "Uploaded"
+			if skipped {
+				status = "Layer already exists"
+			}
+			fmt.Fprintf(streams.Err(), "%s: %s (%s)\n", shortDigest(descriptor), status, units.HumanSize(float64(descriptor.Size)))
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if hubClient.DryRun() {
+		fmt.Fprintf(streams.Out(), "Would upload %s to %s (%s)\n", path, ref, image.Digest)
+		return nil
+	}
+	fmt.Fprintf(streams.Out(), "Uploaded %s to %s (%s)\n", path, ref, image.Digest)
+	return nil
+}
+
+// extractTarball extracts a tarball of an OCI image layout to a temporary
+// directory
+func extractTarball(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close() //nolint:errcheck
+	dir, err := ioutil.TempDir("", "hub-tool-upload")
+	if err != nil {
+		return "", err
+	}
+	if err := registry.UntarLayout(f, dir); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
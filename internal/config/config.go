@@ -0,0 +1,184 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package config reads and writes the configuration file holding the defaults
+// of hub-tool
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// NamespaceKey is the namespace used when a command isn't given one
+	NamespaceKey = "namespace"
+	// FormatKey is the default output format
+	FormatKey = "format"
+	// PageSizeKey is the default number of elements fetched per request
+	PageSizeKey = "page-size"
+	// ConcurrencyKey is the default number of requests sent concurrently
+	ConcurrencyKey = "concurrency"
+	// RetriesKey is the default number of retries of the transient errors
+	RetriesKey = "retries"
+	// CacheTTLKey is the default duration of the local cache
+	CacheTTLKey = "cache-ttl"
+)
+
+// Key describes a configuration key
+type Key struct {
+	Name        string
+	Description string
+	validate    func(string) error
+}
+
+// Keys lists the supported configuration keys. Except the namespace, each one
+// is the default value of the flag of the same name.
+var Keys = []Key{
+	{NamespaceKey, "Namespace used when a command isn't given one, instead of the current account", nil},
+	{FormatKey, `Output format ("json", "csv", "tsv" or a Go template)`, nil},
+	{PageSizeKey, "Number of elements fetched per request", positiveInt},
+	{ConcurrencyKey, "Number of requests sent concurrently", positiveInt},
+	{RetriesKey, "Number of times a request failing with a transient Hub error is retried", nonNegativeInt},
+	{CacheTTLKey, "Duration of the local cache of Hub responses", duration},
+}
+
+// Config holds the configured values by key
+type Config map[string]string
+
+// Path returns the path of the configuration file
+func Path() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".hub-tool", "config.yaml"), nil
+}
+
+// Load reads the configuration file, which is a YAML mapping of the keys to
+// their values. A missing file is an empty configuration.
+func Load(path string) (Config, error) {
+	buf, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Config{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cfg := Config{}
+	if err := yaml.Unmarshal(buf, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %s", path, strings.TrimPrefix(err.Error(), "yaml: "))
+	}
+	if cfg == nil {
+		return Config{}, nil
+	}
+	for _, key := range cfg.keys() {
+		if err := Validate(key, cfg[key]); err != nil {
+			return nil, fmt.Errorf("invalid configuration file %s: %s", path, err)
+		}
+	}
+	return cfg, nil
+}
+
+// Save writes the configuration file, creating its directory if needed
+func (c Config) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, []byte(c.String()), 0600)
+}
+
+// String returns the configuration as YAML, sorted by key
+func (c Config) String() string {
+	var b strings.Builder
+	for _, key := range c.keys() {
+		fmt.Fprintf(&b, "%s: %s\n", key, strconv.Quote(c[key]))
+	}
+	return b.String()
+}
+
+// keys returns the configured keys, sorted
+func (c Config) keys() []string {
+	var keys []string
+	for key := range c {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// IsKey tells if the key is supported
+func IsKey(key string) bool {
+	for _, k := range Keys {
+		if k.Name == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that the key is supported and its value is valid
+func Validate(key, value string) error {
+	for _, k := range Keys {
+		if k.Name != key {
+			continue
+		}
+		if k.validate == nil {
+			return nil
+		}
+		if err := k.validate(value); err != nil {
+			return fmt.Errorf("invalid %s %q: %s", key, value, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown configuration key %q", key)
+}
+
+func positiveInt(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return errors.New("must be a number")
+	}
+	if n < 1 {
+		return errors.New("must be at least 1")
+	}
+	return nil
+}
+
+func nonNegativeInt(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return errors.New("must be a number")
+	}
+	if n < 0 {
+		return errors.New("must be at least 0")
+	}
+	return nil
+}
+
+func duration(value string) error {
+	_, err := time.ParseDuration(value)
+	return err
+}
@@ -0,0 +1,84 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestLoadMissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "config.yaml"))
+	assert.NilError(t, err)
+	assert.Equal(t, len(cfg), 0)
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `---
+# defaults
+namespace: myorg
+format: '{{.Name}}'
+page-size: 50 # per request
+cache-ttl: "1m"
+`
+	assert.NilError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	cfg, err := Load(path)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, cfg, Config{
+		NamespaceKey: "myorg",
+		FormatKey:    "{{.Name}}",
+		PageSizeKey:  "50",
+		CacheTTLKey:  "1m",
+	})
+}
+
+func TestLoadInvalidFile(t *testing.T) {
+	testCases := []struct {
+		content       string
+		expectedError string
+	}{
+		{"namespace myorg\n", "line 1: cannot unmarshal !!str `namespa...` into config.Config"},
+		{"namespace: [myorg]\n", "line 1: cannot unmarshal !!seq into string"},
+		{"\nnamspace: myorg\n", `unknown configuration key "namspace"`},
+		{"concurrency: 0\n", `invalid concurrency "0": must be at least 1`},
+		{"retries: many\n", `invalid retries "many": must be a number`},
+	}
+	for _, testCase := range testCases {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		assert.NilError(t, ioutil.WriteFile(path, []byte(testCase.content), 0600))
+		_, err := Load(path)
+		assert.ErrorContains(t, err, testCase.expectedError)
+	}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hub-tool", "config.yaml")
+	cfg := Config{FormatKey: `{{.Name}}: "{{.Size}}"`, ConcurrencyKey: "8"}
+	assert.NilError(t, cfg.Save(path))
+	content, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), `concurrency: "8"
+format: "{{.Name}}: \"{{.Size}}\""
+`)
+	loaded, err := Load(path)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, loaded, cfg)
+}
@@ -17,8 +17,12 @@
 package credentials
 
 import (
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/docker/cli/cli/config/configfile"
 	dockercredentials "github.com/docker/cli/cli/config/credentials"
 	clitypes "github.com/docker/cli/cli/config/types"
 	"gopkg.in/square/go-jose.v2/jwt"
@@ -28,6 +32,7 @@ const (
 	hubToolKey             = "hub-tool"
 	hubToolTokenKey        = "hub-tool-token"
 	hubToolRefreshTokenKey = "hub-tool-refresh-token"
+	hubToolProfileKey      = "hub-tool-profile"
 	expirationWindow       = 1 * time.Minute
 )
 
@@ -37,6 +42,18 @@ type Store interface {
 	GetAuth() (*Auth, error)
 	Store(auth Auth) error
 	Erase() error
+	// SetInstance makes the store keep the credentials of the given Hub
+	// instance host apart, an empty host being Docker Hub
+	SetInstance(host string)
+	// SetProfile makes the store use the credentials of the given account
+	// profile, an empty name being the credentials stored before profiles
+	SetProfile(name string)
+	// UseProfile makes the given profile the current one
+	UseProfile(name string) error
+	// CurrentProfile returns the profile set by UseProfile
+	CurrentProfile() (string, error)
+	// Profiles returns the names of the stored profiles
+	Profiles() ([]string, error)
 }
 
 // Auth represents user authentication
@@ -70,7 +87,9 @@ func (a *Auth) TokenExpired() bool {
 }
 
 type store struct {
-	s dockercredentials.Store
+	s        dockercredentials.Store
+	instance string
+	profile  string
 }
 
 // NewStore creates a new credentials store
@@ -80,16 +99,96 @@ func NewStore(provider func(string) dockercredentials.Store) Store {
 	}
 }
 
+// NewConfigStore creates a credentials store backed by the credentials helper
+// set in the docker config file, such as osxkeychain, wincred or pass. The
+// credentials are kept in the config file itself when no helper is set.
+func NewConfigStore(config *configfile.ConfigFile) Store {
+	return NewStore(config.GetCredentialsStore)
+}
+
+func (s *store) SetInstance(host string) {
+	s.instance = host
+}
+
+// SetProfile only changes the key of the credentials, UseProfile being the
+// one remembering the current profile
+func (s *store) SetProfile(name string) {
+	s.profile = name
+}
+
+// key returns the key under which the credentials of the current profile and
+// instance are stored, Docker Hub without profile using the bare key
+func (s *store) key(key string) string {
+	if s.profile != "" {
+		key += "/" + s.profile
+	}
+	return s.instanceKey(key)
+}
+
+func (s *store) instanceKey(key string) string {
+	if s.instance == "" {
+		return key
+	}
+	return key + "@" + s.instance
+}
+
+func (s *store) UseProfile(name string) error {
+	profile := *s
+	profile.profile = name
+	found, err := s.exists(profile.key(hubToolKey))
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("no credentials stored for account %q, please login with it first", name)
+	}
+	return s.s.Store(clitypes.AuthConfig{
+		Username:      name,
+		ServerAddress: s.instanceKey(hubToolProfileKey),
+	})
+}
+
+func (s *store) CurrentProfile() (string, error) {
+	current, err := s.s.Get(s.instanceKey(hubToolProfileKey))
+	if err != nil {
+		return "", err
+	}
+	return current.Username, nil
+}
+
+func (s *store) Profiles() ([]string, error) {
+	all, err := s.s.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	var profiles []string
+	for serverAddress := range all {
+		if !strings.HasPrefix(serverAddress, hubToolKey+"/") {
+			continue
+		}
+		name := strings.TrimPrefix(serverAddress, hubToolKey+"/")
+		host := ""
+		if i := strings.Index(name, "@"); i >= 0 {
+			name, host = name[:i], name[i+1:]
+		}
+		if host == s.instance {
+			profiles = append(profiles, name)
+		}
+	}
+	sort.Strings(profiles)
+	return profiles, nil
+}
+
 func (s *store) GetAuth() (*Auth, error) {
-	auth, err := s.s.Get(hubToolKey)
+	auth, err := s.s.Get(s.key(hubToolKey))
 	if err != nil {
 		return nil, err
 	}
-	token, err := s.s.Get(hubToolTokenKey)
+	token, err := s.s.Get(s.key(hubToolTokenKey))
 	if err != nil {
 		return nil, err
 	}
-	refreshToken, err := s.s.Get(hubToolRefreshTokenKey)
+	refreshToken, err := s.s.Get(s.key(hubToolRefreshTokenKey))
 	if err != nil {
 		return nil, err
 	}
@@ -106,21 +205,21 @@ func (s *store) Store(auth Auth) error {
 	if err := s.s.Store(clitypes.AuthConfig{
 		Username:      auth.Username,
 		IdentityToken: auth.Token,
-		ServerAddress: hubToolTokenKey,
+		ServerAddress: s.key(hubToolTokenKey),
 	}); err != nil {
 		return err
 	}
 	if err := s.s.Store((clitypes.AuthConfig{
 		Username:      auth.Username,
 		IdentityToken: auth.RefreshToken,
-		ServerAddress: hubToolRefreshTokenKey,
+		ServerAddress: s.key(hubToolRefreshTokenKey),
 	})); err != nil {
 		return err
 	}
 	return s.s.Store(clitypes.AuthConfig{
 		Username:      auth.Username,
 		Password:      auth.Password,
-		ServerAddress: hubToolKey,
+		ServerAddress: s.key(hubToolKey),
 	})
 }
 
@@ -138,14 +237,23 @@ func (s *store) exists(serverAddress string) (bool, error) {
 }
 
 func (s *store) Erase() error {
-	if err := s.s.Erase(hubToolKey); err != nil {
-		if found, findErr := s.exists(hubToolKey); findErr == nil && !found {
+	if err := s.s.Erase(s.key(hubToolKey)); err != nil {
+		if found, findErr := s.exists(s.key(hubToolKey)); findErr == nil && !found {
 			return nil
 		}
 		return err
 	}
-	if err := s.s.Erase(hubToolRefreshTokenKey); err != nil {
+	if err := s.s.Erase(s.key(hubToolRefreshTokenKey)); err != nil {
+		return err
+	}
+	if err := s.s.Erase(s.key(hubToolTokenKey)); err != nil {
+		return err
+	}
+	if s.profile == "" {
+		return nil
+	}
+	if current, err := s.CurrentProfile(); err != nil || current != s.profile {
 		return err
 	}
-	return s.s.Erase(hubToolTokenKey)
+	return s.s.Erase(s.instanceKey(hubToolProfileKey))
 }
@@ -0,0 +1,119 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package credentials
+
+import (
+	"testing"
+
+	dockercredentials "github.com/docker/cli/cli/config/credentials"
+	clitypes "github.com/docker/cli/cli/config/types"
+	"gotest.tools/v3/assert"
+)
+
+// memoryStore is a docker credentials store keeping the credentials in memory
+type memoryStore map[string]clitypes.AuthConfig
+
+func (m memoryStore) Erase(serverAddress string) error {
+	delete(m, serverAddress)
+	return nil
+}
+
+func (m memoryStore) Get(serverAddress string) (clitypes.AuthConfig, error) {
+	return m[serverAddress], nil
+}
+
+func (m memoryStore) GetAll() (map[string]clitypes.AuthConfig, error) {
+	return m, nil
+}
+
+func (m memoryStore) Store(authConfig clitypes.AuthConfig) error {
+	m[authConfig.ServerAddress] = authConfig
+	return nil
+}
+
+func TestStoreAuth(t *testing.T) {
+	credentials := memoryStore{}
+	s := NewStore(func(key string) dockercredentials.Store {
+		assert.Equal(t, key, hubToolKey)
+		return credentials
+	})
+	auth := Auth{Username: "jdoe", Password: "secret", Token: "token", RefreshToken: "refresh"}
+	assert.NilError(t, s.Store(auth))
+	assert.Equal(t, len(credentials), 3)
+
+	stored, err := s.GetAuth()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, *stored, auth)
+
+	assert.NilError(t, s.Erase())
+	assert.Equal(t, len(credentials), 0)
+	// Erasing missing credentials succeeds
+	assert.NilError(t, s.Erase())
+}
+
+func TestStoreKeepsInstancesApart(t *testing.T) {
+	credentials := memoryStore{}
+	s := NewStore(func(string) dockercredentials.Store { return credentials })
+	assert.NilError(t, s.Store(Auth{Username: "jdoe", Password: "secret"}))
+
+	s.SetInstance("hub.example.com")
+	auth, err := s.GetAuth()
+	assert.NilError(t, err)
+	assert.Equal(t, auth.Username, "")
+	assert.NilError(t, s.Store(Auth{Username: "jane", Password: "other"}))
+	assert.Equal(t, credentials["hub-tool@hub.example.com"].Username, "jane")
+
+	s.SetInstance("")
+	auth, err = s.GetAuth()
+	assert.NilError(t, err)
+	assert.Equal(t, auth.Username, "jdoe")
+}
+
+func TestStoreProfiles(t *testing.T) {
+	credentials := memoryStore{}
+	s := NewStore(func(string) dockercredentials.Store { return credentials })
+
+	err := s.UseProfile("jdoe")
+	assert.ErrorContains(t, err, `no credentials stored for account "jdoe"`)
+
+	s.SetProfile("jdoe")
+	assert.NilError(t, s.Store(Auth{Username: "jdoe", Password: "secret"}))
+	s.SetProfile("myorg")
+	assert.NilError(t, s.Store(Auth{Username: "myorg", Password: "other"}))
+	assert.NilError(t, s.UseProfile("jdoe"))
+
+	profiles, err := s.Profiles()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, profiles, []string{"jdoe", "myorg"})
+	current, err := s.CurrentProfile()
+	assert.NilError(t, err)
+	assert.Equal(t, current, "jdoe")
+
+	s.SetProfile(current)
+	auth, err := s.GetAuth()
+	assert.NilError(t, err)
+	assert.Equal(t, auth.Password, "secret")
+
+	// Logging out of the current account leaves no current one
+	assert.NilError(t, s.Erase())
+	current, err = s.CurrentProfile()
+	assert.NilError(t, err)
+	assert.Equal(t, current, "")
+	profiles, err = s.Profiles()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, profiles, []string{"myorg"})
+}
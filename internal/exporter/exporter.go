@@ -0,0 +1,276 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+// Package exporter exposes the Hub metrics of accounts, such as the pull
+// counts of their repositories, in the Prometheus text format
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+// unlimited is the limit of the plans without limit
+const unlimited = 9999
+
+// labelEscaper escapes the label values as the text format expects
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Metric is a Prometheus gauge with its values by labels
+type Metric struct {
+	Name   string
+	Help   string
+	Values []Value
+}
+
+// Value is the value of a metric for a set of labels
+type Value struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// Exporter scrapes the metrics of accounts at an interval and serves the last
+// scraped ones
+type Exporter struct {
+	hubClient *hub.Client
+	accounts  []string
+
+	mu      sync.RWMutex
+	metrics []Metric
+}
+
+// New returns an exporter of the metrics of the accounts
+func New(hubClient *hub.Client, accounts []string) *Exporter {
+	return &Exporter{hubClient: hubClient, accounts: accounts}
+}
+
+// Run scrapes the metrics right away, then at each interval until the context
+// is canceled. A failed scrape is logged and reported by the
+// hub_scrape_success metric, the next one may succeed.
+func (e *Exporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		e.Scrape(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scrape collects the metrics of all the accounts, and of the pull rate
+// limits of the authenticated user
+func (e *Exporter) Scrape(ctx context.Context) {
+	start := time.Now()
+	var metrics []Metric
+	success := Metric{Name: "hub_scrape_success", Help: "Whether the last scrape of the account succeeded"}
+	for _, account := range e.accounts {
+		accountMetrics, err := e.scrapeAccount(ctx, account)
+		if err != nil {
+			log.Warnf("Failed to scrape the metrics of %s: %s", account, err)
+		}
+		metrics = append(metrics, accountMetrics...)
+		success.Values = append(success.Values, Value{Labels: map[string]string{"account": account}, Value: boolValue(err == nil)})
+	}
+	limits, err := e.rateLimits(ctx)
+	if err != nil {
+		log.Warnf("Failed to scrape the pull rate limits: %s", err)
+	} else if limits != nil {
+		metrics = append(metrics, rateLimitMetrics(*limits)...)
+	}
+	metrics = append(metrics, success, Metric{
+		Name:   "hub_scrape_duration_seconds",
+		Help:   "Duration of the last scrape",
+		Values: []Value{{Value: time.Since(start).Seconds()}},
+	})
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.metrics = mergeMetrics(metrics)
+}
+
+// rateLimits returns the pull rate limits of the authenticated user, or of
+// the IP address of the exporter without credentials
+func (e *Exporter) rateLimits(ctx context.Context) (*hub.RateLimits, error) {
+	if e.hubClient.IsAnonymous() {
+		return e.hubClient.GetAnonymousRateLimits(ctx)
+	}
+	return e.hubClient.GetRateLimits(ctx)
+}
+
+func (e *Exporter) scrapeAccount(ctx context.Context, account string) ([]Metric, error) {
+	repos, _, err := e.hubClient.GetRepositories(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	metrics := repositoryMetrics(account, repos)
+	quotas, err := e.accountQuotas(ctx, account)
+	if err != nil {
+		return metrics, err
+	}
+	return append(metrics, quotas...), nil
+}
+
+// accountQuotas returns the usage of an organization, or of the current user,
+// against the limits of its plan. Other users are skipped as their plan can't
+// be read.
+func (e *Exporter) accountQuotas(ctx context.Context, account string) ([]Metric, error) {
+	var (
+		info        *hub.Account
+		consumption *hub.Consumption
+	)
+	info, err := e.hubClient.GetOrganizationInfo(ctx, account)
+	switch {
+	case err == nil:
+		consumption, err = e.hubClient.GetOrgConsumption(ctx, account)
+	case hub.IsNotFoundError(err) && account == e.hubClient.AuthConfig.Username:
+		if info, err = e.hubClient.GetUserInfo(ctx); err == nil {
+			consumption, err = e.hubClient.GetUserConsumption(ctx, account)
+		}
+	case hub.IsNotFoundError(err):
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	plan, err := e.hubClient.GetHubPlan(ctx, info.ID)
+	if err != nil {
+		return nil, err
+	}
+	return quotaMetrics(account, *plan, *consumption), nil
+}
+
+// ServeHTTP writes the last scraped metrics
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := WriteMetrics(w, e.metrics); err != nil {
+		log.Warnf("Failed to write the metrics: %s", err)
+	}
+}
+
+func repositoryMetrics(account string, repos []hub.Repository) []Metric {
+	pulls := Metric{Name: "hub_repository_pulls", Help: "Number of pulls of the repository"}
+	stars := Metric{Name: "hub_repository_stars", Help: "Number of stars of the repository"}
+	storage := Metric{Name: "hub_repository_storage_bytes", Help: "Size of the images stored in the repository"}
+	for _, repo := range repos {
+		labels := map[string]string{"namespace": account, "repository": repo.Name}
+		pulls.Values = append(pulls.Values, Value{Labels: labels, Value: float64(repo.PullCount)})
+		stars.Values = append(stars.Values, Value{Labels: labels, Value: float64(repo.StarCount)})
+		storage.Values = append(storage.Values, Value{Labels: labels, Value: float64(repo.StorageSize)})
+	}
+	return []Metric{pulls, stars, storage}
+}
+
+func quotaMetrics(account string, plan hub.Plan, consumption hub.Consumption) []Metric {
+	labels := map[string]string{"account": account}
+	metrics := []Metric{
+		{Name: "hub_seats", Help: "Number of seats used by the account", Values: []Value{{Labels: labels, Value: float64(consumption.Seats)}}},
+		{Name: "hub_private_repositories", Help: "Number of private repositories of the account", Values: []Value{{Labels: labels, Value: float64(consumption.PrivateRepositories)}}},
+		{Name: "hub_storage_bytes", Help: "Size of the images stored by the account", Values: []Value{{Labels: labels, Value: float64(consumption.Storage)}}},
+	}
+	if plan.Limits.Seats != unlimited {
+		metrics = append(metrics, Metric{Name: "hub_seats_limit", Help: "Number of seats of the plan of the account", Values: []Value{{Labels: labels, Value: float64(plan.Limits.Seats)}}})
+	}
+	if plan.Limits.PrivateRepos != unlimited {
+		metrics = append(metrics, Metric{Name: "hub_private_repositories_limit", Help: "Number of private repositories of the plan of the account", Values: []Value{{Labels: labels, Value: float64(plan.Limits.PrivateRepos)}}})
+	}
+	return metrics
+}
+
+func rateLimitMetrics(limits hub.RateLimits) []Metric {
+	var metrics []Metric
+	if limits.Limit != nil {
+		metrics = append(metrics, Metric{Name: "hub_pull_rate_limit", Help: "Number of pulls allowed in the rate limit window", Values: []Value{{Value: float64(*limits.Limit)}}})
+	}
+	if limits.Remaining != nil {
+		metrics = append(metrics, Metric{Name: "hub_pull_rate_limit_remaining", Help: "Number of pulls remaining in the rate limit window", Values: []Value{{Value: float64(*limits.Remaining)}}})
+	}
+	if limits.LimitWindow != nil {
+		metrics = append(metrics, Metric{Name: "hub_pull_rate_limit_window_seconds", Help: "Duration of the rate limit window", Values: []Value{{Value: float64(*limits.LimitWindow)}}})
+	}
+	return metrics
+}
+
+// mergeMetrics merges the values of the metrics of the same name, scraped for
+// several accounts, as a metric must only be described once
+func mergeMetrics(metrics []Metric) []Metric {
+	var merged []Metric
+	index := map[string]int{}
+	for _, metric := range metrics {
+		if i, ok := index[metric.Name]; ok {
+			merged[i].Values = append(merged[i].Values, metric.Values...)
+			continue
+		}
+		index[metric.Name] = len(merged)
+		merged = append(merged, metric)
+	}
+	return merged
+}
+
+// WriteMetrics writes metrics in the Prometheus text format
+func WriteMetrics(w io.Writer, metrics []Metric) error {
+	for _, metric := range metrics {
+		if len(metric.Values) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", metric.Name, metric.Help, metric.Name); err != nil {
+			return err
+		}
+		for _, value := range metric.Values {
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", metric.Name, formatLabels(value.Labels), strconv.FormatFloat(value.Value, 'g', -1, 64)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = fmt.Sprintf(`%s="%s"`, name, labelEscaper.Replace(labels[name]))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
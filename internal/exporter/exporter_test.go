@@ -0,0 +1,64 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package exporter
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	repos := []hub.Repository{
+		{Name: "myorg/app", PullCount: 1200, StarCount: 3, StorageSize: 1 << 20},
+		{Name: "myorg/db", PullCount: 42},
+	}
+	metrics := mergeMetrics(append(repositoryMetrics("myorg", repos[:1]), repositoryMetrics("myorg", repos[1:])...))
+	metrics = append(metrics, Metric{Name: "hub_empty", Help: "Not written without values"})
+	buf := bytes.NewBuffer(nil)
+	assert.NilError(t, WriteMetrics(buf, metrics))
+	assert.Equal(t, buf.String(), `# HELP hub_repository_pulls Number of pulls of the repository
+# TYPE hub_repository_pulls gauge
+hub_repository_pulls{namespace="myorg",repository="myorg/app"} 1200
+hub_repository_pulls{namespace="myorg",repository="myorg/db"} 42
+# HELP hub_repository_stars Number of stars of the repository
+# TYPE hub_repository_stars gauge
+hub_repository_stars{namespace="myorg",repository="myorg/app"} 3
+hub_repository_stars{namespace="myorg",repository="myorg/db"} 0
+# HELP hub_repository_storage_bytes Size of the images stored in the repository
+# TYPE hub_repository_storage_bytes gauge
+hub_repository_storage_bytes{namespace="myorg",repository="myorg/app"} 1.048576e+06
+hub_repository_storage_bytes{namespace="myorg",repository="myorg/db"} 0
+`)
+}
+
+func TestQuotaMetricsSkipUnlimited(t *testing.T) {
+	plan := hub.Plan{Limits: hub.Limits{Seats: 5, PrivateRepos: unlimited}}
+	metrics := quotaMetrics("myorg", plan, hub.Consumption{Seats: 3, PrivateRepositories: 12})
+	var names []string
+	for _, metric := range metrics {
+		names = append(names, metric.Name)
+	}
+	assert.DeepEqual(t, names, []string{"hub_seats", "hub_private_repositories", "hub_storage_bytes", "hub_seats_limit"})
+}
+
+func TestFormatLabelsEscapesValues(t *testing.T) {
+	assert.Equal(t, formatLabels(nil), "")
+	assert.Equal(t, formatLabels(map[string]string{"b": `say "hi"`, "a": `c:\`}), `{a="c:\\",b="say \"hi\""}`)
+}
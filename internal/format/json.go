@@ -34,7 +34,13 @@ type PrettyPrinter func(io.Writer, interface{}) error
 
 //AddFormatFlag add the format flag to a command
 func (o *Option) AddFormatFlag(flags *pflag.FlagSet) {
-	flags.StringVar(&o.format, "format", "", `Print values using a custom format ("json")`)
+	flags.StringVar(&o.format, "format", "", `Print values using a custom format ("json", "csv", "tsv" or a Go template)`)
+}
+
+//Format returns the format given with the format flag, empty for the pretty
+// print format
+func (o *Option) Format() string {
+	return o.format
 }
 
 //Print outputs values depending the given format
@@ -44,8 +50,13 @@ func (o *Option) Print(out io.Writer, values interface{}, prettyPrinter PrettyPr
 		return prettyPrinter(out, values)
 	case "json":
 		return printJSON(out, values)
+	case "csv", "tsv":
+		return printDelimitedValues(out, Format(o.format), values)
 	default:
-		return fmt.Errorf("unsupported format type: %q", o.format)
+		if !isTemplate(o.format) {
+			return fmt.Errorf("unsupported format type: %q", o.format)
+		}
+		return printTemplateValues(out, o.format, values)
 	}
 }
 
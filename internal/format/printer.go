@@ -0,0 +1,383 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package format
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/docker/go-units"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/internal/format/tabwriter"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+// Format is the output format used by a Printer
+type Format string
+
+const (
+	// TableFormat prints the values as a human readable table
+	TableFormat = Format("table")
+	// JSONFormat prints the values as indented JSON
+	JSONFormat = Format("json")
+	// CSVFormat prints the values as comma separated values, with a header row
+	CSVFormat = Format("csv")
+	// TSVFormat prints the values as tab separated values, with a header row
+	TSVFormat = Format("tsv")
+)
+
+// Tabular is implemented by the listings other than repositories and tags, to
+// be printed as csv or tsv
+type Tabular interface {
+	// Table returns the header of each column and a row per element, holding
+	// its raw value for each column
+	Table() ([]string, [][]interface{})
+}
+
+// Printer renders Hub values to a writer. Any other format containing an
+// action is used as a Go template, executed for each value.
+type Printer struct {
+	out    io.Writer
+	format Format
+	// Platforms prints a row per image when printing tags, with its platform
+	// and size
+	Platforms bool
+}
+
+// NewPrinter returns a printer writing to w using the given format
+func NewPrinter(w io.Writer, format Format) *Printer {
+	if format == "" {
+		format = TableFormat
+	}
+	return &Printer{
+		out:    w,
+		format: format,
+	}
+}
+
+type cell struct {
+	value string
+	width int
+	raw   string
+}
+
+// link is a column value rendered as a hyperlink in tables
+type link struct {
+	url  string
+	text string
+}
+
+// timestamp is a column value rendered as the time elapsed since in tables
+type timestamp struct {
+	time time.Time
+	ago  bool
+}
+
+// byteSize is a column value rendered as a human readable size in tables
+type byteSize int
+
+type repositoryColumn struct {
+	header string
+	value  func(r hub.Repository) interface{}
+}
+
+type tagColumn struct {
+	header string
+	value  func(t hub.Tag) interface{}
+}
+
+type tagImageColumn struct {
+	header string
+	value  func(t hub.Tag, i hub.Image) interface{}
+}
+
+var (
+	repositoryColumns = []repositoryColumn{
+		{"REPOSITORY", func(r hub.Repository) interface{} {
+			return link{fmt.Sprintf("https://hub.docker.com/repository/docker/%s", r.Name), r.Name}
+		}},
+		{"DESCRIPTION", func(r hub.Repository) interface{} { return r.Description }},
+		{"LAST UPDATE", func(r hub.Repository) interface{} { return timestamp{r.LastUpdated, true} }},
+		{"PULLS", func(r hub.Repository) interface{} { return r.PullCount }},
+		{"STARS", func(r hub.Repository) interface{} { return r.StarCount }},
+		{"PRIVATE", func(r hub.Repository) interface{} { return r.IsPrivate }},
+	}
+
+	tagColumns = []tagColumn{
+		{"TAG", func(t hub.Tag) interface{} { return t.Name }},
+		{"DIGEST", func(t hub.Tag) interface{} { return tagDigest(t) }},
+		{"ARTIFACT TYPE", func(t hub.Tag) interface{} { return t.ArtifactType }},
+		{"STATUS", func(t hub.Tag) interface{} { return t.Status }},
+		{"LAST UPDATE", func(t hub.Tag) interface{} { return timestamp{t.LastUpdated, true} }},
+		{"LAST PUSHED", func(t hub.Tag) interface{} { return timestamp{t.LastPushed, false} }},
+		{"LAST PULLED", func(t hub.Tag) interface{} { return timestamp{t.LastPulled, false} }},
+		{"SIZE", func(t hub.Tag) interface{} { return byteSize(tagSize(t)) }},
+	}
+
+	tagImageColumns = []tagImageColumn{
+		{"TAG", func(t hub.Tag, i hub.Image) interface{} { return t.Name }},
+		{"DIGEST", func(t hub.Tag, i hub.Image) interface{} { return i.Digest }},
+		{"OS/ARCH", func(t hub.Tag, i hub.Image) interface{} { return imagePlatform(i) }},
+		{"STATUS", func(t hub.Tag, i hub.Image) interface{} { return i.Status }},
+		{"LAST PUSHED", func(t hub.Tag, i hub.Image) interface{} { return timestamp{i.LastPushed, false} }},
+		{"LAST PULLED", func(t hub.Tag, i hub.Image) interface{} { return timestamp{i.LastPulled, false} }},
+		{"SIZE", func(t hub.Tag, i hub.Image) interface{} { return byteSize(i.Size) }},
+	}
+)
+
+// newCell renders a column value both for tables and for raw formats like csv
+func newCell(value interface{}) cell {
+	var c cell
+	switch v := value.(type) {
+	case link:
+		c = cell{value: ansi.Link(v.url, v.text), width: len(v.text), raw: v.text}
+	case timestamp:
+		c.raw = formatTime(v.time)
+		if v.time.Nanosecond() != 0 {
+			c.value = units.HumanDuration(time.Since(v.time))
+			if v.ago {
+				c.value += " ago"
+			}
+		}
+		c.width = len(c.value)
+	case time.Time:
+		if !v.IsZero() {
+			c.value = fmt.Sprintf("%s ago", units.HumanDuration(time.Since(v)))
+		}
+		c.width = len(c.value)
+		c.raw = formatTime(v)
+	case byteSize:
+		c.value = units.HumanSize(float64(v))
+		c.width = len(c.value)
+		c.raw = fmt.Sprintf("%d", v)
+	default:
+		c.value = fmt.Sprintf("%v", v)
+		c.width = len(c.value)
+		c.raw = c.value
+	}
+	return c
+}
+
+// PrintRepositories prints the repositories using the printer format
+func (p *Printer) PrintRepositories(repositories []hub.Repository) error {
+	headers := make([]string, len(repositoryColumns))
+	for i, column := range repositoryColumns {
+		headers[i] = column.header
+	}
+	rows := make([][]cell, len(repositories))
+	items := make([]interface{}, len(repositories))
+	for i, repository := range repositories {
+		items[i] = repository
+		for _, column := range repositoryColumns {
+			rows[i] = append(rows[i], newCell(column.value(repository)))
+		}
+	}
+	return p.print(repositories, items, headers, rows)
+}
+
+// PrintTags prints the tags using the printer format
+func (p *Printer) PrintTags(tags []hub.Tag) error {
+	if p.Platforms {
+		return p.printTagImages(tags)
+	}
+	columns := tagColumns
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.header
+	}
+	rows := make([][]cell, len(tags))
+	items := make([]interface{}, len(tags))
+	for i, tag := range tags {
+		items[i] = tag
+		for _, column := range columns {
+			rows[i] = append(rows[i], newCell(column.value(tag)))
+		}
+	}
+	return p.print(tags, items, headers, rows)
+}
+
+// printTagImages prints a row per image of the tags, the tags without image
+// being printed on a single row
+func (p *Printer) printTagImages(tags []hub.Tag) error {
+	headers := make([]string, len(tagImageColumns))
+	for i, column := range tagImageColumns {
+		headers[i] = column.header
+	}
+	var (
+		rows  [][]cell
+		items []interface{}
+	)
+	for _, tag := range tags {
+		images := tag.Images
+		if len(images) == 0 {
+			images = []hub.Image{{Digest: tag.Digest, Status: tag.Status}}
+		}
+		items = append(items, tag)
+		for _, image := range images {
+			var row []cell
+			for _, column := range tagImageColumns {
+				row = append(row, newCell(column.value(tag, image)))
+			}
+			rows = append(rows, row)
+		}
+	}
+	return p.print(tags, items, headers, rows)
+}
+
+func (p *Printer) print(values interface{}, items []interface{}, headers []string, rows [][]cell) error {
+	switch p.format {
+	case TableFormat:
+		return printTable(p.out, headers, rows)
+	case JSONFormat:
+		return printJSON(p.out, values)
+	case CSVFormat, TSVFormat:
+		return printDelimited(p.out, p.format, headers, rows)
+	default:
+		if !isTemplate(string(p.format)) {
+			return fmt.Errorf("unsupported format type: %q", p.format)
+		}
+		return printTemplate(p.out, string(p.format), items)
+	}
+}
+
+// PrintTable prints a table with a row per element, holding its value for each
+// header. Times are printed as the time elapsed since.
+func PrintTable(out io.Writer, headers []string, rows [][]interface{}) error {
+	cells := make([][]cell, len(rows))
+	for i, row := range rows {
+		for _, value := range row {
+			cells[i] = append(cells[i], newCell(value))
+		}
+	}
+	return printTable(out, headers, cells)
+}
+
+func printTable(out io.Writer, headers []string, rows [][]cell) error {
+	tw := tabwriter.New(out, "    ")
+	for _, header := range headers {
+		tw.Column(ansi.Header(header), len(header))
+	}
+	tw.Line()
+	for _, row := range rows {
+		for _, c := range row {
+			tw.Column(c.value, c.width)
+		}
+		tw.Line()
+	}
+	return tw.Flush()
+}
+
+// printDelimited prints the raw values of the rows as csv or tsv, quoting the
+// values containing the separator, quotes or newlines
+func printDelimited(out io.Writer, format Format, headers []string, rows [][]cell) error {
+	w := csv.NewWriter(out)
+	if format == TSVFormat {
+		w.Comma = '\t'
+	}
+	if err := w.Write(headers); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := make([]string, len(row))
+		for i, c := range row {
+			record[i] = c.raw
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// printDelimitedValues prints the values as csv or tsv, only repositories,
+// tags and the Tabular values having columns
+func printDelimitedValues(out io.Writer, format Format, values interface{}) error {
+	printer := NewPrinter(out, format)
+	switch v := values.(type) {
+	case []hub.Repository:
+		return printer.PrintRepositories(v)
+	case []hub.Tag:
+		return printer.PrintTags(v)
+	case Tabular:
+		headers, rows := v.Table()
+		cells := make([][]cell, len(rows))
+		for i, row := range rows {
+			for _, value := range row {
+				cells[i] = append(cells[i], newCell(value))
+			}
+		}
+		return printDelimited(out, format, headers, cells)
+	default:
+		return fmt.Errorf("unsupported format type: %q", format)
+	}
+}
+
+func printTemplate(out io.Writer, format string, items []interface{}) error {
+	tmpl, err := ParseTemplate(format)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := tmpl.Execute(out, item); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func tagDigest(t hub.Tag) string {
+	if len(t.Images) > 0 {
+		return t.Images[0].Digest
+	}
+	return ""
+}
+
+func tagSize(t hub.Tag) int {
+	size := t.FullSize
+	if len(t.Images) > 0 {
+		size = 0
+		for _, image := range t.Images {
+			size += image.Size
+		}
+	}
+	return size
+}
+
+func imagePlatform(i hub.Image) string {
+	if i.Os == "" && i.Architecture == "" {
+		return ""
+	}
+	platform := fmt.Sprintf("%s/%s", i.Os, i.Architecture)
+	if i.Variant != "" {
+		platform += "/" + i.Variant
+	}
+	return platform
+}
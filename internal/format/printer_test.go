@@ -0,0 +1,153 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package format
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+var repositories = []hub.Repository{
+	{Name: "user/repo", Description: "my, repo", PullCount: 42, StarCount: 1, IsPrivate: true},
+	{Name: "user/other", PullCount: 7},
+}
+
+func TestPrintRepositoriesCSV(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	err := NewPrinter(out, CSVFormat).PrintRepositories(repositories)
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), `REPOSITORY,DESCRIPTION,LAST UPDATE,PULLS,STARS,PRIVATE
+user/repo,"my, repo",,42,1,true
+user/other,,,7,0,false
+`)
+}
+
+func TestOptionPrintCSV(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	opts := Option{format: "csv"}
+	err := opts.Print(out, []hub.Tag{{Name: "latest", FullSize: 1024}}, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), `TAG,DIGEST,ARTIFACT TYPE,STATUS,LAST UPDATE,LAST PUSHED,LAST PULLED,SIZE
+latest,,,,,,,1024
+`)
+
+	err = opts.Print(out, "not a listing", nil)
+	assert.ErrorContains(t, err, `unsupported format type: "csv"`)
+}
+
+func TestOptionPrintTSV(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	opts := Option{format: "tsv"}
+	err := opts.Print(out, repositories, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), "REPOSITORY\tDESCRIPTION\tLAST UPDATE\tPULLS\tSTARS\tPRIVATE\n"+
+		"user/repo\tmy, repo\t\t42\t1\ttrue\n"+
+		"user/other\t\t\t7\t0\tfalse\n")
+}
+
+type teams []hub.Team
+
+func (l teams) Table() ([]string, [][]interface{}) {
+	rows := make([][]interface{}, len(l))
+	for i, team := range l {
+		rows[i] = []interface{}{team.Name, team.Description, len(team.Members)}
+	}
+	return []string{"TEAM", "DESCRIPTION", "MEMBERS"}, rows
+}
+
+func TestOptionPrintTabular(t *testing.T) {
+	values := teams{
+		{Name: "owners", Description: "Owners, \"admins\"", Members: []hub.Member{{Username: "jdoe"}}},
+		{Name: "developers", Description: "Push\taccess"},
+	}
+
+	out := bytes.NewBuffer(nil)
+	err := (&Option{format: "csv"}).Print(out, values, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), `TEAM,DESCRIPTION,MEMBERS
+owners,"Owners, ""admins""",1
+developers,Push	access,0
+`)
+
+	out.Reset()
+	err = (&Option{format: "tsv"}).Print(out, values, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), "TEAM\tDESCRIPTION\tMEMBERS\n"+
+		"owners\t\"Owners, \"\"admins\"\"\"\t1\n"+
+		"developers\t\"Push\taccess\"\t0\n")
+}
+
+func TestPrintTagImagesCSV(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	printer := NewPrinter(out, CSVFormat)
+	printer.Platforms = true
+	err := printer.PrintTags([]hub.Tag{
+		{Name: "latest", Images: []hub.Image{
+			{Digest: "sha256:beef", Os: "linux", Architecture: "amd64", Size: 1024, Status: "active"},
+			{Digest: "sha256:c0ffee", Os: "linux", Architecture: "arm", Variant: "v7", Size: 512, Status: "active"},
+		}},
+		{Name: "broken", Digest: "sha256:dead"},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), `TAG,DIGEST,OS/ARCH,STATUS,LAST PUSHED,LAST PULLED,SIZE
+latest,sha256:beef,linux/amd64,active,,,1024
+latest,sha256:c0ffee,linux/arm/v7,active,,,512
+broken,sha256:dead,,,,,0
+`)
+}
+
+func TestPrintRepositoriesTemplate(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	err := NewPrinter(out, Format("{{.Name}} {{.PullCount}}")).PrintRepositories(repositories)
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), "user/repo 42\nuser/other 7\n")
+}
+
+func TestOptionPrintTemplate(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	opts := Option{format: `{{lower .Name}}\t{{json .IsPrivate}}`}
+	err := opts.Print(out, repositories, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), "user/repo\ttrue\nuser/other\tfalse\n")
+}
+
+func TestOptionPrintTemplateSingleValue(t *testing.T) {
+	out := bytes.NewBuffer(nil)
+	opts := Option{format: "{{upper .Name}}"}
+	err := opts.Print(out, &repositories[0], nil)
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), "USER/REPO\n")
+}
+
+func TestOptionPrintInvalidTemplate(t *testing.T) {
+	opts := Option{format: "{{.Name"}
+	err := opts.Print(bytes.NewBuffer(nil), repositories, nil)
+	assert.ErrorContains(t, err, `invalid format "{{.Name"`)
+}
+
+func TestOptionPrintUnsupportedFormat(t *testing.T) {
+	opts := Option{format: "yaml"}
+	err := opts.Print(bytes.NewBuffer(nil), repositories, nil)
+	assert.Error(t, err, `unsupported format type: "yaml"`)
+
+	err = NewPrinter(bytes.NewBuffer(nil), Format("jsno")).PrintRepositories(repositories)
+	assert.Error(t, err, `unsupported format type: "jsno"`)
+}
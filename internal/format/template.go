@@ -0,0 +1,95 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/docker/go-units"
+)
+
+var (
+	// escapes lets users write tabs and newlines in a template given on the
+	// command line, like docker ps --format
+	escapes = strings.NewReplacer(`\t`, "\t", `\n`, "\n")
+
+	templateFuncs = template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+		"join":  strings.Join,
+		"time": func(t time.Time) string {
+			return formatTime(t)
+		},
+		"ago": func(t time.Time) string {
+			if t.IsZero() {
+				return ""
+			}
+			return units.HumanDuration(time.Since(t)) + " ago"
+		},
+		"size": func(size interface{}) (string, error) {
+			v := reflect.ValueOf(size)
+			switch v.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return units.HumanSize(float64(v.Int())), nil
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				return units.HumanSize(float64(v.Uint())), nil
+			default:
+				return "", fmt.Errorf("size expects an integer, got %T", size)
+			}
+		},
+	}
+)
+
+// isTemplate tells whether a format given on the command line is a Go template
+// rather than a misspelled format name
+func isTemplate(format string) bool {
+	return strings.Contains(format, "{{")
+}
+
+// ParseTemplate parses a Go template given on the command line, with the
+// json, lower, upper, join, time, ago and size helper functions
+func ParseTemplate(format string) (*template.Template, error) {
+	tmpl, err := template.New("").Funcs(templateFuncs).Parse(escapes.Replace(format))
+	if err != nil {
+		return nil, fmt.Errorf("invalid format %q: %s", format, err)
+	}
+	return tmpl, nil
+}
+
+// printTemplateValues executes the template for each element when values is a
+// slice, or once for any other value, each execution followed by a newline
+func printTemplateValues(out io.Writer, format string, values interface{}) error {
+	v := reflect.ValueOf(values)
+	if v.Kind() != reflect.Slice {
+		return printTemplate(out, format, []interface{}{values})
+	}
+	items := make([]interface{}, v.Len())
+	for i := range items {
+		items[i] = v.Index(i).Interface()
+	}
+	return printTemplate(out, format, items)
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"runtime"
 	"strings"
@@ -33,11 +34,12 @@ import (
 	"github.com/docker/hub-tool/internal/ansi"
 	"github.com/docker/hub-tool/internal/credentials"
 	"github.com/docker/hub-tool/internal/errdef"
-	"github.com/docker/hub-tool/internal/hub"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
-// RunLogin logs the user and asks for the 2FA code if needed
-func RunLogin(ctx context.Context, streams command.Streams, hubClient *hub.Client, store credentials.Store, candidateUsername string) error {
+// RunLogin logs the user and asks for the 2FA code if needed. The username and
+// the password, or a personal access token, are prompted for when not given.
+func RunLogin(ctx context.Context, streams command.Streams, hubClient *hub.Client, store credentials.Store, candidateUsername string, candidatePassword string) error {
 	username := candidateUsername
 	if username == "" {
 		var err error
@@ -45,9 +47,12 @@ func RunLogin(ctx context.Context, streams command.Streams, hubClient *hub.Clien
 			return err
 		}
 	}
-	password, err := readPassword(streams)
-	if err != nil {
-		return err
+	password := candidatePassword
+	if password == "" {
+		var err error
+		if password, err = readPassword(streams); err != nil {
+			return err
+		}
 	}
 
 	token, refreshToken, err := Login(ctx, streams, hubClient, username, password)
@@ -59,17 +64,23 @@ func RunLogin(ctx context.Context, streams command.Streams, hubClient *hub.Clien
 		return err
 	}
 
-	return store.Store(credentials.Auth{
+	// Each account gets its own profile, the last one logged in becoming the
+	// current one
+	store.SetProfile(username)
+	if err := store.Store(credentials.Auth{
 		Username:     username,
 		Password:     password,
 		Token:        token,
 		RefreshToken: refreshToken,
-	})
+	}); err != nil {
+		return err
+	}
+	return store.UseProfile(username)
 }
 
 // Login runs login and optionnaly the 2FA
 func Login(ctx context.Context, streams command.Streams, hubClient *hub.Client, username string, password string) (string, string, error) {
-	return hubClient.Login(username, password, func() (string, error) {
+	return hubClient.Login(ctx, username, password, func() (string, error) {
 		return readClearText(ctx, streams, "2FA required, please provide the 6 digit code: ")
 	})
 }
@@ -91,6 +102,20 @@ func readClearText(ctx context.Context, streams command.Streams, prompt string)
 	return input, nil
 }
 
+// ReadPasswordFromStdin reads the password, or a personal access token, from
+// the whole input, so that it can be piped without a TTY
+func ReadPasswordFromStdin(in io.Reader) (string, error) {
+	contents, err := ioutil.ReadAll(in)
+	if err != nil {
+		return "", err
+	}
+	password := strings.TrimSuffix(strings.TrimSuffix(string(contents), "\n"), "\r")
+	if password == "" {
+		return "", errors.Errorf("password required")
+	}
+	return password, nil
+}
+
 func readPassword(streams command.Streams) (string, error) {
 	in := streams.In()
 	// On Windows, force the use of the regular OS stdin stream. Fixes #14336/#14210
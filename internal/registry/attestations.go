@@ -0,0 +1,204 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/platforms"
+	"github.com/containerd/containerd/remotes"
+	"github.com/docker/distribution/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// SPDXPredicateType is the predicate type of the SPDX SBOMs
+	SPDXPredicateType = "https://spdx.dev/Document"
+	// CycloneDXPredicateType is the predicate type of the CycloneDX SBOMs
+	CycloneDXPredicateType = "https://cyclonedx.org/bom"
+	// SLSAProvenancePredicateType is the prefix of the predicate types of the
+	// SLSA provenances, followed by their version
+	SLSAProvenancePredicateType = "https://slsa.dev/provenance/"
+
+	// AttestationSourceBuildKit is the source of the attestations BuildKit
+	// attaches to the image index
+	AttestationSourceBuildKit = "buildkit"
+	// AttestationSourceCosign is the source of the attestations cosign pushes
+	// to the sha256-<digest>.att tag
+	AttestationSourceCosign = "cosign"
+
+	referenceTypeAnnotation   = "vnd.docker.reference.type"
+	referenceDigestAnnotation = "vnd.docker.reference.digest"
+	attestationManifestType   = "attestation-manifest"
+	predicateTypeAnnotation   = "in-toto.io/predicate-type"
+)
+
+// Statement is an in-toto statement, whose predicate is described by its type
+type Statement struct {
+	Type          string          `json:"_type"`
+	PredicateType string          `json:"predicateType"`
+	Subject       []Subject       `json:"subject"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+// Subject is an artifact an in-toto statement is about
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Attestation is an in-toto statement attached to an image
+type Attestation struct {
+	// Platform is the platform of the image of a multi-platform image the
+	// statement is about, empty when it's about the whole image
+	Platform  string    `json:"platform,omitempty"`
+	Source    string    `json:"source"`
+	Statement Statement `json:"statement"`
+}
+
+// dsseEnvelope is the envelope cosign wraps its attestations in
+type dsseEnvelope struct {
+	PayloadType string `json:"payloadType"`
+	Payload     string `json:"payload"`
+}
+
+// GetAttestations returns the attestations attached to an image, by BuildKit
+// or by cosign, whose predicate type starts with one of the given ones
+func GetAttestations(ctx context.Context, resolver remotes.Resolver, ref string, predicateTypes ...string) ([]Attestation, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return nil, err
+	}
+	fullName, descriptor, err := resolver.Resolve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	var attestations []Attestation
+	if descriptor.MediaType == images.MediaTypeDockerSchema2ManifestList || descriptor.MediaType == ocispec.MediaTypeImageIndex {
+		attestations, err = getBuildKitAttestations(ctx, resolver, fullName, descriptor, predicateTypes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	manifest, err := GetCosignManifest(ctx, resolver, named.Name(), descriptor, CosignAttestationSuffix)
+	if err != nil {
+		return nil, err
+	}
+	if manifest != nil {
+		for _, layer := range manifest.Layers {
+			raw, err := GetBlob(ctx, resolver, fullName, layer)
+			if err != nil {
+				return nil, err
+			}
+			statement, err := ParseDSSEStatement(raw)
+			if err != nil {
+				return nil, err
+			}
+			if matchesPredicateType(statement.PredicateType, predicateTypes) {
+				attestations = append(attestations, Attestation{Source: AttestationSourceCosign, Statement: *statement})
+			}
+		}
+	}
+	return attestations, nil
+}
+
+// getBuildKitAttestations reads the attestation manifests of an index, which
+// reference the image of their platform
+func getBuildKitAttestations(ctx context.Context, resolver remotes.Resolver, name string, descriptor ocispec.Descriptor, predicateTypes []string) ([]Attestation, error) {
+	raw, err := GetBlob(ctx, resolver, name, descriptor)
+	if err != nil {
+		return nil, err
+	}
+	var index ocispec.Index
+	if err := json.Unmarshal(raw, &index); err != nil {
+		return nil, err
+	}
+	imagePlatforms := map[string]string{}
+	for _, manifest := range index.Manifests {
+		if manifest.Platform != nil {
+			imagePlatforms[manifest.Digest.String()] = platforms.Format(*manifest.Platform)
+		}
+	}
+	var attestations []Attestation
+	for _, manifest := range index.Manifests {
+		if manifest.Annotations[referenceTypeAnnotation] != attestationManifestType {
+			continue
+		}
+		raw, err := GetBlob(ctx, resolver, name, manifest)
+		if err != nil {
+			return nil, err
+		}
+		var attestationManifest ocispec.Manifest
+		if err := json.Unmarshal(raw, &attestationManifest); err != nil {
+			return nil, err
+		}
+		for _, layer := range attestationManifest.Layers {
+			if !matchesPredicateType(layer.Annotations[predicateTypeAnnotation], predicateTypes) {
+				continue
+			}
+			raw, err := GetBlob(ctx, resolver, name, layer)
+			if err != nil {
+				return nil, err
+			}
+			var statement Statement
+			if err := json.Unmarshal(raw, &statement); err != nil {
+				return nil, err
+			}
+			attestations = append(attestations, Attestation{
+				Platform:  imagePlatforms[manifest.Annotations[referenceDigestAnnotation]],
+				Source:    AttestationSourceBuildKit,
+				Statement: statement,
+			})
+		}
+	}
+	return attestations, nil
+}
+
+// ParseDSSEStatement reads the in-toto statement wrapped in a DSSE envelope
+func ParseDSSEStatement(raw []byte) (*Statement, error) {
+	var envelope dsseEnvelope
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, err
+	}
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid attestation payload: %s", err)
+	}
+	var statement Statement
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return nil, fmt.Errorf("invalid attestation payload: %s", err)
+	}
+	return &statement, nil
+}
+
+// matchesPredicateType tells if a predicate type starts with one of the given
+// ones, any predicate type matching when none is given
+func matchesPredicateType(predicateType string, predicateTypes []string) bool {
+	if len(predicateTypes) == 0 {
+		return true
+	}
+	for _, prefix := range predicateTypes {
+		if strings.HasPrefix(predicateType, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package registry
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseDSSEStatement(t *testing.T) {
+	payload := base64.StdEncoding.EncodeToString([]byte(`{
+  "_type": "https://in-toto.io/Statement/v0.1",
+  "predicateType": "https://spdx.dev/Document",
+  "subject": [{"name": "index.docker.io/myorg/app", "digest": {"sha256": "4ff3ca91"}}],
+  "predicate": {"spdxVersion": "SPDX-2.3"}
+}`))
+	statement, err := ParseDSSEStatement([]byte(`{"payloadType": "application/vnd.in-toto+json", "payload": "` + payload + `", "signatures": []}`))
+	assert.NilError(t, err)
+	assert.Equal(t, statement.PredicateType, SPDXPredicateType)
+	assert.DeepEqual(t, statement.Subject, []Subject{{Name: "index.docker.io/myorg/app", Digest: map[string]string{"sha256": "4ff3ca91"}}})
+	assert.Equal(t, string(statement.Predicate), `{"spdxVersion": "SPDX-2.3"}`)
+
+	_, err = ParseDSSEStatement([]byte(`{"payload": "not base64"}`))
+	assert.ErrorContains(t, err, "invalid attestation payload")
+}
+
+func TestMatchesPredicateType(t *testing.T) {
+	assert.Assert(t, matchesPredicateType(SPDXPredicateType, nil))
+	assert.Assert(t, matchesPredicateType("https://slsa.dev/provenance/v0.2", []string{SPDXPredicateType, SLSAProvenancePredicateType}))
+	assert.Assert(t, !matchesPredicateType("https://cyclonedx.org/bom/v1.4", []string{SPDXPredicateType}))
+}
@@ -0,0 +1,149 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package registry
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/remotes"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// CosignSignatureSuffix is the suffix of the tags cosign pushes the
+	// signatures of an image to
+	CosignSignatureSuffix = "sig"
+	// CosignAttestationSuffix is the suffix of the tags cosign pushes the
+	// attestations of an image to
+	CosignAttestationSuffix = "att"
+
+	cosignSignatureAnnotation   = "dev.cosignproject.cosign/signature"
+	cosignCertificateAnnotation = "dev.sigstore.cosign/certificate"
+	cosignBundleAnnotation      = "dev.sigstore.cosign/bundle"
+)
+
+// fulcioIssuerOID is the extension of the Fulcio certificates holding the
+// OIDC issuer of the signer identity
+var fulcioIssuerOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+
+// CosignSignature is a signature pushed by cosign. The signature itself isn't
+// verified, only read.
+type CosignSignature struct {
+	// Digest is the digest of the signed payload
+	Digest string `json:"digest"`
+	// Identity is the subject of the signing certificate, an email or a URI,
+	// empty when the image was signed with a key
+	Identity string `json:"identity,omitempty"`
+	// Issuer is the OIDC issuer which authenticated the identity
+	Issuer string `json:"issuer,omitempty"`
+	// TransparencyLog tells if the signature was recorded in Rekor
+	TransparencyLog bool `json:"transparency_log"`
+}
+
+// CosignTag returns the tag cosign pushes the artifacts of an image to, such
+// as sha256-<hex>.sig for its signatures
+func CosignTag(digest, suffix string) string {
+	return strings.Replace(digest, ":", "-", 1) + "." + suffix
+}
+
+// GetCosignManifest returns the manifest of the cosign artifacts of the given
+// suffix attached to an image, or nil if there's none
+func GetCosignManifest(ctx context.Context, resolver remotes.Resolver, name string, descriptor ocispec.Descriptor, suffix string) (*ocispec.Manifest, error) {
+	fullName, artifact, err := resolver.Resolve(ctx, name+":"+CosignTag(descriptor.Digest.String(), suffix))
+	if errdefs.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	raw, err := GetBlob(ctx, resolver, fullName, artifact)
+	if err != nil {
+		return nil, err
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
+
+// GetCosignSignatures returns the cosign signatures of an image
+func GetCosignSignatures(ctx context.Context, resolver remotes.Resolver, name string, descriptor ocispec.Descriptor) ([]CosignSignature, error) {
+	manifest, err := GetCosignManifest(ctx, resolver, name, descriptor, CosignSignatureSuffix)
+	if err != nil || manifest == nil {
+		return nil, err
+	}
+	return ParseCosignSignatures(*manifest)
+}
+
+// ParseCosignSignatures reads the signatures of a cosign signature manifest,
+// one per layer
+func ParseCosignSignatures(manifest ocispec.Manifest) ([]CosignSignature, error) {
+	var signatures []CosignSignature
+	for _, layer := range manifest.Layers {
+		if layer.Annotations[cosignSignatureAnnotation] == "" {
+			continue
+		}
+		signature := CosignSignature{
+			Digest:          layer.Digest.String(),
+			TransparencyLog: layer.Annotations[cosignBundleAnnotation] != "",
+		}
+		if cert := layer.Annotations[cosignCertificateAnnotation]; cert != "" {
+			identity, issuer, err := certificateIdentity(cert)
+			if err != nil {
+				return nil, err
+			}
+			signature.Identity = identity
+			signature.Issuer = issuer
+		}
+		signatures = append(signatures, signature)
+	}
+	return signatures, nil
+}
+
+// certificateIdentity returns the subject alternative name and the OIDC
+// issuer of a Fulcio certificate
+func certificateIdentity(certificate string) (string, string, error) {
+	block, _ := pem.Decode([]byte(certificate))
+	if block == nil {
+		return "", "", fmt.Errorf("invalid signing certificate: no PEM block")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid signing certificate: %s", err)
+	}
+	var identity string
+	switch {
+	case len(cert.EmailAddresses) > 0:
+		identity = cert.EmailAddresses[0]
+	case len(cert.URIs) > 0:
+		identity = cert.URIs[0].String()
+	}
+	var issuer string
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(fulcioIssuerOID) {
+			issuer = string(ext.Value)
+		}
+	}
+	return identity, issuer, nil
+}
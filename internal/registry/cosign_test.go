@@ -0,0 +1,75 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package registry
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+)
+
+func TestCosignTag(t *testing.T) {
+	assert.Equal(t, CosignTag("sha256:4ff3ca91", CosignSignatureSuffix), "sha256-4ff3ca91.sig")
+}
+
+func TestParseCosignSignatures(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:    big.NewInt(1),
+		Subject:         pkix.Name{CommonName: "sigstore"},
+		NotBefore:       time.Now(),
+		NotAfter:        time.Now().Add(10 * time.Minute),
+		EmailAddresses:  []string{"jane@example.com"},
+		ExtraExtensions: []pkix.Extension{{Id: fulcioIssuerOID, Value: []byte("https://github.com/login/oauth")}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NilError(t, err)
+	certificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	manifest := ocispec.Manifest{
+		Layers: []ocispec.Descriptor{
+			{
+				Digest: "sha256:1111",
+				Annotations: map[string]string{
+					cosignSignatureAnnotation:   "MEUCIQ==",
+					cosignCertificateAnnotation: certificate,
+					cosignBundleAnnotation:      `{"SignedEntryTimestamp": "MEQCIA=="}`,
+				},
+			},
+			{
+				Digest:      "sha256:2222",
+				Annotations: map[string]string{cosignSignatureAnnotation: "MEYCIQ=="},
+			},
+			{Digest: "sha256:3333"},
+		},
+	}
+	signatures, err := ParseCosignSignatures(manifest)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, signatures, []CosignSignature{
+		{Digest: "sha256:1111", Identity: "jane@example.com", Issuer: "https://github.com/login/oauth", TransparencyLog: true},
+		{Digest: "sha256:2222"},
+	})
+}
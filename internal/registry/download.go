@@ -0,0 +1,255 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/platforms"
+	"github.com/containerd/containerd/remotes"
+	"github.com/docker/distribution/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/sync/errgroup"
+)
+
+// DownloadOptions tunes the download of an image
+type DownloadOptions struct {
+	// Platform selects the image of a multi-platform image to download, all
+	// of them being downloaded when nil
+	Platform *ocispec.Platform
+	// Parallelism is the maximum number of blobs fetched at once
+	Parallelism int
+	// Progress is called, possibly concurrently, once a blob is in the layout
+	// with skipped set when it was already there
+	Progress func(descriptor ocispec.Descriptor, skipped bool)
+}
+
+// Download fetches an image and all its blobs into an OCI image layout
+// directory. The blobs already in the layout are skipped and the partially
+// downloaded ones resumed, so that an interrupted download can be run again.
+func Download(ctx context.Context, resolver remotes.Resolver, ref string, dir string, opts DownloadOptions) (ocispec.Descriptor, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	named = reference.TagNameOnly(named)
+	fullName, root, err := resolver.Resolve(ctx, named.String())
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	fetcher, err := resolver.Fetcher(ctx, fullName)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	root, blobs, err := writeManifests(ctx, resolver, fullName, dir, root, opts.Platform)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if err := fetchBlobs(ctx, fetcher, dir, blobs, opts); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	root.Annotations = map[string]string{imageNameAnnotation: named.String()}
+	if tagged, ok := named.(reference.Tagged); ok {
+		root.Annotations[ocispec.AnnotationRefName] = tagged.Tag()
+	}
+	return root, writeLayout(dir, root)
+}
+
+// writeManifests writes the manifests of an image to the layout, only keeping
+// the one of the platform of a multi-platform image when given, and returns
+// the descriptor of the image with the blobs its manifests reference
+func writeManifests(ctx context.Context, resolver remotes.Resolver, name string, dir string, descriptor ocispec.Descriptor, platform *ocispec.Platform) (ocispec.Descriptor, []ocispec.Descriptor, error) {
+	raw, err := GetBlob(ctx, resolver, name, descriptor)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, err
+	}
+	switch descriptor.MediaType {
+	case images.MediaTypeDockerSchema2Manifest, ocispec.MediaTypeImageManifest:
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(raw, &manifest); err != nil {
+			return ocispec.Descriptor{}, nil, err
+		}
+		blobs := []ocispec.Descriptor{manifest.Config}
+		for _, layer := range manifest.Layers {
+			if isDistributable(layer) {
+				blobs = append(blobs, layer)
+			}
+		}
+		return descriptor, blobs, writeBlob(dir, descriptor, raw)
+	case images.MediaTypeDockerSchema2ManifestList, ocispec.MediaTypeImageIndex:
+		var index ocispec.Index
+		if err := json.Unmarshal(raw, &index); err != nil {
+			return ocispec.Descriptor{}, nil, err
+		}
+		if platform != nil {
+			matcher := platforms.NewMatcher(*platform)
+			for _, child := range index.Manifests {
+				if child.Platform != nil && matcher.Match(*child.Platform) {
+					return writeManifests(ctx, resolver, name, dir, child, nil)
+				}
+			}
+			return ocispec.Descriptor{}, nil, fmt.Errorf("no image for platform %s", platforms.Format(*platform))
+		}
+		var blobs []ocispec.Descriptor
+		for _, child := range index.Manifests {
+			_, childBlobs, err := writeManifests(ctx, resolver, name, dir, child, nil)
+			if err != nil {
+				return ocispec.Descriptor{}, nil, err
+			}
+			blobs = append(blobs, childBlobs...)
+		}
+		return descriptor, blobs, writeBlob(dir, descriptor, raw)
+	default:
+		return ocispec.Descriptor{}, nil, fmt.Errorf("unsupported media type %q", descriptor.MediaType)
+	}
+}
+
+// isDistributable is false for the layers, such as the Windows base layers,
+// which can't be fetched from the registry
+func isDistributable(layer ocispec.Descriptor) bool {
+	switch layer.MediaType {
+	case images.MediaTypeDockerSchema2LayerForeign, images.MediaTypeDockerSchema2LayerForeignGzip,
+		ocispec.MediaTypeImageLayerNonDistributable, ocispec.MediaTypeImageLayerNonDistributableGzip:
+		return false
+	default:
+		return true
+	}
+}
+
+func writeBlob(dir string, descriptor ocispec.Descriptor, raw []byte) error {
+	path := blobPath(dir, descriptor)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, raw, 0644)
+}
+
+// fetchBlobs fetches the blobs not yet in the layout, opts.Parallelism at once
+func fetchBlobs(ctx context.Context, fetcher remotes.Fetcher, dir string, blobs []ocispec.Descriptor, opts DownloadOptions) error {
+	parallelism := opts.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	eg, egCtx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, parallelism)
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	for _, blob := range blobs {
+		if seen[blob.Digest.String()] {
+			continue
+		}
+		seen[blob.Digest.String()] = true
+		select {
+		case <-egCtx.Done():
+		case sem <- struct{}{}:
+			blob := blob
+			eg.Go(func() error {
+				defer func() { <-sem }()
+				skipped, err := fetchBlob(egCtx, fetcher, dir, blob)
+				if err != nil {
+					return err
+				}
+				if opts.Progress != nil {
+					mu.Lock()
+					defer mu.Unlock()
+					opts.Progress(blob, skipped)
+				}
+				return nil
+			})
+		}
+	}
+	return eg.Wait()
+}
+
+// fetchBlob downloads a blob to the layout, resuming from its partial file
+// left by a previous download, and checks its digest. It returns true when the
+// blob was already downloaded.
+func fetchBlob(ctx context.Context, fetcher remotes.Fetcher, dir string, descriptor ocispec.Descriptor) (bool, error) {
+	if err := descriptor.Digest.Validate(); err != nil {
+		return false, err
+	}
+	path := blobPath(dir, descriptor)
+	if info, err := os.Stat(path); err == nil && info.Size() == descriptor.Size {
+		return true, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	partial := path + partialSuffix
+	f, err := os.OpenFile(partial, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close() //nolint:errcheck
+
+	verifier := descriptor.Digest.Verifier()
+	offset, err := io.Copy(verifier, f)
+	if err != nil {
+		return false, err
+	}
+	if offset > descriptor.Size {
+		if err := f.Truncate(0); err != nil {
+			return false, err
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return false, err
+		}
+		verifier = descriptor.Digest.Verifier()
+		offset = 0
+	}
+
+	if offset < descriptor.Size {
+		rc, err := fetcher.Fetch(ctx, descriptor)
+		if err != nil {
+			return false, err
+		}
+		defer rc.Close() //nolint:errcheck
+		if offset > 0 {
+			if seeker, ok := rc.(io.Seeker); ok {
+				_, err = seeker.Seek(offset, io.SeekStart)
+			} else {
+				_, err = io.CopyN(ioutil.Discard, rc, offset)
+			}
+			if err != nil {
+				return false, err
+			}
+		}
+		if _, err := io.Copy(io.MultiWriter(f, verifier), rc); err != nil {
+			return false, err
+		}
+	}
+	if !verifier.Verified() {
+		_ = os.Remove(partial)
+		return false, fmt.Errorf("invalid content for blob %s: digest mismatch", descriptor.Digest)
+	}
+	if err := f.Close(); err != nil {
+		return false, err
+	}
+	return false, os.Rename(partial, path)
+}
@@ -0,0 +1,81 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package registry
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+)
+
+type fetcherFunc func(context.Context, ocispec.Descriptor) (io.ReadCloser, error)
+
+func (f fetcherFunc) Fetch(ctx context.Context, descriptor ocispec.Descriptor) (io.ReadCloser, error) {
+	return f(ctx, descriptor)
+}
+
+// helloWorld is the descriptor of the "hello world" blob
+var helloWorld = ocispec.Descriptor{
+	MediaType: ocispec.MediaTypeImageLayerGzip,
+	Digest:    "sha256:b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9",
+	Size:      11,
+}
+
+func TestFetchBlobResumesPartialDownload(t *testing.T) {
+	dir := fs.NewDir(t, "layout")
+	defer dir.Remove()
+	path := blobPath(dir.Path(), helloWorld)
+	assert.NilError(t, os.MkdirAll(dir.Join("blobs", "sha256"), 0755))
+	assert.NilError(t, ioutil.WriteFile(path+partialSuffix, []byte("hello"), 0644))
+
+	fetches := 0
+	fetcher := fetcherFunc(func(context.Context, ocispec.Descriptor) (io.ReadCloser, error) {
+		fetches++
+		return ioutil.NopCloser(strings.NewReader("hello world")), nil
+	})
+	skipped, err := fetchBlob(context.Background(), fetcher, dir.Path(), helloWorld)
+	assert.NilError(t, err)
+	assert.Assert(t, !skipped)
+	content, err := ioutil.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "hello world")
+	_, err = os.Stat(path + partialSuffix)
+	assert.Assert(t, os.IsNotExist(err))
+
+	skipped, err = fetchBlob(context.Background(), fetcher, dir.Path(), helloWorld)
+	assert.NilError(t, err)
+	assert.Assert(t, skipped)
+	assert.Equal(t, fetches, 1)
+}
+
+func TestFetchBlobChecksDigest(t *testing.T) {
+	dir := fs.NewDir(t, "layout")
+	defer dir.Remove()
+	fetcher := fetcherFunc(func(context.Context, ocispec.Descriptor) (io.ReadCloser, error) {
+		return ioutil.NopCloser(strings.NewReader("hello there")), nil
+	})
+	_, err := fetchBlob(context.Background(), fetcher, dir.Path(), helloWorld)
+	assert.ErrorContains(t, err, "digest mismatch")
+	_, err = os.Stat(blobPath(dir.Path(), helloWorld) + partialSuffix)
+	assert.Assert(t, os.IsNotExist(err))
+}
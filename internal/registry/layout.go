@@ -0,0 +1,159 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package registry
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// imageNameAnnotation is the annotation containerd, and so docker load,
+	// reads the full name of an image from
+	imageNameAnnotation = "io.containerd.image.name"
+	// partialSuffix is appended to the blobs being downloaded
+	partialSuffix = ".partial"
+)
+
+// blobPath returns where a blob is stored in an OCI image layout
+func blobPath(dir string, descriptor ocispec.Descriptor) string {
+	return filepath.Join(dir, "blobs", descriptor.Digest.Algorithm().String(), descriptor.Digest.Encoded())
+}
+
+// writeLayout writes the oci-layout and index.json files of an OCI image
+// layout, whose index references the given manifests
+func writeLayout(dir string, manifests ...ocispec.Descriptor) error {
+	layout, err := json.Marshal(ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, ocispec.ImageLayoutFile), layout, 0644); err != nil {
+		return err
+	}
+	index := ocispec.Index{Manifests: manifests}
+	index.SchemaVersion = 2
+	raw, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, "index.json"), raw, 0644)
+}
+
+// readLayoutIndex reads the index.json file of an OCI image layout
+func readLayoutIndex(dir string) (ocispec.Index, error) {
+	raw, err := ioutil.ReadFile(filepath.Join(dir, "index.json"))
+	if os.IsNotExist(err) {
+		return ocispec.Index{}, fmt.Errorf("%s is not an OCI image layout: index.json not found", dir)
+	}
+	if err != nil {
+		return ocispec.Index{}, err
+	}
+	var index ocispec.Index
+	if err := json.Unmarshal(raw, &index); err != nil {
+		return ocispec.Index{}, err
+	}
+	return index, nil
+}
+
+// TarLayout writes an OCI image layout directory as a tarball
+func TarLayout(dir string, w io.Writer) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil || name == "." {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close() //nolint:errcheck
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// UntarLayout extracts a tarball of an OCI image layout to a directory
+func UntarLayout(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(path, filepath.Clean(dir)+string(filepath.Separator)) {
+			return fmt.Errorf("invalid path %q in the tarball", header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			if err := extractFile(tr, path); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func extractFile(r io.Reader, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
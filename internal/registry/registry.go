@@ -0,0 +1,110 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package registry reads and writes the images of the Docker Hub registry,
+// authenticated as the Hub user
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+// NewResolver returns a resolver of the images in the registry, authenticated
+// as the Hub user
+func NewResolver(hubClient *hub.Client) remotes.Resolver {
+	authorizer := docker.NewDockerAuthorizer(docker.WithAuthCreds(func(string) (string, string, error) {
+		return hubClient.AuthConfig.Username, hubClient.AuthConfig.Password, nil
+	}))
+	registryHosts := docker.ConfigureDefaultRegistries(docker.WithClient(hubClient.HTTPClient()), docker.WithAuthorizer(authorizer))
+
+	return docker.NewResolver(docker.ResolverOptions{
+		Hosts: registryHosts,
+	})
+}
+
+// GetBlob fetches a blob, such as a manifest or a config, of a repository
+func GetBlob(ctx context.Context, resolver remotes.Resolver, fullName string, descriptor ocispec.Descriptor) ([]byte, error) {
+	fetcher, err := resolver.Fetcher(ctx, fullName)
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := fetcher.Fetch(ctx, descriptor)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rc.Close()
+	}()
+
+	buf := bytes.NewBuffer(nil)
+	if _, err = io.Copy(buf, rc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GetManifests returns the manifest of an image reference, or the manifests
+// of all its platforms for a multi-platform image
+func GetManifests(ctx context.Context, resolver remotes.Resolver, ref string) ([]ocispec.Manifest, error) {
+	fullName, descriptor, err := resolver.Resolve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := GetBlob(ctx, resolver, fullName, descriptor)
+	if err != nil {
+		return nil, err
+	}
+	switch descriptor.MediaType {
+	case images.MediaTypeDockerSchema2Manifest, ocispec.MediaTypeImageManifest:
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(raw, &manifest); err != nil {
+			return nil, err
+		}
+		return []ocispec.Manifest{manifest}, nil
+	case images.MediaTypeDockerSchema2ManifestList, ocispec.MediaTypeImageIndex:
+		var index ocispec.Index
+		if err := json.Unmarshal(raw, &index); err != nil {
+			return nil, err
+		}
+		var manifests []ocispec.Manifest
+		for _, child := range index.Manifests {
+			raw, err := GetBlob(ctx, resolver, fullName, child)
+			if err != nil {
+				return nil, err
+			}
+			var manifest ocispec.Manifest
+			if err := json.Unmarshal(raw, &manifest); err != nil {
+				return nil, err
+			}
+			manifests = append(manifests, manifest)
+		}
+		return manifests, nil
+	default:
+		return nil, fmt.Errorf("unsupported media type %q for %s", descriptor.MediaType, ref)
+	}
+}
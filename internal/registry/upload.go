@@ -0,0 +1,435 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/images"
+	"github.com/docker/distribution/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	// registryURL is the API of the Docker Hub registry
+	registryURL = "https://registry-1.docker.io"
+	// DefaultChunkSize is the size of the chunks blobs are uploaded in
+	DefaultChunkSize = 16 << 20
+	// tokenExpiryMargin renews the registry token before it expires during
+	// long uploads
+	tokenExpiryMargin = 30 * time.Second
+)
+
+// UploadOptions tunes the upload of an image
+type UploadOptions struct {
+	// ChunkSize is the size of the chunks blobs are uploaded in
+	ChunkSize int64
+	// Progress is called once a blob is in the repository, with skipped set
+	// when it was already there
+	Progress func(descriptor ocispec.Descriptor, skipped bool)
+}
+
+// Upload pushes the image of an OCI image layout directory to a repository
+// tag, with the registry API as the layout already holds the manifests and
+// the blobs. Blobs are uploaded in chunks, a failed chunk being sent again
+// from where the registry stopped receiving it.
+func Upload(ctx context.Context, hubClient *hub.Client, dir string, ref string, opts UploadOptions) (ocispec.Descriptor, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	tagged, ok := named.(reference.NamedTagged)
+	if !ok {
+		return ocispec.Descriptor{}, fmt.Errorf("invalid reference %q: tag must be specified", ref)
+	}
+	repository := reference.Path(named)
+	u := &uploader{
+		client:     hubClient,
+		retries:    hubClient.Retries(),
+		base:       registryURL,
+		repository: repository,
+		token: func(ctx context.Context) (*hub.RegistryToken, error) {
+			return hubClient.RegistryToken(ctx, repository, "pull,push")
+		},
+		opts:   opts,
+		dryRun: hubClient.DryRun(),
+	}
+	return upload(ctx, u, dir, tagged.Tag())
+}
+
+func upload(ctx context.Context, u *uploader, dir string, tag string) (ocispec.Descriptor, error) {
+	index, err := readLayoutIndex(dir)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if len(index.Manifests) != 1 {
+		return ocispec.Descriptor{}, fmt.Errorf("the image layout holds %d images, only one can be uploaded", len(index.Manifests))
+	}
+	root := index.Manifests[0]
+	if err := u.pushImage(ctx, dir, root, tag); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	root.Annotations = nil
+	return root, nil
+}
+
+// doer sends the requests, retrying them on the transient errors
+type doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// uploader pushes blobs and manifests to a repository of the registry. The
+// requests are retried by the client, except the chunks which can't be sent
+// again as is: a failed chunk is resumed from where the registry stopped
+// receiving it, up to retries times.
+type uploader struct {
+	client     doer
+	retries    int
+	base       string
+	repository string
+	token      func(ctx context.Context) (*hub.RegistryToken, error)
+	opts       UploadOptions
+	// dryRun is set when the client answers the pushes itself, without
+	// sending them
+	dryRun bool
+
+	mu            sync.Mutex
+	registryToken *hub.RegistryToken
+}
+
+// pushImage pushes the blobs of a manifest, or the manifests of an index,
+// before the manifest itself as the registry checks it references existing
+// content. Only the root manifest is pushed under the tag.
+func (u *uploader) pushImage(ctx context.Context, dir string, descriptor ocispec.Descriptor, tag string) error {
+	raw, err := ioutil.ReadFile(blobPath(dir, descriptor))
+	if err != nil {
+		return err
+	}
+	switch descriptor.MediaType {
+	case images.MediaTypeDockerSchema2Manifest, ocispec.MediaTypeImageManifest:
+		var manifest ocispec.Manifest
+		if err := json.Unmarshal(raw, &manifest); err != nil {
+			return err
+		}
+		blobs := []ocispec.Descriptor{manifest.Config}
+		for _, layer := range manifest.Layers {
+			if isDistributable(layer) {
+				blobs = append(blobs, layer)
+			}
+		}
+		for _, blob := range blobs {
+			if err := u.pushBlob(ctx, dir, blob); err != nil {
+				return err
+			}
+		}
+	case images.MediaTypeDockerSchema2ManifestList, ocispec.MediaTypeImageIndex:
+		var index ocispec.Index
+		if err := json.Unmarshal(raw, &index); err != nil {
+			return err
+		}
+		for _, child := range index.Manifests {
+			if err := u.pushImage(ctx, dir, child, child.Digest.String()); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported media type %q", descriptor.MediaType)
+	}
+	return u.putManifest(ctx, tag, descriptor.MediaType, raw)
+}
+
+// pushBlob uploads a blob of the layout unless the repository already has it
+func (u *uploader) pushBlob(ctx context.Context, dir string, descriptor ocispec.Descriptor) error {
+	resp, err := u.send(ctx, "HEAD", u.url("/blobs/"+descriptor.Digest.String()), nil, nil, http.StatusOK, http.StatusNotFound)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusOK {
+		u.progress(descriptor, true)
+		return nil
+	}
+	if u.dryRun {
+		// The upload would stop at its first request, answered by the client
+		if _, err := u.send(ctx, "POST", u.url("/blobs/uploads/"), nil, nil, http.StatusOK); err != nil {
+			return err
+		}
+		u.progress(descriptor, false)
+		return nil
+	}
+
+	f, err := os.Open(blobPath(dir, descriptor))
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck
+
+	resp, err = u.send(ctx, "POST", u.url("/blobs/uploads/"), nil, nil, http.StatusAccepted)
+	if err != nil {
+		return err
+	}
+	location, err := u.location(resp)
+	if err != nil {
+		return err
+	}
+	var offset int64
+	attempt := 0
+	for offset < descriptor.Size {
+		location, offset, err = u.uploadChunk(ctx, location, f, offset, descriptor.Size)
+		if err == nil {
+			attempt = 0
+			continue
+		}
+		if attempt >= u.retries || !isRetryable(err) {
+			return err
+		}
+		if err := wait(ctx, err, attempt); err != nil {
+			return err
+		}
+		attempt++
+		// The chunk may have been partially received, resume from where
+		// the registry stopped
+		if location, offset, err = u.uploadStatus(ctx, location); err != nil {
+			return err
+		}
+	}
+
+	q := location.Query()
+	q.Set("digest", descriptor.Digest.String())
+	location.RawQuery = q.Encode()
+	if _, err := u.send(ctx, "PUT", location, nil, nil, http.StatusCreated); err != nil {
+		return err
+	}
+	u.progress(descriptor, false)
+	return nil
+}
+
+// uploadChunk sends the chunk of the blob starting at offset, returning the
+// location of the upload and the offset of the next chunk
+func (u *uploader) uploadChunk(ctx context.Context, location *url.URL, f io.ReaderAt, offset, size int64) (*url.URL, int64, error) {
+	chunkSize := u.opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	end := offset + chunkSize
+	if end > size {
+		end = size
+	}
+	header := http.Header{}
+	header.Set("Content-Type", "application/octet-stream")
+	header.Set("Content-Range", fmt.Sprintf("%d-%d", offset, end-1))
+	body := io.NewSectionReader(f, offset, end-offset)
+	resp, err := u.send(ctx, "PATCH", location, header, body, http.StatusAccepted)
+	if err != nil {
+		return location, offset, err
+	}
+	next, err := u.location(resp)
+	if err != nil {
+		return location, offset, err
+	}
+	return next, end, nil
+}
+
+// uploadStatus returns the location of an upload and the offset the registry
+// expects the next chunk at
+func (u *uploader) uploadStatus(ctx context.Context, location *url.URL) (*url.URL, int64, error) {
+	resp, err := u.send(ctx, "GET", location, nil, nil, http.StatusNoContent)
+	if err != nil {
+		return nil, 0, err
+	}
+	next, err := u.location(resp)
+	if err != nil {
+		return nil, 0, err
+	}
+	// The range is inclusive, 0-0 being returned before any byte is received
+	fields := strings.SplitN(resp.Header.Get("Range"), "-", 2)
+	if len(fields) != 2 {
+		return next, 0, nil
+	}
+	end, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil || end == 0 {
+		return next, 0, nil
+	}
+	return next, end + 1, nil
+}
+
+func (u *uploader) putManifest(ctx context.Context, tag, mediaType string, raw []byte) error {
+	header := http.Header{}
+	header.Set("Content-Type", mediaType)
+	expected := []int{http.StatusCreated}
+	if u.dryRun {
+		expected = append(expected, http.StatusOK)
+	}
+	_, err := u.send(ctx, "PUT", u.url("/manifests/"+tag), header, bytes.NewReader(raw), expected...)
+	return err
+}
+
+func (u *uploader) progress(descriptor ocispec.Descriptor, skipped bool) {
+	if u.opts.Progress != nil {
+		u.opts.Progress(descriptor, skipped)
+	}
+}
+
+func (u *uploader) url(path string) *url.URL {
+	return &url.URL{Path: "/v2/" + u.repository + path}
+}
+
+// location returns the URL of an upload, resolving it against the registry
+// as it is usually relative
+func (u *uploader) location(resp *http.Response) (*url.URL, error) {
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return nil, errors.New("missing upload location in the registry response")
+	}
+	return url.Parse(location)
+}
+
+// send sends a request to the registry, returning an error when its status
+// isn't one of the expected ones
+func (u *uploader) send(ctx context.Context, method string, target *url.URL, header http.Header, body io.Reader, expected ...int) (*http.Response, error) {
+	base, err := url.Parse(u.base)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, base.ResolveReference(target).String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if sr, ok := body.(*io.SectionReader); ok {
+		req.ContentLength = sr.Size()
+	}
+	token, err := u.bearerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range expected {
+		if resp.StatusCode == status {
+			return resp, nil
+		}
+	}
+	return nil, &registryError{statusCode: resp.StatusCode, status: resp.Status, message: registryErrorMessage(buf), retryAfter: resp.Header.Get("Retry-After")}
+}
+
+// bearerToken returns the registry token, requesting a new one when it is
+// about to expire
+func (u *uploader) bearerToken(ctx context.Context) (string, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.registryToken == nil || time.Now().Add(tokenExpiryMargin).After(u.registryToken.ExpiresAt()) {
+		token, err := u.token(ctx)
+		if err != nil {
+			return "", err
+		}
+		u.registryToken = token
+	}
+	return u.registryToken.Token, nil
+}
+
+// registryError is an unexpected response of the registry
+type registryError struct {
+	statusCode int
+	status     string
+	message    string
+	retryAfter string
+}
+
+func (e *registryError) Error() string {
+	if e.message != "" {
+		return fmt.Sprintf("registry returned %q: %s", e.status, e.message)
+	}
+	return fmt.Sprintf("registry returned %q", e.status)
+}
+
+// registryErrorMessage extracts the messages of the errors returned by the
+// registry, as described by the distribution specification
+func registryErrorMessage(body []byte) string {
+	var response struct {
+		Errors []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return ""
+	}
+	var messages []string
+	for _, e := range response.Errors {
+		messages = append(messages, e.Message)
+	}
+	return strings.Join(messages, ", ")
+}
+
+// isRetryable is true for the network errors and the server errors, as
+// opposed to the rejected requests which would fail again
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var e *registryError
+	if errors.As(err, &e) {
+		return e.statusCode >= http.StatusInternalServerError || e.statusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// wait waits before resuming an upload after the chunk failed with err, as
+// long as the Hub requests wait before being retried
+func wait(ctx context.Context, err error, attempt int) error {
+	var retryAfter string
+	var e *registryError
+	if errors.As(err, &e) {
+		retryAfter = e.retryAfter
+	}
+	delay, ok := hub.RetryDelay(retryAfter, attempt)
+	if !ok {
+		return err
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
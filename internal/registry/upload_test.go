@@ -0,0 +1,162 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package registry
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+// descriptorOf returns the descriptor of a content
+func descriptorOf(t *testing.T, mediaType string, content []byte) ocispec.Descriptor {
+	raw := fmt.Sprintf(`{"mediaType": %q, "digest": "sha256:%x", "size": %d}`, mediaType, sha256.Sum256(content), len(content))
+	var descriptor ocispec.Descriptor
+	assert.NilError(t, json.Unmarshal([]byte(raw), &descriptor))
+	return descriptor
+}
+
+// testRegistry stores the blobs and manifests pushed to it, failing the
+// second chunk of each upload once
+type testRegistry struct {
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	uploads   map[string][]byte
+	manifests map[string][]byte
+	failed    bool
+}
+
+func (r *testRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	body, _ := ioutil.ReadAll(req.Body)
+	path := req.URL.Path
+	switch {
+	case req.Method == "HEAD":
+		if _, ok := r.blobs[path[len("/v2/jdoe/app/blobs/"):]]; ok {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	case req.Method == "POST":
+		id := strconv.Itoa(len(r.uploads))
+		r.uploads[id] = nil
+		w.Header().Set("Location", "/v2/jdoe/app/blobs/uploads/"+id)
+		w.WriteHeader(http.StatusAccepted)
+	case req.Method == "PATCH":
+		id := path[len("/v2/jdoe/app/blobs/uploads/"):]
+		if len(r.uploads[id]) > 0 && !r.failed {
+			r.failed = true
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		r.uploads[id] = append(r.uploads[id], body...)
+		w.Header().Set("Location", path)
+		w.WriteHeader(http.StatusAccepted)
+	case req.Method == "GET":
+		w.Header().Set("Location", path)
+		w.Header().Set("Range", fmt.Sprintf("0-%d", len(r.uploads[path[len("/v2/jdoe/app/blobs/uploads/"):]])-1))
+		w.WriteHeader(http.StatusNoContent)
+	case req.Method == "PUT" && req.URL.Query().Get("digest") != "":
+		r.blobs[req.URL.Query().Get("digest")] = r.uploads[path[len("/v2/jdoe/app/blobs/uploads/"):]]
+		w.WriteHeader(http.StatusCreated)
+	case req.Method == "PUT":
+		r.manifests[path[len("/v2/jdoe/app/manifests/"):]] = body
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+func TestUpload(t *testing.T) {
+	config := []byte(`{"architecture": "amd64", "os": "linux"}`)
+	layer := []byte("a layer uploaded in several chunks")
+	configDescriptor := descriptorOf(t, ocispec.MediaTypeImageConfig, config)
+	layerDescriptor := descriptorOf(t, ocispec.MediaTypeImageLayerGzip, layer)
+	manifest, err := json.Marshal(ocispec.Manifest{Config: configDescriptor, Layers: []ocispec.Descriptor{layerDescriptor}})
+	assert.NilError(t, err)
+	manifestDescriptor := descriptorOf(t, ocispec.MediaTypeImageManifest, manifest)
+
+	dir := fs.NewDir(t, "layout")
+	defer dir.Remove()
+	assert.NilError(t, writeBlob(dir.Path(), configDescriptor, config))
+	assert.NilError(t, writeBlob(dir.Path(), layerDescriptor, layer))
+	assert.NilError(t, writeBlob(dir.Path(), manifestDescriptor, manifest))
+	assert.NilError(t, writeLayout(dir.Path(), manifestDescriptor))
+
+	registry := &testRegistry{
+		blobs:     map[string][]byte{configDescriptor.Digest.String(): config},
+		uploads:   map[string][]byte{},
+		manifests: map[string][]byte{},
+	}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+	hubClient, err := hub.NewClient(hub.WithRetries(2))
+	assert.NilError(t, err)
+	var skipped []bool
+	u := &uploader{
+		client:     hubClient,
+		retries:    hubClient.Retries(),
+		base:       server.URL,
+		repository: "jdoe/app",
+		token: func(context.Context) (*hub.RegistryToken, error) {
+			return &hub.RegistryToken{Token: "token", ExpiresIn: time.Hour, IssuedAt: time.Now()}, nil
+		},
+		opts: UploadOptions{
+			ChunkSize: 10,
+			Progress: func(_ ocispec.Descriptor, s bool) {
+				skipped = append(skipped, s)
+			},
+		},
+	}
+
+	image, err := upload(context.Background(), u, dir.Path(), "latest")
+	assert.NilError(t, err)
+	assert.Equal(t, image.Digest, manifestDescriptor.Digest)
+	assert.Assert(t, registry.failed)
+	assert.DeepEqual(t, registry.blobs[layerDescriptor.Digest.String()], layer)
+	assert.DeepEqual(t, registry.manifests["latest"], manifest)
+	assert.DeepEqual(t, skipped, []bool{true, false})
+}
+
+func TestTarLayoutRoundTrip(t *testing.T) {
+	src := fs.NewDir(t, "layout", fs.WithFile("oci-layout", `{"imageLayoutVersion": "1.0.0"}`),
+		fs.WithDir("blobs", fs.WithDir("sha256", fs.WithFile("beef", "content"))))
+	defer src.Remove()
+	buf := bytes.NewBuffer(nil)
+	assert.NilError(t, TarLayout(src.Path(), buf))
+
+	dst := fs.NewDir(t, "extracted")
+	defer dst.Remove()
+	assert.NilError(t, UntarLayout(buf, dst.Path()))
+	content, err := ioutil.ReadFile(dst.Join("blobs", "sha256", "beef"))
+	assert.NilError(t, err)
+	assert.Equal(t, string(content), "content")
+}
@@ -0,0 +1,86 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package retention
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+// RepositoryPlan lists the tags of a repository its rule deletes
+type RepositoryPlan struct {
+	Repository string `json:"repository"`
+	// Rule is the number of the rule of the repository, from 1
+	Rule   int      `json:"rule"`
+	Tags   int      `json:"tags"`
+	Delete []string `json:"delete"`
+}
+
+// Plan evaluates the policy against all the repositories of its organization
+// and returns the ones with tags to delete, without deleting anything. The
+// client must fetch all the elements.
+func Plan(ctx context.Context, hubClient *hub.Client, policy *Policy, now time.Time) ([]RepositoryPlan, error) {
+	repos, _, err := hubClient.GetRepositories(ctx, policy.Organization)
+	if err != nil {
+		return nil, err
+	}
+	plans := make([]*RepositoryPlan, len(repos))
+	sem := make(chan struct{}, hubClient.Concurrency())
+	g, ctx := errgroup.WithContext(ctx)
+	for i := range repos {
+		i := i
+		name := repos[i].Name[strings.Index(repos[i].Name, "/")+1:]
+		rule := policy.RuleFor(name)
+		if rule < 0 {
+			continue
+		}
+		g.Go(func() error {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			defer func() { <-sem }()
+			tags, _, err := hubClient.GetTags(ctx, repos[i].Name)
+			if err != nil {
+				return fmt.Errorf("%s: %w", repos[i].Name, err)
+			}
+			plans[i] = &RepositoryPlan{
+				Repository: repos[i].Name,
+				Rule:       rule + 1,
+				Tags:       len(tags),
+				Delete:     policy.Rules[rule].Prune(tags, now),
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	result := []RepositoryPlan{}
+	for _, plan := range plans {
+		if plan != nil && len(plan.Delete) > 0 {
+			result = append(result, *plan)
+		}
+	}
+	return result, nil
+}
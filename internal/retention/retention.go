@@ -0,0 +1,237 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+// Package retention reads the retention policy of the tags of an organization
+// and plans the deletions it requires
+package retention
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/docker/hub-tool/internal/age"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+// Policy is the retention policy of the repositories of an organization
+type Policy struct {
+	Organization string
+	// Rules apply to the repositories in order, the first one matching the
+	// name of a repository being its rule
+	Rules []Rule
+}
+
+// Rule tells which tags of the repositories it matches are deleted. Without
+// KeepLast nor OlderThan, all of them are kept.
+type Rule struct {
+	// Repositories is a glob on the repository names, without namespace
+	Repositories string
+	// Tags is a glob on the tags the rule deletes, the others are kept
+	Tags string
+	// KeepLast is the number of last pushed tags kept
+	KeepLast int
+	// OlderThan only deletes the tags last pushed before this age
+	OlderThan time.Duration
+	// Exclude are globs of the tags never deleted
+	Exclude []string
+}
+
+// Load reads a policy file
+func Load(file string) (*Policy, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() //nolint:errcheck
+	return Parse(f, file)
+}
+
+// Parse reads a YAML policy, name being used in the error messages:
+//
+//   organization: myorg
+//   rules:
+//     - repositories: "app-*"
+//       tags: "pr-*"
+//       keep-last: 5
+//       older-than: 30d
+//     - repositories: "*"
+//       keep-last: 20
+//       exclude: ["latest", "release-*"]
+func Parse(r io.Reader, name string) (*Policy, error) {
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var file policyFile
+	if err := yaml.UnmarshalStrict(buf, &file); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %s", name, strings.TrimPrefix(err.Error(), "yaml: "))
+	}
+	policy := Policy{Organization: file.Organization}
+	for i, r := range file.Rules {
+		rule, err := r.rule()
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy file %s, rule %d: %s", name, i+1, err)
+		}
+		policy.Rules = append(policy.Rules, rule)
+	}
+	if err := policy.validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %s", name, err)
+	}
+	return &policy, nil
+}
+
+// policyFile is the content of a policy file
+type policyFile struct {
+	Organization string     `yaml:"organization"`
+	Rules        []ruleFile `yaml:"rules"`
+}
+
+type ruleFile struct {
+	Repositories string   `yaml:"repositories"`
+	Tags         string   `yaml:"tags"`
+	KeepLast     int      `yaml:"keep-last"`
+	OlderThan    string   `yaml:"older-than"`
+	Exclude      patterns `yaml:"exclude"`
+}
+
+// patterns is a list of globs, which can also be written as a single one
+type patterns []string
+
+func (p *patterns) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var pattern string
+	if err := unmarshal(&pattern); err == nil {
+		*p = patterns{pattern}
+		return nil
+	}
+	var list []string
+	if err := unmarshal(&list); err != nil {
+		return err
+	}
+	*p = list
+	return nil
+}
+
+func (r ruleFile) rule() (Rule, error) {
+	rule := Rule{
+		Repositories: r.Repositories,
+		Tags:         r.Tags,
+		KeepLast:     r.KeepLast,
+		Exclude:      r.Exclude,
+	}
+	for _, pattern := range append([]string{r.Repositories, r.Tags}, r.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return Rule{}, fmt.Errorf("invalid pattern %q: %s", pattern, err)
+		}
+	}
+	if r.KeepLast < 0 {
+		return Rule{}, fmt.Errorf("invalid keep-last %d: should be a positive number", r.KeepLast)
+	}
+	if r.OlderThan != "" {
+		d, err := age.Parse(r.OlderThan)
+		if err != nil {
+			return Rule{}, err
+		}
+		rule.OlderThan = d
+	}
+	return rule, nil
+}
+
+func (p *Policy) validate() error {
+	if p.Organization == "" {
+		return fmt.Errorf("missing organization")
+	}
+	if len(p.Rules) == 0 {
+		return fmt.Errorf("missing rules")
+	}
+	for i := range p.Rules {
+		if p.Rules[i].Repositories == "" {
+			p.Rules[i].Repositories = "*"
+		}
+		if p.Rules[i].Tags == "" {
+			p.Rules[i].Tags = "*"
+		}
+	}
+	return nil
+}
+
+// RuleFor returns the index of the first rule matching a repository name,
+// given without namespace, or -1 when none does
+func (p *Policy) RuleFor(repository string) int {
+	for i, rule := range p.Rules {
+		if matched, _ := path.Match(rule.Repositories, repository); matched {
+			return i
+		}
+	}
+	return -1
+}
+
+// Prune returns the names of the tags the rule deletes, from the oldest pushed:
+// only the tags matching the rule are candidates, except the excluded ones,
+// and neither the KeepLast last pushed of them nor the ones pushed after the
+// OlderThan age are deleted
+func (r Rule) Prune(tags []hub.Tag, now time.Time) []string {
+	if r.KeepLast == 0 && r.OlderThan == 0 {
+		return nil
+	}
+	var candidates []hub.Tag
+	for _, tag := range tags {
+		name := shortTagName(tag)
+		if matched, _ := path.Match(r.Tags, name); matched && !r.isExcluded(name) {
+			candidates = append(candidates, tag)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return lastPushed(candidates[i]).After(lastPushed(candidates[j]))
+	})
+	var pruned []string
+	for i := len(candidates) - 1; i >= r.KeepLast; i-- {
+		if r.OlderThan != 0 && !lastPushed(candidates[i]).Before(now.Add(-r.OlderThan)) {
+			continue
+		}
+		pruned = append(pruned, shortTagName(candidates[i]))
+	}
+	return pruned
+}
+
+func (r Rule) isExcluded(tag string) bool {
+	for _, pattern := range r.Exclude {
+		if matched, _ := path.Match(pattern, tag); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// lastPushed returns when the tag was last pushed, falling back to its last
+// update for the tags without push date
+func lastPushed(tag hub.Tag) time.Time {
+	if tag.LastPushed.IsZero() {
+		return tag.LastUpdated
+	}
+	return tag.LastPushed
+}
+
+// shortTagName strips the repository the tag names are prefixed with
+func shortTagName(tag hub.Tag) string {
+	return tag.Name[strings.LastIndex(tag.Name, ":")+1:]
+}
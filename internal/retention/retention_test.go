@@ -0,0 +1,107 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package retention
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+func TestParse(t *testing.T) {
+	policy, err := Parse(strings.NewReader(`---
+# retention of myorg
+organization: myorg
+rules:
+  - repositories: "app-*"
+    tags: 'pr-*' # pull requests
+    keep-last: 5
+    older-than: 30d
+  - keep-last: 20
+    exclude:
+      - latest
+      - release-*
+  - repositories: db
+    exclude: latest
+`), "retention.yaml")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, policy, &Policy{
+		Organization: "myorg",
+		Rules: []Rule{
+			{Repositories: "app-*", Tags: "pr-*", KeepLast: 5, OlderThan: 30 * 24 * time.Hour},
+			{Repositories: "*", Tags: "*", KeepLast: 20, Exclude: []string{"latest", "release-*"}},
+			{Repositories: "db", Tags: "*", Exclude: []string{"latest"}},
+		},
+	})
+	assert.Equal(t, policy.RuleFor("app-web"), 0)
+	assert.Equal(t, policy.RuleFor("db"), 1)
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		content       string
+		expectedError string
+	}{
+		{name: "missing organization", content: "rules:\n  - keep-last: 1\n", expectedError: "invalid policy file retention.yaml: missing organization"},
+		{name: "missing rules", content: "organization: myorg\n", expectedError: "invalid policy file retention.yaml: missing rules"},
+		{name: "unknown key", content: "org: myorg\n", expectedError: "invalid policy file retention.yaml: unmarshal errors:\n  line 1: field org not found"},
+		{name: "unknown rule key", content: "organization: myorg\nrules:\n  - keep: 1\n", expectedError: "line 3: field keep not found"},
+		{name: "invalid keep-last", content: "organization: myorg\nrules:\n  - keep-last: -1\n", expectedError: "invalid policy file retention.yaml, rule 1: invalid keep-last -1: should be a positive number"},
+		{name: "invalid age", content: "organization: myorg\nrules:\n  - keep-last: 1\n  - older-than: soon\n", expectedError: `invalid policy file retention.yaml, rule 2: invalid age "soon"`},
+		{name: "invalid pattern", content: "organization: myorg\nrules:\n  - tags: \"[\"\n", expectedError: `rule 1: invalid pattern "["`},
+		{name: "rules not a list", content: "organization: myorg\nrules: all\n", expectedError: "line 2: cannot unmarshal !!str `all`"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(testCase.content), "retention.yaml")
+			assert.ErrorContains(t, err, testCase.expectedError)
+		})
+	}
+}
+
+func TestRulePrune(t *testing.T) {
+	now := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return now.Add(-time.Duration(days) * 24 * time.Hour) }
+	tags := []hub.Tag{
+		{Name: "myorg/app:latest", LastPushed: daysAgo(1)},
+		{Name: "myorg/app:pr-1", LastPushed: daysAgo(60)},
+		{Name: "myorg/app:pr-2", LastPushed: daysAgo(40)},
+		{Name: "myorg/app:pr-3", LastUpdated: daysAgo(20)},
+		{Name: "myorg/app:pr-4", LastPushed: daysAgo(2)},
+		{Name: "myorg/app:release-1", LastPushed: daysAgo(300)},
+	}
+	testCases := []struct {
+		name     string
+		rule     Rule
+		expected []string
+	}{
+		{name: "keep everything", rule: Rule{Tags: "*"}},
+		{name: "keep last", rule: Rule{Tags: "*", KeepLast: 3}, expected: []string{"release-1", "pr-1", "pr-2"}},
+		{name: "older than", rule: Rule{Tags: "*", OlderThan: 30 * 24 * time.Hour}, expected: []string{"release-1", "pr-1", "pr-2"}},
+		{name: "matching tags", rule: Rule{Tags: "pr-*", KeepLast: 1}, expected: []string{"pr-1", "pr-2", "pr-3"}},
+		{name: "excluded tags", rule: Rule{Tags: "*", KeepLast: 1, OlderThan: 10 * 24 * time.Hour, Exclude: []string{"release-*"}}, expected: []string{"pr-1", "pr-2", "pr-3"}},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.DeepEqual(t, testCase.rule.Prune(tags, now), testCase.expected)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package sarif writes vulnerability scan reports in the SARIF format, to be
+// uploaded to GitHub code scanning and the other SARIF consumers
+package sarif
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/docker/hub-tool/internal"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+const (
+	version = "2.1.0"
+	schema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// levels maps the Hub severities to the SARIF levels
+var levels = map[string]string{
+	hub.SeverityCritical: "error",
+	hub.SeverityHigh:     "error",
+	hub.SeverityMedium:   "warning",
+	hub.SeverityLow:      "note",
+}
+
+// securitySeverities maps the Hub severities to the CVSS like scores GitHub
+// code scanning uses to rank the security alerts
+var securitySeverities = map[string]string{
+	hub.SeverityCritical: "9.5",
+	hub.SeverityHigh:     "8.0",
+	hub.SeverityMedium:   "5.5",
+	hub.SeverityLow:      "2.0",
+}
+
+// Write outputs the scan report of an image, given by its reference, as a
+// SARIF log with one rule per vulnerability
+func Write(out io.Writer, image string, report *hub.ScanReport) error {
+	r := run{
+		Tool: tool{Driver: driver{
+			Name:           "hub-tool",
+			InformationURI: "https://github.com/docker/hub-tool",
+			Version:        internal.Version,
+			Rules:          []rule{},
+		}},
+		Results: []result{},
+	}
+	seen := map[string]bool{}
+	for _, vulnerability := range report.Vulnerabilities {
+		level := Level(vulnerability.Severity)
+		if !seen[vulnerability.ID] {
+			seen[vulnerability.ID] = true
+			r.Tool.Driver.Rules = append(r.Tool.Driver.Rules, rule{
+				ID:                   vulnerability.ID,
+				ShortDescription:     message{Text: vulnerability.Title},
+				HelpURI:              vulnerability.URL,
+				DefaultConfiguration: configuration{Level: level},
+				Properties: properties{
+					SecuritySeverity: securitySeverities[vulnerability.Severity],
+					Tags:             []string{"security", vulnerability.Severity},
+				},
+			})
+		}
+		r.Results = append(r.Results, result{
+			RuleID:    vulnerability.ID,
+			Level:     level,
+			Message:   message{Text: resultMessage(vulnerability)},
+			Locations: []location{{PhysicalLocation: physicalLocation{ArtifactLocation: artifactLocation{URI: image}}}},
+		})
+	}
+	data, err := json.MarshalIndent(log{Version: version, Schema: schema, Runs: []run{r}}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(out, string(data))
+	return err
+}
+
+// Level returns the SARIF level of a Hub severity, "none" when unknown
+func Level(severity string) string {
+	if level, ok := levels[severity]; ok {
+		return level
+	}
+	return "none"
+}
+
+func resultMessage(v hub.Vulnerability) string {
+	if v.FixedVersion == "" {
+		return fmt.Sprintf("%s %s in %s %s, no fixed version", v.Severity, v.ID, v.Package, v.Version)
+	}
+	return fmt.Sprintf("%s %s in %s %s, fixed in %s", v.Severity, v.ID, v.Package, v.Version, v.FixedVersion)
+}
+
+type log struct {
+	Version string `json:"version"`
+	Schema  string `json:"$schema"`
+	Runs    []run  `json:"runs"`
+}
+
+type run struct {
+	Tool    tool     `json:"tool"`
+	Results []result `json:"results"`
+}
+
+type tool struct {
+	Driver driver `json:"driver"`
+}
+
+type driver struct {
+	Name           string `json:"name"`
+	InformationURI string `json:"informationUri"`
+	Version        string `json:"version"`
+	Rules          []rule `json:"rules"`
+}
+
+type rule struct {
+	ID                   string        `json:"id"`
+	ShortDescription     message       `json:"shortDescription"`
+	HelpURI              string        `json:"helpUri,omitempty"`
+	DefaultConfiguration configuration `json:"defaultConfiguration"`
+	Properties           properties    `json:"properties"`
+}
+
+type configuration struct {
+	Level string `json:"level"`
+}
+
+type properties struct {
+	SecuritySeverity string   `json:"security-severity,omitempty"`
+	Tags             []string `json:"tags,omitempty"`
+}
+
+type result struct {
+	RuleID    string     `json:"ruleId"`
+	Level     string     `json:"level"`
+	Message   message    `json:"message"`
+	Locations []location `json:"locations"`
+}
+
+type message struct {
+	Text string `json:"text"`
+}
+
+type location struct {
+	PhysicalLocation physicalLocation `json:"physicalLocation"`
+}
+
+type physicalLocation struct {
+	ArtifactLocation artifactLocation `json:"artifactLocation"`
+}
+
+type artifactLocation struct {
+	URI string `json:"uri"`
+}
@@ -0,0 +1,63 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package sarif
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+func TestWrite(t *testing.T) {
+	report := &hub.ScanReport{
+		Digest: "sha256:abcd",
+		Status: "completed",
+		Vulnerabilities: []hub.Vulnerability{
+			{
+				ID:           "CVE-2021-3156",
+				Severity:     hub.SeverityCritical,
+				Title:        "Heap-based buffer overflow",
+				Package:      "sudo",
+				Version:      "1.9.0-r0",
+				FixedVersion: "1.9.5p2-r0",
+			},
+			{
+				ID:       "CVE-2020-28928",
+				Severity: hub.SeverityLow,
+				Title:    "Out-of-bounds write",
+				Package:  "musl",
+				Version:  "1.1.24-r9",
+				URL:      "https://nvd.nist.gov/vuln/detail/CVE-2020-28928",
+			},
+		},
+	}
+	buf := bytes.NewBuffer(nil)
+	assert.NilError(t, Write(buf, "jdoe/app:latest", report))
+	golden.Assert(t, buf.String(), "report.golden")
+}
+
+func TestLevel(t *testing.T) {
+	assert.Equal(t, Level(hub.SeverityCritical), "error")
+	assert.Equal(t, Level(hub.SeverityHigh), "error")
+	assert.Equal(t, Level(hub.SeverityMedium), "warning")
+	assert.Equal(t, Level(hub.SeverityLow), "note")
+	assert.Equal(t, Level("unknown"), "none")
+}
@@ -0,0 +1,123 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+// Package stats keeps the history of the pull and star counts of the
+// repositories, which Hub only returns as their current values
+package stats
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// sparks are the bars of the sparklines, from the lowest to the highest value
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Sample is the pull and star counts of a repository at a time
+type Sample struct {
+	Time  time.Time `json:"time"`
+	Pulls int       `json:"pulls"`
+	Stars int       `json:"stars"`
+}
+
+// Path returns the path of the history of a repository, given as
+// namespace/name
+func Path(repository string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".hub-tool", "stats", filepath.FromSlash(repository)+".jsonl"), nil
+}
+
+// Append adds a sample at the end of a history file, one JSON object per line
+func Append(path string, sample Sample) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	raw, err := json.Marshal(sample)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(raw, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load reads the samples of a history file. A missing file is an empty
+// history.
+func Load(path string) ([]Sample, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() //nolint:errcheck
+
+	var samples []Sample
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var sample Sample
+		if err := json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+			return nil, fmt.Errorf("invalid history file %s, line %d: %s", path, line, err)
+		}
+		samples = append(samples, sample)
+	}
+	return samples, scanner.Err()
+}
+
+// Sparkline draws the values as a line of bars scaled between the lowest and
+// the highest one
+func Sparkline(values []int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	min, max := values[0], values[0]
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	line := make([]rune, len(values))
+	for i, v := range values {
+		index := 0
+		if max > min {
+			index = (v - min) * (len(sparks) - 1) / (max - min)
+		}
+		line[i] = sparks[index]
+	}
+	return string(line)
+}
@@ -0,0 +1,56 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package stats
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestLoadMissingFile(t *testing.T) {
+	samples, err := Load(filepath.Join(t.TempDir(), "missing.jsonl"))
+	assert.NilError(t, err)
+	assert.Equal(t, len(samples), 0)
+}
+
+func TestAppendAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jdoe", "app.jsonl")
+	first := Sample{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), Pulls: 10, Stars: 1}
+	second := Sample{Time: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC), Pulls: 25, Stars: 2}
+	assert.NilError(t, Append(path, first))
+	assert.NilError(t, Append(path, second))
+
+	samples, err := Load(path)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, samples, []Sample{first, second})
+}
+
+func TestLoadInvalidLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.jsonl")
+	assert.NilError(t, ioutil.WriteFile(path, []byte("{\"pulls\": 1}\nnot json\n"), 0600))
+	_, err := Load(path)
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, Sparkline(nil), "")
+	assert.Equal(t, Sparkline([]int{5, 5}), "▁▁")
+	assert.Equal(t, Sparkline([]int{0, 7, 14}), "▁▄█")
+}
@@ -0,0 +1,277 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+// Package trust reads the Docker Content Trust signatures of the tags from a
+// Notary server
+package trust
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultServer is the Notary server of Docker Hub
+	DefaultServer = "https://notary.docker.io"
+	// RepoAdmin is the signer of the tags signed with the targets key of the
+	// repository rather than a delegation key
+	RepoAdmin = "Repo Admin"
+
+	targetsRole = "targets"
+)
+
+// errNoTrustData is returned when a repository has no trust data at all
+var errNoTrustData = errors.New("no trust data")
+
+// Signature is the signed target of a tag
+type Signature struct {
+	Tag     string
+	Digest  string
+	Size    int64
+	Signers []string
+}
+
+// Client reads the trust data of a Notary server. The signatures are not
+// verified against the root of trust of the repositories, only read: the
+// signed targets tell what the trust data claims, not that it is genuine.
+type Client struct {
+	server     string
+	httpClient *http.Client
+	username   string
+	password   string
+	token      string
+}
+
+// NewClient returns a client of a Notary server, authenticated with the given
+// credentials when they are not empty
+func NewClient(server string, httpClient *http.Client, username, password string) *Client {
+	return &Client{
+		server:     strings.TrimSuffix(server, "/"),
+		httpClient: httpClient,
+		username:   username,
+		password:   password,
+	}
+}
+
+// Signature returns the signed target of a tag of a repository, named as its
+// Globally Unique Name such as docker.io/library/alpine, or nil if the trust
+// data has none. The TUF signatures of the trust data aren't verified.
+func (c *Client) Signature(ctx context.Context, gun, tag string) (*Signature, error) {
+	targets, err := c.getTargets(ctx, gun, targetsRole)
+	if errors.Is(err, errNoTrustData) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var signature *Signature
+	addSigner := func(signer string, target tufTarget) error {
+		if signature == nil {
+			d, err := target.digest()
+			if err != nil {
+				return err
+			}
+			signature = &Signature{Tag: tag, Digest: d, Size: target.Length}
+		}
+		signature.Signers = append(signature.Signers, signer)
+		return nil
+	}
+	if target, ok := targets.Signed.Targets[tag]; ok {
+		if err := addSigner(RepoAdmin, target); err != nil {
+			return nil, err
+		}
+	}
+	for _, role := range targets.Signed.Delegations.Roles {
+		delegation, err := c.getTargets(ctx, gun, role.Name)
+		if errors.Is(err, errNoTrustData) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if target, ok := delegation.Signed.Targets[tag]; ok {
+			if err := addSigner(strings.TrimPrefix(role.Name, targetsRole+"/"), target); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if signature != nil {
+		sort.Strings(signature.Signers)
+	}
+	return signature, nil
+}
+
+func (c *Client) getTargets(ctx context.Context, gun, role string) (*tufTargets, error) {
+	u := fmt.Sprintf("%s/v2/%s/_trust/tuf/%s.json", c.server, gun, role)
+	resp, err := c.get(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode == http.StatusUnauthorized && c.token == "" {
+		if err := c.authenticate(ctx, resp.Header.Get("WWW-Authenticate")); err != nil {
+			return nil, err
+		}
+		return c.getTargets(ctx, gun, role)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errNoTrustData
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to read the trust data of %s: bad status code %q", gun, resp.Status)
+	}
+	var targets tufTargets
+	if err := json.NewDecoder(resp.Body).Decode(&targets); err != nil {
+		return nil, err
+	}
+	return &targets, nil
+}
+
+func (c *Client) get(ctx context.Context, u string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return c.httpClient.Do(req)
+}
+
+// authenticate gets a token from the authorization server of a Bearer
+// challenge, such as:
+// Bearer realm="https://auth.docker.io/token",service="notary.docker.io",scope="repository:docker.io/library/alpine:pull"
+func (c *Client) authenticate(ctx context.Context, challenge string) error {
+	params, err := parseChallenge(challenge)
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(params["realm"])
+	if err != nil {
+		return err
+	}
+	q := u.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			q.Set(key, params[key])
+		}
+	}
+	u.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to authenticate to the Notary server: bad status code %q", resp.Status)
+	}
+	var token struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(buf, &token); err != nil {
+		return err
+	}
+	if token.Token == "" {
+		return errors.New("failed to authenticate to the Notary server: no token returned")
+	}
+	c.token = token.Token
+	return nil
+}
+
+func parseChallenge(challenge string) (map[string]string, error) {
+	const scheme = "Bearer "
+	if !strings.HasPrefix(challenge, scheme) {
+		return nil, fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+	params := map[string]string{}
+	rest := strings.TrimPrefix(challenge, scheme)
+	for {
+		rest = strings.TrimLeft(rest, ", ")
+		i := strings.Index(rest, "=")
+		if i < 0 {
+			break
+		}
+		key, value := strings.TrimSpace(rest[:i]), ""
+		rest = rest[i+1:]
+		// Quoted values may hold commas, such as scopes with several actions
+		if strings.HasPrefix(rest, `"`) {
+			end := strings.Index(rest[1:], `"`)
+			if end < 0 {
+				return nil, fmt.Errorf("invalid authentication challenge %q", challenge)
+			}
+			value, rest = rest[1:end+1], rest[end+2:]
+		} else {
+			end := strings.Index(rest, ",")
+			if end < 0 {
+				end = len(rest)
+			}
+			value, rest = rest[:end], rest[end:]
+		}
+		params[key] = value
+	}
+	if params["realm"] == "" {
+		return nil, fmt.Errorf("no realm in the authentication challenge %q", challenge)
+	}
+	return params, nil
+}
+
+type tufTargets struct {
+	Signed struct {
+		Targets     map[string]tufTarget `json:"targets"`
+		Delegations struct {
+			Roles []struct {
+				Name string `json:"name"`
+			} `json:"roles"`
+		} `json:"delegations"`
+	} `json:"signed"`
+}
+
+type tufTarget struct {
+	Hashes map[string]string `json:"hashes"`
+	Length int64             `json:"length"`
+}
+
+// digest returns the digest of the target, the hashes being base64 encoded
+func (t tufTarget) digest() (string, error) {
+	hash, ok := t.Hashes["sha256"]
+	if !ok {
+		return "", errors.New("no sha256 hash in the signed target")
+	}
+	buf, err := base64.StdEncoding.DecodeString(hash)
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,125 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package trust
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+const (
+	// hash of the manifest sha256:4ff3ca91275773af45cb4b0834e12b7eb47d1c18f770a0b151381cd227f4c253
+	alpineHash    = "T/PKkSdXc69Fy0sINOErfrR9HBj3cKCxUTgc0if0wlM="
+	alpineTargets = `{"signed": {
+  "targets": {"3.12": {"hashes": {"sha256": "` + alpineHash + `"}, "length": 1638}},
+  "delegations": {"roles": [{"name": "targets/releases"}, {"name": "targets/alice"}]}
+}}`
+	alpineReleases = `{"signed": {"targets": {
+  "3.12": {"hashes": {"sha256": "` + alpineHash + `"}, "length": 1638},
+  "3.13": {"hashes": {"sha256": "` + alpineHash + `"}, "length": 1638}
+}}}`
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			assert.Equal(t, r.URL.Query().Get("service"), "notary.docker.io")
+			_, _ = w.Write([]byte(`{"token": "notary-token"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer notary-token" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="notary.docker.io",scope="repository:docker.io/library/alpine:pull"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v2/docker.io/library/alpine/_trust/tuf/targets.json":
+			_, _ = w.Write([]byte(alpineTargets))
+		case "/v2/docker.io/library/alpine/_trust/tuf/targets/releases.json":
+			_, _ = w.Write([]byte(alpineReleases))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSignature(t *testing.T) {
+	server := newTestServer(t)
+
+	testCases := []struct {
+		name     string
+		gun      string
+		tag      string
+		expected *Signature
+	}{
+		{
+			name: "signed by the admin and a delegation",
+			gun:  "docker.io/library/alpine",
+			tag:  "3.12",
+			expected: &Signature{
+				Tag:     "3.12",
+				Digest:  "sha256:4ff3ca91275773af45cb4b0834e12b7eb47d1c18f770a0b151381cd227f4c253",
+				Size:    1638,
+				Signers: []string{RepoAdmin, "releases"},
+			},
+		},
+		{
+			name: "signed by a delegation",
+			gun:  "docker.io/library/alpine",
+			tag:  "3.13",
+			expected: &Signature{
+				Tag:     "3.13",
+				Digest:  "sha256:4ff3ca91275773af45cb4b0834e12b7eb47d1c18f770a0b151381cd227f4c253",
+				Size:    1638,
+				Signers: []string{"releases"},
+			},
+		},
+		{name: "unsigned tag", gun: "docker.io/library/alpine", tag: "edge"},
+		{name: "repository without trust data", gun: "docker.io/library/busybox", tag: "latest"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := NewClient(server.URL, server.Client(), "", "")
+			signature, err := client.Signature(context.Background(), tc.gun, tc.tag)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, signature, tc.expected)
+		})
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	params, err := parseChallenge(`Bearer realm="https://auth.docker.io/token",service="notary.docker.io",scope="repository:docker.io/jdoe/app:pull,push"`)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, params, map[string]string{
+		"realm":   "https://auth.docker.io/token",
+		"service": "notary.docker.io",
+		"scope":   "repository:docker.io/jdoe/app:pull,push",
+	})
+
+	_, err = parseChallenge(`Basic realm="Registry"`)
+	assert.ErrorContains(t, err, "unsupported authentication challenge")
+	_, err = parseChallenge(`Bearer service="notary.docker.io"`)
+	assert.ErrorContains(t, err, "no realm in the authentication challenge")
+	_, err = parseChallenge(`Bearer realm="https://auth.docker.io/token`)
+	assert.ErrorContains(t, err, "invalid authentication challenge")
+}
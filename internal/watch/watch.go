@@ -0,0 +1,100 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package watch re-runs the listings at an interval, redrawing their output
+package watch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/streams"
+	"github.com/spf13/pflag"
+
+	"github.com/docker/hub-tool/internal/ansi"
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+// clearScreen moves the cursor to the top left corner and clears the screen
+const clearScreen = "\033[H\033[2J"
+
+// Watcher handles the watch flag
+type Watcher struct {
+	seconds int
+}
+
+// AddWatchFlag adds the watch flag to a command
+func (w *Watcher) AddWatchFlag(flags *pflag.FlagSet) {
+	flags.IntVarP(&w.seconds, "watch", "w", 0, "Re-run the command every N seconds, redrawing its output")
+}
+
+// Watching tells if the watch flag was given
+func (w *Watcher) Watching() bool {
+	return w.seconds != 0
+}
+
+// Watch runs the command, then re-runs it at the watch interval until the
+// context is canceled. The output of each run replaces the previous one.
+// A failed run is printed and doesn't stop watching, as the next one may
+// succeed. The local cache is disabled, for each run to get fresh values.
+func (w *Watcher) Watch(ctx context.Context, cliStreams command.Streams, hubClient *hub.Client, title string, run func(command.Streams) error) error {
+	if w.seconds < 0 {
+		return fmt.Errorf("invalid watch interval %d, must be at least 1 second", w.seconds)
+	}
+	if err := hubClient.Update(hub.WithCache("", 0)); err != nil {
+		return err
+	}
+	interval := time.Duration(w.seconds) * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	header := fmt.Sprintf("Every %s: %s", interval, title)
+	buf := &bytes.Buffer{}
+	for {
+		// Render the whole output before clearing the screen, not to
+		// flicker while waiting for Hub
+		buf.Reset()
+		if err := run(bufferedStreams{cliStreams, streams.NewOut(buf)}); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			fmt.Fprintln(buf, ansi.Error(err.Error()))
+		}
+		now := time.Now().Format("15:04:05")
+		fmt.Fprint(cliStreams.Out(), clearScreen)
+		fmt.Fprintf(cliStreams.Out(), "%s%s%s\n\n", ansi.Info(header), strings.Repeat(" ", 4), now)
+		_, _ = buf.WriteTo(cliStreams.Out())
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// bufferedStreams writes the output of a run to a buffer
+type bufferedStreams struct {
+	command.Streams
+	out *streams.Out
+}
+
+func (s bufferedStreams) Out() *streams.Out {
+	return s.out
+}
@@ -0,0 +1,86 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package watch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/cli/cli/streams"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/hub-tool/pkg/hub"
+)
+
+type testStreams struct {
+	out *streams.Out
+}
+
+func (s testStreams) In() *streams.In   { return nil }
+func (s testStreams) Out() *streams.Out { return s.out }
+func (s testStreams) Err() io.Writer    { return ioutil.Discard }
+
+func TestWatchInvalidInterval(t *testing.T) {
+	w := Watcher{seconds: -1}
+	assert.Assert(t, w.Watching())
+	err := w.Watch(context.Background(), testStreams{}, nil, "repo ls", func(command.Streams) error {
+		t.Fatal("the command shouldn't run")
+		return nil
+	})
+	assert.Error(t, err, "invalid watch interval -1, must be at least 1 second")
+}
+
+func TestWatch(t *testing.T) {
+	hubClient, err := hub.NewClient()
+	assert.NilError(t, err)
+	out := bytes.NewBuffer(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := 0
+	w := Watcher{seconds: 1}
+	err = w.Watch(ctx, testStreams{out: streams.NewOut(out)}, hubClient, "repo ls", func(s command.Streams) error {
+		runs++
+		switch runs {
+		case 1:
+			fmt.Fprintln(s.Out(), "first run")
+			return nil
+		case 2:
+			return errors.New("service unavailable")
+		}
+		// Canceled while running, the output isn't redrawn
+		cancel()
+		return context.Canceled
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, runs, 3)
+
+	draws := strings.Split(out.String(), clearScreen)
+	assert.Equal(t, len(draws), 3)
+	assert.Equal(t, draws[0], "")
+	assert.Assert(t, strings.Contains(draws[1], "Every 1s: repo ls"))
+	assert.Assert(t, strings.Contains(draws[1], "first run\n"))
+	assert.Assert(t, strings.Contains(draws[2], "service unavailable"))
+	assert.Assert(t, !strings.Contains(draws[2], "first run"))
+}
@@ -25,12 +25,11 @@ import (
 	"syscall"
 
 	"github.com/docker/cli/cli/command"
-	dockercredentials "github.com/docker/cli/cli/config/credentials"
 	cliflags "github.com/docker/cli/cli/flags"
 
 	"github.com/docker/hub-tool/internal/commands"
 	"github.com/docker/hub-tool/internal/credentials"
-	"github.com/docker/hub-tool/internal/hub"
+	"github.com/docker/hub-tool/pkg/hub"
 )
 
 func main() {
@@ -47,30 +46,19 @@ func main() {
 		log.Fatal(err)
 	}
 
-	store := credentials.NewStore(func(key string) dockercredentials.Store {
-		config := dockerCli.ConfigFile()
-		return config.GetCredentialsStore(key)
-	})
-	auth, err := store.GetAuth()
-	if err != nil {
-		log.Fatal(err)
-	}
-
+	store := credentials.NewConfigStore(dockerCli.ConfigFile())
 	hubClient, err := hub.NewClient(
-		hub.WithContext(ctx),
 		hub.WithInStream(dockerCli.In()),
-		hub.WithOutStream(dockerCli.Out()),
-		hub.WithHubAccount(auth.Username),
-		hub.WithPassword(auth.Password),
-		hub.WithRefreshToken(auth.RefreshToken),
-		hub.WithHubToken(auth.Token))
+		hub.WithOutStream(dockerCli.Out()))
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	rootCmd := commands.NewRootCmd(dockerCli, hubClient, store, os.Args[0])
 	if err := rootCmd.ExecuteContext(ctx); err != nil {
-		os.Exit(1)
+		cmd, _, _ := rootCmd.Find(os.Args[1:])
+		commands.PrintError(dockerCli.Err(), cmd, err)
+		os.Exit(commands.ExitCode(err))
 	}
 	os.Exit(0)
 }
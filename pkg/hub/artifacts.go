@@ -0,0 +1,84 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package hub
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	// ArtifactTypeImage is a container image or an image index
+	ArtifactTypeImage = "image"
+	// ArtifactTypeHelm is a Helm chart pushed as an OCI artifact
+	ArtifactTypeHelm = "helm"
+	// ArtifactTypeWasm is a WebAssembly module pushed as an OCI artifact
+	ArtifactTypeWasm = "wasm"
+	// ArtifactTypeCosign is a cosign signature, attestation or SBOM
+	ArtifactTypeCosign = "cosign"
+	// ArtifactTypeUnknown is any other OCI artifact
+	ArtifactTypeUnknown = "unknown"
+)
+
+// ArtifactTypes lists the artifact types a tag can have
+var ArtifactTypes = []string{
+	ArtifactTypeImage,
+	ArtifactTypeHelm,
+	ArtifactTypeWasm,
+	ArtifactTypeCosign,
+	ArtifactTypeUnknown,
+}
+
+var (
+	// cosignTagRegexp matches the tags cosign derives from the digest of
+	// the image it signs or attests
+	cosignTagRegexp = regexp.MustCompile(`^sha256-[a-f0-9]{64}\.(sig|att|sbom)$`)
+
+	imageMediaTypes = map[string]bool{
+		"application/vnd.docker.distribution.manifest.v2+json":      true,
+		"application/vnd.docker.distribution.manifest.list.v2+json": true,
+		"application/vnd.oci.image.manifest.v1+json":                true,
+		"application/vnd.oci.image.index.v1+json":                   true,
+	}
+
+	// contentTypes maps the content types reported by Hub to artifact types
+	contentTypes = map[string]string{
+		"image":  ArtifactTypeImage,
+		"helm":   ArtifactTypeHelm,
+		"wasm":   ArtifactTypeWasm,
+		"cosign": ArtifactTypeCosign,
+	}
+)
+
+// artifactType guesses the kind of artifact a tag points to. The cosign tag
+// naming scheme wins as cosign pushes plain image manifests, then comes the
+// content type Hub computes from the config media type. Tags without any
+// media type predate OCI artifacts on Hub and are images.
+func artifactType(result hubTagResult) string {
+	if cosignTagRegexp.MatchString(result.Name) {
+		return ArtifactTypeCosign
+	}
+	if result.ContentType != "" {
+		if t, ok := contentTypes[strings.ToLower(result.ContentType)]; ok {
+			return t
+		}
+		return ArtifactTypeUnknown
+	}
+	if result.MediaType == "" || imageMediaTypes[result.MediaType] {
+		return ArtifactTypeImage
+	}
+	return ArtifactTypeUnknown
+}
@@ -0,0 +1,111 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// AuditLogsURL path to the Hub API listing the audit log of an account
+	AuditLogsURL = "/api/audit-logs/v1/logs/%s/"
+)
+
+// AuditLog is an event of the audit log of an organization, such as a push,
+// a deletion or a settings change
+type AuditLog struct {
+	Action      string            `json:"action"`
+	Description string            `json:"description"`
+	Name        string            `json:"name"`
+	Actor       string            `json:"actor"`
+	Data        map[string]string `json:"data,omitempty"`
+	Timestamp   time.Time         `json:"timestamp"`
+}
+
+// GetAuditLogs returns the audit log of an account since the given date, the
+// most recent events first.
+// The audit log API doesn't return the total nor the next page, so the pages
+// are fetched until one isn't full.
+func (c *Client) GetAuditLogs(ctx context.Context, account string, since time.Time) ([]AuditLog, error) {
+	u, err := url.Parse(c.domain + fmt.Sprintf(AuditLogsURL, account))
+	if err != nil {
+		return nil, err
+	}
+	var logs []AuditLog
+	for page := 1; ; page++ {
+		q := url.Values{}
+		q.Add("from", since.UTC().Format(time.RFC3339))
+		q.Add("page_size", fmt.Sprintf("%v", itemsPerPage))
+		q.Add("page", fmt.Sprintf("%v", page))
+		u.RawQuery = q.Encode()
+
+		pageLogs, err := c.getAuditLogsPage(ctx, u.String())
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, pageLogs...)
+		if len(pageLogs) < itemsPerPage {
+			return logs, nil
+		}
+	}
+}
+
+func (c *Client) getAuditLogsPage(ctx context.Context, url string) ([]AuditLog, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, err
+	}
+	var hubResponse hubAuditLogsResponse
+	if err := json.Unmarshal(response, &hubResponse); err != nil {
+		return nil, err
+	}
+	var logs []AuditLog
+	for _, result := range hubResponse.Logs {
+		logs = append(logs, AuditLog{
+			Action:      result.Action,
+			Description: result.ActionDescription,
+			Name:        result.Name,
+			Actor:       result.Actor,
+			Data:        result.Data,
+			Timestamp:   result.Timestamp,
+		})
+	}
+	return logs, nil
+}
+
+type hubAuditLogsResponse struct {
+	Logs []hubAuditLog `json:"logs"`
+}
+
+type hubAuditLog struct {
+	Account           string            `json:"account"`
+	Action            string            `json:"action"`
+	Name              string            `json:"name"`
+	Actor             string            `json:"actor"`
+	Data              map[string]string `json:"data"`
+	Timestamp         time.Time         `json:"timestamp"`
+	ActionDescription string            `json:"action_description"`
+}
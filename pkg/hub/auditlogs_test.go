@@ -0,0 +1,67 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestGetAuditLogsFetchesPagesUntilNotFull(t *testing.T) {
+	since := time.Date(2020, 11, 2, 0, 0, 0, 0, time.UTC)
+	var pages []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Path, "/api/audit-logs/v1/logs/myorg/")
+		assert.Equal(t, r.URL.Query().Get("from"), "2020-11-02T00:00:00Z")
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		count := itemsPerPage
+		if page == "2" {
+			count = 1
+		}
+		var response hubAuditLogsResponse
+		for i := 0; i < count; i++ {
+			response.Logs = append(response.Logs, hubAuditLog{
+				Action:            "repo.tag.push",
+				Name:              "myorg/app",
+				Actor:             "jdoe",
+				Data:              map[string]string{"tag": "latest"},
+				Timestamp:         since,
+				ActionDescription: "pushed the tag latest",
+			})
+		}
+		assert.NilError(t, json.NewEncoder(w).Encode(response))
+	}))
+
+	logs, err := client.GetAuditLogs(context.Background(), "myorg", since)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, pages, []string{"1", "2"})
+	assert.Equal(t, len(logs), itemsPerPage+1)
+	assert.DeepEqual(t, logs[0], AuditLog{
+		Action:      "repo.tag.push",
+		Description: "pushed the tag latest",
+		Name:        "myorg/app",
+		Actor:       "jdoe",
+		Data:        map[string]string{"tag": "latest"},
+		Timestamp:   since,
+	})
+}
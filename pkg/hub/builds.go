@@ -0,0 +1,287 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// BuildSettingsURL path to the Hub API returning the automated build
+	// settings of the repositories of a namespace
+	BuildSettingsURL = "/api/build/v1/%s/source/"
+	// BuildTriggerURL path to the Hub API triggering an automated build
+	BuildTriggerURL = "/api/build/v1/%s/source/%s/trigger/"
+	// BuildURL path to the Hub API returning an automated build
+	BuildURL = "/api/build/v1/%s/build/%s/"
+	// BuildLogsURL path to the Hub API returning the logs of an automated build
+	BuildLogsURL = "/api/build/v1/%s/build/%s/logs/"
+
+	// BuildSourceBranch builds a branch of the source repository
+	BuildSourceBranch = "Branch"
+	// BuildSourceTag builds a tag of the source repository
+	BuildSourceTag = "Tag"
+
+	// BuildStateSuccess is the state of a successful build
+	BuildStateSuccess = "Success"
+	// BuildStateFailed is the state of a failed build
+	BuildStateFailed = "Failed"
+	// BuildStateCanceled is the state of a canceled build
+	BuildStateCanceled = "Canceled"
+)
+
+// BuildSettings are the automated build settings of a repository
+type BuildSettings struct {
+	ID string `json:"id"`
+	// Provider is the source code hosting service, github or bitbucket
+	Provider string `json:"provider"`
+	// Source is the source repository, as owner/name
+	Source    string      `json:"source"`
+	Autotests string      `json:"autotests"`
+	Rules     []BuildRule `json:"rules"`
+}
+
+// BuildRule tells which branches or tags of the source repository are built,
+// and how they are tagged
+type BuildRule struct {
+	SourceType   string `json:"source_type"`
+	SourceName   string `json:"source_name"`
+	Tag          string `json:"tag"`
+	Dockerfile   string `json:"dockerfile"`
+	BuildContext string `json:"build_context"`
+	Autobuild    bool   `json:"autobuild"`
+}
+
+// Build is an automated build of a repository
+type Build struct {
+	ID         string    `json:"id"`
+	State      string    `json:"state"`
+	SourceType string    `json:"source_type"`
+	SourceName string    `json:"source_name"`
+	Tag        string    `json:"tag"`
+	Commit     string    `json:"commit,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	StartedAt  time.Time `json:"started_at,omitempty"`
+	EndedAt    time.Time `json:"ended_at,omitempty"`
+}
+
+// Done tells if the build is over, whether it succeeded or not
+func (b Build) Done() bool {
+	return b.State == BuildStateSuccess || b.State == BuildStateFailed || b.State == BuildStateCanceled
+}
+
+// GetBuildSettings returns the automated build settings of a repository
+func (c *Client) GetBuildSettings(ctx context.Context, repository string) (*BuildSettings, error) {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return nil, err
+	}
+	namespace, _ := splitRepoPath(repoPath)
+	u, err := url.Parse(c.domain + fmt.Sprintf(BuildSettingsURL, namespace))
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	q.Add("image", repoPath)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, err
+	}
+	var hubResponse hubBuildSettingsResponse
+	if err := json.Unmarshal(response, &hubResponse); err != nil {
+		return nil, err
+	}
+	if len(hubResponse.Objects) == 0 {
+		return nil, &notFoundError{err: fmt.Errorf("automated builds aren't configured for %s", repoPath)}
+	}
+	result := hubResponse.Objects[0]
+	settings := BuildSettings{
+		ID:        result.UUID,
+		Provider:  result.Provider,
+		Source:    result.Owner + "/" + result.Repository,
+		Autotests: result.Autotests,
+	}
+	for _, rule := range result.BuildSettings {
+		settings.Rules = append(settings.Rules, BuildRule(rule))
+	}
+	return &settings, nil
+}
+
+// TriggerBuild builds a branch or a tag, depending on the source type, of the
+// source repository and returns the queued builds, one per matching rule
+func (c *Client) TriggerBuild(ctx context.Context, repository, sourceType, sourceName string) ([]Build, error) {
+	settings, err := c.GetBuildSettings(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return nil, err
+	}
+	namespace, _ := splitRepoPath(repoPath)
+	data, err := json.Marshal(hubBuildTriggerRequest{SourceType: sourceType, SourceName: sourceName})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.domain+fmt.Sprintf(BuildTriggerURL, namespace, settings.ID), bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, err
+	}
+	var hubResponse hubBuildsResponse
+	if err := json.Unmarshal(response, &hubResponse); err != nil {
+		return nil, err
+	}
+	if len(hubResponse.Objects) == 0 {
+		return nil, fmt.Errorf("no build rule matches the %s %q", sourceType, sourceName)
+	}
+	builds := make([]Build, len(hubResponse.Objects))
+	for i, result := range hubResponse.Objects {
+		builds[i] = toBuild(result)
+	}
+	return builds, nil
+}
+
+// GetBuild returns an automated build of a repository
+func (c *Client) GetBuild(ctx context.Context, repository, id string) (*Build, error) {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return nil, err
+	}
+	namespace, _ := splitRepoPath(repoPath)
+	req, err := http.NewRequestWithContext(ctx, "GET", c.domain+fmt.Sprintf(BuildURL, namespace, id), nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, err
+	}
+	var result hubBuild
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, err
+	}
+	build := toBuild(result)
+	return &build, nil
+}
+
+// GetBuildLogs returns the logs of an automated build from the given offset,
+// to only get the new logs of a running build
+func (c *Client) GetBuildLogs(ctx context.Context, repository, id string, offset int) (string, error) {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return "", err
+	}
+	namespace, _ := splitRepoPath(repoPath)
+	u, err := url.Parse(c.domain + fmt.Sprintf(BuildLogsURL, namespace, id))
+	if err != nil {
+		return "", err
+	}
+	q := url.Values{}
+	q.Add("offset", fmt.Sprintf("%v", offset))
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return "", err
+	}
+	var result hubBuildLogs
+	if err := json.Unmarshal(response, &result); err != nil {
+		return "", err
+	}
+	return result.Logs, nil
+}
+
+func toBuild(result hubBuild) Build {
+	return Build{
+		ID:         result.UUID,
+		State:      result.State,
+		SourceType: result.SourceType,
+		SourceName: result.SourceName,
+		Tag:        result.Tag,
+		Commit:     result.Commit,
+		CreatedAt:  result.Created,
+		StartedAt:  result.StartDate,
+		EndedAt:    result.EndDate,
+	}
+}
+
+type hubBuildSettingsResponse struct {
+	Objects []hubBuildSource `json:"objects"`
+}
+
+type hubBuildSource struct {
+	UUID          string         `json:"uuid"`
+	Provider      string         `json:"provider"`
+	Owner         string         `json:"owner"`
+	Repository    string         `json:"repository"`
+	Autotests     string         `json:"autotests"`
+	BuildSettings []hubBuildRule `json:"build_settings"`
+}
+
+type hubBuildRule struct {
+	SourceType   string `json:"source_type"`
+	SourceName   string `json:"source_name"`
+	Tag          string `json:"tag"`
+	Dockerfile   string `json:"dockerfile"`
+	BuildContext string `json:"build_context"`
+	Autobuild    bool   `json:"autobuild"`
+}
+
+type hubBuildTriggerRequest struct {
+	SourceType string `json:"source_type"`
+	SourceName string `json:"source_name"`
+}
+
+type hubBuildLogs struct {
+	Logs string `json:"logs"`
+}
+
+type hubBuildsResponse struct {
+	Objects []hubBuild `json:"objects"`
+}
+
+type hubBuild struct {
+	UUID       string    `json:"uuid"`
+	State      string    `json:"state"`
+	SourceType string    `json:"source_type"`
+	SourceName string    `json:"source_name"`
+	Tag        string    `json:"tag"`
+	Commit     string    `json:"commit"`
+	Created    time.Time `json:"created"`
+	StartDate  time.Time `json:"start_date"`
+	EndDate    time.Time `json:"end_date"`
+}
@@ -0,0 +1,78 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+const buildSettings = `{"objects": [{
+	"uuid": "1234",
+	"provider": "github",
+	"owner": "jdoe",
+	"repository": "app-src",
+	"autotests": "OFF",
+	"build_settings": [
+		{"source_type": "Branch", "source_name": "main", "tag": "latest", "dockerfile": "Dockerfile", "build_context": "/", "autobuild": true},
+		{"source_type": "Tag", "source_name": "/^v([0-9.]+)$/", "tag": "{\\1}", "dockerfile": "Dockerfile", "build_context": "/", "autobuild": false}
+	]
+}]}`
+
+func TestGetBuildSettings(t *testing.T) {
+	client := newTestClient(t, routes{"GET /api/build/v1/jdoe/source/": buildSettings})
+	settings, err := client.GetBuildSettings(context.Background(), "jdoe/app")
+	assert.NilError(t, err)
+	assert.Equal(t, settings.Source, "jdoe/app-src")
+	assert.DeepEqual(t, settings.Rules[0], BuildRule{SourceType: BuildSourceBranch, SourceName: "main", Tag: "latest", Dockerfile: "Dockerfile", BuildContext: "/", Autobuild: true})
+	assert.Equal(t, settings.Rules[1].Tag, `{\1}`)
+
+	client = newTestClient(t, routes{"GET /api/build/v1/jdoe/source/": `{"objects": []}`})
+	_, err = client.GetBuildSettings(context.Background(), "jdoe/app")
+	assert.Assert(t, IsNotFoundError(err))
+}
+
+func TestTriggerBuild(t *testing.T) {
+	client := newTestClient(t, routes{
+		"GET /api/build/v1/jdoe/source/":               buildSettings,
+		"POST /api/build/v1/jdoe/source/1234/trigger/": `{"objects": [{"uuid": "b1", "state": "Pending", "source_type": "Branch", "source_name": "main", "tag": "latest"}]}`,
+		"GET /api/build/v1/jdoe/build/b1/":             `{"uuid": "b1", "state": "Success", "source_type": "Branch", "source_name": "main", "tag": "latest"}`,
+	})
+	builds, err := client.TriggerBuild(context.Background(), "jdoe/app", BuildSourceBranch, "main")
+	assert.NilError(t, err)
+	assert.Equal(t, len(builds), 1)
+	assert.Equal(t, builds[0].ID, "b1")
+	assert.Assert(t, !builds[0].Done())
+
+	build, err := client.GetBuild(context.Background(), "jdoe/app", "b1")
+	assert.NilError(t, err)
+	assert.Assert(t, build.Done())
+}
+
+func TestGetBuildLogs(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Path, "/api/build/v1/jdoe/build/b1/logs/")
+		assert.Equal(t, r.URL.Query().Get("offset"), "12")
+		_, _ = w.Write([]byte(`{"logs": "Step 2/3\n"}`))
+	}))
+	logs, err := client.GetBuildLogs(context.Background(), "jdoe/app", "b1", 12)
+	assert.NilError(t, err)
+	assert.Equal(t, logs, "Step 2/3\n")
+}
@@ -0,0 +1,82 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// forEachConcurrently calls fn for each index in [0, n), with at most the
+// client concurrency calls running at once. Once ctx is done no other call
+// is started, the calls in flight being canceled through their requests. The
+// error of each call is returned at its index, nil if it succeeded or was never
+// started: check ctx.Err() to tell them apart.
+func (c *Client) forEachConcurrently(ctx context.Context, n int, fn func(i int) error) []error {
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, n)
+		sem  = make(chan struct{}, c.maxConcurrentRequests())
+	)
+	for i := 0; i < n && ctx.Err() == nil; i++ {
+		select {
+		case <-ctx.Done():
+			continue
+		case sem <- struct{}{}:
+		}
+		i := i
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = fn(i)
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+// firstError returns the first non nil error
+func firstError(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bulkError lists the names whose operation failed in a single error, whose
+// message is given by format with the number of failures
+func bulkError(format string, names []string, errs []error) error {
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", names[i], err))
+		}
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+	sort.Strings(failed)
+	return fmt.Errorf(format+": %s", len(failed), strings.Join(failed, ", "))
+}
@@ -0,0 +1,104 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// responseCache keeps the body of the successful GET responses on disk, one
+// file per account and URL, and serves them again until they are older than
+// the TTL
+type responseCache struct {
+	dir string
+	ttl time.Duration
+	now func() time.Time
+}
+
+// WithCache makes the client serve GET requests from an on-disk cache stored
+// in dir, the responses being kept for ttl. A zero ttl disables the cache.
+func WithCache(dir string, ttl time.Duration) ClientOp {
+	return func(c *Client) error {
+		if ttl < 0 {
+			return fmt.Errorf("invalid cache TTL %s, must be positive", ttl)
+		}
+		if ttl == 0 {
+			c.cache = nil
+			return nil
+		}
+		c.cache = &responseCache{dir: dir, ttl: ttl, now: time.Now}
+		return nil
+	}
+}
+
+// ClearCache removes all the responses cached in dir
+func ClearCache(dir string) error {
+	return os.RemoveAll(dir)
+}
+
+func (c *Client) cacheable(req *http.Request) bool {
+	return c.cache != nil && req.Method == http.MethodGet && !c.isConditional(req)
+}
+
+func (r *responseCache) path(account string, req *http.Request) string {
+	sum := sha256.Sum256([]byte(account + "\n" + req.URL.String()))
+	return filepath.Join(r.dir, hex.EncodeToString(sum[:]))
+}
+
+func (r *responseCache) get(account string, req *http.Request) ([]byte, bool) {
+	path := r.path(account, req)
+	info, err := os.Stat(path)
+	if err != nil || r.now().Sub(info.ModTime()) > r.ttl {
+		return nil, false
+	}
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	return buf, true
+}
+
+// put stores the response body, failing to do so only costs a request the
+// next time
+func (r *responseCache) put(account string, req *http.Request, buf []byte) error {
+	if err := os.MkdirAll(r.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create the cache directory: %s", err)
+	}
+	tmp, err := ioutil.TempFile(r.dir, ".tmp-")
+	if err != nil {
+		return fmt.Errorf("failed to cache the response: %s", err)
+	}
+	_, err = tmp.Write(buf)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), r.path(account, req))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return fmt.Errorf("failed to cache the response: %s", err)
+	}
+	return nil
+}
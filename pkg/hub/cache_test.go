@@ -0,0 +1,79 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCacheServesGetResponses(t *testing.T) {
+	var requests int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte(`{"name":"app"}`))
+	}))
+	now := time.Now()
+	assert.NilError(t, client.Update(WithHubAccount("jdoe"), WithCache(t.TempDir(), time.Minute)))
+	client.cache.now = func() time.Time { return now }
+
+	get := func() string {
+		req, err := http.NewRequest("GET", client.domain+"/v2/repositories/jdoe/app/", nil)
+		assert.NilError(t, err)
+		buf, err := client.doRequest(req)
+		assert.NilError(t, err)
+		return string(buf)
+	}
+	assert.Equal(t, get(), `{"name":"app"}`)
+	assert.Equal(t, get(), `{"name":"app"}`)
+	assert.Equal(t, atomic.LoadInt32(&requests), int32(1))
+
+	// Another account doesn't share the cached responses
+	assert.NilError(t, client.Update(WithHubAccount("jane")))
+	assert.Equal(t, get(), `{"name":"app"}`)
+	assert.Equal(t, atomic.LoadInt32(&requests), int32(2))
+
+	// Expired responses are fetched again
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, get(), `{"name":"app"}`)
+	assert.Equal(t, atomic.LoadInt32(&requests), int32(3))
+}
+
+func TestCacheIgnoresOtherMethods(t *testing.T) {
+	var requests int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	assert.NilError(t, client.Update(WithCache(t.TempDir(), time.Minute)))
+
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequest("DELETE", client.domain+"/v2/repositories/jdoe/app/", nil)
+		assert.NilError(t, err)
+		_, err = client.doRequest(req)
+		assert.NilError(t, err)
+	}
+	assert.Equal(t, atomic.LoadInt32(&requests), int32(2))
+}
+
+func TestWithCacheRejectsNegativeTTL(t *testing.T) {
+	_, err := NewClient(WithCache(t.TempDir(), -time.Second))
+	assert.Error(t, err, "invalid cache TTL -1s, must be positive")
+}
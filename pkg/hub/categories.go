@@ -0,0 +1,98 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	// CategoriesURL path to the Hub API listing the repository categories
+	CategoriesURL = "/v2/categories/"
+	// RepositoryCategoriesURL path to the Hub API setting the categories of a repository
+	RepositoryCategoriesURL = "/v2/repositories/%s/categories/"
+)
+
+// Category helps finding a repository on Hub, such as "Databases & storage"
+type Category struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// GetCategories returns the categories a repository can be given
+func (c *Client) GetCategories(ctx context.Context) ([]Category, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.domain+CategoriesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, err
+	}
+	var categories []Category
+	if err := json.Unmarshal(response, &categories); err != nil {
+		return nil, err
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Slug < categories[j].Slug })
+	return categories, nil
+}
+
+// SetRepositoryCategories replaces the categories of a repository with the
+// given ones, identified by their slug. The slugs are checked against the
+// categories returned by GetCategories.
+func (c *Client) SetRepositoryCategories(ctx context.Context, repository string, slugs []string) error {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return err
+	}
+	allowed, err := c.GetCategories(ctx)
+	if err != nil {
+		return err
+	}
+	bySlug := map[string]Category{}
+	for _, category := range allowed {
+		bySlug[category.Slug] = category
+	}
+	categories := []Category{}
+	for _, slug := range slugs {
+		category, ok := bySlug[slug]
+		if !ok {
+			valid := make([]string, len(allowed))
+			for i, category := range allowed {
+				valid[i] = category.Slug
+			}
+			return fmt.Errorf("invalid category %q: should be one of %s", slug, strings.Join(valid, ", "))
+		}
+		categories = append(categories, category)
+	}
+	data, err := json.Marshal(categories)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "PATCH", c.domain+fmt.Sprintf(RepositoryCategoriesURL, repoPath), bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	_, err = c.doRequest(req, withHubToken(c.token))
+	return err
+}
@@ -0,0 +1,51 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+const categories = `[
+	{"name": "Monitoring & observability", "slug": "monitoring"},
+	{"name": "Databases & storage", "slug": "databases"}
+]`
+
+func TestSetRepositoryCategories(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v2/categories/":
+			_, _ = w.Write([]byte(categories))
+		case "PATCH /v2/repositories/myorg/app/categories/":
+			body, err := ioutil.ReadAll(r.Body)
+			assert.NilError(t, err)
+			assert.Equal(t, string(body), `[{"name":"Databases & storage","slug":"databases"},{"name":"Monitoring & observability","slug":"monitoring"}]`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	assert.NilError(t, client.SetRepositoryCategories(context.Background(), "myorg/app", []string{"databases", "monitoring"}))
+
+	err := client.SetRepositoryCategories(context.Background(), "myorg/app", []string{"games"})
+	assert.Error(t, err, `invalid category "games": should be one of databases, monitoring`)
+}
@@ -0,0 +1,614 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/docker/hub-tool/internal"
+)
+
+const (
+	// LoginURL path to the Hub login URL
+	LoginURL = "/v2/users/login?refresh_token=true"
+	// TwoFactorLoginURL path to the 2FA
+	TwoFactorLoginURL = "/v2/users/2fa-login?refresh_token=true"
+	// SecondFactorDetailMessage returned by login if 2FA is enabled
+	SecondFactorDetailMessage = "Require secondary authentication on MFA enabled account"
+
+	itemsPerPage       = 100
+	defaultConcurrency = 4
+)
+
+//Client sends authenticated calls to the Hub API
+type Client struct {
+	AuthConfig types.AuthConfig
+
+	domain           string
+	token            string
+	refreshToken     string
+	password         string
+	account          string
+	namespace        string
+	fetchAllElements bool
+	concurrency      int
+	retries          int
+	cache            *responseCache
+	httpClient       *http.Client
+	customTransport  http.RoundTripper
+	middlewares      []Middleware
+	userAgent        string
+	requestLogger    RequestLogger
+	log              log.Ext1FieldLogger
+	dryRunOut        io.Writer
+	in               io.Reader
+	out              io.Writer
+
+	conditionalRequests bool
+	validatorsMutex     sync.Mutex
+	validators          map[string]validators
+}
+
+// validators are the cache validators returned by the Hub for a given URL
+type validators struct {
+	etag         string
+	lastModified time.Time
+}
+
+type twoFactorResponse struct {
+	Detail        string `json:"detail"`
+	Login2FAToken string `json:"login_2fa_token"`
+}
+
+type twoFactorRequest struct {
+	Code          string `json:"code"`
+	Login2FAToken string `json:"login_2fa_token"`
+}
+
+type tokenResponse struct {
+	Detail       string `json:"detail"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+//ClientOp represents an option given to NewClient constructor to customize client behavior.
+type ClientOp func(*Client) error
+
+//RequestOp represents an option to customize the request sent to the Hub API
+type RequestOp func(r *http.Request) error
+
+//NewClient logs the user to the hub and returns a client which can send authenticated requests
+// to the Hub API
+func NewClient(ops ...ClientOp) (*Client, error) {
+	hubInstance := getInstance()
+
+	client := &Client{
+		domain:      hubInstance.APIHubBaseURL,
+		concurrency: defaultConcurrency,
+		retries:     defaultRetries,
+	}
+	for _, op := range ops {
+		if err := op(client); err != nil {
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
+
+//Update changes client behavior using ClientOp
+func (c *Client) Update(ops ...ClientOp) error {
+	for _, op := range ops {
+		if err := op(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//WithAllElements makes the client fetch all the elements it can find, enabling pagination.
+func WithAllElements() ClientOp {
+	return func(c *Client) error {
+		c.fetchAllElements = true
+		return nil
+	}
+}
+
+//WithConcurrency sets the number of requests sent concurrently, both to fetch
+// the pages of a listing and to run bulk operations. It defaults to 4, use 1 to
+// send the requests one by one. Listings are returned in the server order
+// whatever the concurrency.
+func WithConcurrency(concurrency int) ClientOp {
+	return func(c *Client) error {
+		if concurrency < 1 {
+			return fmt.Errorf("invalid concurrency %d, must be at least 1", concurrency)
+		}
+		c.concurrency = concurrency
+		return nil
+	}
+}
+
+//WithRetries sets the number of times a request failing with a transient error
+// is sent again, defaults to 3. Use 0 to disable the retries.
+func WithRetries(retries int) ClientOp {
+	return func(c *Client) error {
+		if retries < 0 {
+			return fmt.Errorf("invalid retries %d, must be at least 0", retries)
+		}
+		c.retries = retries
+		return nil
+	}
+}
+
+//WithInstance makes the client send its requests to another Hub compatible API
+// than Docker Hub, given by its base URL. An empty URL keeps the current one.
+func WithInstance(instance string) ClientOp {
+	return func(c *Client) error {
+		if instance == "" {
+			return nil
+		}
+		u, err := ParseInstanceURL(instance)
+		if err != nil {
+			return err
+		}
+		c.domain = u.String()
+		return nil
+	}
+}
+
+//WithDryRun makes the client print the requests which would change something
+// on the Hub or on the registry to out instead of sending them, answering them
+// with an empty JSON object. It applies to every request sent through
+// HTTPClient. The read requests, the logins and the registry token requests are
+// still sent.
+func WithDryRun(out io.Writer) ClientOp {
+	return func(c *Client) error {
+		c.dryRunOut = out
+		return nil
+	}
+}
+
+//WithLogger makes the client log with the given logger instead of the logrus
+// standard logger. The messages have fields, such as the method and the URL of
+// the requests.
+func WithLogger(logger log.Ext1FieldLogger) ClientOp {
+	return func(c *Client) error {
+		c.log = logger
+		return nil
+	}
+}
+
+//WithInStream sets the input stream
+func WithInStream(in io.Reader) ClientOp {
+	return func(c *Client) error {
+		c.in = in
+		return nil
+	}
+}
+
+//WithOutStream sets the output stream
+func WithOutStream(out io.Writer) ClientOp {
+	return func(c *Client) error {
+		c.out = out
+		return nil
+	}
+}
+
+//WithConditionalRequests makes the client remember the ETag and Last-Modified
+// validators of each listing, and send them back on the next request for the
+// same listing. Only the first page of a listing is checked: ErrNotModified is
+// returned for the whole listing when it didn't change, otherwise all the
+// pages are fetched again.
+func WithConditionalRequests() ClientOp {
+	return func(c *Client) error {
+		c.conditionalRequests = true
+		c.validators = map[string]validators{}
+		return nil
+	}
+}
+
+// WithHubAccount sets the current account name
+func WithHubAccount(account string) ClientOp {
+	return func(c *Client) error {
+		c.account = account
+		c.AuthConfig.Username = account
+		return nil
+	}
+}
+
+// WithDefaultNamespace sets the namespace the commands use when none is given,
+// instead of the current account
+func WithDefaultNamespace(namespace string) ClientOp {
+	return func(c *Client) error {
+		c.namespace = namespace
+		return nil
+	}
+}
+
+// WithHubToken sets the bearer token to the client
+func WithHubToken(token string) ClientOp {
+	return func(c *Client) error {
+		c.token = token
+		return nil
+	}
+}
+
+// WithRefreshToken sets the refresh token to the client
+func WithRefreshToken(refreshToken string) ClientOp {
+	return func(c *Client) error {
+		c.refreshToken = refreshToken
+		return nil
+	}
+}
+
+// WithPassword sets the password to the client
+func WithPassword(password string) ClientOp {
+	return func(c *Client) error {
+		c.password = password
+		c.AuthConfig.Password = password
+		return nil
+	}
+}
+
+// withHubToken authenticates the request, which is sent anonymously without
+// a token so that the public repositories can be read without logging in
+func withHubToken(token string) RequestOp {
+	return func(req *http.Request) error {
+		if token == "" {
+			return nil
+		}
+		req.Header["Authorization"] = []string{fmt.Sprintf("Bearer %s", token)}
+		return nil
+	}
+}
+
+//WithSortingOrder adds a sorting order query parameter to the request
+func WithSortingOrder(order string) RequestOp {
+	return func(req *http.Request) error {
+		values, err := url.ParseQuery(req.URL.RawQuery)
+		if err != nil {
+			return err
+		}
+		values.Add("ordering", order)
+		req.URL.RawQuery = values.Encode()
+		return nil
+	}
+}
+
+//WithIfNoneMatch adds an If-None-Match header to the request, ErrNotModified
+// is returned if the resource still matches the given ETag
+func WithIfNoneMatch(etag string) RequestOp {
+	return func(req *http.Request) error {
+		req.Header.Set("If-None-Match", etag)
+		return nil
+	}
+}
+
+//WithIfModifiedSince adds an If-Modified-Since header to the request,
+// ErrNotModified is returned if the resource hasn't changed since the given time
+func WithIfModifiedSince(t time.Time) RequestOp {
+	return func(req *http.Request) error {
+		req.Header.Set("If-Modified-Since", t.UTC().Format(http.TimeFormat))
+		return nil
+	}
+}
+
+// Login tries to authenticate, it will call the twoFactorCodeProvider if the
+// user has 2FA activated
+func (c *Client) Login(ctx context.Context, username string, password string, twoFactorCodeProvider func() (string, error)) (string, string, error) {
+	data, err := json.Marshal(types.AuthConfig{
+		Username: username,
+		Password: password,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	body := bytes.NewBuffer(data)
+
+	// Login on the Docker Hub
+	req, err := http.NewRequestWithContext(withLogin(ctx), "POST", c.domain+LoginURL, ioutil.NopCloser(body))
+	if err != nil {
+		return "", "", err
+	}
+	resp, err := c.doRawRequest(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Login is OK, return the token
+	if resp.StatusCode == http.StatusOK {
+		creds := tokenResponse{}
+		if err := json.Unmarshal(buf, &creds); err != nil {
+			return "", "", err
+		}
+		return creds.Token, "", nil
+	} else if resp.StatusCode == http.StatusUnauthorized {
+		response2FA := twoFactorResponse{}
+		if err := json.Unmarshal(buf, &response2FA); err != nil {
+			return "", "", err
+		}
+		// Check if 2FA is enabled and needs a second authentication
+		if response2FA.Detail != SecondFactorDetailMessage {
+			return "", "", fmt.Errorf(response2FA.Detail)
+		}
+		return c.getTwoFactorToken(ctx, response2FA.Login2FAToken, twoFactorCodeProvider)
+	}
+	if ok, err := extractError(buf, resp); ok {
+		return "", "", err
+	}
+	return "", "", fmt.Errorf("failed to authenticate: bad status code %q: %s", resp.Status, string(buf))
+}
+
+func (c *Client) getTwoFactorToken(ctx context.Context, token string, twoFactorCodeProvider func() (string, error)) (string, string, error) {
+	code, err := twoFactorCodeProvider()
+	if err != nil {
+		return "", "", err
+	}
+
+	body2FA := twoFactorRequest{
+		Code:          code,
+		Login2FAToken: token,
+	}
+	data, err := json.Marshal(body2FA)
+	if err != nil {
+		return "", "", err
+	}
+
+	body := bytes.NewBuffer(data)
+
+	// Request 2FA on the Docker Hub
+	req, err := http.NewRequestWithContext(withLogin(ctx), "POST", c.domain+TwoFactorLoginURL, ioutil.NopCloser(body))
+	if err != nil {
+		return "", "", err
+	}
+	resp, err := c.doRawRequest(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Login is OK, return the token
+	if resp.StatusCode == http.StatusOK {
+		creds := tokenResponse{}
+		if err := json.Unmarshal(buf, &creds); err != nil {
+			return "", "", err
+		}
+
+		return creds.Token, creds.RefreshToken, nil
+	}
+	if ok, err := extractError(buf, resp); ok {
+		return "", "", err
+	}
+	return "", "", fmt.Errorf("failed to authenticate: bad status code %q: %s", resp.Status, string(buf))
+}
+
+func (c *Client) doRequest(req *http.Request, reqOps ...RequestOp) ([]byte, error) {
+	logger := c.logger().WithFields(log.Fields{"method": req.Method, "url": req.URL.String()})
+	logger.Debug("HTTP request")
+	logger.Tracef("HTTP request: %+v", req)
+	if c.cacheable(req) {
+		if buf, ok := c.cache.get(c.account, req); ok {
+			logger.Debug("HTTP response served from the cache")
+			return buf, nil
+		}
+	}
+	reqOps = append(c.conditionalRequestOps(req), reqOps...)
+	resp, err := c.doRawRequest(req, reqOps...)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil {
+		defer resp.Body.Close() //nolint:errcheck
+	}
+	logger = logger.WithField("status", resp.StatusCode)
+	logger.Tracef("HTTP response: %+v", resp)
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, ErrNotModified
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if resp.StatusCode == http.StatusForbidden {
+			return nil, &forbiddenError{}
+		}
+		buf, err := ioutil.ReadAll(resp.Body)
+		logger.Debugf("bad status code %q: %s", resp.Status, buf)
+		statusErr := &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+		if err == nil {
+			statusErr.Message = extractMessage(buf)
+		}
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			return nil, &notFoundError{err: statusErr}
+		case http.StatusUnauthorized:
+			return nil, &unauthorizedError{err: statusErr}
+		case http.StatusTooManyRequests:
+			return nil, &rateLimitedError{err: statusErr}
+		}
+		return nil, statusErr
+	}
+	buf, err := ioutil.ReadAll(resp.Body)
+	logger.Tracef("HTTP response body: %s", buf)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("bad status code %q: %s", resp.Status, string(buf))
+	}
+	c.storeValidators(req, resp)
+	recordMetadata(req, resp)
+	if c.cacheable(req) {
+		if err := c.cache.put(c.account, req, buf); err != nil {
+			logger.Debug(err)
+		}
+	}
+
+	return buf, nil
+}
+
+func (c *Client) conditionalRequestOps(req *http.Request) []RequestOp {
+	if !c.isConditional(req) {
+		return nil
+	}
+	c.validatorsMutex.Lock()
+	v, ok := c.validators[req.URL.String()]
+	c.validatorsMutex.Unlock()
+	if !ok {
+		return nil
+	}
+	var ops []RequestOp
+	if v.etag != "" {
+		ops = append(ops, WithIfNoneMatch(v.etag))
+	}
+	if !v.lastModified.IsZero() {
+		ops = append(ops, WithIfModifiedSince(v.lastModified))
+	}
+	return ops
+}
+
+func (c *Client) storeValidators(req *http.Request, resp *http.Response) {
+	if !c.isConditional(req) {
+		return
+	}
+	v := validators{etag: resp.Header.Get("ETag")}
+	if lastModified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		v.lastModified = lastModified
+	}
+	if v.etag == "" && v.lastModified.IsZero() {
+		return
+	}
+	c.validatorsMutex.Lock()
+	c.validators[req.URL.String()] = v
+	c.validatorsMutex.Unlock()
+}
+
+// isMutating tells if a request with this method changes something on the Hub
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
+
+// isConditional tells if the validators apply to the request: only the first
+// page of a listing is sent conditionally, the following ones being fetched
+// only when the first one changed
+func (c *Client) isConditional(req *http.Request) bool {
+	if !c.conditionalRequests || req.Method != http.MethodGet {
+		return false
+	}
+	page := req.URL.Query().Get("page")
+	return page == "" || page == "1"
+}
+
+func (c *Client) doRawRequest(req *http.Request, reqOps ...RequestOp) (*http.Response, error) {
+	req.Header["Accept"] = []string{"application/json"}
+	req.Header["Content-Type"] = []string{"application/json"}
+	req.Header["User-Agent"] = []string{fmt.Sprintf("hub-tool/%s", internal.Version)}
+	if c.userAgent != "" {
+		req.Header["User-Agent"] = []string{c.userAgent}
+	}
+	for _, op := range reqOps {
+		if err := op(req); err != nil {
+			return nil, err
+		}
+	}
+	start := time.Now()
+	resp, err := c.sendWithRetries(req)
+	c.logRequest(req, resp, err, time.Since(start))
+	return resp, err
+}
+
+// logger returns the logger of the client, the logrus standard logger unless
+// another one was set
+func (c *Client) logger() log.Ext1FieldLogger {
+	if c.log == nil {
+		return log.StandardLogger()
+	}
+	return c.log
+}
+
+// DefaultNamespace returns the namespace to use when none is given, the current
+// account unless another one was set
+func (c *Client) DefaultNamespace() string {
+	if c.namespace != "" {
+		return c.namespace
+	}
+	return c.AuthConfig.Username
+}
+
+// IsAnonymous tells if the client has no credentials, and can only read the
+// public content of the Hub
+func (c *Client) IsAnonymous() bool {
+	return c.token == "" && c.AuthConfig.Username == ""
+}
+
+// Concurrency returns the number of requests the client sends concurrently,
+// for callers fanning out requests of their own
+func (c *Client) Concurrency() int {
+	return c.maxConcurrentRequests()
+}
+
+// maxConcurrentRequests returns the client concurrency, falling back to the
+// default one for clients not built with NewClient
+func (c *Client) maxConcurrentRequests() int {
+	if c.concurrency > 0 {
+		return c.concurrency
+	}
+	return defaultConcurrency
+}
+
+func extractError(buf []byte, resp *http.Response) (bool, error) {
+	if msg := extractMessage(buf); msg != "" {
+		return true, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status, Message: msg}
+	}
+	return false, nil
+}
+
+// extractMessage returns the error message of a Hub response body, if any
+func extractMessage(buf []byte) string {
+	var responseBody map[string]string
+	if err := json.Unmarshal(buf, &responseBody); err == nil {
+		for _, k := range []string{"message", "detail"} {
+			if msg, ok := responseBody[k]; ok {
+				return msg
+			}
+		}
+	}
+	return ""
+}
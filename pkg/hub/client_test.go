@@ -0,0 +1,224 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"gotest.tools/v3/assert"
+
+	"github.com/docker/hub-tool/internal"
+)
+
+// newTestClient returns a client sending its requests to a test server
+// answering with the given handler
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &Client{domain: server.URL}
+}
+
+// routes answers the requests matching "METHOD /path" with the given JSON
+// body, and fails the test on any other request
+type routes map[string]string
+
+func (r routes) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, ok := r[req.Method+" "+req.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = fmt.Fprintf(w, `{"detail": "unexpected request %s %s"}`, req.Method, req.URL.Path)
+		return
+	}
+	_, _ = w.Write([]byte(body))
+}
+
+func TestDoRequestAddsCustomUserAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Header.Get("Accept"), "application/json")
+		assert.Equal(t, r.Header.Get("User-Agent"), "hub-tool/"+internal.Version)
+	}))
+	defer server.Close()
+	req, err := http.NewRequest("GET", server.URL, nil)
+	assert.NilError(t, err)
+	client := Client{}
+	_, err = client.doRequest(req)
+	assert.NilError(t, err)
+}
+
+func TestAnonymousRequestIsNotAuthenticated(t *testing.T) {
+	var authorization []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = append(authorization, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"count": 0}`))
+	}))
+	assert.Assert(t, client.IsAnonymous())
+	_, _, err := client.GetTags(context.Background(), "library/alpine")
+	assert.NilError(t, err)
+
+	client.token = "secret"
+	_, _, err = client.GetTags(context.Background(), "library/alpine")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, authorization, []string{"", "Bearer secret"})
+}
+
+func TestConditionalRequestReturnsNotModified(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+	client, err := NewClient(WithConditionalRequests())
+	assert.NilError(t, err)
+
+	req, err := http.NewRequest("GET", server.URL, nil)
+	assert.NilError(t, err)
+	_, err = client.doRequest(req)
+	assert.NilError(t, err)
+
+	req, err = http.NewRequest("GET", server.URL, nil)
+	assert.NilError(t, err)
+	_, err = client.doRequest(req)
+	assert.Equal(t, err, ErrNotModified)
+}
+
+func TestConditionalListingOnlyChecksFirstPage(t *testing.T) {
+	var etag atomic.Value
+	etag.Store(`"v1"`)
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			assert.Equal(t, r.Header.Get("If-None-Match"), "")
+			w.Header().Set("ETag", `"page-2"`)
+			_, _ = w.Write([]byte(`{"count": 2, "results": [{"name": "second"}]}`))
+			return
+		}
+		if r.Header.Get("If-None-Match") == etag.Load() {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag.Load().(string))
+		_, _ = fmt.Fprintf(w, `{"count": 2, "next": "http://%s%s?page=2", "results": [{"name": "first"}]}`, r.Host, r.URL.Path)
+	}))
+	assert.NilError(t, client.Update(WithConditionalRequests(), WithAllElements()))
+
+	repos, _, err := client.GetRepositories(context.Background(), "jdoe")
+	assert.NilError(t, err)
+	assert.Equal(t, len(repos), 2)
+
+	_, _, err = client.GetRepositories(context.Background(), "jdoe")
+	assert.Equal(t, err, ErrNotModified)
+
+	etag.Store(`"v2"`)
+	repos, _, err = client.GetRepositories(context.Background(), "jdoe")
+	assert.NilError(t, err)
+	assert.Equal(t, len(repos), 2)
+}
+
+func TestDryRunOnlySendsReadRequests(t *testing.T) {
+	client := newTestClient(t, routes{
+		"GET /v2/repositories/jdoe/repo/tags/": `{"count": 1, "results": [{"name": "latest"}]}`,
+	})
+	out := bytes.NewBuffer(nil)
+	assert.NilError(t, client.Update(WithDryRun(out)))
+
+	tags, _, err := client.GetTags(context.Background(), "jdoe/repo")
+	assert.NilError(t, err)
+	assert.Equal(t, len(tags), 1)
+	assert.NilError(t, client.RemoveTag(context.Background(), "jdoe/repo", "latest"))
+	assert.Equal(t, out.String(), "Would send DELETE "+client.domain+"/v2/repositories/jdoe/repo/tags/latest/\n")
+
+	out.Reset()
+	resp, err := client.HTTPClient().Post(client.domain+"/webhook", "application/json", strings.NewReader("{}"))
+	assert.NilError(t, err)
+	assert.NilError(t, resp.Body.Close())
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	assert.Equal(t, out.String(), "Would send POST "+client.domain+"/webhook\n")
+}
+
+func TestLoginWithTwoFactorAuthentication(t *testing.T) {
+	testCases := []struct {
+		name          string
+		status        int
+		response      string
+		expectedToken string
+		expectedError string
+	}{
+		{name: "valid code", status: http.StatusOK, response: `{"token": "jwt", "refresh_token": "refresh"}`, expectedToken: "jwt"},
+		{name: "invalid code", status: http.StatusUnauthorized, response: `{"detail": "Incorrect authentication credentials"}`, expectedError: `failed to authenticate: bad status code "401 Unauthorized": Incorrect authentication credentials`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/v2/users/login":
+					w.WriteHeader(http.StatusUnauthorized)
+					_, _ = fmt.Fprintf(w, `{"detail": %q, "login_2fa_token": "2fa-token"}`, SecondFactorDetailMessage)
+				case "/v2/users/2fa-login":
+					var body twoFactorRequest
+					assert.NilError(t, json.NewDecoder(r.Body).Decode(&body))
+					assert.DeepEqual(t, body, twoFactorRequest{Code: "123456", Login2FAToken: "2fa-token"})
+					w.WriteHeader(tc.status)
+					_, _ = w.Write([]byte(tc.response))
+				default:
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+			}))
+			prompted := false
+			token, _, err := client.Login(context.Background(), "jdoe", "secret", func() (string, error) {
+				prompted = true
+				return "123456", nil
+			})
+			assert.Assert(t, prompted)
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, token, tc.expectedToken)
+		})
+	}
+}
+
+func TestLoggerHasRequestFields(t *testing.T) {
+	client := newTestClient(t, routes{
+		"GET /v2/repositories/jdoe/app/": `{"name": "app", "namespace": "jdoe"}`,
+	})
+	logger, hook := logtest.NewNullLogger()
+	logger.SetLevel(log.DebugLevel)
+	assert.NilError(t, client.Update(WithLogger(logger)))
+
+	_, err := client.GetRepository(context.Background(), "jdoe/app")
+	assert.NilError(t, err)
+	assert.Equal(t, len(hook.Entries), 1)
+	entry := hook.LastEntry()
+	assert.Equal(t, entry.Message, "HTTP request")
+	assert.Equal(t, entry.Data["method"], "GET")
+	assert.Equal(t, entry.Data["url"], client.domain+"/v2/repositories/jdoe/app/")
+}
@@ -0,0 +1,169 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const (
+	// CollaboratorsURL path to the Hub API listing the collaborators of a repository
+	CollaboratorsURL = "/v2/repositories/%s/collaborators/"
+	// CollaboratorURL path to the Hub API managing a collaborator of a repository
+	CollaboratorURL = "/v2/repositories/%s/collaborators/%s/"
+)
+
+// Collaborator is a user given access to a personal repository
+type Collaborator struct {
+	Username string
+}
+
+// GetCollaborators lists all the collaborators of a repository
+func (c *Client) GetCollaborators(ctx context.Context, repository string) ([]Collaborator, error) {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(c.domain + fmt.Sprintf(CollaboratorsURL, repoPath))
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	q.Add("page_size", fmt.Sprintf("%v", itemsPerPage))
+	q.Add("page", "1")
+	u.RawQuery = q.Encode()
+
+	collaborators, next, err := c.getCollaboratorsPage(ctx, u.String())
+	if err != nil {
+		return nil, err
+	}
+	for next != "" {
+		pageCollaborators, n, err := c.getCollaboratorsPage(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+		next = n
+		collaborators = append(collaborators, pageCollaborators...)
+	}
+	return collaborators, nil
+}
+
+// AddCollaborator gives a user access to a personal repository
+func (c *Client) AddCollaborator(ctx context.Context, repository, username string) error {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(hubCollaboratorResult{User: username})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.domain+fmt.Sprintf(CollaboratorsURL, repoPath), bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	_, err = c.doRequest(req, withHubToken(c.token))
+	return err
+}
+
+// RemoveCollaborator removes the access of a user to a personal repository
+func (c *Client) RemoveCollaborator(ctx context.Context, repository, username string) error {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.domain+fmt.Sprintf(CollaboratorURL, repoPath, username), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.doRequest(req, withHubToken(c.token))
+	return err
+}
+
+// AuditPublicReposWithCollaborators returns the public repositories of an
+// account which also have explicit collaborators, as they are likely meant to
+// be private.
+// The context deadline bounds the whole audit: when it expires, the
+// repositories found so far are returned with the context error.
+func (c *Client) AuditPublicReposWithCollaborators(ctx context.Context, account string) ([]Repository, error) {
+	repos, _, err := c.GetRepositories(withAllElements(ctx), account)
+	if err != nil {
+		return nil, err
+	}
+
+	suspicious := make([]bool, len(repos))
+	errs := c.forEachConcurrently(ctx, len(repos), func(i int) error {
+		if repos[i].IsPrivate {
+			return nil
+		}
+		collaborators, err := c.GetCollaborators(ctx, repos[i].Name)
+		if err != nil {
+			return err
+		}
+		suspicious[i] = len(collaborators) > 0
+		return nil
+	})
+	result := []Repository{}
+	for i, repo := range repos {
+		if suspicious[i] {
+			result = append(result, repo)
+		}
+	}
+	if ctx.Err() != nil {
+		return result, ctx.Err()
+	}
+	if err := firstError(errs); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) getCollaboratorsPage(ctx context.Context, url string) ([]Collaborator, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, "", err
+	}
+	var hubResponse hubCollaboratorResponse
+	if err := json.Unmarshal(response, &hubResponse); err != nil {
+		return nil, "", err
+	}
+	var collaborators []Collaborator
+	for _, result := range hubResponse.Results {
+		collaborators = append(collaborators, Collaborator{Username: result.User})
+	}
+	return collaborators, hubResponse.Next, nil
+}
+
+type hubCollaboratorResponse struct {
+	Count    int                     `json:"count"`
+	Next     string                  `json:"next,omitempty"`
+	Previous string                  `json:"previous,omitempty"`
+	Results  []hubCollaboratorResult `json:"results,omitempty"`
+}
+
+type hubCollaboratorResult struct {
+	User string `json:"user"`
+}
@@ -0,0 +1,58 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestAuditPublicReposWithCollaborators(t *testing.T) {
+	// The private repository has no collaborators route: querying it fails the audit
+	client := newTestClient(t, routes{
+		"GET /v2/repositories/jdoe/": `{"count": 3, "results": [
+			{"name": "shared", "namespace": "jdoe", "is_private": false},
+			{"name": "public", "namespace": "jdoe", "is_private": false},
+			{"name": "secret", "namespace": "jdoe", "is_private": true}
+		]}`,
+		"GET /v2/repositories/jdoe/shared/collaborators/": `{"count": 1, "results": [{"user": "alice"}]}`,
+		"GET /v2/repositories/jdoe/public/collaborators/": `{"count": 0, "results": []}`,
+	})
+
+	repos, err := client.AuditPublicReposWithCollaborators(context.Background(), "jdoe")
+	assert.NilError(t, err)
+	assert.Equal(t, len(repos), 1)
+	assert.Equal(t, repos[0].Name, "jdoe/shared")
+
+	collaborators, err := client.GetCollaborators(context.Background(), "jdoe/shared")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, collaborators, []Collaborator{{Username: "alice"}})
+}
+
+func TestManageCollaborators(t *testing.T) {
+	client := newTestClient(t, routes{
+		"POST /v2/repositories/jdoe/app/collaborators/":         `{"user": "alice"}`,
+		"DELETE /v2/repositories/jdoe/app/collaborators/alice/": ``,
+	})
+	assert.NilError(t, client.AddCollaborator(context.Background(), "jdoe/app", "alice"))
+	assert.NilError(t, client.RemoveCollaborator(context.Background(), "jdoe/app", "alice"))
+	err := client.RemoveCollaborator(context.Background(), "jdoe/app", "bob")
+	assert.Assert(t, errors.Is(err, ErrNotFound))
+}
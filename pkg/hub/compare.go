@@ -0,0 +1,125 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AccountDiff lists the differences between the repositories of two accounts.
+// Repositories are matched by their name without the namespace.
+type AccountDiff struct {
+	Source string
+	Target string
+	// OnlySource are the repositories missing in the target account
+	OnlySource []Repository
+	// OnlyTarget are the repositories missing in the source account
+	OnlyTarget []Repository
+	// Mismatches are the repositories present in both accounts with different settings
+	Mismatches []RepositoryMismatch
+}
+
+// Equal returns true if both accounts have the same repositories with the same settings
+func (d AccountDiff) Equal() bool {
+	return len(d.OnlySource) == 0 && len(d.OnlyTarget) == 0 && len(d.Mismatches) == 0
+}
+
+// RepositoryMismatch is a repository present in both accounts whose fields differ
+type RepositoryMismatch struct {
+	Name   string
+	Fields []FieldMismatch
+}
+
+// FieldMismatch is a repository field whose value differs between accounts
+type FieldMismatch struct {
+	Field  string
+	Source string
+	Target string
+}
+
+func (f FieldMismatch) String() string {
+	return fmt.Sprintf("%s: %q != %q", f.Field, f.Source, f.Target)
+}
+
+// CompareAccounts lists all the repositories of both accounts and returns their
+// differences, typically to verify a migration from one namespace to another.
+// The description and the privacy of the repositories are compared.
+func (c *Client) CompareAccounts(ctx context.Context, source, target string) (AccountDiff, error) {
+	diff := AccountDiff{Source: source, Target: target}
+	ctx = withAllElements(ctx)
+	sourceRepos, _, err := c.GetRepositories(ctx, source)
+	if err != nil {
+		return diff, err
+	}
+	targetRepos, _, err := c.GetRepositories(ctx, target)
+	if err != nil {
+		return diff, err
+	}
+
+	targets := map[string]Repository{}
+	for _, repo := range targetRepos {
+		targets[shortName(repo.Name)] = repo
+	}
+	for _, sourceRepo := range sourceRepos {
+		name := shortName(sourceRepo.Name)
+		targetRepo, ok := targets[name]
+		if !ok {
+			diff.OnlySource = append(diff.OnlySource, sourceRepo)
+			continue
+		}
+		delete(targets, name)
+		if fields := compareRepositories(sourceRepo, targetRepo); len(fields) > 0 {
+			diff.Mismatches = append(diff.Mismatches, RepositoryMismatch{Name: name, Fields: fields})
+		}
+	}
+	for _, repo := range targetRepos {
+		if _, ok := targets[shortName(repo.Name)]; ok {
+			diff.OnlyTarget = append(diff.OnlyTarget, repo)
+		}
+	}
+
+	sort.Slice(diff.OnlySource, func(i, j int) bool { return diff.OnlySource[i].Name < diff.OnlySource[j].Name })
+	sort.Slice(diff.OnlyTarget, func(i, j int) bool { return diff.OnlyTarget[i].Name < diff.OnlyTarget[j].Name })
+	sort.Slice(diff.Mismatches, func(i, j int) bool { return diff.Mismatches[i].Name < diff.Mismatches[j].Name })
+	return diff, nil
+}
+
+func compareRepositories(source, target Repository) []FieldMismatch {
+	var fields []FieldMismatch
+	if source.IsPrivate != target.IsPrivate {
+		fields = append(fields, FieldMismatch{
+			Field:  "private",
+			Source: fmt.Sprintf("%v", source.IsPrivate),
+			Target: fmt.Sprintf("%v", target.IsPrivate),
+		})
+	}
+	if source.Description != target.Description {
+		fields = append(fields, FieldMismatch{
+			Field:  "description",
+			Source: source.Description,
+			Target: target.Description,
+		})
+	}
+	return fields
+}
+
+func shortName(repository string) string {
+	return repository[strings.LastIndex(repository, "/")+1:]
+}
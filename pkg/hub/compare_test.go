@@ -0,0 +1,56 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCompareAccounts(t *testing.T) {
+	client := newTestClient(t, routes{
+		"GET /v2/repositories/olduser/": `{"count": 3, "results": [
+			{"name": "app", "namespace": "olduser", "description": "The app"},
+			{"name": "worker", "namespace": "olduser", "is_private": true},
+			{"name": "legacy", "namespace": "olduser"}
+		]}`,
+		"GET /v2/repositories/neworg/": `{"count": 3, "results": [
+			{"name": "app", "namespace": "neworg", "description": "The app"},
+			{"name": "worker", "namespace": "neworg", "description": "Jobs"},
+			{"name": "extra", "namespace": "neworg"}
+		]}`,
+	})
+
+	diff, err := client.CompareAccounts(context.Background(), "olduser", "neworg")
+	assert.NilError(t, err)
+	assert.Assert(t, !diff.Equal())
+	assert.DeepEqual(t, diff.OnlySource, []Repository{{Name: "olduser/legacy"}})
+	assert.DeepEqual(t, diff.OnlyTarget, []Repository{{Name: "neworg/extra"}})
+	assert.DeepEqual(t, diff.Mismatches, []RepositoryMismatch{{
+		Name: "worker",
+		Fields: []FieldMismatch{
+			{Field: "private", Source: "true", Target: "false"},
+			{Field: "description", Source: "", Target: "Jobs"},
+		},
+	}})
+
+	diff, err = client.CompareAccounts(context.Background(), "olduser", "olduser")
+	assert.NilError(t, err)
+	assert.Assert(t, diff.Equal())
+}
@@ -27,19 +27,22 @@ type Consumption struct {
 	Seats               int
 	PrivateRepositories int
 	Teams               int
+	// Storage is the size of the images stored in all the repositories, in bytes
+	Storage int64
 }
 
 //GetOrgConsumption return the current organization consumption
-func (c *Client) GetOrgConsumption(org string) (*Consumption, error) {
+func (c *Client) GetOrgConsumption(ctx context.Context, org string) (*Consumption, error) {
 	var (
 		members      int
 		privateRepos int
 		teams        int
+		storage      int64
 	)
 	c.fetchAllElements = true
-	eg, _ := errgroup.WithContext(context.Background())
+	eg, ctx := errgroup.WithContext(ctx)
 	eg.Go(func() error {
-		count, err := c.GetMembersCount(org)
+		count, err := c.GetMembersCount(ctx, org)
 		if err != nil {
 			return err
 		}
@@ -47,7 +50,7 @@ func (c *Client) GetOrgConsumption(org string) (*Consumption, error) {
 		return nil
 	})
 	eg.Go(func() error {
-		count, err := c.GetTeamsCount(org)
+		count, err := c.GetTeamsCount(ctx, org)
 		if err != nil {
 			return err
 		}
@@ -55,7 +58,7 @@ func (c *Client) GetOrgConsumption(org string) (*Consumption, error) {
 		return nil
 	})
 	eg.Go(func() error {
-		repos, _, err := c.GetRepositories(org)
+		repos, _, err := c.GetRepositories(ctx, org)
 		if err != nil {
 			return err
 		}
@@ -63,6 +66,7 @@ func (c *Client) GetOrgConsumption(org string) (*Consumption, error) {
 			if r.IsPrivate {
 				privateRepos++
 			}
+			storage += r.StorageSize
 		}
 		return nil
 	})
@@ -75,14 +79,16 @@ func (c *Client) GetOrgConsumption(org string) (*Consumption, error) {
 		Seats:               members,
 		PrivateRepositories: privateRepos,
 		Teams:               teams,
+		Storage:             storage,
 	}, nil
 }
 
 //GetUserConsumption return the current user consumption
-func (c *Client) GetUserConsumption(user string) (*Consumption, error) {
+func (c *Client) GetUserConsumption(ctx context.Context, user string) (*Consumption, error) {
 	c.fetchAllElements = true
 	privateRepos := 0
-	repos, _, err := c.GetRepositories(user)
+	var storage int64
+	repos, _, err := c.GetRepositories(ctx, user)
 	if err != nil {
 		return nil, err
 	}
@@ -90,10 +96,12 @@ func (c *Client) GetUserConsumption(user string) (*Consumption, error) {
 		if r.IsPrivate {
 			privateRepos++
 		}
+		storage += r.StorageSize
 	}
 	return &Consumption{
 		Seats:               1,
 		PrivateRepositories: privateRepos,
 		Teams:               0,
+		Storage:             storage,
 	}, nil
 }
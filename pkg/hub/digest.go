@@ -0,0 +1,82 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/docker/distribution/reference"
+)
+
+var anchoredDigestRegexp = regexp.MustCompile("^" + reference.DigestRegexp.String() + "$")
+
+// FindTagsByDigest scans all the repositories of an account and returns, for
+// each repository, the tags referencing the given digest either as their
+// manifest list or as one of their platform images. Canceling the context
+// stops the scan early, returning what was found so far with the context error.
+func (c *Client) FindTagsByDigest(ctx context.Context, account, digest string) (map[string][]string, error) {
+	if !anchoredDigestRegexp.MatchString(digest) {
+		return nil, fmt.Errorf("invalid digest %q", digest)
+	}
+	ctx = withAllElements(ctx)
+	repos, _, err := c.GetRepositories(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu     sync.Mutex
+		result = map[string][]string{}
+	)
+	errs := c.forEachConcurrently(ctx, len(repos), func(i int) error {
+		tags, _, err := c.GetTags(ctx, repos[i].Name)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, tag := range tags {
+			if referencesDigest(tag, digest) {
+				result[repos[i].Name] = append(result[repos[i].Name], tag.Name[strings.LastIndex(tag.Name, ":")+1:])
+			}
+		}
+		return nil
+	})
+	if ctx.Err() != nil {
+		return result, ctx.Err()
+	}
+	if err := firstError(errs); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func referencesDigest(tag Tag, digest string) bool {
+	if tag.Digest == digest {
+		return true
+	}
+	for _, image := range tag.Images {
+		if image.Digest == digest {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+const (
+	indexDigest = "sha256:4bf2ad5b3ab1db8b29b9d5fa7d6ce4b3b9dd7d11a4bb5c5b1cbd2a6b7c8d9e0f"
+	armDigest   = "sha256:9c2d6e1f0a3b4c5d6e7f8091a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d5"
+)
+
+func TestFindTagsByDigest(t *testing.T) {
+	client := newTestClient(t, routes{
+		"GET /v2/repositories/jdoe/": `{"count": 2, "results": [
+			{"name": "app", "namespace": "jdoe"},
+			{"name": "worker", "namespace": "jdoe"}
+		]}`,
+		"GET /v2/repositories/jdoe/app/tags/": fmt.Sprintf(`{"count": 3, "results": [
+			{"name": "latest", "digest": %q, "images": [{"architecture": "arm64", "os": "linux", "digest": %q}]},
+			{"name": "1.0", "digest": %[1]q},
+			{"name": "0.9", "digest": "sha256:0000000000000000000000000000000000000000000000000000000000000000"}
+		]}`, indexDigest, armDigest),
+		"GET /v2/repositories/jdoe/worker/tags/": fmt.Sprintf(`{"count": 1, "results": [
+			{"name": "arm", "images": [{"architecture": "arm64", "os": "linux", "digest": %q}]}
+		]}`, armDigest),
+	})
+
+	testCases := []struct {
+		name     string
+		digest   string
+		expected map[string][]string
+		err      string
+	}{
+		{name: "manifest list", digest: indexDigest, expected: map[string][]string{"jdoe/app": {"latest", "1.0"}}},
+		{name: "platform image", digest: armDigest, expected: map[string][]string{"jdoe/app": {"latest"}, "jdoe/worker": {"arm"}}},
+		{name: "invalid digest", digest: "sha256:beef", err: `invalid digest "sha256:beef"`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tags, err := client.FindTagsByDigest(context.Background(), "jdoe", tc.digest)
+			if tc.err != "" {
+				assert.Error(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tags, tc.expected)
+		})
+	}
+}
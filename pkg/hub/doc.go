@@ -0,0 +1,40 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package hub is a client of the Docker Hub API, managing the repositories,
+// tags, tokens and organizations of an account. It is the client behind
+// hub-tool, for Go programs to use the Hub without running the CLI.
+//
+// Create a client with New, log in with Login, then give the returned token to
+// the client:
+//
+//	client, err := hub.New(hub.Options{AllElements: true})
+//	if err != nil {
+//		return err
+//	}
+//	token, _, err := client.Login(ctx, username, password, readTwoFactorCode)
+//	if err != nil {
+//		return err
+//	}
+//	if err := client.Update(hub.WithHubAccount(username), hub.WithHubToken(token)); err != nil {
+//		return err
+//	}
+//	repositories, _, err := client.GetRepositories(ctx, username)
+//
+// Every call takes a context to cancel it. The errors match ErrNotFound,
+// ErrUnauthorized, ErrForbidden or ErrRateLimited with errors.Is, and wrap a
+// StatusError with the response status when the Hub answered with an error.
+package hub
@@ -0,0 +1,172 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotModified is returned on a conditional request when the resource hasn't
+// changed since the last fetch
+var ErrNotModified = errors.New("not modified")
+
+// ErrStopIteration is returned by an iterator callback to stop the iteration early
+var ErrStopIteration = errors.New("stop iteration")
+
+// The errors returned by the client match these errors with errors.Is, telling
+// why a request failed
+var (
+	// ErrNotFound matches the errors on missing resources
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized matches the authentication errors, such as an expired
+	// token or wrong credentials
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrForbidden matches the errors on operations the user isn't allowed to
+	// do
+	ErrForbidden = errors.New("forbidden")
+	// ErrRateLimited matches the errors on requests refused by the Hub rate
+	// limiting, once the retries are exhausted
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// StatusError is the error of a request answered with an unexpected status
+// code. The errors matching ErrNotFound, ErrUnauthorized and ErrRateLimited
+// wrap it, get it with errors.As.
+type StatusError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Status is the HTTP status of the response, such as "404 Not Found"
+	Status string
+	// Message is the error message returned by the Hub, if any
+	Message string
+}
+
+func (s *StatusError) Error() string {
+	if s.Message != "" {
+		return fmt.Sprintf("failed to authenticate: bad status code %q: %s", s.Status, s.Message)
+	}
+	return fmt.Sprintf("bad status code %q", s.Status)
+}
+
+type authenticationError struct {
+}
+
+func (a authenticationError) Error() string {
+	return "authentication error"
+}
+
+func (a authenticationError) Is(target error) bool {
+	return target == ErrUnauthorized
+}
+
+// IsAuthenticationError check if the error type is an authentication error
+func IsAuthenticationError(err error) bool {
+	_, ok := err.(*authenticationError)
+	return ok
+}
+
+type invalidTokenError struct {
+	token string
+}
+
+func (i invalidTokenError) Error() string {
+	return fmt.Sprintf("invalid authentication token %q", i.token)
+}
+
+func (i invalidTokenError) Is(target error) bool {
+	return target == ErrUnauthorized
+}
+
+// IsInvalidTokenError check if the error type is an invalid token error
+func IsInvalidTokenError(err error) bool {
+	_, ok := err.(*invalidTokenError)
+	return ok
+}
+
+type forbiddenError struct{}
+
+func (f forbiddenError) Error() string {
+	return "operation not permitted"
+}
+
+func (f forbiddenError) Is(target error) bool {
+	return target == ErrForbidden
+}
+
+// IsForbiddenError check if the error type is a forbidden error
+func IsForbiddenError(err error) bool {
+	_, ok := err.(*forbiddenError)
+	return ok
+}
+
+type notFoundError struct {
+	err error
+}
+
+func (n notFoundError) Error() string {
+	if n.err != nil {
+		return n.err.Error()
+	}
+	return "resource not found"
+}
+
+func (n notFoundError) Unwrap() error {
+	return n.err
+}
+
+func (n notFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// IsNotFoundError check if the error type is a not found error
+func IsNotFoundError(err error) bool {
+	_, ok := err.(*notFoundError)
+	return ok
+}
+
+type unauthorizedError struct {
+	err error
+}
+
+func (u unauthorizedError) Error() string {
+	return u.err.Error()
+}
+
+func (u unauthorizedError) Unwrap() error {
+	return u.err
+}
+
+func (u unauthorizedError) Is(target error) bool {
+	return target == ErrUnauthorized
+}
+
+type rateLimitedError struct {
+	err error
+}
+
+func (r rateLimitedError) Error() string {
+	return r.err.Error()
+}
+
+func (r rateLimitedError) Unwrap() error {
+	return r.err
+}
+
+func (r rateLimitedError) Is(target error) bool {
+	return target == ErrRateLimited
+}
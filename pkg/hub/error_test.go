@@ -0,0 +1,71 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestIsAuthenticationError(t *testing.T) {
+	assert.Assert(t, IsAuthenticationError(&authenticationError{}))
+	assert.Assert(t, !IsAuthenticationError(errors.New("")))
+}
+
+func TestIsInvalidTokenError(t *testing.T) {
+	assert.Assert(t, IsInvalidTokenError(&invalidTokenError{}))
+	assert.Assert(t, !IsInvalidTokenError(errors.New("")))
+}
+
+func TestIsForbiddenError(t *testing.T) {
+	assert.Assert(t, IsForbiddenError(&forbiddenError{}))
+	assert.Assert(t, !IsForbiddenError(errors.New("")))
+}
+
+func TestIsNotFoundError(t *testing.T) {
+	assert.Assert(t, IsNotFoundError(&notFoundError{}))
+	assert.Assert(t, !IsNotFoundError(errors.New("")))
+}
+
+func TestErrorsMatchTheirKind(t *testing.T) {
+	assert.Assert(t, errors.Is(&notFoundError{}, ErrNotFound))
+	assert.Assert(t, errors.Is(&forbiddenError{}, ErrForbidden))
+	assert.Assert(t, errors.Is(&authenticationError{}, ErrUnauthorized))
+	assert.Assert(t, errors.Is(&invalidTokenError{}, ErrUnauthorized))
+	assert.Assert(t, errors.Is(&unauthorizedError{err: errors.New("")}, ErrUnauthorized))
+	assert.Assert(t, errors.Is(&rateLimitedError{err: errors.New("")}, ErrRateLimited))
+	assert.Assert(t, errors.Is(fmt.Errorf("listing: %w", &notFoundError{}), ErrNotFound))
+	assert.Assert(t, !errors.Is(&notFoundError{}, ErrForbidden))
+}
+
+func TestStatusErrorIsWrapped(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "object not found"}`))
+	}))
+	_, err := client.GetRepository(context.Background(), "jdoe/missing")
+	assert.Assert(t, errors.Is(err, ErrNotFound))
+	var statusErr *StatusError
+	assert.Assert(t, errors.As(err, &statusErr))
+	assert.Equal(t, statusErr.StatusCode, http.StatusNotFound)
+	assert.Equal(t, statusErr.Message, "object not found")
+}
@@ -0,0 +1,236 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// ExportCheckpointVersion is the current version of the export checkpoint format
+	ExportCheckpointVersion = 1
+	// MetadataExportVersion is the current version of the metadata export format
+	MetadataExportVersion = 1
+)
+
+// AccountExport holds all the repositories of an account with their tags
+type AccountExport struct {
+	Account      string
+	Repositories []RepositoryExport
+}
+
+// RepositoryExport is a repository with all its tags
+type RepositoryExport struct {
+	Repository Repository
+	Tags       []Tag
+}
+
+// checkpointRecord is a line of the checkpoint journal. The first record only
+// identifies the export, each following one holds a completed repository.
+type checkpointRecord struct {
+	Version    int               `json:"version"`
+	Account    string            `json:"account"`
+	Repository *RepositoryExport `json:"repository,omitempty"`
+}
+
+// ExportAccount fetches all the repositories of an account and their tags.
+// The progress is appended as JSON lines to the checkpoint writer, one line per
+// exported repository. Giving back what was written as the checkpoint reader
+// resumes the export, skipping the repositories already exported. Both can be
+// nil.
+func (c *Client) ExportAccount(ctx context.Context, account string, checkpoint io.Reader, progress io.Writer) (*AccountExport, error) {
+	if account == "" {
+		account = c.account
+	}
+	completed, err := readCheckpoint(checkpoint, account)
+	if err != nil {
+		return nil, err
+	}
+	if progress != nil && len(completed) == 0 {
+		if err := writeCheckpointRecord(progress, checkpointRecord{Version: ExportCheckpointVersion, Account: account}); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx = withAllElements(ctx)
+	repos, _, err := c.GetRepositories(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	export := &AccountExport{Account: account}
+	for _, repo := range repos {
+		if done, ok := completed[repo.Name]; ok {
+			export.Repositories = append(export.Repositories, done)
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		tags, _, err := c.GetTags(ctx, repo.Name)
+		if err != nil {
+			return nil, err
+		}
+		repoExport := RepositoryExport{Repository: repo, Tags: tags}
+		if progress != nil {
+			record := checkpointRecord{Version: ExportCheckpointVersion, Account: account, Repository: &repoExport}
+			if err := writeCheckpointRecord(progress, record); err != nil {
+				return nil, err
+			}
+		}
+		export.Repositories = append(export.Repositories, repoExport)
+	}
+	return export, nil
+}
+
+func readCheckpoint(checkpoint io.Reader, account string) (map[string]RepositoryExport, error) {
+	completed := map[string]RepositoryExport{}
+	if checkpoint == nil {
+		return completed, nil
+	}
+	scanner := bufio.NewScanner(checkpoint)
+	scanner.Buffer(nil, 16*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record checkpointRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("invalid export checkpoint: %s", err)
+		}
+		if record.Version != ExportCheckpointVersion {
+			return nil, fmt.Errorf("unsupported export checkpoint version %d", record.Version)
+		}
+		if record.Account != account {
+			return nil, fmt.Errorf("export checkpoint is for account %q, not %q", record.Account, account)
+		}
+		if record.Repository != nil {
+			completed[record.Repository.Repository.Name] = *record.Repository
+		}
+	}
+	return completed, scanner.Err()
+}
+
+func writeCheckpointRecord(w io.Writer, record checkpointRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// MetadataExport holds the metadata of the repositories of an account, to back
+// them up or to copy them to another account
+type MetadataExport struct {
+	Version      int                  `json:"version"`
+	Account      string               `json:"account"`
+	Repositories []RepositoryMetadata `json:"repositories"`
+}
+
+// RepositoryMetadata holds the settings of a repository, which is named
+// without its namespace
+type RepositoryMetadata struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Overview    string           `json:"overview,omitempty"`
+	IsPrivate   bool             `json:"private"`
+	Categories  []string         `json:"categories,omitempty"`
+	Permissions []TeamPermission `json:"permissions,omitempty"`
+}
+
+// ExportMetadata fetches the description, overview, visibility, categories and
+// team permissions of all the repositories of an account. The team permissions
+// only exist for organizations.
+func (c *Client) ExportMetadata(ctx context.Context, account string) (*MetadataExport, error) {
+	owner, err := c.getOwnerType(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	repos, _, err := c.GetRepositories(withAllElements(ctx), account)
+	if err != nil {
+		return nil, err
+	}
+	export := &MetadataExport{
+		Version:      MetadataExportVersion,
+		Account:      account,
+		Repositories: make([]RepositoryMetadata, len(repos)),
+	}
+	errs := c.forEachConcurrently(ctx, len(repos), func(i int) error {
+		repo, err := c.GetRepository(ctx, repos[i].Name)
+		if err != nil {
+			return err
+		}
+		metadata := RepositoryMetadata{
+			Name:        repo.Name[strings.Index(repo.Name, "/")+1:],
+			Description: repo.Description,
+			Overview:    repo.FullDescription,
+			IsPrivate:   repo.IsPrivate,
+		}
+		for _, category := range repo.Categories {
+			metadata.Categories = append(metadata.Categories, category.Slug)
+		}
+		if owner == OrganizationOwner {
+			if metadata.Permissions, err = c.GetRepositoryPermissions(ctx, repo.Name); err != nil {
+				return err
+			}
+		}
+		export.Repositories[i] = metadata
+		return nil
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := firstError(errs); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// ImportRepositoryMetadata applies the metadata to the repository of the same
+// name in the namespace, creating it if it is missing. The categories replace
+// the existing ones when given, the team permissions are granted on top of the
+// existing ones.
+func (c *Client) ImportRepositoryMetadata(ctx context.Context, namespace string, metadata RepositoryMetadata) error {
+	repo, _, err := c.EnsureRepository(ctx, namespace, metadata.Name, CreateRepositoryOptions{
+		Description: metadata.Description,
+		IsPrivate:   metadata.IsPrivate,
+	})
+	if err != nil {
+		return err
+	}
+	if repo.FullDescription != metadata.Overview {
+		if _, err := c.UpdateRepository(ctx, repo.Name, UpdateRepositoryOptions{FullDescription: &metadata.Overview}); err != nil {
+			return err
+		}
+	}
+	if len(metadata.Categories) > 0 {
+		if err := c.SetRepositoryCategories(ctx, repo.Name, metadata.Categories); err != nil {
+			return err
+		}
+	}
+	for _, permission := range metadata.Permissions {
+		if err := c.GrantTeamPermission(ctx, repo.Name, permission.Team, permission.Permission); err != nil {
+			return fmt.Errorf("failed to grant %s to team %q: %s", permission.Permission, permission.Team, err)
+		}
+	}
+	return nil
+}
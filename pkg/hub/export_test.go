@@ -0,0 +1,170 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+const exportRepositoriesResponse = `{
+  "count": 2,
+  "results": [
+    {"name": "first", "namespace": "jdoe"},
+    {"name": "second", "namespace": "jdoe"}
+  ]
+}`
+
+func newExportClient(t *testing.T, tagRequests map[string]int, mu *sync.Mutex) *Client {
+	return newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/repositories/jdoe/":
+			_, _ = w.Write([]byte(exportRepositoriesResponse))
+		case "/v2/repositories/jdoe/first/tags/", "/v2/repositories/jdoe/second/tags/":
+			mu.Lock()
+			tagRequests[r.URL.Path]++
+			mu.Unlock()
+			_, _ = w.Write([]byte(`{"count": 1, "results": [{"name": "latest"}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestExportAccountWritesCheckpoint(t *testing.T) {
+	var mu sync.Mutex
+	tagRequests := map[string]int{}
+	client := newExportClient(t, tagRequests, &mu)
+
+	var checkpoint bytes.Buffer
+	export, err := client.ExportAccount(context.Background(), "jdoe", nil, &checkpoint)
+	assert.NilError(t, err)
+	assert.Equal(t, len(export.Repositories), 2)
+
+	lines := strings.Split(strings.TrimSpace(checkpoint.String()), "\n")
+	assert.Equal(t, len(lines), 3)
+	assert.Equal(t, lines[0], `{"version":1,"account":"jdoe"}`)
+}
+
+func TestExportAccountResumesFromCheckpoint(t *testing.T) {
+	var mu sync.Mutex
+	tagRequests := map[string]int{}
+	client := newExportClient(t, tagRequests, &mu)
+
+	// A previous export stopped after the first repository
+	var previous bytes.Buffer
+	_, err := client.ExportAccount(context.Background(), "jdoe", nil, &previous)
+	assert.NilError(t, err)
+	lines := strings.SplitAfter(previous.String(), "\n")
+	partial := lines[0] + lines[1]
+
+	tagRequests["/v2/repositories/jdoe/first/tags/"] = 0
+	tagRequests["/v2/repositories/jdoe/second/tags/"] = 0
+	var progress bytes.Buffer
+	export, err := client.ExportAccount(context.Background(), "jdoe", strings.NewReader(partial), &progress)
+	assert.NilError(t, err)
+
+	assert.Equal(t, tagRequests["/v2/repositories/jdoe/first/tags/"], 0)
+	assert.Equal(t, tagRequests["/v2/repositories/jdoe/second/tags/"], 1)
+	assert.Equal(t, len(export.Repositories), 2)
+	assert.Equal(t, export.Repositories[0].Repository.Name, "jdoe/first")
+	assert.Equal(t, export.Repositories[0].Tags[0].Name, "jdoe/first:latest")
+	assert.Equal(t, export.Repositories[1].Repository.Name, "jdoe/second")
+	// Only the newly exported repository is appended, without a new header
+	assert.Equal(t, strings.Count(progress.String(), "\n"), 1)
+	assert.Assert(t, strings.Contains(progress.String(), "jdoe/second"))
+}
+
+func TestExportAccountRejectsInvalidCheckpoint(t *testing.T) {
+	client := &Client{}
+	_, err := client.ExportAccount(context.Background(), "jdoe", strings.NewReader(`{"version":2,"account":"jdoe"}`), nil)
+	assert.Error(t, err, "unsupported export checkpoint version 2")
+
+	_, err = client.ExportAccount(context.Background(), "jdoe", strings.NewReader(`{"version":1,"account":"other"}`), nil)
+	assert.Error(t, err, `export checkpoint is for account "other", not "jdoe"`)
+}
+
+func TestExportMetadata(t *testing.T) {
+	client := newTestClient(t, routes{
+		"GET /v2/users/myorg/":        `{"type": "Organization"}`,
+		"GET /v2/repositories/myorg/": `{"count": 1, "results": [{"name": "app", "namespace": "myorg"}]}`,
+		"GET /v2/repositories/myorg/app/": `{"name": "app", "namespace": "myorg", "description": "An app", "full_description": "# App",
+			"is_private": true, "categories": [{"name": "Databases & storage", "slug": "databases"}]}`,
+		"GET /v2/repositories/myorg/app/groups/": `{"count": 1, "results": [{"group_id": 1, "group_name": "devs", "permission": "write"}]}`,
+	})
+	export, err := client.ExportMetadata(context.Background(), "myorg")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, export, &MetadataExport{
+		Version: MetadataExportVersion,
+		Account: "myorg",
+		Repositories: []RepositoryMetadata{{
+			Name:        "app",
+			Description: "An app",
+			Overview:    "# App",
+			IsPrivate:   true,
+			Categories:  []string{"databases"},
+			Permissions: []TeamPermission{{Team: "devs", Permission: WritePermission}},
+		}},
+	})
+}
+
+func TestImportRepositoryMetadata(t *testing.T) {
+	var requests []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v2/repositories/neworg/app/":
+			w.WriteHeader(http.StatusNotFound)
+		case "POST /v2/repositories/", "PATCH /v2/repositories/neworg/app/":
+			_, _ = w.Write([]byte(`{"name": "app", "namespace": "neworg"}`))
+		case "GET /v2/categories/":
+			_, _ = w.Write([]byte(`[{"name": "Databases & storage", "slug": "databases"}]`))
+		case "GET /v2/orgs/neworg/groups/devs/":
+			_, _ = w.Write([]byte(`{"id": 2, "name": "devs"}`))
+		case "PATCH /v2/repositories/neworg/app/categories/", "POST /v2/repositories/neworg/app/groups/":
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	err := client.ImportRepositoryMetadata(context.Background(), "neworg", RepositoryMetadata{
+		Name:        "app",
+		Description: "An app",
+		Overview:    "# App",
+		IsPrivate:   true,
+		Categories:  []string{"databases"},
+		Permissions: []TeamPermission{{Team: "devs", Permission: WritePermission}},
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, requests, []string{
+		"GET /v2/repositories/neworg/app/",
+		"POST /v2/repositories/",
+		"PATCH /v2/repositories/neworg/app/",
+		"GET /v2/categories/",
+		"PATCH /v2/repositories/neworg/app/categories/",
+		"GET /v2/orgs/neworg/groups/devs/",
+		"POST /v2/repositories/neworg/app/groups/",
+	})
+}
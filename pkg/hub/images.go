@@ -0,0 +1,168 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	// DeleteImagesURL path to the Hub API deleting images of a namespace
+	DeleteImagesURL = "/v2/namespaces/%s/delete-images"
+)
+
+// ImageReferencedError is returned when deleting an image still in use, either
+// as the current image of tags or as part of a tagged manifest list
+type ImageReferencedError struct {
+	Digest string
+	// Tags are the tags referencing the image
+	Tags     []string
+	warnings []hubDeleteImageWarning
+}
+
+func (e ImageReferencedError) Error() string {
+	if len(e.Tags) == 0 {
+		return fmt.Sprintf("image %s is still referenced", e.Digest)
+	}
+	return fmt.Sprintf("image %s is still referenced by the tags %s", e.Digest, strings.Join(e.Tags, ", "))
+}
+
+// IsImageReferencedError check if the error type is an image referenced error
+func IsImageReferencedError(err error) bool {
+	_, ok := err.(*ImageReferencedError)
+	return ok
+}
+
+type hubDeleteImagesRequest struct {
+	DryRun         bool                    `json:"dry_run"`
+	IgnoreWarnings []hubDeleteImageWarning `json:"ignore_warnings,omitempty"`
+	Manifests      []hubImageManifest      `json:"manifests"`
+}
+
+type hubImageManifest struct {
+	Repository string `json:"repository"`
+	Digest     string `json:"digest"`
+}
+
+type hubDeleteImageWarning struct {
+	Repository string   `json:"repository"`
+	Digest     string   `json:"digest"`
+	Warning    string   `json:"warning"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+type hubDeleteImagesError struct {
+	ErrInfo struct {
+		Details struct {
+			Warnings []hubDeleteImageWarning `json:"warnings"`
+		} `json:"details"`
+	} `json:"errinfo"`
+}
+
+// RemoveImage deletes an image of a repository given by its digest. Hub
+// refuses to delete an image still referenced by tags, in which case an
+// *ImageReferencedError is returned, unless force is set: the image is then
+// deleted along with the tags referencing it.
+func (c *Client) RemoveImage(ctx context.Context, repository, digest string, force bool) error {
+	if !anchoredDigestRegexp.MatchString(digest) {
+		return fmt.Errorf("invalid digest %q", digest)
+	}
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return err
+	}
+	namespace, name := splitRepoPath(repoPath)
+	request := hubDeleteImagesRequest{
+		Manifests: []hubImageManifest{{Repository: name, Digest: digest}},
+	}
+	err = c.deleteImages(ctx, namespace, request)
+	referenced, ok := err.(*ImageReferencedError)
+	if !ok || !force {
+		return err
+	}
+	request.IgnoreWarnings = referenced.warnings
+	return c.deleteImages(ctx, namespace, request)
+}
+
+func (c *Client) deleteImages(ctx context.Context, namespace string, request hubDeleteImagesRequest) error {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.domain+fmt.Sprintf(DeleteImagesURL, namespace), bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	resp, err := c.doRawRequest(req, withHubToken(c.token))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	var hubError hubDeleteImagesError
+	if resp.StatusCode == http.StatusBadRequest && json.Unmarshal(buf, &hubError) == nil && len(hubError.ErrInfo.Details.Warnings) > 0 {
+		return toImageReferencedError(request.Manifests[0].Digest, hubError.ErrInfo.Details.Warnings)
+	}
+	switch resp.StatusCode {
+	case http.StatusForbidden:
+		return &forbiddenError{}
+	case http.StatusNotFound:
+		return &notFoundError{err: fmt.Errorf("image %s not found", request.Manifests[0].Digest)}
+	}
+	if ok, err := extractError(buf, resp); ok {
+		return err
+	}
+	return fmt.Errorf("bad status code %q", resp.Status)
+}
+
+func toImageReferencedError(digest string, warnings []hubDeleteImageWarning) *ImageReferencedError {
+	seen := map[string]bool{}
+	var tags []string
+	for _, warning := range warnings {
+		for _, tag := range warning.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return &ImageReferencedError{Digest: digest, Tags: tags, warnings: warnings}
+}
+
+// splitRepoPath splits a repository path, such as library/ubuntu, in its
+// namespace and name
+func splitRepoPath(repoPath string) (string, string) {
+	i := strings.Index(repoPath, "/")
+	if i < 0 {
+		return "library", repoPath
+	}
+	return repoPath[:i], repoPath[i+1:]
+}
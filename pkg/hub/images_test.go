@@ -0,0 +1,78 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+const testDigest = "sha256:a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4"
+
+func TestRemoveImage(t *testing.T) {
+	testCases := []struct {
+		name          string
+		force         bool
+		requests      int
+		expectedError string
+	}{
+		{name: "referenced", requests: 1, expectedError: "image " + testDigest + " is still referenced by the tags latest, v1"},
+		{name: "forced", force: true, requests: 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests []hubDeleteImagesRequest
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.Method+" "+r.URL.Path, "POST /v2/namespaces/jdoe/delete-images")
+				var request hubDeleteImagesRequest
+				assert.NilError(t, json.NewDecoder(r.Body).Decode(&request))
+				requests = append(requests, request)
+				if len(request.IgnoreWarnings) == 0 {
+					w.WriteHeader(http.StatusBadRequest)
+					_, _ = w.Write([]byte(`{"message": "image is referenced", "errinfo": {"type": "validation", "details": {"warnings": [{"repository": "app", "digest": "` + testDigest + `", "warning": "current_tag", "tags": ["v1", "latest"]}]}}}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"summary": {"manifest_deletes": 1, "tag_deletes": 2}}`))
+			}))
+
+			err := client.RemoveImage(context.Background(), "jdoe/app", testDigest, tc.force)
+			if tc.expectedError != "" {
+				assert.Error(t, err, tc.expectedError)
+				assert.Assert(t, IsImageReferencedError(err))
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.Equal(t, len(requests), tc.requests)
+			assert.DeepEqual(t, requests[0].Manifests, []hubImageManifest{{Repository: "app", Digest: testDigest}})
+			if tc.force {
+				assert.DeepEqual(t, requests[1].IgnoreWarnings, []hubDeleteImageWarning{
+					{Repository: "app", Digest: testDigest, Warning: "current_tag", Tags: []string{"v1", "latest"}},
+				})
+			}
+		})
+	}
+}
+
+func TestRemoveImageRejectsInvalidDigest(t *testing.T) {
+	client := newTestClient(t, http.NotFoundHandler())
+	err := client.RemoveImage(context.Background(), "jdoe/app", "sha256:beef", false)
+	assert.Error(t, err, `invalid digest "sha256:beef"`)
+}
@@ -17,7 +17,10 @@
 package hub
 
 import (
+	"fmt"
+	"net/url"
 	"os"
+	"strings"
 
 	"github.com/docker/docker/api/types/registry"
 )
@@ -60,3 +63,23 @@ func getInstance() *Instance {
 
 	return &hub
 }
+
+// ParseInstanceURL parses the base URL of a Hub compatible API, such as a
+// mirror or a test server. The scheme defaults to https.
+func ParseInstanceURL(instance string) (*url.URL, error) {
+	if !strings.Contains(instance, "://") {
+		instance = "https://" + instance
+	}
+	u, err := url.Parse(instance)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Hub instance %q: %s", instance, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid Hub instance %q: scheme must be http or https", instance)
+	}
+	if u.Host == "" || u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("invalid Hub instance %q: must be a base URL such as https://hub.example.com", instance)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return u, nil
+}
@@ -0,0 +1,55 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseInstanceURL(t *testing.T) {
+	testCases := []struct {
+		instance string
+		expected string
+		err      string
+	}{
+		{instance: "https://hub.example.com", expected: "https://hub.example.com"},
+		{instance: "hub.example.com:8443", expected: "https://hub.example.com:8443"},
+		{instance: "http://localhost:8080/api/", expected: "http://localhost:8080/api"},
+		{instance: "ftp://hub.example.com", err: `invalid Hub instance "ftp://hub.example.com": scheme must be http or https`},
+		{instance: "https://hub.example.com?page=1", err: `invalid Hub instance "https://hub.example.com?page=1": must be a base URL such as https://hub.example.com`},
+		{instance: "https://jdoe@hub.example.com", err: `invalid Hub instance "https://jdoe@hub.example.com": must be a base URL such as https://hub.example.com`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.instance, func(t *testing.T) {
+			u, err := ParseInstanceURL(tc.instance)
+			if tc.err != "" {
+				assert.Error(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, u.String(), tc.expected)
+		})
+	}
+}
+
+func TestWithInstance(t *testing.T) {
+	client, err := NewClient(WithInstance("hub.example.com/"))
+	assert.NilError(t, err)
+	assert.Equal(t, client.domain, "https://hub.example.com")
+}
@@ -0,0 +1,211 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	//InvitesURL path to the Hub API inviting users to organizations
+	InvitesURL = "/v2/invites/bulk"
+	//OrgInvitesURL path to the Hub API listing the pending invitations of an organization
+	OrgInvitesURL = "/v2/orgs/%s/invites/"
+	//InviteURL path to the Hub API managing an invitation
+	InviteURL = "/v2/invites/%s/"
+	//ResendInviteURL path to the Hub API sending an invitation again
+	ResendInviteURL = "/v2/invites/%s/resend/"
+
+	// invitee is the type of the members who haven't accepted their
+	// invitation yet
+	invitee = "invitee"
+	// invited is the status of the invitees the invitation was sent to
+	invited = "invited"
+)
+
+// Invitation is a pending invitation to join an organization
+type Invitation struct {
+	ID        string    `json:"id"`
+	Invitee   string    `json:"invitee"`
+	Inviter   string    `json:"inviter"`
+	Team      string    `json:"team,omitempty"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// InviteMember invites a user, given by its Docker ID or email, to join an
+// organization with a role. The user is added to the team, if any, after
+// accepting the invitation.
+func (c *Client) InviteMember(ctx context.Context, organization, team, role, user string) (*Invitation, error) {
+	data, err := json.Marshal(hubInvitesRequest{
+		Org:      organization,
+		Team:     team,
+		Role:     role,
+		Invitees: []hubInvitee{{Invitee: user}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.domain+InvitesURL, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, err
+	}
+	var hubResponse hubInvitesResponse
+	if err := json.Unmarshal(response, &hubResponse); err != nil {
+		return nil, err
+	}
+	// The invitations are sent one by one, an invitee can be refused while
+	// the request succeeds, e.g. when already a member
+	for _, result := range hubResponse.Invitees {
+		if result.Invitee != user {
+			continue
+		}
+		if result.Status != invited || result.Invite == nil {
+			return nil, fmt.Errorf("failed to invite %s: %s", user, result.Status)
+		}
+		invitation := toInvitation(*result.Invite)
+		return &invitation, nil
+	}
+	return nil, fmt.Errorf("failed to invite %s", user)
+}
+
+// GetInvitations lists the pending invitations of an organization
+func (c *Client) GetInvitations(ctx context.Context, organization string) ([]Invitation, error) {
+	u, err := url.Parse(c.domain + fmt.Sprintf(OrgInvitesURL, organization))
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	q.Add("page_size", fmt.Sprintf("%v", itemsPerPage))
+	q.Add("page", "1")
+	u.RawQuery = q.Encode()
+
+	invitations, next, err := c.getInvitationsPage(ctx, u.String())
+	if err != nil {
+		return nil, err
+	}
+
+	for next != "" {
+		pageInvitations, n, err := c.getInvitationsPage(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+		next = n
+		invitations = append(invitations, pageInvitations...)
+	}
+
+	return invitations, nil
+}
+
+// CancelInvitation deletes a pending invitation, given by its ID
+func (c *Client) CancelInvitation(ctx context.Context, id string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.domain+fmt.Sprintf(InviteURL, id), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.doRequest(req, withHubToken(c.token))
+	return err
+}
+
+// ResendInvitation sends a pending invitation, given by its ID, again
+func (c *Client) ResendInvitation(ctx context.Context, id string) error {
+	req, err := http.NewRequestWithContext(ctx, "PATCH", c.domain+fmt.Sprintf(ResendInviteURL, id), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.doRequest(req, withHubToken(c.token))
+	return err
+}
+
+func (c *Client) getInvitationsPage(ctx context.Context, url string) ([]Invitation, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, "", err
+	}
+	var hubResponse hubInvitationsResponse
+	if err := json.Unmarshal(response, &hubResponse); err != nil {
+		return nil, "", err
+	}
+	var invitations []Invitation
+	for _, result := range hubResponse.Results {
+		invitations = append(invitations, toInvitation(result))
+	}
+	return invitations, hubResponse.Next, nil
+}
+
+func toInvitation(result hubInvitation) Invitation {
+	return Invitation{
+		ID:        result.ID,
+		Invitee:   result.Invitee,
+		Inviter:   result.InviterUsername,
+		Team:      result.Team,
+		Role:      result.Role,
+		CreatedAt: result.CreatedAt,
+	}
+}
+
+type hubInvitesRequest struct {
+	Org      string       `json:"org"`
+	Team     string       `json:"team,omitempty"`
+	Role     string       `json:"role"`
+	Invitees []hubInvitee `json:"invitees"`
+}
+
+type hubInvitee struct {
+	Invitee string `json:"invitee"`
+}
+
+type hubInvitesResponse struct {
+	Invitees []hubInviteResult `json:"invitees"`
+}
+
+type hubInviteResult struct {
+	Invitee string         `json:"invitee"`
+	Status  string         `json:"status"`
+	Invite  *hubInvitation `json:"invite,omitempty"`
+}
+
+type hubInvitationsResponse struct {
+	Count    int             `json:"count"`
+	Next     string          `json:"next,omitempty"`
+	Previous string          `json:"previous,omitempty"`
+	Results  []hubInvitation `json:"results,omitempty"`
+}
+
+type hubInvitation struct {
+	ID              string    `json:"id"`
+	InviterUsername string    `json:"inviter_username"`
+	Invitee         string    `json:"invitee"`
+	Org             string    `json:"org"`
+	Team            string    `json:"team"`
+	Role            string    `json:"role"`
+	CreatedAt       time.Time `json:"created_at"`
+}
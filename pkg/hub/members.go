@@ -17,6 +17,7 @@
 package hub
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -29,16 +30,48 @@ const (
 	MembersURL = "/v2/orgs/%s/members/"
 	//MembersPerTeamURL path to the Hub API listing the members in a team
 	MembersPerTeamURL = "/v2/orgs/%s/groups/%s/members/"
+	//MemberURL path to the Hub API managing a member of an organization
+	MemberURL = "/v2/orgs/%s/members/%s/"
 )
 
 //Member is a user part of an organization
 type Member struct {
 	Username string `json:"username"`
 	FullName string `json:"full_name"`
+	Role     string `json:"role,omitempty"`
+	// Pending is set for the users invited to the organization who haven't
+	// accepted the invitation yet
+	Pending bool `json:"pending,omitempty"`
+}
+
+//WithMemberRole only lists the members with the given role
+func WithMemberRole(role string) RequestOp {
+	return func(req *http.Request) error {
+		values, err := url.ParseQuery(req.URL.RawQuery)
+		if err != nil {
+			return err
+		}
+		values.Set("role", role)
+		req.URL.RawQuery = values.Encode()
+		return nil
+	}
+}
+
+//WithPendingInvitations also lists the users invited to the organization
+func WithPendingInvitations() RequestOp {
+	return func(req *http.Request) error {
+		values, err := url.ParseQuery(req.URL.RawQuery)
+		if err != nil {
+			return err
+		}
+		values.Set("invites", "true")
+		req.URL.RawQuery = values.Encode()
+		return nil
+	}
 }
 
 //GetMembers lists all the members in an organization
-func (c *Client) GetMembers(organization string) ([]Member, error) {
+func (c *Client) GetMembers(ctx context.Context, organization string, reqOps ...RequestOp) ([]Member, error) {
 	u, err := url.Parse(c.domain + fmt.Sprintf(MembersURL, organization))
 	if err != nil {
 		return nil, err
@@ -48,13 +81,13 @@ func (c *Client) GetMembers(organization string) ([]Member, error) {
 	q.Add("page", "1")
 	u.RawQuery = q.Encode()
 
-	members, next, err := c.getMembersPage(u.String())
+	members, next, err := c.getMembersPage(ctx, u.String(), reqOps...)
 	if err != nil {
 		return nil, err
 	}
 
 	for next != "" {
-		pageMembers, n, err := c.getMembersPage(next)
+		pageMembers, n, err := c.getMembersPage(ctx, next, reqOps...)
 		if err != nil {
 			return nil, err
 		}
@@ -66,7 +99,7 @@ func (c *Client) GetMembers(organization string) ([]Member, error) {
 }
 
 // GetMembersCount return the number of members in an organization
-func (c *Client) GetMembersCount(organization string) (int, error) {
+func (c *Client) GetMembersCount(ctx context.Context, organization string) (int, error) {
 	u, err := url.Parse(c.domain + fmt.Sprintf(MembersURL, organization))
 	if err != nil {
 		return 0, err
@@ -76,7 +109,7 @@ func (c *Client) GetMembersCount(organization string) (int, error) {
 	q.Add("page", "1")
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return 0, err
 	}
@@ -92,9 +125,9 @@ func (c *Client) GetMembersCount(organization string) (int, error) {
 }
 
 // GetMembersPerTeam returns the members of a team in an organization
-func (c *Client) GetMembersPerTeam(organization, team string) ([]Member, error) {
+func (c *Client) GetMembersPerTeam(ctx context.Context, organization, team string) ([]Member, error) {
 	u := c.domain + fmt.Sprintf(MembersPerTeamURL, organization, team)
-	req, err := http.NewRequest("GET", u, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -109,12 +142,22 @@ func (c *Client) GetMembersPerTeam(organization, team string) ([]Member, error)
 	return members, nil
 }
 
-func (c *Client) getMembersPage(url string) ([]Member, string, error) {
-	req, err := http.NewRequest("GET", url, nil)
+//RemoveMember removes a user from an organization and all its teams
+func (c *Client) RemoveMember(ctx context.Context, organization, username string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.domain+fmt.Sprintf(MemberURL, organization, username), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.doRequest(req, withHubToken(c.token))
+	return err
+}
+
+func (c *Client) getMembersPage(ctx context.Context, url string, reqOps ...RequestOp) ([]Member, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, "", err
 	}
-	response, err := c.doRequest(req, withHubToken(c.token))
+	response, err := c.doRequest(req, append(reqOps, withHubToken(c.token))...)
 	if err != nil {
 		return nil, "", err
 	}
@@ -127,6 +170,8 @@ func (c *Client) getMembersPage(url string) ([]Member, string, error) {
 		member := Member{
 			Username: result.UserName,
 			FullName: result.FullName,
+			Role:     result.Role,
+			Pending:  result.Type == invitee,
 		}
 		members = append(members, member)
 	}
@@ -150,4 +195,5 @@ type hubMemberResult struct {
 	DateJoined  time.Time `json:"date_joined"`
 	ID          string    `json:"id"`
 	ProfileURL  string    `json:"profile_url"`
+	Role        string    `json:"role"`
 }
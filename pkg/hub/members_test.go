@@ -0,0 +1,104 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+)
+
+func TestGetMembersWithFilters(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method+" "+r.URL.Path, "GET /v2/orgs/myorg/members/")
+		assert.Equal(t, r.URL.Query().Get("role"), "owner")
+		assert.Equal(t, r.URL.Query().Get("invites"), "true")
+		_, _ = w.Write(golden.Get(t, "members.json"))
+	}))
+
+	members, err := client.GetMembers(context.Background(), "myorg", WithMemberRole("owner"), WithPendingInvitations())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, members, []Member{
+		{Username: "jdoe", FullName: "John Doe", Role: "owner"},
+		{Username: "jane@example.com", Role: "member", Pending: true},
+	})
+}
+
+func TestMemberManagement(t *testing.T) {
+	var requests []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method+" "+r.URL.Path == "POST /v2/invites/bulk" {
+			var body hubInvitesRequest
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.DeepEqual(t, body, hubInvitesRequest{
+				Org:      "myorg",
+				Team:     "developers",
+				Role:     "editor",
+				Invitees: []hubInvitee{{Invitee: "jdoe"}},
+			})
+			_, _ = w.Write(golden.Get(t, "invites.json"))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	invitation, err := client.InviteMember(context.Background(), "myorg", "developers", "editor", "jdoe")
+	assert.NilError(t, err)
+	assert.Equal(t, invitation.ID, "0b2f8c3e-6b4d-4c59-9a8e-2f1d5a0e7c11")
+	assert.NilError(t, client.ResendInvitation(context.Background(), invitation.ID))
+	assert.NilError(t, client.CancelInvitation(context.Background(), invitation.ID))
+	assert.NilError(t, client.RemoveMember(context.Background(), "myorg", "jdoe"))
+
+	assert.DeepEqual(t, requests, []string{
+		"POST /v2/invites/bulk",
+		"PATCH /v2/invites/0b2f8c3e-6b4d-4c59-9a8e-2f1d5a0e7c11/resend/",
+		"DELETE /v2/invites/0b2f8c3e-6b4d-4c59-9a8e-2f1d5a0e7c11/",
+		"DELETE /v2/orgs/myorg/members/jdoe/",
+	})
+}
+
+func TestInviteMemberRefused(t *testing.T) {
+	client := newTestClient(t, routes{
+		"POST /v2/invites/bulk": `{"invitees": [{"invitee": "jdoe", "status": "existing_org_member"}]}`,
+	})
+
+	_, err := client.InviteMember(context.Background(), "myorg", "", "member", "jdoe")
+	assert.Error(t, err, "failed to invite jdoe: existing_org_member")
+}
+
+func TestGetInvitations(t *testing.T) {
+	client := newTestClient(t, routes{
+		"GET /v2/orgs/myorg/invites/": `{"count": 1, "results": [{"id": "0b2f8c3e-6b4d-4c59-9a8e-2f1d5a0e7c11", "inviter_username": "admin", "invitee": "jdoe", "org": "myorg", "team": "developers", "role": "editor", "created_at": "2020-11-02T10:00:00Z"}]}`,
+	})
+
+	invitations, err := client.GetInvitations(context.Background(), "myorg")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, invitations, []Invitation{{
+		ID:        "0b2f8c3e-6b4d-4c59-9a8e-2f1d5a0e7c11",
+		Invitee:   "jdoe",
+		Inviter:   "admin",
+		Team:      "developers",
+		Role:      "editor",
+		CreatedAt: time.Date(2020, 11, 2, 10, 0, 0, 0, time.UTC),
+	}})
+}
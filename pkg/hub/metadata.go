@@ -0,0 +1,99 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ListResult holds the repositories of a listing along with how they were fetched
+type ListResult struct {
+	Repositories []Repository
+	Total        int
+	Metadata     FetchMetadata
+}
+
+// FetchMetadata describes when and under which rate limits a listing was fetched,
+// so that caches can decide how long to keep it
+type FetchMetadata struct {
+	// FetchedAt is the local time at which the first request was sent
+	FetchedAt time.Time
+	// ServerTime is the Date returned by Hub with the last response, zero if
+	// Hub didn't send one
+	ServerTime time.Time
+	// RateLimits are the API rate limits returned with the last response, nil if
+	// Hub didn't send any
+	RateLimits *RateLimits
+}
+
+// GetRepositoriesWithMeta behaves like GetRepositories and also returns the fetch metadata
+func (c *Client) GetRepositoriesWithMeta(ctx context.Context, account string, filters ...RepositoryFilter) (ListResult, error) {
+	recorder := &metadataRecorder{metadata: FetchMetadata{FetchedAt: time.Now()}}
+	repos, total, err := c.GetRepositories(context.WithValue(ctx, metadataRecorderKey{}, recorder), account, filters...)
+	if err != nil {
+		return ListResult{}, err
+	}
+	return ListResult{
+		Repositories: repos,
+		Total:        total,
+		Metadata:     recorder.metadata,
+	}, nil
+}
+
+type metadataRecorderKey struct{}
+
+type metadataRecorder struct {
+	mu       sync.Mutex
+	metadata FetchMetadata
+}
+
+// recordMetadata updates the fetch metadata recorded in the request context, if any
+func recordMetadata(req *http.Request, resp *http.Response) {
+	recorder, ok := req.Context().Value(metadataRecorderKey{}).(*metadataRecorder)
+	if !ok {
+		return
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil && date.After(recorder.metadata.ServerTime) {
+		recorder.metadata.ServerTime = date
+	}
+	if limits := parseAPIRateLimits(resp.Header); limits != nil {
+		recorder.metadata.RateLimits = limits
+	}
+}
+
+// parseAPIRateLimits reads the rate limit headers of the Hub API, which unlike
+// the registry ones don't carry a window
+func parseAPIRateLimits(header http.Header) *RateLimits {
+	limit, err := strconv.Atoi(header.Get("X-Ratelimit-Limit"))
+	if err != nil {
+		return nil
+	}
+	remaining, err := strconv.Atoi(header.Get("X-Ratelimit-Remaining"))
+	if err != nil {
+		return nil
+	}
+	return &RateLimits{
+		Limit:     &limit,
+		Remaining: &remaining,
+	}
+}
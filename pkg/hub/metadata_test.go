@@ -0,0 +1,54 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestGetRepositoriesWithMeta(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", "Tue, 03 Nov 2020 17:40:05 GMT")
+		w.Header().Set("X-Ratelimit-Limit", "180")
+		w.Header().Set("X-Ratelimit-Remaining", "179")
+		_, _ = w.Write([]byte(`{"count": 1, "results": [{"name": "app", "namespace": "jdoe"}]}`))
+	}))
+
+	before := time.Now()
+	result, err := client.GetRepositoriesWithMeta(context.Background(), "jdoe")
+	assert.NilError(t, err)
+	assert.Equal(t, result.Total, 1)
+	assert.DeepEqual(t, result.Repositories, []Repository{{Name: "jdoe/app"}})
+	assert.Assert(t, !result.Metadata.FetchedAt.Before(before))
+	assert.Assert(t, result.Metadata.ServerTime.Equal(time.Date(2020, 11, 3, 17, 40, 5, 0, time.UTC)))
+	assert.Equal(t, *result.Metadata.RateLimits.Limit, 180)
+	assert.Equal(t, *result.Metadata.RateLimits.Remaining, 179)
+}
+
+func TestParseAPIRateLimits(t *testing.T) {
+	header := http.Header{}
+	assert.Assert(t, parseAPIRateLimits(header) == nil)
+
+	header.Set("X-Ratelimit-Limit", "180")
+	header.Set("X-Ratelimit-Remaining", "unknown")
+	assert.Assert(t, parseAPIRateLimits(header) == nil)
+}
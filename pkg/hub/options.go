@@ -0,0 +1,91 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Options configures a client created with New. The zero value is a client of
+// Docker Hub without credentials, fetching the first page of the listings.
+type Options struct {
+	// Account is the name of the authenticated account
+	Account string
+	// Token is the bearer token of the account, as returned by Login
+	Token string
+	// RefreshToken is the token used to get a new bearer token
+	RefreshToken string
+	// Instance is the base URL of a Hub compatible API, Docker Hub when empty
+	Instance string
+	// AllElements makes the listings fetch all their pages
+	AllElements bool
+	// Concurrency is the number of requests sent concurrently, 4 when zero
+	Concurrency int
+	// Retries is the number of times a request failing with a transient error
+	// is sent again, 3 when nil
+	Retries *int
+	// CacheDir and CacheTTL enable a local cache of the read requests
+	CacheDir string
+	CacheTTL time.Duration
+	// CACert is a PEM file of certificate authorities to trust
+	CACert string
+	// RequestLogger is called after each request
+	RequestLogger RequestLogger
+	// Transport sends the requests instead of the default transport
+	Transport http.RoundTripper
+	// Middlewares wrap the transport, the first one seeing the requests first
+	Middlewares []Middleware
+	// UserAgent replaces the hub-tool User-Agent header
+	UserAgent string
+	// Logger replaces the logrus standard logger
+	Logger log.Ext1FieldLogger
+}
+
+// New returns a client configured with the options. It is the same as
+// NewClient with the matching ClientOps, which can still be given to Update.
+func New(opts Options) (*Client, error) {
+	ops := []ClientOp{
+		WithInstance(opts.Instance),
+		WithHubAccount(opts.Account),
+		WithHubToken(opts.Token),
+		WithRefreshToken(opts.RefreshToken),
+		WithCACert(opts.CACert),
+		WithRequestLogger(opts.RequestLogger),
+		WithMiddleware(opts.Middlewares...),
+		WithUserAgent(opts.UserAgent),
+		WithLogger(opts.Logger),
+	}
+	if opts.Transport != nil {
+		ops = append(ops, WithTransport(opts.Transport))
+	}
+	if opts.AllElements {
+		ops = append(ops, WithAllElements())
+	}
+	if opts.Concurrency != 0 {
+		ops = append(ops, WithConcurrency(opts.Concurrency))
+	}
+	if opts.Retries != nil {
+		ops = append(ops, WithRetries(*opts.Retries))
+	}
+	if opts.CacheDir != "" {
+		ops = append(ops, WithCache(opts.CacheDir, opts.CacheTTL))
+	}
+	return NewClient(ops...)
+}
@@ -0,0 +1,51 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestNew(t *testing.T) {
+	retries := 0
+	client, err := New(Options{
+		Account:     "jdoe",
+		Token:       "token",
+		Instance:    "https://hub.example.com",
+		AllElements: true,
+		Concurrency: 2,
+		Retries:     &retries,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, client.account, "jdoe")
+	assert.Equal(t, client.AuthConfig.Username, "jdoe")
+	assert.Equal(t, client.token, "token")
+	assert.Equal(t, client.domain, "https://hub.example.com")
+	assert.Assert(t, client.fetchAllElements)
+	assert.Equal(t, client.Concurrency(), 2)
+	assert.Equal(t, client.retries, 0)
+
+	client, err = New(Options{})
+	assert.NilError(t, err)
+	assert.Equal(t, client.Concurrency(), defaultConcurrency)
+	assert.Equal(t, client.retries, defaultRetries)
+
+	_, err = New(Options{Concurrency: -1})
+	assert.Error(t, err, "invalid concurrency -1, must be at least 1")
+}
@@ -73,12 +73,12 @@ func (c *Client) GetOrganizations(ctx context.Context) ([]Organization, error) {
 }
 
 //GetOrganizationInfo returns organization info
-func (c *Client) GetOrganizationInfo(orgname string) (*Account, error) {
+func (c *Client) GetOrganizationInfo(ctx context.Context, orgname string) (*Account, error) {
 	u, err := url.Parse(c.domain + fmt.Sprintf(OrganizationInfoURL, orgname))
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -102,7 +102,7 @@ func (c *Client) GetOrganizationInfo(orgname string) (*Account, error) {
 }
 
 func (c *Client) getOrganizationsPage(ctx context.Context, url string) ([]Organization, string, error) {
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, "", err
 	}
@@ -129,11 +129,11 @@ func (c *Client) getOrganizationsPage(ctx context.Context, url string) ([]Organi
 			subeg, _ := errgroup.WithContext(ctx)
 
 			subeg.Go(func() error {
-				teams, err = c.GetTeams(result.OrgName)
+				teams, err = c.GetTeams(ctx, result.OrgName)
 				return err
 			})
 			subeg.Go(func() error {
-				members, err = c.GetMembers(result.OrgName)
+				members, err = c.GetMembers(ctx, result.OrgName)
 				return err
 			})
 
@@ -0,0 +1,138 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const (
+	// OrganizationSettingsURL path to the Hub API managing the settings of an organization
+	OrganizationSettingsURL = "/v2/orgs/%s/settings"
+)
+
+// OrganizationSettings holds the settings of an organization
+type OrganizationSettings struct {
+	// DefaultVisibility is the visibility of the new repositories, "public"
+	// or "private"
+	DefaultVisibility string
+	// RestrictedImages limits the images the members can pull, the image
+	// access management
+	RestrictedImages RestrictedImages
+}
+
+// RestrictedImages tells which images the members of an organization can pull
+// when the image access management is enabled
+type RestrictedImages struct {
+	Enabled                 bool
+	AllowOfficialImages     bool
+	AllowVerifiedPublishers bool
+}
+
+// UpdateOrganizationSettingsOptions holds the settings to change, nil fields
+// are left unchanged
+type UpdateOrganizationSettingsOptions struct {
+	DefaultVisibility       *string
+	RestrictedImages        *bool
+	AllowOfficialImages     *bool
+	AllowVerifiedPublishers *bool
+}
+
+// GetOrganizationSettings returns the settings of an organization
+func (c *Client) GetOrganizationSettings(ctx context.Context, organization string) (*OrganizationSettings, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.domain+fmt.Sprintf(OrganizationSettingsURL, organization), nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, err
+	}
+	var result hubOrganizationSettings
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, err
+	}
+	return result.toSettings(), nil
+}
+
+// UpdateOrganizationSettings changes the given settings of an organization and
+// returns all its settings
+func (c *Client) UpdateOrganizationSettings(ctx context.Context, organization string, opts UpdateOrganizationSettingsOptions) (*OrganizationSettings, error) {
+	if opts.DefaultVisibility != nil && *opts.DefaultVisibility != "public" && *opts.DefaultVisibility != "private" {
+		return nil, fmt.Errorf("invalid default visibility %q, must be public or private", *opts.DefaultVisibility)
+	}
+	request := hubUpdateOrganizationSettings{DefaultVisibility: opts.DefaultVisibility}
+	if opts.RestrictedImages != nil || opts.AllowOfficialImages != nil || opts.AllowVerifiedPublishers != nil {
+		request.RestrictedImages = &hubUpdateRestrictedImages{
+			Enabled:                 opts.RestrictedImages,
+			AllowOfficialImages:     opts.AllowOfficialImages,
+			AllowVerifiedPublishers: opts.AllowVerifiedPublishers,
+		}
+	}
+	data, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "PATCH", c.domain+fmt.Sprintf(OrganizationSettingsURL, organization), bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, err
+	}
+	var result hubOrganizationSettings
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, err
+	}
+	return result.toSettings(), nil
+}
+
+type hubOrganizationSettings struct {
+	DefaultVisibility string `json:"default_repo_visibility"`
+	RestrictedImages  struct {
+		Enabled                 bool `json:"enabled"`
+		AllowOfficialImages     bool `json:"allow_official_images"`
+		AllowVerifiedPublishers bool `json:"allow_verified_publishers"`
+	} `json:"restricted_images"`
+}
+
+func (s hubOrganizationSettings) toSettings() *OrganizationSettings {
+	return &OrganizationSettings{
+		DefaultVisibility: s.DefaultVisibility,
+		RestrictedImages: RestrictedImages{
+			Enabled:                 s.RestrictedImages.Enabled,
+			AllowOfficialImages:     s.RestrictedImages.AllowOfficialImages,
+			AllowVerifiedPublishers: s.RestrictedImages.AllowVerifiedPublishers,
+		},
+	}
+}
+
+type hubUpdateOrganizationSettings struct {
+	DefaultVisibility *string                    `json:"default_repo_visibility,omitempty"`
+	RestrictedImages  *hubUpdateRestrictedImages `json:"restricted_images,omitempty"`
+}
+
+type hubUpdateRestrictedImages struct {
+	Enabled                 *bool `json:"enabled,omitempty"`
+	AllowOfficialImages     *bool `json:"allow_official_images,omitempty"`
+	AllowVerifiedPublishers *bool `json:"allow_verified_publishers,omitempty"`
+}
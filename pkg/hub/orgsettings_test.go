@@ -0,0 +1,63 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+const organizationSettings = `{
+  "default_repo_visibility": "private",
+  "restricted_images": {"enabled": true, "allow_official_images": true, "allow_verified_publishers": false}
+}`
+
+func TestGetOrganizationSettings(t *testing.T) {
+	client := newTestClient(t, routes{"GET /v2/orgs/myorg/settings": organizationSettings})
+	settings, err := client.GetOrganizationSettings(context.Background(), "myorg")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, settings, &OrganizationSettings{
+		DefaultVisibility: "private",
+		RestrictedImages:  RestrictedImages{Enabled: true, AllowOfficialImages: true},
+	})
+}
+
+func TestUpdateOrganizationSettings(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method+" "+r.URL.Path, "PATCH /v2/orgs/myorg/settings")
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NilError(t, err)
+		assert.Equal(t, string(body), `{"default_repo_visibility":"private","restricted_images":{"allow_verified_publishers":false}}`)
+		_, _ = w.Write([]byte(organizationSettings))
+	}))
+	visibility := "private"
+	allow := false
+	settings, err := client.UpdateOrganizationSettings(context.Background(), "myorg", UpdateOrganizationSettingsOptions{
+		DefaultVisibility:       &visibility,
+		AllowVerifiedPublishers: &allow,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, settings.DefaultVisibility, "private")
+
+	visibility = "secret"
+	_, err = client.UpdateOrganizationSettings(context.Background(), "myorg", UpdateOrganizationSettingsOptions{DefaultVisibility: &visibility})
+	assert.Error(t, err, `invalid default visibility "secret", must be public or private`)
+}
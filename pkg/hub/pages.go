@@ -0,0 +1,95 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"golang.org/x/sync/errgroup"
+)
+
+type allElementsKey struct{}
+
+// withAllElements makes the listings sent with the context fetch all their
+// pages, as WithAllElements does for all the listings of the client
+func withAllElements(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allElementsKey{}, true)
+}
+
+// fetchAll tells if a listing should fetch all its pages
+func (c *Client) fetchAll(ctx context.Context) bool {
+	return c.fetchAllElements || ctx.Value(allElementsKey{}) != nil
+}
+
+// pageFetcher fetches with the given context the page at the given URL, stores
+// it at the given index and returns the URL of the next page
+type pageFetcher func(ctx context.Context, index int, url string) (string, error)
+
+// forEachRemainingPage fetches the pages following the first one and returns
+// their number. With a client concurrency above 1, the page URLs are computed
+// from the total count given by the first page and a bounded pool of workers
+// fetches them concurrently, the first failure canceling the other requests.
+// Otherwise the next links are followed one by one. Either way, the index given
+// to fetch is the position of the page in the server order, starting at 0 for
+// the second page.
+func (c *Client) forEachRemainingPage(ctx context.Context, first *url.URL, total int, next string, fetch pageFetcher) (int, error) {
+	if next == "" {
+		return 0, nil
+	}
+	concurrency := c.maxConcurrentRequests()
+	if concurrency <= 1 {
+		count := 0
+		for next != "" {
+			n, err := fetch(ctx, count, next)
+			if err != nil {
+				return 0, err
+			}
+			next = n
+			count++
+		}
+		return count, nil
+	}
+
+	count := (total+itemsPerPage-1)/itemsPerPage - 1
+	if count < 1 {
+		count = 1
+	}
+	eg, egCtx := errgroup.WithContext(ctx)
+	sem := make(chan struct{}, concurrency)
+	for i := 0; i < count && egCtx.Err() == nil; i++ {
+		u := *first
+		q := u.Query()
+		q.Set("page", strconv.Itoa(i+2))
+		u.RawQuery = q.Encode()
+		select {
+		case <-egCtx.Done():
+		case sem <- struct{}{}:
+			i := i
+			eg.Go(func() error {
+				defer func() { <-sem }()
+				_, err := fetch(egCtx, i, u.String())
+				return err
+			})
+		}
+	}
+	if err := eg.Wait(); err != nil {
+		return 0, err
+	}
+	return count, ctx.Err()
+}
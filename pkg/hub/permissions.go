@@ -0,0 +1,193 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// RepositoryGroupsURL path to the Hub API listing the team permissions on a repository
+	RepositoryGroupsURL = "/v2/repositories/%s/groups/"
+	// RepositoryGroupURL path to the Hub API managing the permission of a team on a repository
+	RepositoryGroupURL = "/v2/repositories/%s/groups/%d/"
+)
+
+// Permission is the access level of a team on a repository
+type Permission string
+
+const (
+	// ReadPermission allows to pull the repository
+	ReadPermission = Permission("read")
+	// WritePermission allows to pull and push the repository
+	WritePermission = Permission("write")
+	// AdminPermission allows to pull, push and manage the repository
+	AdminPermission = Permission("admin")
+)
+
+// ParsePermission returns the permission matching the given name
+func ParsePermission(name string) (Permission, error) {
+	switch p := Permission(strings.ToLower(name)); p {
+	case ReadPermission, WritePermission, AdminPermission:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid permission %q, must be one of read, write or admin", name)
+	}
+}
+
+// TeamPermission is the permission granted to a team on a repository
+type TeamPermission struct {
+	Team       string
+	Permission Permission
+}
+
+// GetRepositoryPermissions lists the permissions granted to teams on an organization repository
+func (c *Client) GetRepositoryPermissions(ctx context.Context, repository string) ([]TeamPermission, error) {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(c.domain + fmt.Sprintf(RepositoryGroupsURL, repoPath))
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	q.Add("page_size", fmt.Sprintf("%v", itemsPerPage))
+	q.Add("page", "1")
+	u.RawQuery = q.Encode()
+
+	permissions, next, err := c.getRepositoryPermissionsPage(ctx, u.String())
+	if err != nil {
+		return nil, err
+	}
+	for next != "" {
+		pagePermissions, n, err := c.getRepositoryPermissionsPage(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+		next = n
+		permissions = append(permissions, pagePermissions...)
+	}
+	return permissions, nil
+}
+
+// GrantTeamPermission gives a team of the repository organization a permission on the repository
+func (c *Client) GrantTeamPermission(ctx context.Context, repository, team string, permission Permission) error {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return err
+	}
+	groupID, err := c.getTeamID(ctx, repoNamespace(repoPath), team)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(hubRepositoryGroupRequest{GroupID: groupID, Permission: permission})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.domain+fmt.Sprintf(RepositoryGroupsURL, repoPath), bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	_, err = c.doRequest(req, withHubToken(c.token))
+	return err
+}
+
+// RevokeTeamPermission removes any permission of a team on the repository
+func (c *Client) RevokeTeamPermission(ctx context.Context, repository, team string) error {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return err
+	}
+	groupID, err := c.getTeamID(ctx, repoNamespace(repoPath), team)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.domain+fmt.Sprintf(RepositoryGroupURL, repoPath, groupID), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.doRequest(req, withHubToken(c.token))
+	return err
+}
+
+func (c *Client) getTeamID(ctx context.Context, organization, team string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.domain+fmt.Sprintf(GroupURL, organization, team), nil)
+	if err != nil {
+		return 0, err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return 0, err
+	}
+	var result hubGroupResult
+	if err := json.Unmarshal(response, &result); err != nil {
+		return 0, err
+	}
+	return result.ID, nil
+}
+
+func (c *Client) getRepositoryPermissionsPage(ctx context.Context, url string) ([]TeamPermission, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, "", err
+	}
+	var hubResponse hubRepositoryGroupResponse
+	if err := json.Unmarshal(response, &hubResponse); err != nil {
+		return nil, "", err
+	}
+	var permissions []TeamPermission
+	for _, result := range hubResponse.Results {
+		permissions = append(permissions, TeamPermission{
+			Team:       result.GroupName,
+			Permission: result.Permission,
+		})
+	}
+	return permissions, hubResponse.Next, nil
+}
+
+func repoNamespace(repoPath string) string {
+	return strings.SplitN(repoPath, "/", 2)[0]
+}
+
+type hubRepositoryGroupRequest struct {
+	GroupID    int        `json:"group_id"`
+	Permission Permission `json:"permission"`
+}
+
+type hubRepositoryGroupResponse struct {
+	Count    int                        `json:"count"`
+	Next     string                     `json:"next,omitempty"`
+	Previous string                     `json:"previous,omitempty"`
+	Results  []hubRepositoryGroupResult `json:"results,omitempty"`
+}
+
+type hubRepositoryGroupResult struct {
+	GroupID    int        `json:"group_id"`
+	GroupName  string     `json:"group_name"`
+	Permission Permission `json:"permission"`
+}
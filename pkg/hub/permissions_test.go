@@ -0,0 +1,83 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+)
+
+func TestRepositoryPermissions(t *testing.T) {
+	var granted hubRepositoryGroupRequest
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v2/repositories/myorg/app/groups/":
+			_, _ = w.Write(golden.Get(t, "repository-groups.json"))
+		case "GET /v2/orgs/myorg/groups/developers/":
+			_, _ = w.Write(golden.Get(t, "group.json"))
+		case "POST /v2/repositories/myorg/app/groups/":
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(&granted))
+			w.WriteHeader(http.StatusCreated)
+		case "DELETE /v2/repositories/myorg/app/groups/1234567/":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	permissions, err := client.GetRepositoryPermissions(context.Background(), "myorg/app")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, permissions, []TeamPermission{
+		{Team: "owners", Permission: AdminPermission},
+		{Team: "developers", Permission: WritePermission},
+	})
+
+	assert.NilError(t, client.GrantTeamPermission(context.Background(), "myorg/app", "developers", WritePermission))
+	assert.Equal(t, granted, hubRepositoryGroupRequest{GroupID: 1234567, Permission: WritePermission})
+
+	assert.NilError(t, client.RevokeTeamPermission(context.Background(), "myorg/app", "developers"))
+}
+
+func TestParsePermission(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected Permission
+		err      string
+	}{
+		{name: "read", expected: ReadPermission},
+		{name: "Write", expected: WritePermission},
+		{name: "ADMIN", expected: AdminPermission},
+		{name: "owner", err: `invalid permission "owner", must be one of read, write or admin`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			permission, err := ParsePermission(tc.name)
+			if tc.err != "" {
+				assert.Error(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, permission, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,187 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	//HubPlanURL path to the billing API returning the account hub plan
+	HubPlanURL = "/api/billing/v4/accounts/%s/hub-plan"
+	//SubscriptionURL path to the billing API returning the account subscription
+	SubscriptionURL = "/api/billing/v4/accounts/%s/subscription"
+	//TeamPlan refers to a hub team paid account
+	TeamPlan = "team"
+	//ProPlan refers to a hub individual paid account
+	ProPlan = "pro"
+	//FreePlan refers to a hub non-paid account
+	FreePlan = "free"
+)
+
+//Plan represents the current account Hub plan
+type Plan struct {
+	Name   string
+	Limits Limits
+}
+
+//Limits represents the current account limits
+type Limits struct {
+	Seats          int
+	PrivateRepos   int
+	Teams          int
+	Collaborators  int
+	ParallelBuilds int
+}
+
+//Subscription represents what an account pays for its Hub plan
+type Subscription struct {
+	// Plan is the tier of the subscription, e.g. team
+	Plan string
+	// Seats is the number of purchased seats
+	Seats int
+	// Cycle is the billing cycle, monthly or annual
+	Cycle string
+	// RenewalDate is when the subscription renews, zero for the free plan
+	RenewalDate time.Time
+}
+
+//OrganizationPlan represents the plan of an organization with its seat usage
+type OrganizationPlan struct {
+	Organization string
+	Plan         Plan
+	Subscription Subscription
+	// UsedSeats is the number of members of the organization
+	UsedSeats int
+}
+
+//GetHubPlan returns an account current Hub plan
+func (c *Client) GetHubPlan(ctx context.Context, accountID string) (*Plan, error) {
+	u, err := url.Parse(c.domain + fmt.Sprintf(HubPlanURL, accountID))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, err
+	}
+	var hubResponse hubPlanResponse
+	if err := json.Unmarshal(response, &hubResponse); err != nil {
+		return nil, err
+	}
+	return &Plan{
+		Name: hubResponse.Name,
+		Limits: Limits{
+			Seats:          hubResponse.Seats,
+			PrivateRepos:   hubResponse.PrivateRepos,
+			Teams:          hubResponse.Teams,
+			Collaborators:  hubResponse.Collaborators,
+			ParallelBuilds: hubResponse.ParallelBuilds,
+		},
+	}, nil
+}
+
+type hubPlanResponse struct {
+	Name           string `json:"name"`
+	Legacy         bool   `json:"legacy"`
+	Seats          int    `json:"seats"`
+	PrivateRepos   int    `json:"private_repos"`
+	Teams          int    `json:"teams"`
+	Collaborators  int    `json:"collaborators"`
+	ParallelBuilds int    `json:"parallel_builds"`
+	Duration       string `json:"duration"`
+}
+
+//GetSubscription returns an account current subscription
+func (c *Client) GetSubscription(ctx context.Context, accountID string) (*Subscription, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.domain+fmt.Sprintf(SubscriptionURL, accountID), nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, err
+	}
+	var hubResponse hubSubscriptionResponse
+	if err := json.Unmarshal(response, &hubResponse); err != nil {
+		return nil, err
+	}
+	return &Subscription{
+		Plan:        hubResponse.Plan,
+		Seats:       hubResponse.Quantity,
+		Cycle:       hubResponse.Cycle,
+		RenewalDate: hubResponse.RenewalDate,
+	}, nil
+}
+
+//GetOrganizationPlan returns the plan, the subscription and the seat usage of
+//an organization
+func (c *Client) GetOrganizationPlan(ctx context.Context, organization string) (*OrganizationPlan, error) {
+	org, err := c.GetOrganizationInfo(ctx, organization)
+	if err != nil {
+		return nil, err
+	}
+	result := OrganizationPlan{Organization: org.Name}
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		plan, err := c.GetHubPlan(ctx, org.ID)
+		if err != nil {
+			return err
+		}
+		result.Plan = *plan
+		return nil
+	})
+	eg.Go(func() error {
+		subscription, err := c.GetSubscription(ctx, org.ID)
+		if err != nil {
+			return err
+		}
+		result.Subscription = *subscription
+		return nil
+	})
+	eg.Go(func() error {
+		count, err := c.GetMembersCount(ctx, organization)
+		if err != nil {
+			return err
+		}
+		result.UsedSeats = count
+		return nil
+	})
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+type hubSubscriptionResponse struct {
+	Plan        string    `json:"plan"`
+	Quantity    int       `json:"quantity"`
+	Cycle       string    `json:"cycle"`
+	RenewalDate time.Time `json:"renewal_date"`
+}
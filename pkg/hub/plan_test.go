@@ -0,0 +1,46 @@
+/*
+Copyright 2020 Docker Hub Tool authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package hub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestGetOrganizationPlan(t *testing.T) {
+	client := newTestClient(t, routes{
+		"GET /v2/orgs/myorg":                            `{"id": "123", "orgname": "myorg"}`,
+		"GET /api/billing/v4/accounts/123/hub-plan":     `{"name": "team", "seats": 10, "private_repos": 9999}`,
+		"GET /api/billing/v4/accounts/123/subscription": `{"plan": "team", "quantity": 10, "cycle": "annual", "renewal_date": "2021-03-01T00:00:00Z"}`,
+		"GET /v2/orgs/myorg/members/":                   `{"count": 7}`,
+	})
+	plan, err := client.GetOrganizationPlan(context.Background(), "myorg")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, plan, &OrganizationPlan{
+		Organization: "myorg",
+		Plan:         Plan{Name: "team", Limits: Limits{Seats: 10, PrivateRepos: 9999}},
+		Subscription: Subscription{
+			Plan:        "team",
+			Seats:       10,
+			Cycle:       "annual",
+			RenewalDate: time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		UsedSeats: 7,
+	})
+}
@@ -17,6 +17,7 @@
 package hub
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -41,13 +42,32 @@ const (
 )
 
 // GetRateLimits returns the rate limits for the authenticated user
-func (c *Client) GetRateLimits() (*RateLimits, error) {
-	token, err := tryGetToken(c)
+func (c *Client) GetRateLimits(ctx context.Context) (*RateLimits, error) {
+	token, err := tryGetToken(ctx, c)
 	if err != nil {
 		return nil, err
 	}
+	return c.getRegistryRateLimits(ctx, token)
+}
 
-	req, err := http.NewRequest("HEAD", second, nil)
+// GetAnonymousRateLimits returns the rate limits applying to anonymous pulls
+// from the current IP address
+func (c *Client) GetAnonymousRateLimits(ctx context.Context) (*RateLimits, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", first, nil)
+	if err != nil {
+		return nil, err
+	}
+	token, err := c.requestRegistryToken(req)
+	if err != nil {
+		return nil, err
+	}
+	return c.getRegistryRateLimits(ctx, token)
+}
+
+// getRegistryRateLimits reads the rate limits returned by the registry on a
+// manifest HEAD request, which doesn't count as a pull
+func (c *Client) getRegistryRateLimits(ctx context.Context, token string) (*RateLimits, error) {
+	req, err := http.NewRequestWithContext(ctx, "HEAD", second, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -82,12 +102,12 @@ func (c *Client) GetRateLimits() (*RateLimits, error) {
 	}, nil
 }
 
-func tryGetToken(c *Client) (string, error) {
-	token, err := c.getToken(c.password)
+func tryGetToken(ctx context.Context, c *Client) (string, error) {
+	token, err := c.getToken(ctx, c.password)
 	if err != nil {
-		token, err = c.getToken(c.refreshToken)
+		token, err = c.getToken(ctx, c.refreshToken)
 		if err != nil {
-			token, err = c.getToken(c.token)
+			token, err = c.getToken(ctx, c.token)
 			if err != nil {
 				return "", err
 			}
@@ -96,13 +116,17 @@ func tryGetToken(c *Client) (string, error) {
 	return token, nil
 }
 
-func (c *Client) getToken(password string) (string, error) {
-	req, err := http.NewRequest("GET", first, nil)
+func (c *Client) getToken(ctx context.Context, password string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", first, nil)
 	if err != nil {
 		return "", err
 	}
 
 	req.Header.Add("Authorization", "Basic "+basicAuth(c.account, password))
+	return c.requestRegistryToken(req)
+}
+
+func (c *Client) requestRegistryToken(req *http.Request) (string, error) {
 	resp, err := c.doRawRequest(req)
 	if err != nil {
 		return "", err
@@ -17,23 +17,33 @@
 package hub
 
 import (
-	"errors"
 	"testing"
 
 	"gotest.tools/v3/assert"
 )
 
-func TestIsAuthenticationError(t *testing.T) {
-	assert.Assert(t, IsAuthenticationError(&authenticationError{}))
-	assert.Assert(t, !IsAuthenticationError(errors.New("")))
-}
-
-func TestIsInvalidTokenError(t *testing.T) {
-	assert.Assert(t, IsInvalidTokenError(&invalidTokenError{}))
-	assert.Assert(t, !IsInvalidTokenError(errors.New("")))
-}
-
-func TestIsForbiddenError(t *testing.T) {
-	assert.Assert(t, IsForbiddenError(&forbiddenError{}))
-	assert.Assert(t, !IsForbiddenError(errors.New("")))
+func TestParseLimitHeader(t *testing.T) {
+	testCases := []struct {
+		header string
+		value  int
+		window int
+		err    string
+	}{
+		{header: "100;w=21600", value: 100, window: 21600},
+		{header: "76;w=21600", value: 76, window: 21600},
+		{header: "100", err: "bad limit header 100"},
+		{header: "100;21600", err: "bad limit header 100;21600"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.header, func(t *testing.T) {
+			value, window, err := parseLimitHeader(tc.header)
+			if tc.err != "" {
+				assert.Error(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, value, tc.value)
+			assert.Equal(t, window, tc.window)
+		})
+	}
 }
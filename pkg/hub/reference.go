@@ -0,0 +1,84 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"strings"
+
+	"github.com/docker/distribution/reference"
+)
+
+// Reference is an image reference split in its components, normalized the
+// same way the Docker CLI does
+type Reference struct {
+	Registry   string
+	Namespace  string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// NormalizeReference parses a short image reference like "alpine" or
+// "bitnami/nginx:1.19" and returns its canonical form, defaulting the registry
+// to docker.io, the namespace to library and the tag to latest when no digest
+// is given
+func NormalizeReference(ref string) (Reference, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return Reference{}, err
+	}
+	named = reference.TagNameOnly(named)
+
+	result := Reference{
+		Registry: reference.Domain(named),
+	}
+	path := reference.Path(named)
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		result.Namespace = path[:i]
+		result.Repository = path[i+1:]
+	} else {
+		result.Repository = path
+	}
+	if tagged, ok := named.(reference.Tagged); ok {
+		result.Tag = tagged.Tag()
+	}
+	if digested, ok := named.(reference.Digested); ok {
+		result.Digest = digested.Digest().String()
+	}
+	return result, nil
+}
+
+// Name returns the repository name including its namespace, as used by the
+// Hub API
+func (r Reference) Name() string {
+	if r.Namespace == "" {
+		return r.Repository
+	}
+	return r.Namespace + "/" + r.Repository
+}
+
+// String returns the fully qualified reference
+func (r Reference) String() string {
+	s := r.Registry + "/" + r.Name()
+	if r.Tag != "" {
+		s += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		s += "@" + r.Digest
+	}
+	return s
+}
@@ -0,0 +1,106 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+const digest = "sha256:2c4980f5700c775634dd997484834ba0c6f63c5e2384d22c23c067afec8f2596"
+
+func TestNormalizeReference(t *testing.T) {
+	testCases := []struct {
+		name          string
+		ref           string
+		expected      Reference
+		expectedName  string
+		expectedError string
+	}{
+		{
+			name:         "official image",
+			ref:          "alpine",
+			expected:     Reference{Registry: "docker.io", Namespace: "library", Repository: "alpine", Tag: "latest"},
+			expectedName: "docker.io/library/alpine:latest",
+		},
+		{
+			name:         "official image with tag",
+			ref:          "alpine:3.12",
+			expected:     Reference{Registry: "docker.io", Namespace: "library", Repository: "alpine", Tag: "3.12"},
+			expectedName: "docker.io/library/alpine:3.12",
+		},
+		{
+			name:         "user image",
+			ref:          "bitnami/nginx",
+			expected:     Reference{Registry: "docker.io", Namespace: "bitnami", Repository: "nginx", Tag: "latest"},
+			expectedName: "docker.io/bitnami/nginx:latest",
+		},
+		{
+			name:         "fully qualified",
+			ref:          "docker.io/library/alpine:edge",
+			expected:     Reference{Registry: "docker.io", Namespace: "library", Repository: "alpine", Tag: "edge"},
+			expectedName: "docker.io/library/alpine:edge",
+		},
+		{
+			name:         "digest only keeps no tag",
+			ref:          "alpine@" + digest,
+			expected:     Reference{Registry: "docker.io", Namespace: "library", Repository: "alpine", Digest: digest},
+			expectedName: "docker.io/library/alpine@" + digest,
+		},
+		{
+			name:         "tag and digest",
+			ref:          "bitnami/nginx:1.19@" + digest,
+			expected:     Reference{Registry: "docker.io", Namespace: "bitnami", Repository: "nginx", Tag: "1.19", Digest: digest},
+			expectedName: "docker.io/bitnami/nginx:1.19@" + digest,
+		},
+		{
+			name:         "other registry with nested path",
+			ref:          "ghcr.io/org/team/app:v1",
+			expected:     Reference{Registry: "ghcr.io", Namespace: "org/team", Repository: "app", Tag: "v1"},
+			expectedName: "ghcr.io/org/team/app:v1",
+		},
+		{
+			name:         "registry with port",
+			ref:          "localhost:5000/app",
+			expected:     Reference{Registry: "localhost:5000", Repository: "app", Tag: "latest"},
+			expectedName: "localhost:5000/app:latest",
+		},
+		{
+			name:          "uppercase is invalid",
+			ref:           "Alpine",
+			expectedError: "invalid reference format: repository name must be lowercase",
+		},
+		{
+			name:          "empty reference",
+			ref:           "",
+			expectedError: "invalid reference format",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			actual, err := NormalizeReference(testCase.ref)
+			if testCase.expectedError != "" {
+				assert.Error(t, err, testCase.expectedError)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, actual, testCase.expected)
+			assert.Equal(t, actual.String(), testCase.expectedName)
+		})
+	}
+}
@@ -0,0 +1,148 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// RegistryAuthURL is the token server of the Docker Hub registry
+	RegistryAuthURL = "https://auth.docker.io/token"
+	// RegistryService is the service the registry tokens are issued for
+	RegistryService = "registry.docker.io"
+
+	// defaultRegistryTokenExpiry is the lifetime of the tokens returned
+	// without it, as per the token authentication specification
+	defaultRegistryTokenExpiry = 60 * time.Second
+)
+
+// RegistryTokenScopes lists the actions a registry token can be requested for
+var RegistryTokenScopes = []string{"pull", "push", "pull,push"}
+
+// RegistryToken is a short-lived bearer token to authenticate the requests
+// sent to the registry
+type RegistryToken struct {
+	Token     string
+	ExpiresIn time.Duration
+	IssuedAt  time.Time
+}
+
+// ExpiresAt returns when the token expires
+func (t RegistryToken) ExpiresAt() time.Time {
+	return t.IssuedAt.Add(t.ExpiresIn)
+}
+
+// RegistryToken exchanges the credentials of the client for a registry token
+// allowing the actions of the scope, "pull", "push" or "pull,push", on a
+// repository. Without credentials, an anonymous token is returned, which can
+// only pull the public repositories.
+func (c *Client) RegistryToken(ctx context.Context, repository, scope string) (*RegistryToken, error) {
+	if !isRegistryTokenScope(scope) {
+		return nil, fmt.Errorf("invalid scope %q, must be one of pull, push or pull,push", scope)
+	}
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	q.Set("service", RegistryService)
+	q.Set("scope", fmt.Sprintf("repository:%s:%s", repoPath, scope))
+	u := RegistryAuthURL + "?" + q.Encode()
+
+	var secrets []string
+	for _, secret := range []string{c.password, c.refreshToken, c.token} {
+		if secret != "" {
+			secrets = append(secrets, secret)
+		}
+	}
+	if len(secrets) == 0 {
+		return c.exchangeRegistryToken(ctx, u, "")
+	}
+	// Try each credential in turn, as the password may have been replaced by
+	// a token, or the token may have expired
+	var token *RegistryToken
+	for _, secret := range secrets {
+		token, err = c.exchangeRegistryToken(ctx, u, secret)
+		if err == nil {
+			return token, nil
+		}
+	}
+	return nil, err
+}
+
+func (c *Client) exchangeRegistryToken(ctx context.Context, u, secret string) (*RegistryToken, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if secret != "" {
+		req.Header.Add("Authorization", "Basic "+basicAuth(c.account, secret))
+	}
+	resp, err := c.doRawRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	buf, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status, Message: extractMessage(buf)}
+	}
+	var result hubRegistryTokenResponse
+	if err := json.Unmarshal(buf, &result); err != nil {
+		return nil, err
+	}
+	token := RegistryToken{
+		Token:     result.Token,
+		ExpiresIn: time.Duration(result.ExpiresIn) * time.Second,
+		IssuedAt:  result.IssuedAt,
+	}
+	if token.Token == "" {
+		token.Token = result.AccessToken
+	}
+	if token.ExpiresIn == 0 {
+		token.ExpiresIn = defaultRegistryTokenExpiry
+	}
+	if token.IssuedAt.IsZero() {
+		token.IssuedAt = time.Now()
+	}
+	return &token, nil
+}
+
+func isRegistryTokenScope(scope string) bool {
+	for _, s := range RegistryTokenScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type hubRegistryTokenResponse struct {
+	Token       string    `json:"token"`
+	AccessToken string    `json:"access_token"`
+	ExpiresIn   int       `json:"expires_in"`
+	IssuedAt    time.Time `json:"issued_at"`
+}
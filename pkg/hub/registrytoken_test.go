@@ -0,0 +1,79 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package hub
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRegistryToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Path, "/token")
+		assert.Equal(t, r.URL.Query().Get("service"), "registry.docker.io")
+		assert.Equal(t, r.URL.Query().Get("scope"), "repository:library/alpine:pull")
+		if user, password, ok := r.BasicAuth(); ok && (user != "me" || password != "secret") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"token": "registry-token", "expires_in": 300, "issued_at": "2021-01-05T20:50:48Z"}`))
+	}))
+	defer server.Close()
+	toServer := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			u, _ := url.Parse(server.URL)
+			req.URL.Scheme, req.URL.Host = u.Scheme, u.Host
+			return next.RoundTrip(req)
+		})
+	}
+
+	testCases := []struct {
+		name string
+		ops  []ClientOp
+		err  string
+	}{
+		{name: "anonymous"},
+		{name: "password", ops: []ClientOp{WithHubAccount("me"), WithPassword("secret")}},
+		{name: "next credential", ops: []ClientOp{WithHubAccount("me"), WithRefreshToken("expired"), WithHubToken("secret")}},
+		{name: "wrong password", ops: []ClientOp{WithHubAccount("me"), WithPassword("wrong")}, err: "401 Unauthorized"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := NewClient(append(tc.ops, WithMiddleware(toServer), WithRetries(0))...)
+			assert.NilError(t, err)
+			token, err := client.RegistryToken(context.Background(), "alpine", "pull")
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, token.Token, "registry-token")
+			assert.Equal(t, token.ExpiresAt().Format("15:04:05"), "20:55:48")
+		})
+	}
+}
+
+func TestRegistryTokenRejectsInvalidScope(t *testing.T) {
+	client, err := NewClient()
+	assert.NilError(t, err)
+	_, err = client.RegistryToken(context.Background(), "alpine", "delete")
+	assert.Error(t, err, `invalid scope "delete", must be one of pull, push or pull,push`)
+}
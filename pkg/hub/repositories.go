@@ -0,0 +1,655 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// RepositoriesURL path to the Hub API listing the repositories
+	RepositoriesURL = "/v2/repositories/%s/"
+	// RepositoryURL path to the Hub API returning a single repository
+	RepositoryURL = "/v2/repositories/%s/"
+	// DeleteRepositoryURL path to the Hub API to remove a repository
+	DeleteRepositoryURL = "/v2/repositories/%s/"
+	// CreateRepositoryURL path to the Hub API to create a repository
+	CreateRepositoryURL = "/v2/repositories/"
+	// RepositoryPrivacyURL path to the Hub API to change the visibility of a repository
+	RepositoryPrivacyURL = "/v2/repositories/%s/privacy/"
+	//RepositoryTransferURL path to the Hub API moving a repository to another namespace
+	RepositoryTransferURL = "/v2/repositories/%s/transfer/"
+	//RepositoryRenameURL path to the Hub API renaming a repository in its namespace
+	RepositoryRenameURL = "/v2/repositories/%s/rename/"
+)
+
+//Repository represents a Docker Hub repository
+type Repository struct {
+	Name        string
+	Description string
+	// FullDescription is the overview of the repository, in markdown. It is
+	// only returned by GetRepository
+	FullDescription string
+	LastUpdated     time.Time
+	PullCount       int
+	StarCount       int
+	IsPrivate       bool
+	// StorageSize is the size of the images stored in the repository, in bytes
+	StorageSize int64
+	// User is the last user who pushed to the repository
+	User string
+	// Categories are only returned by GetRepository
+	Categories []Category
+	// OwnerType is only set after calling ResolveOwnerTypes
+	OwnerType OwnerType
+}
+
+//OwnerType tells if a repository belongs to a user or an organization
+type OwnerType string
+
+const (
+	//UserOwner is a repository owned by a user
+	UserOwner = OwnerType("user")
+	//OrganizationOwner is a repository owned by an organization
+	OrganizationOwner = OwnerType("organization")
+)
+
+//RepositoryFilter selects the repositories to return when listing
+type RepositoryFilter func(Repository) bool
+
+//GetRepositories lists all the repositories a user can access, ordered by last
+// update. When filters are given, all the pages are fetched and only the
+// repositories matching all the filters are returned, the total being the number
+// of matching repositories.
+// The order doesn't depend on the client concurrency: pages fetched concurrently
+// are merged back in the server order.
+func (c *Client) GetRepositories(ctx context.Context, account string, filters ...RepositoryFilter) ([]Repository, int, error) {
+	if account == "" {
+		account = c.account
+	}
+	u, err := url.Parse(c.domain + fmt.Sprintf(RepositoriesURL, account))
+	if err != nil {
+		return nil, 0, err
+	}
+	q := url.Values{}
+	q.Add("page_size", fmt.Sprintf("%v", itemsPerPage))
+	q.Add("page", "1")
+	q.Add("ordering", "last_updated")
+	u.RawQuery = q.Encode()
+
+	repos, total, next, err := c.getRepositoriesPage(ctx, u.String(), account)
+	if err != nil {
+		return nil, 0, err
+	}
+	repos = filterRepositories(repos, filters)
+
+	if c.fetchAll(ctx) || len(filters) > 0 {
+		var mu sync.Mutex
+		pages := map[int][]Repository{}
+		count, err := c.forEachRemainingPage(ctx, u, total, next, func(ctx context.Context, index int, url string) (string, error) {
+			pageRepos, _, n, err := c.getRepositoriesPage(ctx, url, account)
+			if err != nil {
+				return "", err
+			}
+			mu.Lock()
+			pages[index] = filterRepositories(pageRepos, filters)
+			mu.Unlock()
+			return n, nil
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		for i := 0; i < count; i++ {
+			repos = append(repos, pages[i]...)
+		}
+	}
+
+	if len(filters) > 0 {
+		total = len(repos)
+	}
+	return repos, total, nil
+}
+
+//RepositoriesIter calls fn for each repository of an account, fetching the
+// pages one at a time as they are consumed. Returning ErrStopIteration from fn
+// stops the iteration without error. A page size of 0 uses the default one.
+// The total number of repositories is returned.
+func (c *Client) RepositoriesIter(ctx context.Context, account string, pageSize int, fn func(Repository) error) (int, error) {
+	if account == "" {
+		account = c.account
+	}
+	if pageSize == 0 {
+		pageSize = itemsPerPage
+	}
+	u, err := url.Parse(c.domain + fmt.Sprintf(RepositoriesURL, account))
+	if err != nil {
+		return 0, err
+	}
+	q := url.Values{}
+	q.Add("page_size", fmt.Sprintf("%v", pageSize))
+	q.Add("page", "1")
+	q.Add("ordering", "last_updated")
+	u.RawQuery = q.Encode()
+
+	total := 0
+	for next := u.String(); next != ""; {
+		var repos []Repository
+		repos, total, next, err = c.getRepositoriesPage(ctx, next, account)
+		if err != nil {
+			return 0, err
+		}
+		for _, repo := range repos {
+			if err := fn(repo); err != nil {
+				if errors.Is(err, ErrStopIteration) {
+					return total, nil
+				}
+				return total, err
+			}
+		}
+	}
+	return total, nil
+}
+
+func filterRepositories(repos []Repository, filters []RepositoryFilter) []Repository {
+	if len(filters) == 0 {
+		return repos
+	}
+	filtered := []Repository{}
+	for _, repo := range repos {
+		if matchesAll(repo, filters) {
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered
+}
+
+func matchesAll(repo Repository, filters []RepositoryFilter) bool {
+	for _, filter := range filters {
+		if !filter(repo) {
+			return false
+		}
+	}
+	return true
+}
+
+//GetRepositoriesByName fetches concurrently the given repositories. The repositories
+// which could be fetched are always returned, along with an error listing the
+// ones which failed.
+// The context deadline bounds the whole operation and not each request: once it
+// expires, in-flight requests are canceled, no other repository is fetched and
+// the context error is returned with the partial result. As every request is
+// bound to this context, retrying a request can't extend the overall deadline.
+func (c *Client) GetRepositoriesByName(ctx context.Context, repositories []string) ([]Repository, error) {
+	result := make([]*Repository, len(repositories))
+	errs := c.forEachConcurrently(ctx, len(repositories), func(i int) error {
+		repo, err := c.GetRepository(ctx, repositories[i])
+		result[i] = repo
+		return err
+	})
+
+	repos := []Repository{}
+	for _, repo := range result {
+		if repo != nil {
+			repos = append(repos, *repo)
+		}
+	}
+	if ctx.Err() != nil {
+		return repos, ctx.Err()
+	}
+	return repos, bulkError("failed to fetch %d repositories", repositories, errs)
+}
+
+//SumPullCounts returns the total pull count of the given repositories. If some
+// repositories can't be fetched, the sum of the others is returned with an error.
+func (c *Client) SumPullCounts(ctx context.Context, repositories []string) (int64, error) {
+	repos, err := c.GetRepositoriesByName(ctx, repositories)
+	var total int64
+	for _, repo := range repos {
+		total += int64(repo.PullCount)
+	}
+	return total, err
+}
+
+//FindNonconformingRepositories lists all the repositories of an account and returns
+// the ones whose name (without the namespace) doesn't match the given pattern
+func (c *Client) FindNonconformingRepositories(ctx context.Context, account string, pattern *regexp.Regexp) ([]Repository, error) {
+	nonconforming, _, err := c.GetRepositories(ctx, account, func(repo Repository) bool {
+		return !pattern.MatchString(repo.Name[strings.LastIndex(repo.Name, "/")+1:])
+	})
+	return nonconforming, err
+}
+
+//GetRepositoriesPushedBy returns the repositories of an account last pushed by
+// the given user. Hub only keeps the last pusher of a repository, so a
+// repository the user pushed to before someone else isn't returned. The result
+// is empty, not nil, when no repository matches.
+func (c *Client) GetRepositoriesPushedBy(ctx context.Context, account, username string) ([]Repository, error) {
+	repos, _, err := c.GetRepositories(ctx, account, func(repo Repository) bool {
+		return repo.User == username
+	})
+	if err != nil {
+		return nil, err
+	}
+	return repos, nil
+}
+
+//ResolveOwnerTypes sets the owner type of each repository, reading once per
+// namespace the type of its public profile
+func (c *Client) ResolveOwnerTypes(ctx context.Context, repositories []Repository) error {
+	owners := map[string]OwnerType{}
+	for i := range repositories {
+		namespace := strings.SplitN(repositories[i].Name, "/", 2)[0]
+		owner, ok := owners[namespace]
+		if !ok {
+			var err error
+			if owner, err = c.getOwnerType(ctx, namespace); err != nil {
+				return err
+			}
+			owners[namespace] = owner
+		}
+		repositories[i].OwnerType = owner
+	}
+	return nil
+}
+
+func (c *Client) getOwnerType(ctx context.Context, namespace string) (OwnerType, error) {
+	if namespace == c.account {
+		return UserOwner, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", c.domain+fmt.Sprintf(ProfileURL, namespace), nil)
+	if err != nil {
+		return "", err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return "", err
+	}
+	var profile hubUserResponse
+	if err := json.Unmarshal(response, &profile); err != nil {
+		return "", err
+	}
+	switch profile.Type {
+	case "User":
+		return UserOwner, nil
+	case "Organization":
+		return OrganizationOwner, nil
+	default:
+		return "", fmt.Errorf("unknown type %q for namespace %q", profile.Type, namespace)
+	}
+}
+
+//CreateRepositoryOptions holds the settings of a repository to create
+type CreateRepositoryOptions struct {
+	Description string
+	IsPrivate   bool
+}
+
+//CreateRepository creates a repository in the given namespace
+func (c *Client) CreateRepository(ctx context.Context, namespace, name string, opts CreateRepositoryOptions) (*Repository, error) {
+	data, err := json.Marshal(hubCreateRepositoryRequest{
+		Namespace:   namespace,
+		Name:        name,
+		Description: opts.Description,
+		IsPrivate:   opts.IsPrivate,
+		Registry:    "registry-1.docker.io",
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.domain+CreateRepositoryURL, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, err
+	}
+	var result hubRepositoryResult
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, err
+	}
+	repo := toRepository(namespace, result)
+	return &repo, nil
+}
+
+//RepositoryPlan lists the changes EnsureRepository makes to reconcile a repository
+type RepositoryPlan struct {
+	// Repository is the repository as it is once reconciled
+	Repository  *Repository
+	Create      bool
+	Description bool
+	Privacy     bool
+}
+
+//PlanRepository computes the changes EnsureRepository would make to reconcile
+// a repository with the given settings, without changing anything on Hub
+func (c *Client) PlanRepository(ctx context.Context, namespace, name string, opts CreateRepositoryOptions) (*RepositoryPlan, error) {
+	fullName := fmt.Sprintf("%s/%s", namespace, name)
+	repo, err := c.GetRepository(ctx, fullName)
+	if IsNotFoundError(err) {
+		return &RepositoryPlan{
+			Repository: &Repository{
+				Name:        fullName,
+				Description: opts.Description,
+				IsPrivate:   opts.IsPrivate,
+			},
+			Create: true,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	plan := &RepositoryPlan{
+		Repository:  repo,
+		Description: repo.Description != opts.Description,
+		Privacy:     repo.IsPrivate != opts.IsPrivate,
+	}
+	repo.Description = opts.Description
+	repo.IsPrivate = opts.IsPrivate
+	return plan, nil
+}
+
+//EnsureRepository makes sure a repository exists with the given settings. It
+// creates the repository if it is missing, returning true, or updates only the
+// description and the privacy which differ from the given options. Use
+// PlanRepository to know the changes beforehand.
+func (c *Client) EnsureRepository(ctx context.Context, namespace, name string, opts CreateRepositoryOptions) (*Repository, bool, error) {
+	plan, err := c.PlanRepository(ctx, namespace, name, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	if plan.Create {
+		repo, err := c.CreateRepository(ctx, namespace, name, opts)
+		return repo, err == nil, err
+	}
+	if plan.Description {
+		if _, err := c.UpdateRepository(ctx, plan.Repository.Name, UpdateRepositoryOptions{Description: &opts.Description}); err != nil {
+			return nil, false, err
+		}
+	}
+	if plan.Privacy {
+		if err := c.SetRepositoryPrivacy(ctx, plan.Repository.Name, opts.IsPrivate); err != nil {
+			return nil, false, err
+		}
+	}
+	return plan.Repository, false, nil
+}
+
+//UpdateRepositoryOptions holds the repository fields to update, nil fields are left unchanged
+type UpdateRepositoryOptions struct {
+	Description *string
+	// FullDescription is the overview of the repository, in markdown
+	FullDescription *string
+}
+
+//UpdateRepository updates the description and the overview of a repository
+func (c *Client) UpdateRepository(ctx context.Context, repository string, opts UpdateRepositoryOptions) (*Repository, error) {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(hubUpdateRepositoryRequest{
+		Description:     opts.Description,
+		FullDescription: opts.FullDescription,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "PATCH", c.domain+fmt.Sprintf(RepositoryURL, repoPath), bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, err
+	}
+	var result hubRepositoryResult
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, err
+	}
+	repo := toRepository(result.Namespace, result)
+	return &repo, nil
+}
+
+//SetRepositoryPrivacy makes a repository private or public
+func (c *Client) SetRepositoryPrivacy(ctx context.Context, repository string, private bool) error {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(hubRepositoryPrivacyRequest{IsPrivate: private})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.domain+fmt.Sprintf(RepositoryPrivacyURL, repoPath), bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	_, err = c.doRequest(req, withHubToken(c.token))
+	return err
+}
+
+//TransferRepository moves a repository, with its tags, to another namespace
+// and returns the moved repository
+func (c *Client) TransferRepository(ctx context.Context, repository, namespace string) (*Repository, error) {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(hubRepositoryTransferRequest{Namespace: namespace})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.domain+fmt.Sprintf(RepositoryTransferURL, repoPath), bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, err
+	}
+	var result hubRepositoryResult
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, err
+	}
+	repo := toRepository(result.Namespace, result)
+	return &repo, nil
+}
+
+//RenameRepository renames a repository, with its tags, in its namespace and
+// returns the renamed repository
+func (c *Client) RenameRepository(ctx context.Context, repository, name string) (*Repository, error) {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(hubRepositoryRenameRequest{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.domain+fmt.Sprintf(RepositoryRenameURL, repoPath), bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, err
+	}
+	var result hubRepositoryResult
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, err
+	}
+	repo := toRepository(result.Namespace, result)
+	return &repo, nil
+}
+
+//RemoveRepositories removes concurrently the given repositories. onResult is
+// called after each deletion, never concurrently, with the error of the
+// deletion if any. The returned error lists the repositories which couldn't be
+// removed.
+// The context deadline bounds the whole operation: once it expires, no other
+// repository is removed and the context error is returned.
+func (c *Client) RemoveRepositories(ctx context.Context, repositories []string, onResult func(repository string, err error)) error {
+	var mu sync.Mutex
+	errs := c.forEachConcurrently(ctx, len(repositories), func(i int) error {
+		err := c.RemoveRepository(ctx, repositories[i])
+		mu.Lock()
+		defer mu.Unlock()
+		onResult(repositories[i], err)
+		return err
+	})
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return bulkError("failed to remove %d repositories", repositories, errs)
+}
+
+//RemoveRepository removes a repository on Hub
+func (c *Client) RemoveRepository(ctx context.Context, repository string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.domain+fmt.Sprintf(DeleteRepositoryURL, repository), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.doRequest(req, withHubToken(c.token))
+	return err
+}
+
+//GetRepository returns a single repository by its full name (namespace/name)
+func (c *Client) GetRepository(ctx context.Context, repository string) (*Repository, error) {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", c.domain+fmt.Sprintf(RepositoryURL, repoPath), nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, err
+	}
+	var result hubRepositoryResult
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, err
+	}
+	repo := toRepository(result.Namespace, result)
+	return &repo, nil
+}
+
+func (c *Client) getRepositoriesPage(ctx context.Context, url, account string) ([]Repository, int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, 0, "", err
+	}
+	var hubResponse hubRepositoryResponse
+	if err := json.Unmarshal(response, &hubResponse); err != nil {
+		return nil, 0, "", err
+	}
+	var repos []Repository
+	for _, result := range hubResponse.Results {
+		repos = append(repos, toRepository(account, result))
+	}
+	return repos, hubResponse.Count, hubResponse.Next, nil
+}
+
+func toRepository(account string, result hubRepositoryResult) Repository {
+	return Repository{
+		Name:            fmt.Sprintf("%s/%s", account, result.Name),
+		Description:     result.Description,
+		FullDescription: result.FullDescription,
+		LastUpdated:     result.LastUpdated,
+		PullCount:       result.PullCount,
+		StarCount:       result.StarCount,
+		IsPrivate:       result.IsPrivate,
+		StorageSize:     result.StorageSize,
+		User:            result.User,
+		Categories:      result.Categories,
+	}
+}
+
+type hubRepositoryResponse struct {
+	Count    int                   `json:"count"`
+	Next     string                `json:"next,omitempty"`
+	Previous string                `json:"previous,omitempty"`
+	Results  []hubRepositoryResult `json:"results,omitempty"`
+}
+
+type hubRepositoryResult struct {
+	Name            string         `json:"name"`
+	Namespace       string         `json:"namespace"`
+	PullCount       int            `json:"pull_count"`
+	StarCount       int            `json:"star_count"`
+	RepositoryType  RepositoryType `json:"repository_type"`
+	CanEdit         bool           `json:"can_edit"`
+	Description     string         `json:"description,omitempty"`
+	FullDescription string         `json:"full_description,omitempty"`
+	IsAutomated     bool           `json:"is_automated"`
+	IsMigrated      bool           `json:"is_migrated"`
+	IsPrivate       bool           `json:"is_private"`
+	LastUpdated     time.Time      `json:"last_updated"`
+	Status          int            `json:"status"`
+	StorageSize     int64          `json:"storage_size"`
+	User            string         `json:"user"`
+	Categories      []Category     `json:"categories,omitempty"`
+}
+
+type hubCreateRepositoryRequest struct {
+	Namespace   string `json:"namespace"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	IsPrivate   bool   `json:"is_private"`
+	Registry    string `json:"registry"`
+}
+
+type hubUpdateRepositoryRequest struct {
+	Description     *string `json:"description,omitempty"`
+	FullDescription *string `json:"full_description,omitempty"`
+}
+
+type hubRepositoryPrivacyRequest struct {
+	IsPrivate bool `json:"is_private"`
+}
+
+type hubRepositoryTransferRequest struct {
+	Namespace string `json:"namespace"`
+}
+
+type hubRepositoryRenameRequest struct {
+	Name string `json:"name"`
+}
+
+//RepositoryType lists all the different repository types handled by the Docker Hub
+type RepositoryType string
+
+const (
+	//ImageType is the classic image type
+	ImageType = RepositoryType("image")
+)
@@ -0,0 +1,330 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestGetRepositoriesConcurrentOrderIsStable(t *testing.T) {
+	const total = 250
+	serial := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		assert.NilError(t, err)
+		// Answer the first pages last, so concurrent results arrive out of order
+		time.Sleep(time.Duration(4-page) * 10 * time.Millisecond)
+
+		response := hubRepositoryResponse{Count: total}
+		for i := (page - 1) * itemsPerPage; i < page*itemsPerPage && i < total; i++ {
+			response.Results = append(response.Results, hubRepositoryResult{Name: fmt.Sprintf("repo-%03d", i)})
+		}
+		if page*itemsPerPage < total {
+			response.Next = fmt.Sprintf("http://%s%s?page=%d&page_size=%d", r.Host, r.URL.Path, page+1, itemsPerPage)
+		}
+		assert.NilError(t, json.NewEncoder(w).Encode(response))
+	}))
+	assert.NilError(t, serial.Update(WithAllElements(), WithConcurrency(1)))
+	expected, _, err := serial.GetRepositories(context.Background(), "jdoe")
+	assert.NilError(t, err)
+	assert.Equal(t, len(expected), total)
+
+	concurrent := &Client{domain: serial.domain}
+	assert.NilError(t, concurrent.Update(WithAllElements(), WithConcurrency(4)))
+	actual, count, err := concurrent.GetRepositories(context.Background(), "jdoe")
+	assert.NilError(t, err)
+	assert.Equal(t, count, total)
+	assert.DeepEqual(t, actual, expected)
+}
+
+func TestGetRepositoriesCancelsPagesOnFailure(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "1":
+			_, _ = fmt.Fprintf(w, `{"count": 1000, "next": "http://%s%s?page=2", "results": []}`, r.Host, r.URL.Path)
+		case "2":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			// Only answer once the failure of the second page canceled the request
+			<-r.Context().Done()
+		}
+	}))
+	assert.NilError(t, WithAllElements()(client))
+
+	start := time.Now()
+	_, _, err := client.GetRepositories(context.Background(), "jdoe")
+	assert.Assert(t, err != nil)
+	assert.Assert(t, time.Since(start) < time.Second)
+}
+
+func TestRepositoryRequests(t *testing.T) {
+	testCases := []struct {
+		name   string
+		call   func(c *Client) error
+		method string
+		path   string
+		body   string
+	}{
+		{
+			name: "create",
+			call: func(c *Client) error {
+				_, err := c.CreateRepository(context.Background(), "myorg", "app", CreateRepositoryOptions{Description: "The app", IsPrivate: true})
+				return err
+			},
+			method: "POST",
+			path:   "/v2/repositories/",
+			body:   `{"namespace":"myorg","name":"app","description":"The app","is_private":true,"registry":"registry-1.docker.io"}`,
+		},
+		{
+			name: "update description",
+			call: func(c *Client) error {
+				description := "The app"
+				_, err := c.UpdateRepository(context.Background(), "myorg/app", UpdateRepositoryOptions{Description: &description})
+				return err
+			},
+			method: "PATCH",
+			path:   "/v2/repositories/myorg/app/",
+			body:   `{"description":"The app"}`,
+		},
+		{
+			name: "clear description and set overview",
+			call: func(c *Client) error {
+				description, overview := "", "# App"
+				_, err := c.UpdateRepository(context.Background(), "myorg/app", UpdateRepositoryOptions{Description: &description, FullDescription: &overview})
+				return err
+			},
+			method: "PATCH",
+			path:   "/v2/repositories/myorg/app/",
+			body:   `{"description":"","full_description":"# App"}`,
+		},
+		{
+			name:   "make private",
+			call:   func(c *Client) error { return c.SetRepositoryPrivacy(context.Background(), "myorg/app", true) },
+			method: "POST",
+			path:   "/v2/repositories/myorg/app/privacy/",
+			body:   `{"is_private":true}`,
+		},
+		{
+			name:   "make public",
+			call:   func(c *Client) error { return c.SetRepositoryPrivacy(context.Background(), "myorg/app", false) },
+			method: "POST",
+			path:   "/v2/repositories/myorg/app/privacy/",
+			body:   `{"is_private":false}`,
+		},
+		{
+			name: "transfer",
+			call: func(c *Client) error {
+				_, err := c.TransferRepository(context.Background(), "jdoe/app", "myorg")
+				return err
+			},
+			method: "POST",
+			path:   "/v2/repositories/jdoe/app/transfer/",
+			body:   `{"namespace":"myorg"}`,
+		},
+		{
+			name: "rename",
+			call: func(c *Client) error {
+				_, err := c.RenameRepository(context.Background(), "jdoe/app", "application")
+				return err
+			},
+			method: "POST",
+			path:   "/v2/repositories/jdoe/app/rename/",
+			body:   `{"name":"application"}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, r.Method+" "+r.URL.Path, tc.method+" "+tc.path)
+				body, err := ioutil.ReadAll(r.Body)
+				assert.NilError(t, err)
+				assert.Equal(t, string(body), tc.body)
+				_, _ = w.Write([]byte(`{"name": "app", "namespace": "myorg"}`))
+			}))
+			assert.NilError(t, tc.call(client))
+		})
+	}
+}
+
+func TestEnsureRepositoryOnlyUpdatesChangedFields(t *testing.T) {
+	var writes []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/v2/repositories/jdoe/app/":
+			_, _ = w.Write([]byte(`{"name": "app", "namespace": "jdoe", "description": "old", "is_private": true}`))
+		case r.Method == "GET":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			writes = append(writes, r.Method+" "+r.URL.Path)
+			_, _ = w.Write([]byte(`{"name": "new", "namespace": "jdoe"}`))
+		}
+	}))
+
+	plan, err := client.PlanRepository(context.Background(), "jdoe", "app", CreateRepositoryOptions{Description: "new", IsPrivate: true})
+	assert.NilError(t, err)
+	assert.Assert(t, !plan.Create)
+	assert.Assert(t, plan.Description)
+	assert.Assert(t, !plan.Privacy)
+	assert.Equal(t, plan.Repository.Description, "new")
+	assert.Equal(t, len(writes), 0)
+
+	_, created, err := client.EnsureRepository(context.Background(), "jdoe", "app", CreateRepositoryOptions{Description: "new", IsPrivate: true})
+	assert.NilError(t, err)
+	assert.Assert(t, !created)
+	assert.DeepEqual(t, writes, []string{"PATCH /v2/repositories/jdoe/app/"})
+
+	writes = nil
+	repo, created, err := client.EnsureRepository(context.Background(), "jdoe", "new", CreateRepositoryOptions{})
+	assert.NilError(t, err)
+	assert.Assert(t, created)
+	assert.Equal(t, repo.Name, "jdoe/new")
+	assert.DeepEqual(t, writes, []string{"POST /v2/repositories/"})
+}
+
+func TestRepositoriesIterStopsEarly(t *testing.T) {
+	var pages []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages = append(pages, r.URL.Query().Get("page"))
+		assert.Equal(t, r.URL.Query().Get("page_size"), "2")
+		_, _ = fmt.Fprintf(w, `{"count": 6, "next": "http://%s%s?page=2&page_size=2", "results": [{"name": "a"}, {"name": "b"}]}`, r.Host, r.URL.Path)
+	}))
+
+	var names []string
+	total, err := client.RepositoriesIter(context.Background(), "jdoe", 2, func(repo Repository) error {
+		names = append(names, repo.Name)
+		if len(names) == 1 {
+			return ErrStopIteration
+		}
+		return nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, total, 6)
+	assert.DeepEqual(t, names, []string{"jdoe/a"})
+	assert.DeepEqual(t, pages, []string{"1"})
+}
+
+func TestSumPullCounts(t *testing.T) {
+	client := newTestClient(t, routes{
+		"GET /v2/repositories/jdoe/app/":      `{"name": "app", "namespace": "jdoe", "pull_count": 1200}`,
+		"GET /v2/repositories/jdoe/worker/":   `{"name": "worker", "namespace": "jdoe", "pull_count": 34}`,
+		"GET /v2/repositories/library/nginx/": `{"name": "nginx", "namespace": "library", "pull_count": 1000000}`,
+	})
+
+	testCases := []struct {
+		name         string
+		repositories []string
+		total        int64
+		err          string
+	}{
+		{name: "all found", repositories: []string{"jdoe/app", "jdoe/worker", "nginx"}, total: 1001234},
+		{name: "partial", repositories: []string{"jdoe/app", "jdoe/missing"}, total: 1200, err: "failed to fetch 1 repositories: jdoe/missing: "},
+		{name: "none", total: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			total, err := client.SumPullCounts(context.Background(), tc.repositories)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.Equal(t, total, tc.total)
+		})
+	}
+}
+
+func TestGetRepositoriesPushedBy(t *testing.T) {
+	client := newTestClient(t, routes{
+		"GET /v2/repositories/myorg/": `{"count": 3, "results": [
+			{"name": "app", "namespace": "myorg", "user": "alice"},
+			{"name": "worker", "namespace": "myorg", "user": "bob"},
+			{"name": "docs", "namespace": "myorg", "user": "alice"}
+		]}`,
+	})
+
+	repos, err := client.GetRepositoriesPushedBy(context.Background(), "myorg", "alice")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, repos, []Repository{
+		{Name: "myorg/app", User: "alice"},
+		{Name: "myorg/docs", User: "alice"},
+	})
+
+	repos, err = client.GetRepositoriesPushedBy(context.Background(), "myorg", "carol")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, repos, []Repository{})
+}
+
+func TestGetRepositoriesByNameStopsAtDeadline(t *testing.T) {
+	var requests int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		// Never answer, the deadline has to cancel the requests in flight
+		<-r.Context().Done()
+	}))
+	names := make([]string, 3*defaultConcurrency)
+	for i := range names {
+		names[i] = fmt.Sprintf("jdoe/repo-%d", i)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	repos, err := client.GetRepositoriesByName(ctx, names)
+	assert.Equal(t, err, context.DeadlineExceeded)
+	assert.Equal(t, len(repos), 0)
+	assert.Assert(t, time.Since(start) < time.Second)
+	assert.Assert(t, atomic.LoadInt32(&requests) <= defaultConcurrency)
+}
+
+func TestResolveOwnerTypes(t *testing.T) {
+	client := newTestClient(t, routes{
+		"GET /v2/users/myorg/": `{"username": "myorg", "type": "Organization"}`,
+		"GET /v2/users/other/": `{"username": "other", "type": "User"}`,
+	})
+	client.account = "jdoe"
+
+	repos := []Repository{{Name: "jdoe/app"}, {Name: "myorg/api"}, {Name: "other/tool"}, {Name: "myorg/web"}}
+	assert.NilError(t, client.ResolveOwnerTypes(context.Background(), repos))
+	var owners []OwnerType
+	for _, repo := range repos {
+		owners = append(owners, repo.OwnerType)
+	}
+	assert.DeepEqual(t, owners, []OwnerType{UserOwner, OrganizationOwner, UserOwner, OrganizationOwner})
+
+	err := client.ResolveOwnerTypes(context.Background(), []Repository{{Name: "missing/app"}})
+	assert.Assert(t, IsNotFoundError(err))
+	assert.ErrorContains(t, err, "unexpected request GET /v2/users/missing/")
+}
+
+func TestGetRepositoryOverview(t *testing.T) {
+	client := newTestClient(t, routes{
+		"GET /v2/repositories/myorg/app/": `{"name": "app", "namespace": "myorg", "description": "The app", "full_description": "# App\n"}`,
+	})
+	repo, err := client.GetRepository(context.Background(), "myorg/app")
+	assert.NilError(t, err)
+	assert.Equal(t, repo.Name, "myorg/app")
+	assert.Equal(t, repo.FullDescription, "# App\n")
+}
@@ -0,0 +1,110 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// rateLimitHeaders are the headers telling the rate limits, the Hub API and
+// the registry using different names
+var rateLimitHeaders = []string{
+	"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset",
+	"RateLimit-Limit", "RateLimit-Remaining",
+}
+
+// RequestLog describes a request sent to Hub, for debugging
+type RequestLog struct {
+	Method string
+	URL    string
+	// Status is the response status, empty when the request failed
+	Status   string
+	Duration time.Duration
+	// Header holds the request headers, with the credentials redacted
+	Header http.Header
+	// RateLimits holds the rate limit headers of the response
+	RateLimits http.Header
+	Err        error
+}
+
+func (l RequestLog) String() string {
+	s := fmt.Sprintf("%s %s", l.Method, l.URL)
+	if l.Err != nil {
+		s += fmt.Sprintf(" failed in %s: %s", l.Duration.Round(time.Millisecond), l.Err)
+	} else {
+		s += fmt.Sprintf(" %s in %s", l.Status, l.Duration.Round(time.Millisecond))
+	}
+	var limits []string
+	for _, name := range rateLimitHeaders {
+		if value := l.RateLimits.Get(name); value != "" {
+			limits = append(limits, fmt.Sprintf("%s=%s", name, value))
+		}
+	}
+	if len(limits) > 0 {
+		s += " (" + strings.Join(limits, ", ") + ")"
+	}
+	return s
+}
+
+// RequestLogger is called after each request sent to Hub
+type RequestLogger func(RequestLog)
+
+// WithRequestLogger calls the logger after each request, with the request
+// method, URL, status, timing and the rate limit headers
+func WithRequestLogger(logger RequestLogger) ClientOp {
+	return func(c *Client) error {
+		c.requestLogger = logger
+		return nil
+	}
+}
+
+func (c *Client) logRequest(req *http.Request, resp *http.Response, err error, duration time.Duration) {
+	if c.requestLogger == nil {
+		return
+	}
+	l := RequestLog{
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		Duration:   duration,
+		Header:     redactHeader(req.Header),
+		RateLimits: http.Header{},
+		Err:        err,
+	}
+	if resp != nil {
+		l.Status = resp.Status
+		for _, name := range rateLimitHeaders {
+			if value := resp.Header.Get(name); value != "" {
+				l.RateLimits.Set(name, value)
+			}
+		}
+	}
+	c.requestLogger(l)
+}
+
+// redactHeader returns a copy of the headers without the credentials
+func redactHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, name := range []string{"Authorization", "Cookie"} {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, "REDACTED")
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,51 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRequestLogger(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "99")
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	var logs []RequestLog
+	assert.NilError(t, client.Update(WithRequestLogger(func(l RequestLog) {
+		logs = append(logs, l)
+	})))
+
+	req, err := http.NewRequest("GET", client.domain+"/v2/repositories/", nil)
+	assert.NilError(t, err)
+	_, err = client.doRequest(req, withHubToken("secret"))
+	assert.ErrorContains(t, err, "418")
+
+	assert.Equal(t, len(logs), 1)
+	l := logs[0]
+	assert.Equal(t, l.Method, "GET")
+	assert.Equal(t, l.URL, client.domain+"/v2/repositories/")
+	assert.Equal(t, l.Status, "418 I'm a teapot")
+	assert.Equal(t, l.Header.Get("Authorization"), "REDACTED")
+	assert.Equal(t, req.Header.Get("Authorization"), "Bearer secret")
+	assert.Equal(t, l.RateLimits.Get("X-RateLimit-Remaining"), "99")
+	assert.Assert(t, !strings.Contains(l.String(), "secret"))
+}
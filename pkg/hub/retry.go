@@ -0,0 +1,140 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultRetries = 3
+	minRetryDelay  = 500 * time.Millisecond
+	maxRetryDelay  = 30 * time.Second
+)
+
+// Do sends a request with the HTTP client of the client, retrying it on the
+// transient errors as the Hub requests are, so that other clients such as the
+// registry one share the retry policy
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	return c.sendWithRetries(req)
+}
+
+// Retries returns the number of times a request failing with a transient
+// error is sent again
+func (c *Client) Retries() int {
+	return c.retries
+}
+
+// RetryDelay returns how long to wait before the given retry of a request,
+// starting at 0, from the Retry-After header of the failed response if any or
+// else from a backoff with jitter. The request shouldn't be retried when false
+// is returned.
+func RetryDelay(retryAfter string, retry int) (time.Duration, bool) {
+	return retryDelay(retryAfter, retry, time.Now())
+}
+
+// sendWithRetries sends the request, sending it again while Hub answers with
+// a transient error: too many requests, or a server error for the idempotent
+// methods only as the request may have been applied anyway. Each retry waits for
+// the delay given by the Retry-After header, or else for an exponential backoff
+// with jitter. The request context bounds the whole exchange, waits included.
+func (c *Client) sendWithRetries(req *http.Request) (*http.Response, error) {
+	for retry := 0; ; retry++ {
+		resp, err := c.HTTPClient().Do(req)
+		if err != nil || retry >= c.retries || !isTransient(req, resp) || !canResend(req) {
+			return resp, err
+		}
+		delay, ok := retryDelay(resp.Header.Get("Retry-After"), retry, time.Now())
+		if !ok {
+			return resp, nil
+		}
+		c.logger().WithFields(log.Fields{"method": req.Method, "url": req.URL.String(), "status": resp.StatusCode}).Debugf("HTTP request failed with %q, retrying in %s", resp.Status, delay)
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// isTransient tells if the request can be sent again after the response
+func isTransient(req *http.Request, resp *http.Response) bool {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return resp.StatusCode >= 500 && isIdempotent(req.Method)
+}
+
+// isIdempotent tells if sending a request with this method several times has
+// the same effect as sending it once
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// canResend tells if the request body, if any, can be read again
+func canResend(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// retryDelay returns how long to wait before the given retry, starting at 0.
+// A Retry-After value, either in seconds or as a date, takes precedence over
+// the backoff. The request isn't retried when Hub asks to wait longer than the
+// maximum delay, which happens when a rate limit window is exhausted.
+func retryDelay(retryAfter string, retry int, now time.Time) (time.Duration, bool) {
+	if retryAfter != "" {
+		var delay time.Duration
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			delay = time.Duration(seconds) * time.Second
+		} else if date, err := http.ParseTime(retryAfter); err == nil {
+			delay = date.Sub(now)
+		}
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, delay <= maxRetryDelay
+	}
+	backoff := maxRetryDelay
+	if retry < 10 {
+		if d := minRetryDelay << uint(retry); d < maxRetryDelay {
+			backoff = d
+		}
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2))), true
+}
@@ -0,0 +1,119 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRetryTransientErrors(t *testing.T) {
+	testCases := []struct {
+		name       string
+		method     string
+		status     int
+		retryAfter string
+		failures   int32
+		retries    int
+		requests   int32
+		err        string
+	}{
+		{name: "server error", method: "PUT", status: http.StatusServiceUnavailable, retryAfter: "0", failures: 2, retries: 3, requests: 3},
+		{name: "server error on a non idempotent request", method: "POST", status: http.StatusServiceUnavailable, retryAfter: "0", failures: 2, retries: 3, requests: 1, err: `bad status code "503 Service Unavailable"`},
+		{name: "too many requests", method: "POST", status: http.StatusTooManyRequests, retryAfter: "0", failures: 1, retries: 3, requests: 2},
+		{name: "retries exhausted", method: "PUT", status: http.StatusBadGateway, retryAfter: "0", failures: 5, retries: 2, requests: 3, err: `bad status code "502 Bad Gateway"`},
+		{name: "wait too long", method: "POST", status: http.StatusTooManyRequests, retryAfter: "3600", failures: 1, retries: 3, requests: 1, err: `bad status code "429 Too Many Requests"`},
+		{name: "client error", method: "PUT", status: http.StatusBadRequest, failures: 1, retries: 3, requests: 1, err: `bad status code "400 Bad Request"`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests int32
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := ioutil.ReadAll(r.Body)
+				assert.NilError(t, err)
+				assert.Equal(t, string(body), `{"name":"app"}`)
+				if atomic.AddInt32(&requests, 1) <= tc.failures {
+					w.Header().Set("Retry-After", tc.retryAfter)
+					w.WriteHeader(tc.status)
+					return
+				}
+				_, _ = w.Write([]byte("{}"))
+			}))
+			assert.NilError(t, client.Update(WithRetries(tc.retries)))
+
+			req, err := http.NewRequest(tc.method, client.domain, strings.NewReader(`{"name":"app"}`))
+			assert.NilError(t, err)
+			_, err = client.doRequest(req)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.Equal(t, atomic.LoadInt32(&requests), tc.requests)
+		})
+	}
+}
+
+func TestRetryStopsWithContext(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "20")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	assert.NilError(t, client.Update(WithRetries(3)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", client.domain, nil)
+	assert.NilError(t, err)
+	start := time.Now()
+	_, err = client.doRequest(req)
+	assert.Equal(t, err, context.DeadlineExceeded)
+	assert.Assert(t, time.Since(start) < time.Second)
+}
+
+func TestRetryDelay(t *testing.T) {
+	now := time.Date(2020, 11, 3, 17, 40, 5, 0, time.UTC)
+	testCases := []struct {
+		name       string
+		retryAfter string
+		retry      int
+		min, max   time.Duration
+		ok         bool
+	}{
+		{name: "seconds", retryAfter: "12", min: 12 * time.Second, max: 12 * time.Second, ok: true},
+		{name: "date", retryAfter: "Tue, 03 Nov 2020 17:40:15 GMT", min: 10 * time.Second, max: 10 * time.Second, ok: true},
+		{name: "past date", retryAfter: "Tue, 03 Nov 2020 17:00:00 GMT", ok: true},
+		{name: "too long", retryAfter: "3600", min: time.Hour, max: time.Hour},
+		{name: "first backoff", min: 250 * time.Millisecond, max: 500 * time.Millisecond, ok: true},
+		{name: "third backoff", retry: 2, min: time.Second, max: 2 * time.Second, ok: true},
+		{name: "capped backoff", retry: 20, min: 15 * time.Second, max: 30 * time.Second, ok: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			delay, ok := retryDelay(tc.retryAfter, tc.retry, now)
+			assert.Equal(t, ok, tc.ok)
+			assert.Assert(t, delay >= tc.min && delay <= tc.max, "delay %s not in [%s, %s]", delay, tc.min, tc.max)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+const (
+	// ScanURL path to the Hub API returning the vulnerability scan of an image
+	ScanURL = "/api/scan/v1/namespaces/%s/repositories/%s/images/%s/scans"
+
+	// SeverityCritical is the most severe level of vulnerabilities
+	SeverityCritical = "critical"
+	// SeverityHigh is the level of the high severity vulnerabilities
+	SeverityHigh = "high"
+	// SeverityMedium is the level of the medium severity vulnerabilities
+	SeverityMedium = "medium"
+	// SeverityLow is the least severe level of vulnerabilities
+	SeverityLow = "low"
+)
+
+// Severities are the levels of vulnerabilities, from the least severe
+var Severities = []string{SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+// Vulnerability is a vulnerability found in a package of an image
+type Vulnerability struct {
+	ID       string `json:"id"`
+	Severity string `json:"severity"`
+	Title    string `json:"title"`
+	Package  string `json:"package"`
+	Version  string `json:"version"`
+	// FixedVersion is the first version of the package fixing the
+	// vulnerability, if any
+	FixedVersion string `json:"fixed_version,omitempty"`
+	URL          string `json:"url,omitempty"`
+}
+
+// ScanReport is the result of the vulnerability scan of an image
+type ScanReport struct {
+	Digest          string          `json:"digest"`
+	Status          string          `json:"status"`
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+}
+
+// SeverityRank returns the rank of a severity level in Severities, -1 when
+// unknown
+func SeverityRank(severity string) int {
+	for i, s := range Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetScanReport returns the vulnerabilities found by the last scan of an image
+// of a repository, given by its digest, the most severe first
+func (c *Client) GetScanReport(ctx context.Context, repository, digest string) (*ScanReport, error) {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return nil, err
+	}
+	namespace, name := splitRepoPath(repoPath)
+	req, err := http.NewRequestWithContext(ctx, "GET", c.domain+fmt.Sprintf(ScanURL, namespace, name, digest), nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		if IsNotFoundError(err) {
+			return nil, &notFoundError{err: fmt.Errorf("no scan result for image %s, is vulnerability scanning enabled on %s?", digest, repository)}
+		}
+		return nil, err
+	}
+	var hubResponse hubScanResponse
+	if err := json.Unmarshal(response, &hubResponse); err != nil {
+		return nil, err
+	}
+	report := &ScanReport{
+		Digest:          digest,
+		Status:          hubResponse.Scan.Status,
+		Vulnerabilities: []Vulnerability{},
+	}
+	for _, result := range hubResponse.Vulnerabilities {
+		report.Vulnerabilities = append(report.Vulnerabilities, Vulnerability{
+			ID:           result.ID,
+			Severity:     result.Severity,
+			Title:        result.Title,
+			Package:      result.Package.Name,
+			Version:      result.Package.Version,
+			FixedVersion: result.FixedIn,
+			URL:          result.URL,
+		})
+	}
+	sort.SliceStable(report.Vulnerabilities, func(i, j int) bool {
+		return SeverityRank(report.Vulnerabilities[i].Severity) > SeverityRank(report.Vulnerabilities[j].Severity)
+	})
+	return report, nil
+}
+
+type hubScanResponse struct {
+	Scan struct {
+		Status    string `json:"status"`
+		CreatedAt string `json:"created_at"`
+	} `json:"scan"`
+	Vulnerabilities []hubVulnerability `json:"vulnerabilities"`
+}
+
+type hubVulnerability struct {
+	ID       string `json:"id"`
+	Severity string `json:"severity"`
+	Title    string `json:"title"`
+	Package  struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"package"`
+	FixedIn string `json:"fixed_in,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
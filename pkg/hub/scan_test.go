@@ -0,0 +1,62 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+)
+
+func TestGetScanReport(t *testing.T) {
+	client := newTestClient(t, routes{
+		"GET /api/scan/v1/namespaces/jdoe/repositories/app/images/sha256:abcd/scans": string(golden.Get(t, "scan.json")),
+	})
+
+	report, err := client.GetScanReport(context.Background(), "jdoe/app", "sha256:abcd")
+	assert.NilError(t, err)
+	assert.Equal(t, report.Status, "completed")
+	var ids []string
+	for _, vulnerability := range report.Vulnerabilities {
+		ids = append(ids, vulnerability.ID)
+	}
+	assert.DeepEqual(t, ids, []string{"CVE-2021-3156", "CVE-2020-1971", "CVE-2020-28928"})
+	assert.DeepEqual(t, report.Vulnerabilities[1], Vulnerability{
+		ID:           "CVE-2020-1971",
+		Severity:     SeverityHigh,
+		Title:        "NULL pointer dereference",
+		Package:      "openssl",
+		Version:      "1.1.1g-r0",
+		FixedVersion: "1.1.1i-r0",
+		URL:          "https://nvd.nist.gov/vuln/detail/CVE-2020-1971",
+	})
+}
+
+func TestGetScanReportNotScanned(t *testing.T) {
+	client := newTestClient(t, routes{})
+
+	_, err := client.GetScanReport(context.Background(), "jdoe/app", "sha256:abcd")
+	assert.Assert(t, IsNotFoundError(err))
+	assert.ErrorContains(t, err, "is vulnerability scanning enabled on jdoe/app?")
+}
+
+func TestSeverityRank(t *testing.T) {
+	assert.Assert(t, SeverityRank(SeverityCritical) > SeverityRank(SeverityHigh))
+	assert.Equal(t, SeverityRank("unknown"), -1)
+}
@@ -0,0 +1,201 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// SearchURL path to the Hub API searching the images
+	SearchURL = "/api/content/v1/products/search"
+
+	searchPageSize = 25
+	// filterTypeOfficial and filterTypeVerified are the values of the image
+	// filter for the official images and the verified publishers ones
+	filterTypeOfficial = "official"
+	filterTypeVerified = "store"
+)
+
+// SearchResult represents an image found by a search
+type SearchResult struct {
+	Name              string
+	Publisher         string
+	Description       string
+	StarCount         int
+	PullCount         string
+	Official          bool
+	VerifiedPublisher bool
+	Categories        []string
+}
+
+// SearchFilter narrows the results of a search
+type SearchFilter func(*searchOptions)
+
+type searchOptions struct {
+	query     url.Values
+	minStars  int
+	namespace string
+}
+
+// WithOfficialImages only returns the Docker official images
+func WithOfficialImages() SearchFilter {
+	return func(o *searchOptions) {
+		o.query.Add("image_filter", filterTypeOfficial)
+	}
+}
+
+// WithVerifiedPublishers only returns the images of verified publishers
+func WithVerifiedPublishers() SearchFilter {
+	return func(o *searchOptions) {
+		o.query.Add("image_filter", filterTypeVerified)
+	}
+}
+
+// WithCategory only returns the images of the given category, such as
+// "database" or "monitoring"
+func WithCategory(category string) SearchFilter {
+	return func(o *searchOptions) {
+		o.query.Add("category", category)
+	}
+}
+
+// WithMinStars only returns the images starred at least the given number of
+// times
+func WithMinStars(stars int) SearchFilter {
+	return func(o *searchOptions) {
+		o.minStars = stars
+	}
+}
+
+// WithNamespace only returns the images of the given namespace, such as the
+// organization of a verified publisher
+func WithNamespace(namespace string) SearchFilter {
+	return func(o *searchOptions) {
+		o.namespace = namespace
+	}
+}
+
+type hubSearchResponse struct {
+	Count     int                `json:"count"`
+	Next      string             `json:"next"`
+	Summaries []hubSearchSummary `json:"summaries"`
+}
+
+type hubSearchSummary struct {
+	Name      string `json:"name"`
+	Publisher struct {
+		Name string `json:"name"`
+	} `json:"publisher"`
+	ShortDescription string `json:"short_description"`
+	StarCount        int    `json:"star_count"`
+	PullCount        string `json:"pull_count"`
+	FilterType       string `json:"filter_type"`
+	Categories       []struct {
+		Name string `json:"name"`
+	} `json:"categories"`
+}
+
+// Search looks for the images matching the query, returning at most max of
+// them along with the total number of matches. The minimum star count and the
+// namespace are checked on the returned images, so fewer than max of them may
+// be returned.
+func (c *Client) Search(ctx context.Context, query string, max int, filters ...SearchFilter) ([]SearchResult, int, error) {
+	if max < 1 {
+		return nil, 0, fmt.Errorf("invalid maximum number of results %d, must be at least 1", max)
+	}
+	opts := searchOptions{query: url.Values{}}
+	for _, filter := range filters {
+		filter(&opts)
+	}
+	pageSize := searchPageSize
+	if max < pageSize {
+		pageSize = max
+	}
+	opts.query.Set("q", query)
+	opts.query.Set("type", "image")
+	opts.query.Set("page_size", fmt.Sprintf("%v", pageSize))
+
+	var (
+		results []SearchResult
+		total   int
+	)
+	for page, fetched := 1, 0; fetched < max; page++ {
+		opts.query.Set("page", fmt.Sprintf("%v", page))
+		response, err := c.getSearchPage(ctx, c.domain+SearchURL+"?"+opts.query.Encode())
+		if err != nil {
+			return nil, 0, err
+		}
+		total = response.Count
+		for _, summary := range response.Summaries {
+			if fetched == max {
+				break
+			}
+			fetched++
+			if summary.StarCount < opts.minStars {
+				continue
+			}
+			if opts.namespace != "" && !strings.HasPrefix(summary.Name, opts.namespace+"/") {
+				continue
+			}
+			results = append(results, toSearchResult(summary))
+		}
+		if response.Next == "" || len(response.Summaries) == 0 {
+			break
+		}
+	}
+	return results, total, nil
+}
+
+func (c *Client) getSearchPage(ctx context.Context, url string) (*hubSearchResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Search-Version", "v3")
+	response, err := c.doRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	var hubResponse hubSearchResponse
+	if err := json.Unmarshal(response, &hubResponse); err != nil {
+		return nil, err
+	}
+	return &hubResponse, nil
+}
+
+func toSearchResult(summary hubSearchSummary) SearchResult {
+	categories := make([]string, 0, len(summary.Categories))
+	for _, category := range summary.Categories {
+		categories = append(categories, category.Name)
+	}
+	return SearchResult{
+		Name:              summary.Name,
+		Publisher:         summary.Publisher.Name,
+		Description:       summary.ShortDescription,
+		StarCount:         summary.StarCount,
+		PullCount:         summary.PullCount,
+		Official:          summary.FilterType == filterTypeOfficial,
+		VerifiedPublisher: summary.FilterType == filterTypeVerified,
+		Categories:        categories,
+	}
+}
@@ -0,0 +1,88 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+)
+
+func TestSearch(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Path, SearchURL)
+		q := r.URL.Query()
+		assert.Equal(t, q.Get("q"), "postgres")
+		assert.Equal(t, q.Get("type"), "image")
+		assert.Equal(t, q.Get("category"), "database")
+		assert.DeepEqual(t, q["image_filter"], []string{"official", "store"})
+		switch q.Get("page") {
+		case "1":
+			_, _ = w.Write(golden.Get(t, "search.json"))
+		case "2":
+			_, _ = w.Write([]byte(`{"count": 4, "summaries": [{"name": "circleci/postgres", "star_count": 30, "filter_type": "community"}, {"name": "postgis/postgis", "star_count": 5, "filter_type": "community"}]}`))
+		default:
+			t.Errorf("unexpected page %s", q.Get("page"))
+		}
+	}))
+
+	results, total, err := client.Search(context.Background(), "postgres", 3,
+		WithOfficialImages(), WithVerifiedPublishers(), WithCategory("database"), WithMinStars(100))
+	assert.NilError(t, err)
+	assert.Equal(t, total, 4)
+	assert.DeepEqual(t, results, []SearchResult{
+		{
+			Name:        "postgres",
+			Publisher:   "Docker",
+			Description: "The PostgreSQL object-relational database system provides reliability and data integrity.",
+			StarCount:   11234,
+			PullCount:   "1B+",
+			Official:    true,
+			Categories:  []string{"database"},
+		},
+		{
+			Name:              "bitnami/postgresql",
+			Publisher:         "VMware",
+			Description:       "Bitnami PostgreSQL Docker Image",
+			StarCount:         215,
+			PullCount:         "100M+",
+			VerifiedPublisher: true,
+			Categories:        []string{},
+		},
+	})
+}
+
+func TestSearchWithNamespace(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Query().Get("image_filter"), "store")
+		_, _ = w.Write([]byte(`{"count": 2, "summaries": [{"name": "bitnami/postgresql", "filter_type": "store"}, {"name": "bitnamilabs/redis", "filter_type": "store"}]}`))
+	}))
+
+	results, _, err := client.Search(context.Background(), "bitnami", 25, WithVerifiedPublishers(), WithNamespace("bitnami"))
+	assert.NilError(t, err)
+	assert.Equal(t, len(results), 1)
+	assert.Equal(t, results[0].Name, "bitnami/postgresql")
+}
+
+func TestSearchRejectsInvalidMax(t *testing.T) {
+	client := newTestClient(t, http.NotFoundHandler())
+	_, _, err := client.Search(context.Background(), "postgres", 0)
+	assert.Error(t, err, "invalid maximum number of results 0, must be at least 1")
+}
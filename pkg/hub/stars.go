@@ -0,0 +1,107 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const (
+	// RepositoryStarsURL path to the Hub API starring a repository
+	RepositoryStarsURL = "/v2/repositories/%s/stars/"
+	// StarredRepositoriesURL path to the Hub API listing the repositories
+	// starred by a user or an organization
+	StarredRepositoriesURL = "/v2/users/%s/repositories/starred/"
+)
+
+// StarRepository adds the repository to the favorites of the current user
+func (c *Client) StarRepository(ctx context.Context, repository string) error {
+	return c.setRepositoryStar(ctx, "POST", repository)
+}
+
+// UnstarRepository removes the repository from the favorites of the current
+// user
+func (c *Client) UnstarRepository(ctx context.Context, repository string) error {
+	return c.setRepositoryStar(ctx, "DELETE", repository)
+}
+
+// GetStarredRepositories lists the repositories starred by a user or an
+// organization, the current account by default
+func (c *Client) GetStarredRepositories(ctx context.Context, account string) ([]Repository, error) {
+	if account == "" {
+		account = c.account
+	}
+	u, err := url.Parse(c.domain + fmt.Sprintf(StarredRepositoriesURL, account))
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	q.Add("page_size", fmt.Sprintf("%v", itemsPerPage))
+	q.Add("page", "1")
+	u.RawQuery = q.Encode()
+
+	repos, next, err := c.getStarredRepositoriesPage(ctx, u.String())
+	if err != nil {
+		return nil, err
+	}
+	for next != "" {
+		pageRepos, n, err := c.getStarredRepositoriesPage(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+		next = n
+		repos = append(repos, pageRepos...)
+	}
+	return repos, nil
+}
+
+func (c *Client) setRepositoryStar(ctx context.Context, method, repository string) error {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.domain+fmt.Sprintf(RepositoryStarsURL, repoPath), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.doRequest(req, withHubToken(c.token))
+	return err
+}
+
+func (c *Client) getStarredRepositoriesPage(ctx context.Context, url string) ([]Repository, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, "", err
+	}
+	var hubResponse hubRepositoryResponse
+	if err := json.Unmarshal(response, &hubResponse); err != nil {
+		return nil, "", err
+	}
+	var repos []Repository
+	for _, result := range hubResponse.Results {
+		repos = append(repos, toRepository(result.Namespace, result))
+	}
+	return repos, hubResponse.Next, nil
+}
@@ -0,0 +1,61 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestStars(t *testing.T) {
+	var starred, unstarred bool
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /v2/repositories/library/postgres/stars/":
+			starred = true
+			w.WriteHeader(http.StatusCreated)
+		case "DELETE /v2/repositories/library/postgres/stars/":
+			unstarred = true
+			w.WriteHeader(http.StatusNoContent)
+		case "GET /v2/users/jdoe/repositories/starred/":
+			if r.URL.Query().Get("page") == "1" {
+				_, _ = w.Write([]byte(`{"count": 2, "next": "` + "http://" + r.Host + r.URL.Path + `?page=2", "results": [{"namespace": "library", "name": "postgres", "star_count": 11234}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"count": 2, "results": [{"namespace": "bitnami", "name": "redis", "star_count": 230}]}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	assert.NilError(t, client.StarRepository(context.Background(), "postgres"))
+	assert.Assert(t, starred)
+	assert.NilError(t, client.UnstarRepository(context.Background(), "postgres"))
+	assert.Assert(t, unstarred)
+
+	assert.NilError(t, client.Update(WithHubAccount("jdoe")))
+	repos, err := client.GetStarredRepositories(context.Background(), "")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, repos, []Repository{
+		{Name: "library/postgres", StarCount: 11234},
+		{Name: "bitnami/redis", StarCount: 230},
+	})
+}
@@ -0,0 +1,66 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"fmt"
+)
+
+// Platform identifies the operating system and architecture of an image
+type Platform struct {
+	OS           string
+	Architecture string
+	Variant      string
+}
+
+func (p Platform) String() string {
+	if p.Variant == "" {
+		return fmt.Sprintf("%s/%s", p.OS, p.Architecture)
+	}
+	return fmt.Sprintf("%s/%s/%s", p.OS, p.Architecture, p.Variant)
+}
+
+// GetRepositoryStorageByArch returns the size of the images of a repository,
+// summed by platform.
+// Each image is counted once even when several tags reference it. The sizes are
+// the compressed sizes reported by Hub for each image, so layers shared between
+// images are counted once per image: the numbers are an upper bound of the
+// storage used, and dropping a platform frees at most its size.
+// Image sizes come with the tag listing, so the number of requests only depends
+// on the number of tag pages and not on the number of tags.
+func (c *Client) GetRepositoryStorageByArch(ctx context.Context, repository string) (map[Platform]int64, error) {
+	tags, _, err := c.GetTags(withAllElements(ctx), repository)
+	if err != nil {
+		return nil, err
+	}
+	storage := map[Platform]int64{}
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		for _, image := range tag.Images {
+			if image.Digest != "" {
+				if seen[image.Digest] {
+					continue
+				}
+				seen[image.Digest] = true
+			}
+			platform := Platform{OS: image.Os, Architecture: image.Architecture, Variant: image.Variant}
+			storage[platform] += int64(image.Size)
+		}
+	}
+	return storage, nil
+}
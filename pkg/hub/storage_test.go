@@ -0,0 +1,47 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestGetRepositoryStorageByArch(t *testing.T) {
+	client := newTestClient(t, routes{
+		"GET /v2/repositories/jdoe/app/tags/": `{"count": 2, "results": [
+			{"name": "latest", "images": [
+				{"os": "linux", "architecture": "amd64", "digest": "sha256:amd64", "size": 1000},
+				{"os": "linux", "architecture": "arm", "variant": "v7", "digest": "sha256:armv7", "size": 800}
+			]},
+			{"name": "1.0", "images": [
+				{"os": "linux", "architecture": "amd64", "digest": "sha256:amd64", "size": 1000},
+				{"os": "linux", "architecture": "amd64", "digest": "sha256:old", "size": 900}
+			]}
+		]}`,
+	})
+
+	storage, err := client.GetRepositoryStorageByArch(context.Background(), "jdoe/app")
+	assert.NilError(t, err)
+	// The image shared by both tags is only counted once
+	assert.DeepEqual(t, storage, map[Platform]int64{
+		{OS: "linux", Architecture: "amd64"}:              1900,
+		{OS: "linux", Architecture: "arm", Variant: "v7"}: 800,
+	})
+}
@@ -0,0 +1,137 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// TagLockVersion is the current version of the tag lock format
+	TagLockVersion = 1
+)
+
+// TagLock pins the digest of every tag of a set of repositories at a point in time
+type TagLock struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Repositories maps each repository to its tags and their digest
+	Repositories map[string]map[string]string `json:"repositories"`
+}
+
+// Drift is a tag whose digest changed since the lock was created. Actual is
+// empty if the tag doesn't exist anymore.
+type Drift struct {
+	Repository string
+	Tag        string
+	Expected   string
+	Actual     string
+}
+
+// ExportTagLock fetches concurrently all the tags of the given repositories
+// and returns their current digest.
+// If the context deadline expires, the lock only contains the repositories
+// already fetched and the context error is returned.
+func (c *Client) ExportTagLock(ctx context.Context, repositories []string) (*TagLock, error) {
+	digests, err := c.getTagDigests(ctx, repositories)
+	if err != nil && ctx.Err() == nil {
+		return nil, err
+	}
+	lock := &TagLock{
+		Version:      TagLockVersion,
+		CreatedAt:    time.Now().UTC(),
+		Repositories: map[string]map[string]string{},
+	}
+	for i, repository := range repositories {
+		if digests[i] != nil {
+			lock.Repositories[repository] = digests[i]
+		}
+	}
+	return lock, err
+}
+
+// VerifyTagLock resolves again the digests of all the locked tags and returns
+// the ones which differ.
+// If the context deadline expires, only the drifts of the repositories already
+// fetched are returned along with the context error.
+func (c *Client) VerifyTagLock(ctx context.Context, lock *TagLock) ([]Drift, error) {
+	var repositories []string
+	for repository := range lock.Repositories {
+		repositories = append(repositories, repository)
+	}
+	sort.Strings(repositories)
+	digests, err := c.getTagDigests(ctx, repositories)
+	if err != nil && ctx.Err() == nil {
+		return nil, err
+	}
+
+	drifts := []Drift{}
+	for i, repository := range repositories {
+		if digests[i] == nil {
+			continue
+		}
+		locked := lock.Repositories[repository]
+		tags := make([]string, 0, len(locked))
+		for tag := range locked {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+		for _, tag := range tags {
+			if actual := digests[i][tag]; actual != locked[tag] {
+				drifts = append(drifts, Drift{
+					Repository: repository,
+					Tag:        tag,
+					Expected:   locked[tag],
+					Actual:     actual,
+				})
+			}
+		}
+	}
+	return drifts, err
+}
+
+// getTagDigests returns the tag digests of each repository, or nil for the
+// repositories which couldn't be fetched before the context was done
+func (c *Client) getTagDigests(ctx context.Context, repositories []string) ([]map[string]string, error) {
+	ctx = withAllElements(ctx)
+	digests := make([]map[string]string, len(repositories))
+	errs := c.forEachConcurrently(ctx, len(repositories), func(i int) error {
+		tags, _, err := c.GetTags(ctx, repositories[i])
+		if err != nil {
+			return err
+		}
+		digests[i] = map[string]string{}
+		for _, tag := range tags {
+			digests[i][tag.Name[strings.LastIndex(tag.Name, ":")+1:]] = tagDigest(tag)
+		}
+		return nil
+	})
+	if ctx.Err() != nil {
+		return digests, ctx.Err()
+	}
+	return digests, firstError(errs)
+}
+
+func tagDigest(tag Tag) string {
+	if tag.Digest != "" || len(tag.Images) == 0 {
+		return tag.Digest
+	}
+	return tag.Images[0].Digest
+}
@@ -0,0 +1,58 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestTagLock(t *testing.T) {
+	client := newTestClient(t, routes{
+		"GET /v2/repositories/jdoe/app/tags/": `{"count": 2, "results": [
+			{"name": "latest", "digest": "sha256:aaa"},
+			{"name": "1.0", "images": [{"architecture": "amd64", "os": "linux", "digest": "sha256:bbb"}]}
+		]}`,
+		"GET /v2/repositories/jdoe/worker/tags/": `{"count": 1, "results": [{"name": "latest", "digest": "sha256:ccc"}]}`,
+	})
+
+	lock, err := client.ExportTagLock(context.Background(), []string{"jdoe/app", "jdoe/worker"})
+	assert.NilError(t, err)
+	assert.Equal(t, lock.Version, TagLockVersion)
+	assert.DeepEqual(t, lock.Repositories, map[string]map[string]string{
+		"jdoe/app":    {"latest": "sha256:aaa", "1.0": "sha256:bbb"},
+		"jdoe/worker": {"latest": "sha256:ccc"},
+	})
+
+	drifts, err := client.VerifyTagLock(context.Background(), lock)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, drifts, []Drift{})
+
+	lock.Repositories["jdoe/app"]["latest"] = "sha256:old"
+	lock.Repositories["jdoe/app"]["0.9"] = "sha256:removed"
+	drifts, err = client.VerifyTagLock(context.Background(), lock)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, drifts, []Drift{
+		{Repository: "jdoe/app", Tag: "0.9", Expected: "sha256:removed", Actual: ""},
+		{Repository: "jdoe/app", Tag: "latest", Expected: "sha256:old", Actual: "sha256:aaa"},
+	})
+
+	_, err = client.ExportTagLock(context.Background(), []string{"jdoe/missing"})
+	assert.Assert(t, IsNotFoundError(err))
+}
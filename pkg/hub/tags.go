@@ -0,0 +1,320 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/docker/distribution/reference"
+)
+
+const (
+	// TagsURL path to the Hub API listing the tags
+	TagsURL = "/v2/repositories/%s/tags/"
+	// DeleteTagURL path to the Hub API to remove a tag
+	DeleteTagURL = "/v2/repositories/%s/tags/%s/"
+	// TagURL path to the Hub API returning a tag
+	TagURL = "/v2/repositories/%s/tags/%s/"
+)
+
+//Tag can point to a manifest or manifest list
+type Tag struct {
+	Name                string
+	Digest              string
+	FullSize            int
+	LastUpdated         time.Time
+	LastUpdaterUserName string
+	Images              []Image
+	LastPulled          time.Time
+	LastPushed          time.Time
+	Status              string
+	// MediaType of the manifest the tag points to
+	MediaType string
+	// ArtifactType tells images apart from the other OCI artifacts, see
+	// the ArtifactType constants
+	ArtifactType string
+	// IsDangling is true when the tag doesn't reference any image, which
+	// usually means a push failed
+	IsDangling bool
+}
+
+//Image represents the metadata of a manifest
+type Image struct {
+	Digest       string
+	Architecture string
+	Os           string
+	Variant      string
+	Size         int
+	LastPulled   time.Time
+	LastPushed   time.Time
+	Status       string
+}
+
+//GetTags calls the hub repo API and returns all the information on all tags
+func (c *Client) GetTags(ctx context.Context, repository string, reqOps ...RequestOp) ([]Tag, int, error) {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return nil, 0, err
+	}
+	u, err := url.Parse(c.domain + fmt.Sprintf(TagsURL, repoPath))
+	if err != nil {
+		return nil, 0, err
+	}
+	q := url.Values{}
+	q.Add("page_size", fmt.Sprintf("%v", itemsPerPage))
+	q.Add("page", "1")
+	u.RawQuery = q.Encode()
+
+	tags, total, next, err := c.getTagsPage(ctx, u.String(), repository, reqOps...)
+	if err != nil {
+		return nil, 0, err
+	}
+	if c.fetchAll(ctx) {
+		var mu sync.Mutex
+		pages := map[int][]Tag{}
+		count, err := c.forEachRemainingPage(ctx, u, total, next, func(ctx context.Context, index int, url string) (string, error) {
+			pageTags, _, n, err := c.getTagsPage(ctx, url, repository, reqOps...)
+			if err != nil {
+				return "", err
+			}
+			mu.Lock()
+			pages[index] = pageTags
+			mu.Unlock()
+			return n, nil
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		for i := 0; i < count; i++ {
+			tags = append(tags, pages[i]...)
+		}
+	}
+
+	return tags, total, nil
+}
+
+//GetTag returns a tag of a repository with the images it references
+func (c *Client) GetTag(ctx context.Context, repository, tag string) (*Tag, error) {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", c.domain+fmt.Sprintf(TagURL, repoPath, tag), nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, err
+	}
+	var result hubTagResult
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, err
+	}
+	t := toTag(repository, result)
+	return &t, nil
+}
+
+//GetDanglingTags returns all the tags of a repository which don't reference any image
+func (c *Client) GetDanglingTags(ctx context.Context, repository string) ([]Tag, error) {
+	tags, _, err := c.GetTags(withAllElements(ctx), repository)
+	if err != nil {
+		return nil, err
+	}
+	dangling := []Tag{}
+	for _, tag := range tags {
+		if tag.IsDangling {
+			dangling = append(dangling, tag)
+		}
+	}
+	return dangling, nil
+}
+
+//IsRepositoryEmpty returns true if the repository has no tag, fetching a single
+// tag at most. A missing repository isn't empty: its error can be checked with
+// IsNotFoundError.
+func (c *Client) IsRepositoryEmpty(ctx context.Context, repository string) (bool, error) {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return false, err
+	}
+	u, err := url.Parse(c.domain + fmt.Sprintf(TagsURL, repoPath))
+	if err != nil {
+		return false, err
+	}
+	q := url.Values{}
+	q.Add("page_size", "1")
+	q.Add("page", "1")
+	u.RawQuery = q.Encode()
+
+	tags, total, _, err := c.getTagsPage(ctx, u.String(), repository)
+	if err != nil {
+		return false, err
+	}
+	return total == 0 && len(tags) == 0, nil
+}
+
+//RemoveTags removes concurrently tags of a repository. onResult is called
+// after each deletion, never concurrently, with the error of the deletion if
+// any. The tags which were removed are always returned, in the given order,
+// along with an error listing the ones which couldn't be.
+// The context deadline bounds the whole operation: once it expires, no other
+// tag is removed and the context error is returned.
+func (c *Client) RemoveTags(ctx context.Context, repository string, tags []string, onResult func(tag string, err error)) ([]string, error) {
+	var mu sync.Mutex
+	removed := make([]bool, len(tags))
+	errs := c.forEachConcurrently(ctx, len(tags), func(i int) error {
+		err := c.RemoveTag(ctx, repository, tags[i])
+		mu.Lock()
+		defer mu.Unlock()
+		onResult(tags[i], err)
+		removed[i] = err == nil
+		return err
+	})
+
+	result := []string{}
+	for i, tag := range tags {
+		if removed[i] {
+			result = append(result, tag)
+		}
+	}
+	if ctx.Err() != nil {
+		return result, ctx.Err()
+	}
+	return result, bulkError("failed to remove %d tags", tags, errs)
+}
+
+//RemoveTag removes a tag in a repository on Hub
+func (c *Client) RemoveTag(ctx context.Context, repository, tag string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.domain+fmt.Sprintf(DeleteTagURL, repository, tag), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.doRequest(req, withHubToken(c.token))
+	return err
+}
+
+func (c *Client) getTagsPage(ctx context.Context, url, repository string, reqOps ...RequestOp) ([]Tag, int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	response, err := c.doRequest(req, append(reqOps, withHubToken(c.token))...)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	var hubResponse hubTagResponse
+	if err := json.Unmarshal(response, &hubResponse); err != nil {
+		return nil, 0, "", err
+	}
+	var tags []Tag
+	for _, result := range hubResponse.Results {
+		tags = append(tags, toTag(repository, result))
+	}
+	return tags, hubResponse.Count, hubResponse.Next, nil
+}
+
+func toTag(repository string, result hubTagResult) Tag {
+	return Tag{
+		Name:                fmt.Sprintf("%s:%s", repository, result.Name),
+		Digest:              result.Digest,
+		FullSize:            result.FullSize,
+		LastUpdated:         result.LastUpdated,
+		LastUpdaterUserName: result.LastUpdaterUserName,
+		Images:              toImages(result.Images),
+		Status:              result.Status,
+		LastPulled:          result.LastPulled,
+		LastPushed:          result.LastPushed,
+		IsDangling:          len(result.Images) == 0,
+		MediaType:           result.MediaType,
+		ArtifactType:        artifactType(result),
+	}
+}
+
+type hubTagResponse struct {
+	Count    int            `json:"count"`
+	Next     string         `json:"next,omitempty"`
+	Previous string         `json:"previous,omitempty"`
+	Results  []hubTagResult `json:"results,omitempty"`
+}
+
+type hubTagResult struct {
+	Creator             int           `json:"creator"`
+	ID                  int           `json:"id"`
+	Name                string        `json:"name"`
+	Digest              string        `json:"digest,omitempty"`
+	ImageID             string        `json:"image_id,omitempty"`
+	LastUpdated         time.Time     `json:"last_updated"`
+	LastUpdater         int           `json:"last_updater"`
+	LastUpdaterUserName string        `json:"last_updater_username"`
+	Images              []hubTagImage `json:"images,omitempty"`
+	Repository          int           `json:"repository"`
+	FullSize            int           `json:"full_size"`
+	V2                  bool          `json:"v2"`
+	LastPulled          time.Time     `json:"tag_last_pulled,omitempty"`
+	LastPushed          time.Time     `json:"tag_last_pushed,omitempty"`
+	Status              string        `json:"tag_status,omitempty"`
+	MediaType           string        `json:"media_type,omitempty"`
+	ContentType         string        `json:"content_type,omitempty"`
+}
+
+type hubTagImage struct {
+	Architecture string    `json:"architecture"`
+	Os           string    `json:"os"`
+	Features     string    `json:"features,omitempty"`
+	Variant      string    `json:"variant,omitempty"`
+	Digest       string    `json:"digest"`
+	OsFeatures   string    `json:"os_features,omitempty"`
+	OsVersion    string    `json:"os_version,omitempty"`
+	Size         int       `json:"size"`
+	LastPulled   time.Time `json:"last_pulled,omitempty"`
+	LastPushed   time.Time `json:"last_pushed,omitempty"`
+	Status       string    `json:"status,omitempty"`
+}
+
+func getRepoPath(s string) (string, error) {
+	ref, err := reference.ParseNormalizedNamed(s)
+	if err != nil {
+		return "", err
+	}
+	ref = reference.TagNameOnly(ref)
+	ref = reference.TrimNamed(ref)
+	return reference.Path(ref), nil
+}
+
+func toImages(result []hubTagImage) []Image {
+	images := make([]Image, len(result))
+	for i := range result {
+		images[i] = Image{
+			Digest:       result[i].Digest,
+			Architecture: result[i].Architecture,
+			Os:           result[i].Os,
+			Variant:      result[i].Variant,
+			Size:         result[i].Size,
+			Status:       result[i].Status,
+			LastPulled:   result[i].LastPulled,
+			LastPushed:   result[i].LastPushed,
+		}
+	}
+	return images
+}
@@ -0,0 +1,113 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+const danglingTagsResponse = `{
+  "count": 3,
+  "results": [
+    {"name": "latest", "images": [{"architecture": "amd64", "os": "linux", "digest": "sha256:beef", "size": 42}]},
+    {"name": "broken", "images": []},
+    {"name": "missing"}
+  ]
+}`
+
+func TestGetDanglingTags(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Path, "/v2/repositories/library/alpine/tags/")
+		_, _ = w.Write([]byte(danglingTagsResponse))
+	}))
+
+	tags, err := client.GetDanglingTags(context.Background(), "alpine")
+	assert.NilError(t, err)
+	assert.Equal(t, len(tags), 2)
+	assert.Equal(t, tags[0].Name, "alpine:broken")
+	assert.Equal(t, tags[1].Name, "alpine:missing")
+	for _, tag := range tags {
+		assert.Assert(t, tag.IsDangling)
+		assert.Equal(t, len(tag.Images), 0)
+	}
+	assert.Assert(t, !client.fetchAllElements)
+}
+
+func TestIsRepositoryEmpty(t *testing.T) {
+	tags := routes{
+		"GET /v2/repositories/jdoe/empty/tags/": `{"count": 0, "results": []}`,
+		"GET /v2/repositories/jdoe/full/tags/":  `{"count": 12, "results": [{"name": "latest"}]}`,
+	}
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Query().Get("page_size"), "1")
+		tags.ServeHTTP(w, r)
+	}))
+
+	testCases := []struct {
+		repository string
+		empty      bool
+		notFound   bool
+	}{
+		{repository: "jdoe/empty", empty: true},
+		{repository: "jdoe/full", empty: false},
+		{repository: "jdoe/missing", notFound: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.repository, func(t *testing.T) {
+			empty, err := client.IsRepositoryEmpty(context.Background(), tc.repository)
+			if tc.notFound {
+				assert.Assert(t, IsNotFoundError(err))
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, empty, tc.empty)
+		})
+	}
+}
+
+func TestGetTagsArtifactType(t *testing.T) {
+	client := newTestClient(t, routes{
+		"GET /v2/repositories/jdoe/mixed/tags/": `{"count": 6, "results": [
+			{"name": "old"},
+			{"name": "latest", "media_type": "application/vnd.oci.image.index.v1+json", "content_type": "image"},
+			{"name": "chart", "media_type": "application/vnd.oci.image.manifest.v1+json", "content_type": "helm"},
+			{"name": "module", "media_type": "application/vnd.oci.image.manifest.v1+json", "content_type": "wasm"},
+			{"name": "sha256-6c3c624b58dbbcd3c0dd82b4c53f04194d1247c6eebdaab7c610cf7d66709b3b.sig", "media_type": "application/vnd.oci.image.manifest.v1+json", "content_type": "image"},
+			{"name": "other", "media_type": "application/vnd.oci.artifact.manifest.v1+json"}
+		]}`,
+	})
+
+	tags, _, err := client.GetTags(context.Background(), "jdoe/mixed")
+	assert.NilError(t, err)
+	var types []string
+	for _, tag := range tags {
+		types = append(types, tag.ArtifactType)
+	}
+	assert.DeepEqual(t, types, []string{
+		ArtifactTypeImage,
+		ArtifactTypeImage,
+		ArtifactTypeHelm,
+		ArtifactTypeWasm,
+		ArtifactTypeCosign,
+		ArtifactTypeUnknown,
+	})
+	assert.Equal(t, tags[1].MediaType, "application/vnd.oci.image.index.v1+json")
+}
@@ -17,6 +17,7 @@
 package hub
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -30,6 +31,10 @@ import (
 const (
 	//GroupsURL path to the Hub API listing the groups in an organization
 	GroupsURL = "/v2/orgs/%s/groups/"
+	//GroupURL path to the Hub API managing a group in an organization
+	GroupURL = "/v2/orgs/%s/groups/%s/"
+	//GroupMemberURL path to the Hub API managing a member of a group
+	GroupMemberURL = "/v2/orgs/%s/groups/%s/members/%s/"
 )
 
 //Team represents a hub group in an organization
@@ -40,7 +45,7 @@ type Team struct {
 }
 
 //GetTeams lists all the teams in an organization
-func (c *Client) GetTeams(organization string) ([]Team, error) {
+func (c *Client) GetTeams(ctx context.Context, organization string) ([]Team, error) {
 	u, err := url.Parse(c.domain + fmt.Sprintf(GroupsURL, organization))
 	if err != nil {
 		return nil, err
@@ -50,13 +55,13 @@ func (c *Client) GetTeams(organization string) ([]Team, error) {
 	q.Add("page", "1")
 	u.RawQuery = q.Encode()
 
-	teams, next, err := c.getTeamsPage(u.String(), organization)
+	teams, next, err := c.getTeamsPage(ctx, u.String(), organization)
 	if err != nil {
 		return nil, err
 	}
 
 	for next != "" {
-		pageTeams, n, err := c.getTeamsPage(next, organization)
+		pageTeams, n, err := c.getTeamsPage(ctx, next, organization)
 		if err != nil {
 			return nil, err
 		}
@@ -68,7 +73,7 @@ func (c *Client) GetTeams(organization string) ([]Team, error) {
 }
 
 //GetTeamsCount returns the number of teams in an organization
-func (c *Client) GetTeamsCount(organization string) (int, error) {
+func (c *Client) GetTeamsCount(ctx context.Context, organization string) (int, error) {
 	u, err := url.Parse(c.domain + fmt.Sprintf(GroupsURL, organization))
 	if err != nil {
 		return 0, err
@@ -78,7 +83,7 @@ func (c *Client) GetTeamsCount(organization string) (int, error) {
 	q.Add("page", "1")
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return 0, err
 	}
@@ -93,8 +98,66 @@ func (c *Client) GetTeamsCount(organization string) (int, error) {
 	return hubResponse.Count, nil
 }
 
-func (c *Client) getTeamsPage(url, organization string) ([]Team, string, error) {
-	req, err := http.NewRequest("GET", url, nil)
+//CreateTeam creates a team in an organization
+func (c *Client) CreateTeam(ctx context.Context, organization, name, description string) (*Team, error) {
+	data, err := json.Marshal(hubGroupRequest{Name: name, Description: description})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.domain+fmt.Sprintf(GroupsURL, organization), bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, err
+	}
+	var result hubGroupResult
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, err
+	}
+	return &Team{
+		Name:        result.Name,
+		Description: result.Description,
+	}, nil
+}
+
+//RemoveTeam removes a team from an organization
+func (c *Client) RemoveTeam(ctx context.Context, organization, team string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.domain+fmt.Sprintf(GroupURL, organization, team), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.doRequest(req, withHubToken(c.token))
+	return err
+}
+
+//AddTeamMember adds a member of the organization to a team
+func (c *Client) AddTeamMember(ctx context.Context, organization, team, username string) error {
+	data, err := json.Marshal(hubGroupMemberRequest{Member: username})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.domain+fmt.Sprintf(MembersPerTeamURL, organization, team), bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	_, err = c.doRequest(req, withHubToken(c.token))
+	return err
+}
+
+//RemoveTeamMember removes a member from a team, the user stays a member of the organization
+func (c *Client) RemoveTeamMember(ctx context.Context, organization, team, username string) error {
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.domain+fmt.Sprintf(GroupMemberURL, organization, team, username), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.doRequest(req, withHubToken(c.token))
+	return err
+}
+
+func (c *Client) getTeamsPage(ctx context.Context, url, organization string) ([]Team, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, "", err
 	}
@@ -107,11 +170,11 @@ func (c *Client) getTeamsPage(url, organization string) ([]Team, string, error)
 		return nil, "", err
 	}
 	var teams []Team
-	eg, _ := errgroup.WithContext(context.Background())
+	eg, ctx := errgroup.WithContext(ctx)
 	for _, result := range hubResponse.Results {
 		result := result
 		eg.Go(func() error {
-			members, err := c.GetMembersPerTeam(organization, result.Name)
+			members, err := c.GetMembersPerTeam(ctx, organization, result.Name)
 			if err != nil {
 				return err
 			}
@@ -148,3 +211,12 @@ type hubGroupResult struct {
 	Description string `json:"description"`
 	ID          int    `json:"id"`
 }
+
+type hubGroupRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type hubGroupMemberRequest struct {
+	Member string `json:"member"`
+}
@@ -0,0 +1,62 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+)
+
+func TestTeamManagement(t *testing.T) {
+	var requests []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "POST /v2/orgs/myorg/groups/":
+			var body hubGroupRequest
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, body, hubGroupRequest{Name: "developers", Description: "Push access to the application repositories"})
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write(golden.Get(t, "group.json"))
+		case "POST /v2/orgs/myorg/groups/developers/members/":
+			var body hubGroupMemberRequest
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, body.Member, "jdoe")
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+
+	team, err := client.CreateTeam(context.Background(), "myorg", "developers", "Push access to the application repositories")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, team, &Team{Name: "developers", Description: "Push access to the application repositories"})
+	assert.NilError(t, client.AddTeamMember(context.Background(), "myorg", "developers", "jdoe"))
+	assert.NilError(t, client.RemoveTeamMember(context.Background(), "myorg", "developers", "jdoe"))
+	assert.NilError(t, client.RemoveTeam(context.Background(), "myorg", "developers"))
+
+	assert.DeepEqual(t, requests, []string{
+		"POST /v2/orgs/myorg/groups/",
+		"POST /v2/orgs/myorg/groups/developers/members/",
+		"DELETE /v2/orgs/myorg/groups/developers/members/jdoe/",
+		"DELETE /v2/orgs/myorg/groups/developers/",
+	})
+}
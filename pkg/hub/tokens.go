@@ -18,10 +18,13 @@ package hub
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -34,6 +37,20 @@ const (
 	TokenURL = "/v2/api_tokens/%s"
 )
 
+const (
+	//RepoAdminScope allows to read, write and delete repositories
+	RepoAdminScope = "repo:admin"
+	//RepoWriteScope allows to read and write repositories
+	RepoWriteScope = "repo:write"
+	//RepoReadScope allows to read repositories
+	RepoReadScope = "repo:read"
+	//RepoPublicReadScope allows to read public repositories only
+	RepoPublicReadScope = "repo:public_read"
+)
+
+//TokenScopes lists all the scopes a token can be created with
+var TokenScopes = []string{RepoAdminScope, RepoWriteScope, RepoReadScope, RepoPublicReadScope}
+
 //Token is a personal access token. The token field will only be filled at creation and can never been accessed again.
 type Token struct {
 	UUID        uuid.UUID
@@ -42,20 +59,32 @@ type Token struct {
 	CreatorUA   string
 	CreatedAt   time.Time
 	LastUsed    time.Time
+	LastUsedIP  string
 	GeneratedBy string
 	IsActive    bool
 	Token       string
 	Description string
+	Scopes      []string
+}
+
+//OverprivilegedToken is a token holding scopes beyond the expected ones
+type OverprivilegedToken struct {
+	Token       Token
+	ExtraScopes []string
 }
 
-// CreateToken creates a Personal Access Token and returns the token field only once
-func (c *Client) CreateToken(description string) (*Token, error) {
-	data, err := json.Marshal(hubTokenRequest{Description: description})
+// CreateToken creates a Personal Access Token and returns the token field only once.
+// Without scopes, Hub gives the token the default ones.
+func (c *Client) CreateToken(ctx context.Context, description string, scopes ...string) (*Token, error) {
+	if err := ValidateTokenScopes(scopes); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(hubTokenRequest{Description: description, Scopes: scopes})
 	if err != nil {
 		return nil, err
 	}
 	body := bytes.NewBuffer(data)
-	req, err := http.NewRequest("POST", c.domain+TokensURL, body)
+	req, err := http.NewRequestWithContext(ctx, "POST", c.domain+TokensURL, body)
 	if err != nil {
 		return nil, err
 	}
@@ -75,7 +104,7 @@ func (c *Client) CreateToken(description string) (*Token, error) {
 }
 
 //GetTokens calls the hub repo API and returns all the information on all tokens
-func (c *Client) GetTokens() ([]Token, int, error) {
+func (c *Client) GetTokens(ctx context.Context) ([]Token, int, error) {
 	u, err := url.Parse(c.domain + TokensURL)
 	if err != nil {
 		return nil, 0, err
@@ -85,13 +114,13 @@ func (c *Client) GetTokens() ([]Token, int, error) {
 	q.Add("page", "1")
 	u.RawQuery = q.Encode()
 
-	tokens, total, next, err := c.getTokensPage(u.String())
+	tokens, total, next, err := c.getTokensPage(ctx, u.String())
 	if err != nil {
 		return nil, 0, err
 	}
-	if c.fetchAllElements {
+	if c.fetchAll(ctx) {
 		for next != "" {
-			pageTokens, _, n, err := c.getTokensPage(next)
+			pageTokens, _, n, err := c.getTokensPage(ctx, next)
 			if err != nil {
 				return nil, 0, err
 			}
@@ -103,9 +132,62 @@ func (c *Client) GetTokens() ([]Token, int, error) {
 	return tokens, total, nil
 }
 
+//FindOverprivilegedTokens lists all the tokens and returns the ones holding
+// scopes which aren't in the expected ones, along with these extra scopes
+func (c *Client) FindOverprivilegedTokens(ctx context.Context, expectedScopes []string) ([]OverprivilegedToken, error) {
+	tokens, _, err := c.GetTokens(withAllElements(ctx))
+	if err != nil {
+		return nil, err
+	}
+	expected := map[string]bool{}
+	for _, scope := range expectedScopes {
+		expected[scope] = true
+	}
+	overprivileged := []OverprivilegedToken{}
+	for _, token := range tokens {
+		var extra []string
+		for _, scope := range token.Scopes {
+			if !expected[scope] {
+				extra = append(extra, scope)
+			}
+		}
+		if len(extra) > 0 {
+			sort.Strings(extra)
+			overprivileged = append(overprivileged, OverprivilegedToken{Token: token, ExtraScopes: extra})
+		}
+	}
+	return overprivileged, nil
+}
+
+//FindUnusedTokens lists all the tokens and returns the active ones which
+// haven't been used since the given time, the ones never used being compared
+// by their creation date
+func (c *Client) FindUnusedTokens(ctx context.Context, since time.Time) ([]Token, error) {
+	tokens, _, err := c.GetTokens(withAllElements(ctx))
+	if err != nil {
+		return nil, err
+	}
+	unused := []Token{}
+	for _, token := range tokens {
+		if token.IsActive && token.lastActivity().Before(since) {
+			unused = append(unused, token)
+		}
+	}
+	return unused, nil
+}
+
+// lastActivity returns when the token was last used, or created if it never
+// was
+func (t Token) lastActivity() time.Time {
+	if t.LastUsed.IsZero() {
+		return t.CreatedAt
+	}
+	return t.LastUsed
+}
+
 //GetToken calls the hub repo API and returns the information on one token
-func (c *Client) GetToken(tokenUUID string) (*Token, error) {
-	req, err := http.NewRequest("GET", c.domain+fmt.Sprintf(TokenURL, tokenUUID), nil)
+func (c *Client) GetToken(ctx context.Context, tokenUUID string) (*Token, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.domain+fmt.Sprintf(TokenURL, tokenUUID), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -125,7 +207,7 @@ func (c *Client) GetToken(tokenUUID string) (*Token, error) {
 }
 
 // UpdateToken updates a token's description and activeness
-func (c *Client) UpdateToken(tokenUUID, description string, isActive bool) (*Token, error) {
+func (c *Client) UpdateToken(ctx context.Context, tokenUUID, description string, isActive bool) (*Token, error) {
 	tokenRequest := hubTokenRequest{IsActive: isActive}
 	if description != "" {
 		tokenRequest.Description = description
@@ -135,7 +217,7 @@ func (c *Client) UpdateToken(tokenUUID, description string, isActive bool) (*Tok
 		return nil, err
 	}
 	body := bytes.NewBuffer(data)
-	req, err := http.NewRequest("PATCH", c.domain+fmt.Sprintf(TokenURL, tokenUUID), body)
+	req, err := http.NewRequestWithContext(ctx, "PATCH", c.domain+fmt.Sprintf(TokenURL, tokenUUID), body)
 	if err != nil {
 		return nil, err
 	}
@@ -154,10 +236,34 @@ func (c *Client) UpdateToken(tokenUUID, description string, isActive bool) (*Tok
 	return &token, nil
 }
 
+//ValidateTokenScopes checks the scopes are ones Hub supports, suggesting the
+// right one for a scope given without its "repo:" prefix
+func ValidateTokenScopes(scopes []string) error {
+	for _, scope := range scopes {
+		if isTokenScope(scope) {
+			continue
+		}
+		if isTokenScope("repo:" + scope) {
+			return fmt.Errorf("invalid scope %q, did you mean %q?", scope, "repo:"+scope)
+		}
+		return fmt.Errorf("invalid scope %q, must be one of %s", scope, strings.Join(TokenScopes, ", "))
+	}
+	return nil
+}
+
+func isTokenScope(scope string) bool {
+	for _, s := range TokenScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
 //RemoveToken deletes a token from personal access token
-func (c *Client) RemoveToken(tokenUUID string) error {
+func (c *Client) RemoveToken(ctx context.Context, tokenUUID string) error {
 	//DELETE https://hub.docker.com/v2/api_tokens/8208674e-d08a-426f-b6f4-e3aba7058459 => 202
-	req, err := http.NewRequest("DELETE", c.domain+fmt.Sprintf(TokenURL, tokenUUID), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.domain+fmt.Sprintf(TokenURL, tokenUUID), nil)
 	if err != nil {
 		return err
 	}
@@ -165,8 +271,8 @@ func (c *Client) RemoveToken(tokenUUID string) error {
 	return err
 }
 
-func (c *Client) getTokensPage(url string) ([]Token, int, string, error) {
-	req, err := http.NewRequest("GET", url, nil)
+func (c *Client) getTokensPage(ctx context.Context, url string) ([]Token, int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, 0, "", err
 	}
@@ -190,8 +296,9 @@ func (c *Client) getTokensPage(url string) ([]Token, int, string, error) {
 }
 
 type hubTokenRequest struct {
-	Description string `json:"token_label,omitempty"`
-	IsActive    bool   `json:"is_active"`
+	Description string   `json:"token_label,omitempty"`
+	IsActive    bool     `json:"is_active"`
+	Scopes      []string `json:"scopes,omitempty"`
 }
 
 type hubTokenResponse struct {
@@ -208,10 +315,12 @@ type hubTokenResult struct {
 	CreatorUA   string    `json:"creator_ua"`
 	CreatedAt   time.Time `json:"created_at"`
 	LastUsed    time.Time `json:"last_used,omitempty"`
+	LastUsedIP  string    `json:"last_used_ip,omitempty"`
 	GeneratedBy string    `json:"generated_by"`
 	IsActive    bool      `json:"is_active"`
 	Token       string    `json:"token"`
 	TokenLabel  string    `json:"token_label"`
+	Scopes      []string  `json:"scopes,omitempty"`
 }
 
 func convertToken(response hubTokenResult) (Token, error) {
@@ -226,9 +335,11 @@ func convertToken(response hubTokenResult) (Token, error) {
 		CreatorUA:   response.CreatorUA,
 		CreatedAt:   response.CreatedAt,
 		LastUsed:    response.LastUsed,
+		LastUsedIP:  response.LastUsedIP,
 		GeneratedBy: response.GeneratedBy,
 		IsActive:    response.IsActive,
 		Token:       response.Token,
 		Description: response.TokenLabel,
+		Scopes:      response.Scopes,
 	}, nil
 }
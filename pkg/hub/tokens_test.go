@@ -0,0 +1,108 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+)
+
+func TestFindOverprivilegedTokens(t *testing.T) {
+	client := newTestClient(t, routes{
+		"GET /v2/api_tokens": string(golden.Get(t, "tokens.json")),
+	})
+
+	testCases := []struct {
+		name     string
+		expected []string
+		labels   []string
+		extra    [][]string
+	}{
+		{name: "read and write", expected: []string{RepoWriteScope, RepoReadScope}, labels: []string{"Laptop"}, extra: [][]string{{RepoAdminScope}}},
+		{name: "read only", expected: []string{RepoReadScope}, labels: []string{"CI pushes", "Laptop"}, extra: [][]string{{RepoWriteScope}, {RepoAdminScope, RepoWriteScope}}},
+		{name: "all scopes", expected: TokenScopes},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tokens, err := client.FindOverprivilegedTokens(context.Background(), tc.expected)
+			assert.NilError(t, err)
+			assert.Equal(t, len(tokens), len(tc.labels))
+			for i, token := range tokens {
+				assert.Equal(t, token.Token.Description, tc.labels[i])
+				assert.DeepEqual(t, token.ExtraScopes, tc.extra[i])
+			}
+		})
+	}
+}
+
+func TestFindUnusedTokens(t *testing.T) {
+	client := newTestClient(t, routes{
+		"GET /v2/api_tokens": string(golden.Get(t, "tokens.json")),
+	})
+
+	testCases := []struct {
+		name   string
+		since  time.Time
+		labels []string
+	}{
+		{name: "before all", since: time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "never used", since: time.Date(2020, 11, 3, 0, 0, 0, 0, time.UTC), labels: []string{"CI pushes"}},
+		{name: "after all", since: time.Date(2020, 12, 1, 0, 0, 0, 0, time.UTC), labels: []string{"CI pushes", "Laptop"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tokens, err := client.FindUnusedTokens(context.Background(), tc.since)
+			assert.NilError(t, err)
+			assert.Equal(t, len(tokens), len(tc.labels))
+			for i, token := range tokens {
+				assert.Equal(t, token.Description, tc.labels[i])
+			}
+		})
+	}
+}
+
+func TestCreateTokenWithScopes(t *testing.T) {
+	var created hubTokenRequest
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method+" "+r.URL.Path, "POST /v2/api_tokens")
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&created))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"uuid": "2b7c5a1e-8d0f-4b3a-9a5e-1f2d3c4b5a69", "is_active": true, "token": "dckr_pat_secret", "token_label": "CI pulls", "scopes": ["repo:read"]}`))
+	}))
+
+	token, err := client.CreateToken(context.Background(), "CI pulls", RepoReadScope)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, created, hubTokenRequest{Description: "CI pulls", Scopes: []string{RepoReadScope}})
+	assert.Equal(t, token.Token, "dckr_pat_secret")
+	assert.DeepEqual(t, token.Scopes, []string{RepoReadScope})
+
+	_, err = client.CreateToken(context.Background(), "CI pulls", "repo:owner")
+	assert.Error(t, err, `invalid scope "repo:owner", must be one of repo:admin, repo:write, repo:read, repo:public_read`)
+}
+
+func TestValidateTokenScopes(t *testing.T) {
+	assert.NilError(t, ValidateTokenScopes(nil))
+	assert.NilError(t, ValidateTokenScopes([]string{RepoReadScope, RepoWriteScope}))
+	assert.Error(t, ValidateTokenScopes([]string{RepoReadScope, "write"}), `invalid scope "write", did you mean "repo:write"?`)
+	assert.Error(t, ValidateTokenScopes([]string{"admin:org"}), `invalid scope "admin:org", must be one of repo:admin, repo:write, repo:read, repo:public_read`)
+}
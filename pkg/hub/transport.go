@@ -0,0 +1,198 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// WithCACert makes the client trust the certificate authorities of the given PEM
+// file on top of the system ones, for Hub instances or proxies using a private
+// authority
+func WithCACert(path string) ClientOp {
+	return func(c *Client) error {
+		if path == "" {
+			return nil
+		}
+		pem, err := ioutil.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read the CA certificates: %s", err)
+		}
+		if c.customTransport != nil {
+			return errors.New("the CA certificates can't be set with a custom transport")
+		}
+		tlsConfig := c.transport().TLSClientConfig
+		if tlsConfig.RootCAs == nil {
+			if tlsConfig.RootCAs, err = x509.SystemCertPool(); err != nil {
+				tlsConfig.RootCAs = x509.NewCertPool()
+			}
+		}
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no CA certificate found in %s", path)
+		}
+		return nil
+	}
+}
+
+// WithInsecureSkipVerify disables the verification of the server certificates,
+// which should only be used for testing
+func WithInsecureSkipVerify() ClientOp {
+	return func(c *Client) error {
+		if c.customTransport != nil {
+			return errors.New("the certificates verification can't be disabled with a custom transport")
+		}
+		c.transport().TLSClientConfig.InsecureSkipVerify = true //nolint:gosec
+		return nil
+	}
+}
+
+// Middleware wraps the transport of the client, to instrument or change the
+// requests and the responses, e.g. for metrics or tracing
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is a function used as an http.RoundTripper, to write
+// middlewares
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls the function
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// WithTransport makes the client send its requests with the given transport
+// instead of the default one. WithCACert and WithInsecureSkipVerify can't be
+// used with it, configure the transport instead.
+func WithTransport(transport http.RoundTripper) ClientOp {
+	return func(c *Client) error {
+		if transport == nil {
+			return errors.New("invalid nil transport")
+		}
+		c.customTransport = transport
+		return nil
+	}
+}
+
+// WithMiddleware wraps the transport of the client with the middlewares. The
+// first middleware given is the first to see a request, the middlewares of
+// successive calls wrap the previous ones. Each retry of a request goes
+// through them.
+func WithMiddleware(middlewares ...Middleware) ClientOp {
+	return func(c *Client) error {
+		c.middlewares = append(append([]Middleware{}, middlewares...), c.middlewares...)
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header of the requests, instead of the
+// hub-tool one
+func WithUserAgent(userAgent string) ClientOp {
+	return func(c *Client) error {
+		c.userAgent = userAgent
+		return nil
+	}
+}
+
+// HTTPClient returns the HTTP client the requests are sent with, so that other
+// clients of the Hub, such as the registry one, share its configuration
+func (c *Client) HTTPClient() *http.Client {
+	if c.customTransport == nil && len(c.middlewares) == 0 && c.dryRunOut == nil {
+		if c.httpClient == nil {
+			return http.DefaultClient
+		}
+		return c.httpClient
+	}
+	transport := c.customTransport
+	if transport == nil {
+		transport = http.DefaultTransport
+		if c.httpClient != nil {
+			transport = c.httpClient.Transport
+		}
+	}
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		transport = c.middlewares[i](transport)
+	}
+	if c.dryRunOut != nil {
+		transport = dryRun(c.dryRunOut)(transport)
+	}
+	return &http.Client{Transport: transport}
+}
+
+// DryRun tells if the client only prints the requests which would change
+// something instead of sending them
+func (c *Client) DryRun() bool {
+	return c.dryRunOut != nil
+}
+
+type loginKey struct{}
+
+// withLogin marks the requests of a login, which are sent even in dry-run
+func withLogin(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loginKey{}, true)
+}
+
+// dryRun prints the requests which would change something to out and answers
+// them with an empty JSON object, letting the read requests, the logins and
+// the registry token requests through
+func dryRun(out io.Writer) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if !isMutating(req.Method) || req.Context().Value(loginKey{}) != nil || strings.HasPrefix(req.URL.String(), RegistryAuthURL) {
+				return next.RoundTrip(req)
+			}
+			if req.Body != nil {
+				_ = req.Body.Close()
+			}
+			fmt.Fprintf(out, "Would send %s %s\n", req.Method, req.URL)
+			body := []byte("{}")
+			return &http.Response{
+				Status:        "200 OK",
+				StatusCode:    http.StatusOK,
+				Proto:         req.Proto,
+				ProtoMajor:    req.ProtoMajor,
+				ProtoMinor:    req.ProtoMinor,
+				Header:        http.Header{"Content-Type": []string{"application/json"}},
+				Body:          ioutil.NopCloser(bytes.NewReader(body)),
+				ContentLength: int64(len(body)),
+				Request:       req,
+			}, nil
+		})
+	}
+}
+
+// transport returns the transport of the client, creating it from the default
+// one the first time it is configured. Proxies are still taken from the
+// HTTPS_PROXY and NO_PROXY environment variables.
+func (c *Client) transport() *http.Transport {
+	if c.httpClient == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = http.ProxyFromEnvironment
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		c.httpClient = &http.Client{Transport: transport}
+	}
+	return c.httpClient.Transport.(*http.Transport)
+}
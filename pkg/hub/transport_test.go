@@ -0,0 +1,105 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestTLSConfiguration(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	caCert := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NilError(t, ioutil.WriteFile(caCert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+
+	testCases := []struct {
+		name string
+		ops  []ClientOp
+		err  bool
+	}{
+		{name: "untrusted", err: true},
+		{name: "CA certificate", ops: []ClientOp{WithCACert(caCert)}},
+		{name: "insecure", ops: []ClientOp{WithInsecureSkipVerify()}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, err := NewClient(append(tc.ops, WithRetries(0))...)
+			assert.NilError(t, err)
+			req, err := http.NewRequest("GET", server.URL, nil)
+			assert.NilError(t, err)
+			_, err = client.doRequest(req)
+			assert.Equal(t, err != nil, tc.err, "error: %v", err)
+		})
+	}
+}
+
+func TestWithCACertRejectsInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	assert.NilError(t, ioutil.WriteFile(path, []byte("not a certificate"), 0600))
+	_, err := NewClient(WithCACert(path))
+	assert.Error(t, err, "no CA certificate found in "+path)
+}
+
+func TestMiddlewaresAndUserAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Header.Get("User-Agent"), "mytool/1.0")
+		assert.Equal(t, r.Header.Get("X-Trace"), "outer,inner")
+		_, _ = w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name)
+				if v := req.Header.Get("X-Trace"); v != "" {
+					name = v + "," + name
+				}
+				req.Header.Set("X-Trace", name)
+				return next.RoundTrip(req)
+			})
+		}
+	}
+	client, err := NewClient(
+		WithTransport(http.DefaultTransport),
+		WithMiddleware(trace("inner")),
+		WithMiddleware(trace("outer")),
+		WithUserAgent("mytool/1.0"),
+	)
+	assert.NilError(t, err)
+	req, err := http.NewRequest("GET", server.URL, nil)
+	assert.NilError(t, err)
+	_, err = client.doRequest(req)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, calls, []string{"outer", "inner"})
+}
+
+func TestCustomTransportRejectsTLSOptions(t *testing.T) {
+	_, err := NewClient(WithTransport(http.DefaultTransport), WithInsecureSkipVerify())
+	assert.Error(t, err, "the certificates verification can't be disabled with a custom transport")
+}
@@ -17,6 +17,7 @@
 package hub
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/url"
@@ -26,6 +27,8 @@ import (
 const (
 	//UserURL path to user informations
 	UserURL = "/v2/user/"
+	//ProfileURL path to the public profile of a user or an organization
+	ProfileURL = "/v2/users/%s/"
 )
 
 //Account represents a user or organization information
@@ -39,12 +42,12 @@ type Account struct {
 }
 
 //GetUserInfo returns the information on the user retrieved from Hub
-func (c *Client) GetUserInfo() (*Account, error) {
+func (c *Client) GetUserInfo(ctx context.Context) (*Account, error) {
 	u, err := url.Parse(c.domain + UserURL)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,290 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// WebhooksURL path to the Hub API listing the webhooks of a repository
+	WebhooksURL = "/v2/repositories/%s/webhook_pipeline/"
+	// WebhookURL path to the Hub API managing a webhook of a repository
+	WebhookURL = "/v2/repositories/%s/webhook_pipeline/%s/"
+)
+
+// Webhook is called by Hub each time an image is pushed to the repository
+type Webhook struct {
+	Name string
+	// Slug identifies the webhook in the repository
+	Slug      string
+	HookURL   string
+	CreatedAt time.Time
+}
+
+// GetWebhooks lists all the webhooks of a repository
+func (c *Client) GetWebhooks(ctx context.Context, repository string) ([]Webhook, error) {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(c.domain + fmt.Sprintf(WebhooksURL, repoPath))
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	q.Add("page_size", fmt.Sprintf("%v", itemsPerPage))
+	q.Add("page", "1")
+	u.RawQuery = q.Encode()
+
+	webhooks, next, err := c.getWebhooksPage(ctx, u.String())
+	if err != nil {
+		return nil, err
+	}
+	for next != "" {
+		pageWebhooks, n, err := c.getWebhooksPage(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+		next = n
+		webhooks = append(webhooks, pageWebhooks...)
+	}
+	return webhooks, nil
+}
+
+// CreateWebhook adds a webhook to a repository
+func (c *Client) CreateWebhook(ctx context.Context, repository, name, hookURL string) (*Webhook, error) {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(hubWebhookPipelineRequest{
+		Name:     name,
+		Webhooks: []hubWebhook{{Name: name, HookURL: hookURL}},
+		Registry: "registry-1.docker.io",
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.domain+fmt.Sprintf(WebhooksURL, repoPath), bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, err
+	}
+	var result hubWebhookPipelineResult
+	if err := json.Unmarshal(response, &result); err != nil {
+		return nil, err
+	}
+	webhook := toWebhook(result)
+	return &webhook, nil
+}
+
+// RemoveWebhook removes a webhook, identified by its slug, from a repository
+func (c *Client) RemoveWebhook(ctx context.Context, repository, slug string) error {
+	repoPath, err := getRepoPath(repository)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "DELETE", c.domain+fmt.Sprintf(WebhookURL, repoPath, slug), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.doRequest(req, withHubToken(c.token))
+	return err
+}
+
+// WebhookPayload is the body Hub posts to the webhooks of a repository when an
+// image is pushed
+type WebhookPayload struct {
+	// CallbackURL is where the receiver can report the result of its
+	// processing. Test payloads don't have any.
+	CallbackURL string            `json:"callback_url,omitempty"`
+	PushData    WebhookPushData   `json:"push_data"`
+	Repository  WebhookRepository `json:"repository"`
+}
+
+// WebhookPushData describes the push which fired the webhook
+type WebhookPushData struct {
+	PushedAt int64  `json:"pushed_at"`
+	Pusher   string `json:"pusher"`
+	Tag      string `json:"tag"`
+}
+
+// WebhookRepository describes the repository an image was pushed to
+type WebhookRepository struct {
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace"`
+	Owner           string `json:"owner"`
+	RepoName        string `json:"repo_name"`
+	RepoURL         string `json:"repo_url"`
+	Description     string `json:"description"`
+	FullDescription string `json:"full_description"`
+	IsOfficial      bool   `json:"is_official"`
+	IsPrivate       bool   `json:"is_private"`
+	StarCount       int    `json:"star_count"`
+	Status          string `json:"status"`
+}
+
+// WebhookTestResult is the response of a webhook receiver to a test payload
+type WebhookTestResult struct {
+	HookURL    string
+	StatusCode int
+	Status     string
+	Duration   time.Duration
+}
+
+// TestWebhook posts to a webhook of a repository, identified by its slug, the
+// payload Hub would send for a push of the tag. Hub can't fire a webhook on
+// demand, so the payload is built from the repository and sent by the client.
+func (c *Client) TestWebhook(ctx context.Context, repository, slug, tag string) (*WebhookTestResult, error) {
+	webhooks, err := c.GetWebhooks(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	var webhook *Webhook
+	for i := range webhooks {
+		if webhooks[i].Slug == slug {
+			webhook = &webhooks[i]
+		}
+	}
+	if webhook == nil {
+		return nil, &notFoundError{fmt.Errorf("webhook %q not found in %s", slug, repository)}
+	}
+	repo, err := c.GetRepository(ctx, repository)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(newWebhookPayload(*repo, c.account, tag, time.Now()))
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", webhook.HookURL, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	start := time.Now()
+	resp, err := c.HTTPClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	return &WebhookTestResult{
+		HookURL:    webhook.HookURL,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Duration:   time.Since(start),
+	}, nil
+}
+
+func newWebhookPayload(repo Repository, pusher, tag string, pushedAt time.Time) WebhookPayload {
+	namespace, name := repo.Name, repo.Name
+	if i := strings.Index(repo.Name, "/"); i >= 0 {
+		namespace, name = repo.Name[:i], repo.Name[i+1:]
+	}
+	return WebhookPayload{
+		PushData: WebhookPushData{
+			PushedAt: pushedAt.Unix(),
+			Pusher:   pusher,
+			Tag:      tag,
+		},
+		Repository: WebhookRepository{
+			Name:            name,
+			Namespace:       namespace,
+			Owner:           namespace,
+			RepoName:        repo.Name,
+			RepoURL:         fmt.Sprintf("https://hub.docker.com/r/%s", repo.Name),
+			Description:     repo.Description,
+			FullDescription: repo.FullDescription,
+			IsOfficial:      namespace == "library",
+			IsPrivate:       repo.IsPrivate,
+			StarCount:       repo.StarCount,
+			Status:          "Active",
+		},
+	}
+}
+
+func (c *Client) getWebhooksPage(ctx context.Context, url string) ([]Webhook, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	response, err := c.doRequest(req, withHubToken(c.token))
+	if err != nil {
+		return nil, "", err
+	}
+	var hubResponse hubWebhookPipelineResponse
+	if err := json.Unmarshal(response, &hubResponse); err != nil {
+		return nil, "", err
+	}
+	var webhooks []Webhook
+	for _, result := range hubResponse.Results {
+		webhooks = append(webhooks, toWebhook(result))
+	}
+	return webhooks, hubResponse.Next, nil
+}
+
+// toWebhook converts a pipeline, which Hub models as a list of hooks although
+// it only ever creates a single one
+func toWebhook(result hubWebhookPipelineResult) Webhook {
+	webhook := Webhook{
+		Name:      result.Name,
+		Slug:      result.Slug,
+		CreatedAt: result.Created,
+	}
+	if len(result.Webhooks) > 0 {
+		webhook.HookURL = result.Webhooks[0].HookURL
+	}
+	return webhook
+}
+
+type hubWebhookPipelineRequest struct {
+	Name                string       `json:"name"`
+	ExpectFinalCallback bool         `json:"expect_final_callback"`
+	Webhooks            []hubWebhook `json:"webhooks"`
+	Registry            string       `json:"registry"`
+}
+
+type hubWebhookPipelineResponse struct {
+	Count    int                        `json:"count"`
+	Next     string                     `json:"next,omitempty"`
+	Previous string                     `json:"previous,omitempty"`
+	Results  []hubWebhookPipelineResult `json:"results,omitempty"`
+}
+
+type hubWebhookPipelineResult struct {
+	Name                string       `json:"name"`
+	Slug                string       `json:"slug"`
+	ExpectFinalCallback bool         `json:"expect_final_callback"`
+	Webhooks            []hubWebhook `json:"webhooks"`
+	Created             time.Time    `json:"created"`
+}
+
+type hubWebhook struct {
+	Name    string `json:"name"`
+	HookURL string `json:"hook_url"`
+}
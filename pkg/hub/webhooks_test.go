@@ -0,0 +1,101 @@
+/*
+   Copyright 2020 Docker Hub Tool authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+)
+
+func TestWebhooks(t *testing.T) {
+	var created hubWebhookPipelineRequest
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v2/repositories/jdoe/app/webhook_pipeline/":
+			_, _ = w.Write(golden.Get(t, "webhooks.json"))
+		case "POST /v2/repositories/jdoe/app/webhook_pipeline/":
+			assert.NilError(t, json.NewDecoder(r.Body).Decode(&created))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"name": "ci", "slug": "ci", "webhooks": [{"name": "ci", "hook_url": "https://ci.example.com/hooks/docker"}]}`))
+		case "DELETE /v2/repositories/jdoe/app/webhook_pipeline/deploy-staging/":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	webhooks, err := client.GetWebhooks(context.Background(), "jdoe/app")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, webhooks, []Webhook{
+		{Name: "ci", Slug: "ci", HookURL: "https://ci.example.com/hooks/docker", CreatedAt: time.Date(2020, 11, 2, 9, 12, 41, 412812000, time.UTC)},
+		{Name: "Deploy staging", Slug: "deploy-staging", HookURL: "https://deploy.example.com/staging", CreatedAt: time.Date(2020, 11, 3, 17, 40, 5, 118293000, time.UTC)},
+	})
+
+	webhook, err := client.CreateWebhook(context.Background(), "jdoe/app", "ci", "https://ci.example.com/hooks/docker")
+	assert.NilError(t, err)
+	assert.Equal(t, webhook.Slug, "ci")
+	assert.DeepEqual(t, created, hubWebhookPipelineRequest{
+		Name:     "ci",
+		Webhooks: []hubWebhook{{Name: "ci", HookURL: "https://ci.example.com/hooks/docker"}},
+		Registry: "registry-1.docker.io",
+	})
+
+	assert.NilError(t, client.RemoveWebhook(context.Background(), "jdoe/app", "deploy-staging"))
+}
+
+func TestTestWebhook(t *testing.T) {
+	var payload WebhookPayload
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Header.Get("Content-Type"), "application/json")
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer receiver.Close()
+	client := newTestClient(t, routes{
+		"GET /v2/repositories/jdoe/app/webhook_pipeline/": `{"count": 1, "results": [{"name": "ci", "slug": "ci", "webhooks": [{"name": "ci", "hook_url": "` + receiver.URL + `"}]}]}`,
+		"GET /v2/repositories/jdoe/app/":                  `{"name": "app", "namespace": "jdoe", "description": "The app", "star_count": 4}`,
+	})
+	client.account = "jdoe"
+
+	result, err := client.TestWebhook(context.Background(), "jdoe/app", "ci", "latest")
+	assert.NilError(t, err)
+	assert.Equal(t, result.StatusCode, http.StatusAccepted)
+	assert.Equal(t, result.HookURL, receiver.URL)
+	assert.Equal(t, payload.PushData.Pusher, "jdoe")
+	assert.Equal(t, payload.PushData.Tag, "latest")
+	assert.DeepEqual(t, payload.Repository, WebhookRepository{
+		Name:        "app",
+		Namespace:   "jdoe",
+		Owner:       "jdoe",
+		RepoName:    "jdoe/app",
+		RepoURL:     "https://hub.docker.com/r/jdoe/app",
+		Description: "The app",
+		StarCount:   4,
+		Status:      "Active",
+	})
+
+	_, err = client.TestWebhook(context.Background(), "jdoe/app", "deploy", "latest")
+	assert.Assert(t, IsNotFoundError(err))
+}